@@ -234,6 +234,11 @@ func (p KeyCloakActivityProcessor) Process(ctx api.ActivityContext) api.Activity
 		}
 		return vaultClientResult
 	}
+	if ctx.Discriminator() == api.UpdateDiscriminator || ctx.Discriminator() == api.MigrateDiscriminator {
+		// Keycloak clients do not depend on VPA properties or placement
+		p.monitor.Infof("No Keycloak changes required for %s", ctx.Discriminator())
+		return api.ActivityResult{Result: api.ActivityResultComplete}
+	}
 	return api.ActivityResult{Result: api.ActivityResultFatalError, Error: fmt.Errorf("the '%s' discriminator is not supported", ctx.Discriminator())}
 }
 
@@ -0,0 +1,93 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/metaform/connector-fabric-manager/assembly/routing"
+	"github.com/metaform/connector-fabric-manager/common/metrics"
+	"github.com/metaform/connector-fabric-manager/common/system"
+)
+
+const metricsPath = "/metrics"
+
+// MetricsServiceAssembly exposes the Prometheus metrics endpoint on the router.
+type MetricsServiceAssembly struct {
+	system.DefaultServiceAssembly
+}
+
+func (m *MetricsServiceAssembly) Name() string {
+	return "Metrics"
+}
+
+func (m *MetricsServiceAssembly) Requires() []system.ServiceType {
+	return []system.ServiceType{routing.RouterKey}
+}
+
+func (m *MetricsServiceAssembly) Init(ctx *system.InitContext) error {
+	router := ctx.Registry.Resolve(routing.RouterKey).(chi.Router)
+	router.Handle(metricsPath, metrics.Handler())
+	return nil
+}
+
+// MetricsServerServiceAssembly exposes the Prometheus metrics endpoint on a dedicated HTTP server. It is used by
+// runtimes that do not serve an HTTP API, such as activity agents.
+type MetricsServerServiceAssembly struct {
+	system.DefaultServiceAssembly
+	Port    int
+	server  *http.Server
+	monitor system.LogMonitor
+}
+
+func (m *MetricsServerServiceAssembly) Name() string {
+	return "Metrics Server"
+}
+
+func (m *MetricsServerServiceAssembly) Init(ctx *system.InitContext) error {
+	m.monitor = ctx.LogMonitor
+	return nil
+}
+
+func (m *MetricsServerServiceAssembly) Start(_ *system.StartContext) error {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, metrics.Handler())
+	m.server = &http.Server{
+		Addr:    ":" + strconv.Itoa(m.Port),
+		Handler: mux,
+	}
+
+	go func() {
+		m.monitor.Infof("Metrics server listening on [%d]", m.Port)
+		if err := m.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			m.monitor.Severew("failed to start metrics server", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (m *MetricsServerServiceAssembly) Shutdown() error {
+	if m.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.server.Shutdown(ctx); err != nil {
+		m.monitor.Severew("Error attempting metrics server shutdown", "error", err)
+	}
+	return nil
+}
@@ -20,8 +20,14 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/metaform/connector-fabric-manager/common/metrics"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -89,6 +95,8 @@ func (r *RouterServiceAssembly) setupRouter(monitor system.LogMonitor, mode syst
 	if mode == system.DebugMode {
 		router.Use(createLoggerHandler(monitor))
 	}
+	router.Use(createTracingHandler())
+	router.Use(createMetricsHandler())
 	router.Use(middleware.Recoverer)
 
 	return router
@@ -115,3 +123,62 @@ func createLoggerHandler(monitor system.LogMonitor) func(next http.Handler) http
 		})
 	}
 }
+
+// createMetricsHandler records request metrics labeled with the matched route pattern.
+func createMetricsHandler() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				metrics.HTTPRequest(r.Method, routePattern(r), responseStatus(ww), time.Since(start))
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// createTracingHandler creates a server span for each request, continuing the trace of the caller if the request carries
+// trace context.
+func createTracingHandler() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				route := routePattern(r)
+				status := responseStatus(ww)
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.Int("http.response.status_code", status))
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+				span.End()
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
+
+// routePattern returns the route pattern matched by the request. Must be called after the request has been routed.
+func routePattern(r *http.Request) string {
+	if routeContext := chi.RouteContext(r.Context()); routeContext != nil && routeContext.RoutePattern() != "" {
+		return routeContext.RoutePattern()
+	}
+	return "unmatched"
+}
+
+func responseStatus(ww middleware.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,77 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package routing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/metrics"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
+	"github.com/metaform/connector-fabric-manager/common/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRouter_RecordsRequestMetrics(t *testing.T) {
+	assembly := &RouterServiceAssembly{}
+	router := assembly.setupRouter(system.NoopMonitor{}, system.ProductionMode)
+	router.Get("/routing-test/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/routing-test/123", nil))
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Requests are labeled with the route pattern, not the path
+	assert.Contains(t, recorder.Body.String(), `cfm_http_requests_total{method="GET",route="/routing-test/{id}",status="202"} 1`)
+}
+
+func TestRouter_ContinuesTrace(t *testing.T) {
+	exporter, restore := tracingtest.InstallInMemoryExporter()
+	defer restore()
+
+	assembly := &RouterServiceAssembly{}
+	router := assembly.setupRouter(system.NoopMonitor{}, system.ProductionMode)
+	var handlerSpan trace.SpanContext
+	router.Post("/routing-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	callerCtx, caller := tracing.Start(context.Background(), "caller")
+	caller.End()
+	request := httptest.NewRequest(http.MethodPost, "/routing-test/123", nil)
+	tracing.Inject(callerCtx, propagation.HeaderCarrier(request.Header))
+
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	server := spans[1]
+	assert.Equal(t, "POST /routing-test/{id}", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, caller.SpanContext().SpanID(), server.Parent.SpanID())
+	assert.Equal(t, codes.Error, server.Status.Code)
+	assert.Equal(t, server.SpanContext.SpanID(), handlerSpan.SpanID())
+}
@@ -0,0 +1,91 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	endpointKey = "tracing.endpoint"
+	insecureKey = "tracing.insecure"
+)
+
+// TracingServiceAssembly installs a tracer provider that exports spans over OTLP/HTTP to the endpoint configured with
+// the 'tracing.endpoint' key. Tracing is disabled if no endpoint is configured.
+type TracingServiceAssembly struct {
+	system.DefaultServiceAssembly
+
+	// ServiceName is reported as the service.name resource attribute of exported spans.
+	ServiceName string
+
+	provider *sdktrace.TracerProvider
+	monitor  system.LogMonitor
+}
+
+func (t *TracingServiceAssembly) Name() string {
+	return "Tracing"
+}
+
+func (t *TracingServiceAssembly) Init(ctx *system.InitContext) error {
+	t.monitor = ctx.LogMonitor
+	endpoint := ctx.GetConfigStrOrDefault(endpointKey, "")
+	if endpoint == "" {
+		ctx.LogMonitor.Debugf("Tracing disabled: no OTLP endpoint configured")
+		return nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if ctx.Config.GetBool(insecureKey) {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return fmt.Errorf("error creating OTLP trace exporter: %w", err)
+	}
+
+	serviceResource, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", t.ServiceName)))
+	if err != nil {
+		return fmt.Errorf("error creating tracing resource: %w", err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(serviceResource))
+	otel.SetTracerProvider(t.provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	ctx.LogMonitor.Infof("Exporting traces to %s", endpoint)
+	return nil
+}
+
+func (t *TracingServiceAssembly) Shutdown() error {
+	if t.provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		t.monitor.Warnf("Error flushing traces on shutdown: %v", err)
+	}
+	return nil
+}
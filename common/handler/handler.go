@@ -32,7 +32,11 @@ import (
 	"github.com/metaform/connector-fabric-manager/common/types"
 )
 
-const contentType = "application/json"
+const (
+	contentType   = "application/json"
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
 
 // ErrorResponse represents a generic JSON error response
 type ErrorResponse struct {
@@ -40,6 +44,8 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 	Code    int    `json:"code,omitempty"`
 	ID      string `json:"id,omitempty"`
+	// Fields contains field-level validation failures
+	Fields []types.FieldError `json:"fields,omitempty"`
 }
 
 type HttpHandler struct {
@@ -52,9 +58,6 @@ func (h HttpHandler) WriteError(w http.ResponseWriter, message string, statusCod
 
 // WriteErrorWithID writes a JSON error response to the response writer
 func (h HttpHandler) WriteErrorWithID(w http.ResponseWriter, message string, statusCode int, errorID string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
 	response := ErrorResponse{
 		Error:   http.StatusText(statusCode),
 		Message: message,
@@ -65,6 +68,23 @@ func (h HttpHandler) WriteErrorWithID(w http.ResponseWriter, message string, sta
 		response.ID = errorID
 	}
 
+	h.writeErrorResponse(w, response)
+}
+
+// WriteValidationError writes a 400 JSON error response containing field-level validation failures
+func (h HttpHandler) WriteValidationError(w http.ResponseWriter, err types.ValidationError) {
+	h.writeErrorResponse(w, ErrorResponse{
+		Error:   http.StatusText(http.StatusBadRequest),
+		Message: fmt.Sprintf("Bad request: %s", err.Message),
+		Code:    http.StatusBadRequest,
+		Fields:  err.Fields,
+	})
+}
+
+func (h HttpHandler) writeErrorResponse(w http.ResponseWriter, response ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Code)
+
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.Monitor.Infow("Error encoding JSON error response: %v", err)
 	}
@@ -104,14 +124,22 @@ func (h HttpHandler) HandleError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, types.ErrNotFound):
 		h.WriteError(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, types.ErrPreconditionFailed):
+		h.WriteError(w, errorMessage("Precondition failed", err, types.ErrPreconditionFailed), http.StatusPreconditionFailed)
 	case errors.Is(err, types.ErrConflict):
-		h.WriteError(w, "Conflict", http.StatusConflict)
+		h.WriteError(w, errorMessage("Conflict", err, types.ErrConflict), http.StatusConflict)
+	case errors.Is(err, types.ErrUnavailable):
+		h.WriteError(w, errorMessage("Service unavailable", err, types.ErrUnavailable), http.StatusServiceUnavailable)
+	case errors.Is(err, types.ErrForbidden):
+		h.WriteError(w, errorMessage("Forbidden", err, types.ErrForbidden), http.StatusForbidden)
 	case errors.Is(err, types.ErrInvalidInput):
 		h.WriteError(w, "Invalid input", http.StatusBadRequest)
 	case types.IsClientError(err):
 		var clientErr types.ClientError
 		errors.As(err, &clientErr)
-		if badReq, ok := clientErr.(types.BadRequestError); ok {
+		if validationErr, ok := clientErr.(types.ValidationError); ok {
+			h.WriteValidationError(w, validationErr)
+		} else if badReq, ok := clientErr.(types.BadRequestError); ok {
 			h.WriteError(w, fmt.Sprintf("Bad request: %s", badReq.Message), http.StatusBadRequest)
 		} else {
 			h.WriteError(w, fmt.Sprintf("Client error: %v", clientErr), http.StatusBadRequest)
@@ -125,6 +153,15 @@ func (h HttpHandler) HandleError(w http.ResponseWriter, err error) {
 	}
 }
 
+// errorMessage returns the message for an error wrapping a sentinel. The error description is included without the
+// sentinel description if the error is not the sentinel itself.
+func errorMessage(message string, err error, sentinel error) string {
+	if err == sentinel {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, strings.TrimSuffix(err.Error(), ": "+sentinel.Error()))
+}
+
 func (h HttpHandler) Created(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(http.StatusCreated)
@@ -145,6 +182,10 @@ func (h HttpHandler) OK(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h HttpHandler) NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h HttpHandler) ResponseOK(w http.ResponseWriter, response any) {
 	h.OK(w)
 	h.write(w, response)
@@ -170,6 +211,38 @@ func (h HttpHandler) ExtractPathVariable(w http.ResponseWriter, req *http.Reques
 	return value, true
 }
 
+// ETag returns the entity tag of an entity version.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// WriteETag sets the ETag header of the response to the entity version. It must be called before the response status
+// is written.
+func (h HttpHandler) WriteETag(w http.ResponseWriter, version int64) {
+	w.Header().Set(etagHeader, ETag(version))
+}
+
+// ReadIfMatch returns a context that requires the entity with the given ID to be at the version of the If-Match header
+// of the request. The request context is returned if the header is absent or matches any version. If the header is not
+// an entity tag returned by WriteETag, a 400 response is written and false is returned.
+func (h HttpHandler) ReadIfMatch(w http.ResponseWriter, req *http.Request, id string) (context.Context, bool) {
+	value := strings.TrimSpace(req.Header.Get(ifMatchHeader))
+	if value == "" || value == "*" {
+		return req.Context(), true
+	}
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(value, "W/"))
+	if err != nil {
+		h.WriteError(w, fmt.Sprintf("Invalid %s header: %s", ifMatchHeader, value), http.StatusBadRequest)
+		return nil, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil {
+		h.WriteError(w, fmt.Sprintf("Invalid %s header: %s", ifMatchHeader, value), http.StatusBadRequest)
+		return nil, false
+	}
+	return store.WithExpectedVersion(req.Context(), id, version), true
+}
+
 func (h HttpHandler) WriteLinkHeaders(w http.ResponseWriter, path string, offset int64, limit int64, totalCount int64) {
 	var links []string
 	selfLink := fmt.Sprintf("<%s?offset=%d&limit=%d>; rel=\"self\"", path, offset, limit)
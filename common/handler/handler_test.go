@@ -17,8 +17,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/stretchr/testify/assert"
@@ -188,6 +190,70 @@ func TestHandleError(t *testing.T) {
 		assert.Equal(t, 404, response.Code)
 	})
 
+	t.Run("handles wrapped ErrPreconditionFailed", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.NewRecoverableWrappedError(types.ErrPreconditionFailed, "version changed"))
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.statusCode)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.body.Bytes(), &response))
+		assert.Equal(t, "Precondition failed: version changed", response.Message)
+	})
+
+	t.Run("handles wrapped ErrUnavailable", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.NewRecoverableWrappedError(types.ErrUnavailable, "lease issued by another instance"))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.statusCode)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.body.Bytes(), &response))
+		assert.Equal(t, "Service unavailable: lease issued by another instance", response.Message)
+	})
+
+	t.Run("handles version conflict", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.ErrVersionConflict)
+
+		assert.Equal(t, http.StatusConflict, w.statusCode)
+	})
+
+	t.Run("handles ErrConflict", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.ErrConflict)
+
+		assert.Equal(t, http.StatusConflict, w.statusCode)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.body.Bytes(), &response))
+		assert.Equal(t, "Conflict", response.Message)
+	})
+
+	t.Run("handles wrapped ErrConflict", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.NewRecoverableWrappedError(types.ErrConflict, "limit reached"))
+
+		assert.Equal(t, http.StatusConflict, w.statusCode)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.body.Bytes(), &response))
+		assert.Equal(t, "Conflict: limit reached", response.Message)
+	})
+
+	t.Run("handles wrapped ErrForbidden", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		handler.HandleError(w, types.NewRecoverableWrappedError(types.ErrForbidden, "cell not allowed"))
+
+		assert.Equal(t, http.StatusForbidden, w.statusCode)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.body.Bytes(), &response))
+		assert.Equal(t, "Forbidden: cell not allowed", response.Message)
+		assert.Equal(t, 403, response.Code)
+	})
+
 	t.Run("handles BadRequestError", func(t *testing.T) {
 		w := newMockResponseWriter()
 		err := &types.BadRequestError{Message: "invalid input"}
@@ -204,6 +270,27 @@ func TestHandleError(t *testing.T) {
 		assert.Equal(t, 400, response.Code)
 	})
 
+	t.Run("handles ValidationError", func(t *testing.T) {
+		w := newMockResponseWriter()
+		err := types.NewValidationError([]types.FieldError{
+			{Field: "/properties/name", Message: "missing property"},
+		}, "invalid properties")
+
+		handler.HandleError(w, err)
+
+		assert.Equal(t, http.StatusBadRequest, w.statusCode)
+
+		var response ErrorResponse
+		jsonErr := json.Unmarshal(w.body.Bytes(), &response)
+		require.NoError(t, jsonErr)
+
+		assert.Equal(t, "Bad request: invalid properties", response.Message)
+		assert.Equal(t, 400, response.Code)
+		require.Len(t, response.Fields, 1)
+		assert.Equal(t, "/properties/name", response.Fields[0].Field)
+		assert.Equal(t, "missing property", response.Fields[0].Message)
+	})
+
 	t.Run("handles SystemError", func(t *testing.T) {
 		w := newMockResponseWriter()
 		err := &types.SystemError{Message: "database connection failed"}
@@ -310,6 +397,18 @@ func TestOK(t *testing.T) {
 	})
 }
 
+func TestNoContent(t *testing.T) {
+	t.Run("sets no content status code", func(t *testing.T) {
+		w := newMockResponseWriter()
+		handler := HttpHandler{Monitor: system.NoopMonitor{}}
+
+		handler.NoContent(w)
+
+		assert.Equal(t, http.StatusNoContent, w.statusCode)
+		assert.Empty(t, w.body.String())
+	})
+}
+
 func TestResponseOK(t *testing.T) {
 	t.Run("sets OK status and writes response", func(t *testing.T) {
 		w := newMockResponseWriter()
@@ -327,3 +426,71 @@ func TestResponseOK(t *testing.T) {
 		assert.Equal(t, "ok", result["status"])
 	})
 }
+
+func TestETag(t *testing.T) {
+	handler := HttpHandler{Monitor: system.NoopMonitor{}}
+	w := newMockResponseWriter()
+
+	handler.WriteETag(w, 3)
+
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+}
+
+func TestReadIfMatch(t *testing.T) {
+	handler := HttpHandler{Monitor: system.NoopMonitor{}}
+	entity := &testVersionedEntity{id: "entity-1", version: 3}
+
+	t.Run("no header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/entities/entity-1", nil)
+
+		ctx, ok := handler.ReadIfMatch(newMockResponseWriter(), req, "entity-1")
+
+		require.True(t, ok)
+		assert.NoError(t, store.CheckExpectedVersion(ctx, &testVersionedEntity{id: "entity-1", version: 1}))
+	})
+
+	t.Run("any version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/entities/entity-1", nil)
+		req.Header.Set("If-Match", "*")
+
+		ctx, ok := handler.ReadIfMatch(newMockResponseWriter(), req, "entity-1")
+
+		require.True(t, ok)
+		assert.NoError(t, store.CheckExpectedVersion(ctx, entity))
+	})
+
+	t.Run("entity tag", func(t *testing.T) {
+		for _, value := range []string{`"3"`, `W/"3"`} {
+			req := httptest.NewRequest(http.MethodPatch, "/entities/entity-1", nil)
+			req.Header.Set("If-Match", value)
+
+			ctx, ok := handler.ReadIfMatch(newMockResponseWriter(), req, "entity-1")
+
+			require.True(t, ok)
+			assert.NoError(t, store.CheckExpectedVersion(ctx, entity))
+			assert.ErrorIs(t, store.CheckExpectedVersion(ctx, &testVersionedEntity{id: "entity-1", version: 4}), types.ErrPreconditionFailed)
+		}
+	})
+
+	t.Run("invalid entity tag", func(t *testing.T) {
+		for _, value := range []string{"3", `"abc"`} {
+			req := httptest.NewRequest(http.MethodPatch, "/entities/entity-1", nil)
+			req.Header.Set("If-Match", value)
+			w := newMockResponseWriter()
+
+			_, ok := handler.ReadIfMatch(w, req, "entity-1")
+
+			require.False(t, ok)
+			assert.Equal(t, http.StatusBadRequest, w.statusCode)
+		}
+	})
+}
+
+type testVersionedEntity struct {
+	id      string
+	version int64
+}
+
+func (e *testVersionedEntity) GetID() string     { return e.id }
+func (e *testVersionedEntity) GetVersion() int64 { return e.version }
+func (e *testVersionedEntity) IncrementVersion() { e.version++ }
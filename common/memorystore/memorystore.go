@@ -93,11 +93,15 @@ func (s *InMemoryEntityStore[T]) Update(_ context.Context, entity T) error {
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	entity.IncrementVersion()
 
-	if _, exists := s.cache[entity.GetID()]; !exists {
+	existing, exists := s.cache[entity.GetID()]
+	if !exists {
 		return types.ErrNotFound
 	}
+	if existing.GetVersion() != entity.GetVersion() {
+		return types.ErrVersionConflict
+	}
+	entity.IncrementVersion()
 
 	copied, err := copyEntity(entity)
 	if err != nil {
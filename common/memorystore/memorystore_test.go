@@ -546,6 +546,33 @@ func TestInMemoryEntityStore_Update_VersionIncremented(t *testing.T) {
 	assert.Equal(t, int64(1), updated.GetVersion(), "Version should be incremented to 1 after update")
 }
 
+func TestInMemoryEntityStore_Update_VersionConflict(t *testing.T) {
+	store := NewInMemoryEntityStore[*testEntity]()
+	ctx := context.Background()
+
+	_, err := store.Create(ctx, &testEntity{ID: "test-id", Value: "initial data"})
+	require.NoError(t, err)
+
+	first, err := store.FindByID(ctx, "test-id")
+	require.NoError(t, err)
+	second, err := store.FindByID(ctx, "test-id")
+	require.NoError(t, err)
+
+	first.Value = "first"
+	require.NoError(t, store.Update(ctx, first))
+
+	second.Value = "second"
+	err = store.Update(ctx, second)
+	require.ErrorIs(t, err, types.ErrVersionConflict)
+	assert.ErrorIs(t, err, types.ErrConflict)
+	assert.Equal(t, int64(0), second.GetVersion())
+
+	stored, err := store.FindByID(ctx, "test-id")
+	require.NoError(t, err)
+	assert.Equal(t, "first", stored.Value)
+	assert.Equal(t, int64(1), stored.GetVersion())
+}
+
 func TestInMemoryEntityStore_GetAllCount(t *testing.T) {
 	store := NewInMemoryEntityStore[*testEntity]()
 	ctx := context.Background()
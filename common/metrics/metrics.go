@@ -0,0 +1,158 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Package metrics defines the Prometheus metrics recorded by CFM components.
+//
+// Metrics are registered with a registry shared by all components in a process. The registry is exposed over HTTP by the
+// metrics service assemblies.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cfm"
+
+// Registry contains the CFM metrics and the Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	orchestrationsStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orchestrations_started_total",
+		Help:      "Number of orchestrations started.",
+	}, []string{"type"})
+
+	orchestrationsCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orchestrations_completed_total",
+		Help:      "Number of orchestrations completed successfully.",
+	}, []string{"type"})
+
+	orchestrationsErrored = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orchestrations_errored_total",
+		Help:      "Number of orchestrations that failed.",
+	}, []string{"type"})
+
+	activityDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "activity_duration_seconds",
+		Help:      "Time taken by activity processors to process an activity.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900},
+	}, []string{"type", "result"})
+
+	messageRedeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nats_redeliveries_total",
+		Help:      "Number of activity messages delivered more than once.",
+	}, []string{"type"})
+
+	orchestrationUpdateConflicts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orchestration_update_conflicts_total",
+		Help:      "Number of orchestration updates retried because of a revision conflict.",
+	})
+
+	participantDeployments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "participant_deployments_total",
+		Help:      "Number of participant deployment state transitions by target state.",
+	}, []string{"state"})
+
+	cellEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cell_events_total",
+		Help:      "Number of cell availability transitions by event type.",
+	}, []string{"type"})
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests handled.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		orchestrationsStarted,
+		orchestrationsCompleted,
+		orchestrationsErrored,
+		activityDuration,
+		messageRedeliveries,
+		orchestrationUpdateConflicts,
+		participantDeployments,
+		cellEvents,
+		httpRequests,
+		httpRequestDuration)
+}
+
+// Handler returns an HTTP handler that serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+func OrchestrationStarted(orchestrationType string) {
+	orchestrationsStarted.WithLabelValues(orchestrationType).Inc()
+}
+
+func OrchestrationCompleted(orchestrationType string) {
+	orchestrationsCompleted.WithLabelValues(orchestrationType).Inc()
+}
+
+func OrchestrationErrored(orchestrationType string) {
+	orchestrationsErrored.WithLabelValues(orchestrationType).Inc()
+}
+
+// ActivityProcessed records the time taken to process an activity of the given type and the result returned by the
+// processor.
+func ActivityProcessed(activityType string, result string, duration time.Duration) {
+	activityDuration.WithLabelValues(activityType, result).Observe(duration.Seconds())
+}
+
+func MessageRedelivered(activityType string) {
+	messageRedeliveries.WithLabelValues(activityType).Inc()
+}
+
+func OrchestrationUpdateConflict() {
+	orchestrationUpdateConflicts.Inc()
+}
+
+func ParticipantDeployment(state string) {
+	participantDeployments.WithLabelValues(state).Inc()
+}
+
+func CellEvent(eventType string) {
+	cellEvents.WithLabelValues(eventType).Inc()
+}
+
+// HTTPRequest records a handled HTTP request. The route is the matched route pattern rather than the request path to
+// limit the number of series.
+func HTTPRequest(method string, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
@@ -0,0 +1,65 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrationCounters(t *testing.T) {
+	started := testutil.ToFloat64(orchestrationsStarted.WithLabelValues("test-type"))
+	completed := testutil.ToFloat64(orchestrationsCompleted.WithLabelValues("test-type"))
+	errored := testutil.ToFloat64(orchestrationsErrored.WithLabelValues("test-type"))
+
+	OrchestrationStarted("test-type")
+	OrchestrationStarted("test-type")
+	OrchestrationCompleted("test-type")
+	OrchestrationErrored("test-type")
+
+	assert.Equal(t, started+2, testutil.ToFloat64(orchestrationsStarted.WithLabelValues("test-type")))
+	assert.Equal(t, completed+1, testutil.ToFloat64(orchestrationsCompleted.WithLabelValues("test-type")))
+	assert.Equal(t, errored+1, testutil.ToFloat64(orchestrationsErrored.WithLabelValues("test-type")))
+}
+
+func TestHandler(t *testing.T) {
+	ActivityProcessed("test-activity", "complete", 20*time.Millisecond)
+	MessageRedelivered("test-activity")
+	OrchestrationUpdateConflict()
+	ParticipantDeployment("active")
+	CellEvent("cell.offline")
+	HTTPRequest(http.MethodGet, "/tenants/{id}", http.StatusOK, time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+	assert.Contains(t, output, `cfm_activity_duration_seconds_count{result="complete",type="test-activity"}`)
+	assert.Contains(t, output, `cfm_nats_redeliveries_total{type="test-activity"}`)
+	assert.Contains(t, output, "cfm_orchestration_update_conflicts_total")
+	assert.Contains(t, output, `cfm_participant_deployments_total{state="active"}`)
+	assert.Contains(t, output, `cfm_cell_events_total{type="cell.offline"}`)
+	assert.Contains(t, output, `cfm_http_requests_total{method="GET",route="/tenants/{id}",status="200"}`)
+	assert.Contains(t, output, "go_goroutines")
+}
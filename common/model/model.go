@@ -13,7 +13,11 @@
 package model
 
 import (
+	"encoding/json"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -26,10 +30,21 @@ const (
 
 	VPADeployType  OrchestrationType = "cfm.orchestration.vpa.deploy"
 	VPADisposeType OrchestrationType = "cfm.orchestration.vpa.dispose"
+	VPAUpdateType  OrchestrationType = "cfm.orchestration.vpa.update"
+	VPAMigrateType OrchestrationType = "cfm.orchestration.vpa.migrate"
+
+	DataspaceDeployType  OrchestrationType = "cfm.orchestration.dataspace.deploy"
+	DataspaceDisposeType OrchestrationType = "cfm.orchestration.dataspace.dispose"
 
 	VPAData        = "cfm.vpa.data"
 	CredentialData = "cfm.vpa.credentials"
 	VPAStateData   = "cfm.vpa.state"
+	VPAUpdateData  = "cfm.vpa.update"
+	VPAResultsData = "cfm.vpa.results"
+	VPAMigrateData = "cfm.vpa.migration"
+
+	DataspaceData      = "cfm.dataspace.data"
+	DataspaceStateData = "cfm.dataspace.state"
 )
 
 var Validator = initValidator()
@@ -47,13 +62,32 @@ type OrchestrationManifest struct {
 
 // OrchestrationResponse returned when a system deployment completes.
 type OrchestrationResponse struct {
-	ID                string            `json:"id" validate:"required"`
-	ManifestID        string            `json:"manifestId" validate:"required"`
-	CorrelationID     string            `json:"correlationId" validate:"required"`
-	OrchestrationType OrchestrationType `json:"orchestrationType" validate:"required"`
-	Success           bool              `json:"success"`
-	ErrorDetail       string            `json:"errorDetail,omitempty"`
-	Properties        map[string]any    `json:"properties"`
+	ID                string               `json:"id" validate:"required"`
+	ManifestID        string               `json:"manifestId" validate:"required"`
+	CorrelationID     string               `json:"correlationId" validate:"required"`
+	OrchestrationType OrchestrationType    `json:"orchestrationType" validate:"required"`
+	Success           bool                 `json:"success"`
+	ErrorDetail       string               `json:"errorDetail,omitempty"`
+	Properties        map[string]any       `json:"properties"`
+	VPAResults        map[string]VPAResult `json:"vpaResults,omitempty"`
+}
+
+// OrchestrationProgress is sent when an activity reports progress while an orchestration is executing. VPAID is set if
+// the activity reported the progress of a single VPA. VPAPercents contains the completion percentage of each VPA of the
+// orchestration, since the activities of an orchestration may operate on different VPAs.
+type OrchestrationProgress struct {
+	ID                   string            `json:"id" validate:"required"`
+	ManifestID           string            `json:"manifestId" validate:"required"`
+	CorrelationID        string            `json:"correlationId" validate:"required"`
+	OrchestrationType    OrchestrationType `json:"orchestrationType" validate:"required"`
+	ActivityID           string            `json:"activityId" validate:"required"`
+	ActivityType         string            `json:"activityType"`
+	Percent              int               `json:"percent" validate:"min=0,max=100"`
+	OrchestrationPercent int               `json:"orchestrationPercent" validate:"min=0,max=100"`
+	VPAID                string            `json:"vpaId,omitempty"`
+	VPAPercents          map[string]int    `json:"vpaPercents,omitempty"`
+	Message              string            `json:"message,omitempty"`
+	Timestamp            time.Time         `json:"timestamp"`
 }
 
 // VPAManifest represents the configuration details for a VPA deployment.
@@ -65,6 +99,124 @@ type VPAManifest struct {
 	Properties     map[string]any `json:"properties,omitempty"`
 }
 
+// VPAMigration describes the move of a VPA from its source cell to the target cell of its VPAManifest.
+type VPAMigration struct {
+	ID                   string  `json:"id" validate:"required"`
+	VPAType              VPAType `json:"vpaType" validate:"required"`
+	SourceCellID         string  `json:"sourceCellId" validate:"required"`
+	SourceExternalCellID string  `json:"sourceExternalCellId"`
+}
+
+// DataspaceManifest represents the configuration details for deploying a dataspace profile to a cell.
+type DataspaceManifest struct {
+	ID                 string         `json:"id" validate:"required"`
+	DataspaceProfileID string         `json:"dataspaceProfileId" validate:"required"`
+	CellID             string         `json:"cellId" validate:"required"`
+	ExternalCellID     string         `json:"externalCellId"`
+	Artifacts          []string       `json:"artifacts,omitempty"`
+	Properties         map[string]any `json:"properties,omitempty"`
+}
+
+// VPAPropertiesDiff is the change to the properties of a deployed VPA sent with a VPA update orchestration.
+type VPAPropertiesDiff struct {
+	ID      string         `json:"id" validate:"required"`
+	VPAType VPAType        `json:"vpaType" validate:"required"`
+	Changed map[string]any `json:"changed,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+}
+
+// VPAResultState is the outcome of an orchestration for a single VPA.
+type VPAResultState string
+
+const (
+	VPAResultSucceeded VPAResultState = "succeeded"
+	VPAResultFailed    VPAResultState = "failed"
+)
+
+// VPAResult is the outcome of an orchestration for a single VPA, including the output values produced for it.
+type VPAResult struct {
+	State     VPAResultState `json:"state" validate:"required,oneof=succeeded failed"`
+	Timestamp time.Time      `json:"timestamp"`
+	Outputs   map[string]any `json:"outputs,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// VPAResultKey returns the output key under which an activity reports its result for a VPA. Results are keyed by
+// activity so that activities operating on the same VPA do not overwrite each other.
+func VPAResultKey(vpaID string, activityID string) string {
+	return VPAResultsData + "/" + vpaID + "/" + activityID
+}
+
+// ParseVPAResultKey returns the VPA and activity IDs of an output key created with VPAResultKey.
+func ParseVPAResultKey(key string) (vpaID string, activityID string, ok bool) {
+	ids, found := strings.CutPrefix(key, VPAResultsData+"/")
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(ids, "/")
+}
+
+// ExtractVPAResults separates the VPA results reported by activities from the other output values. Results reported
+// for the same VPA by different activities are combined: the VPA has failed if any activity reported a failure, the
+// latest timestamp is used, and outputs are merged. Values that cannot be read as results are left in the outputs.
+func ExtractVPAResults(outputs map[string]any) (map[string]VPAResult, map[string]any) {
+	results := make(map[string]VPAResult)
+	remaining := make(map[string]any, len(outputs))
+
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Combine results in a deterministic order
+
+	for _, key := range keys {
+		value := outputs[key]
+		vpaID, _, found := ParseVPAResultKey(key)
+		var result VPAResult
+		if !found || decodeVPAResult(value, &result) != nil {
+			remaining[key] = value
+			continue
+		}
+
+		combined, exists := results[vpaID]
+		if !exists {
+			combined = VPAResult{State: VPAResultSucceeded}
+		}
+		if result.State == VPAResultFailed {
+			combined.State = VPAResultFailed
+		}
+		if result.Timestamp.After(combined.Timestamp) {
+			combined.Timestamp = result.Timestamp
+		}
+		for k, v := range result.Outputs {
+			if combined.Outputs == nil {
+				combined.Outputs = make(map[string]any)
+			}
+			combined.Outputs[k] = v
+		}
+		if result.Error != "" {
+			if combined.Error != "" {
+				combined.Error += "; "
+			}
+			combined.Error += result.Error
+		}
+		results[vpaID] = combined
+	}
+	return results, remaining
+}
+
+// decodeVPAResult reads a result that may have been deserialized as a generic map.
+func decodeVPAResult(value any, result *VPAResult) error {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(serialized, result); err != nil {
+		return err
+	}
+	return Validator.Struct(result)
+}
+
 type CredentialSpec struct {
 	Id              string `json:"id" validate:"required"`
 	Type            string `json:"type" validate:"required"`
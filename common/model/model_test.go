@@ -15,6 +15,7 @@ package model
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -175,3 +176,38 @@ func TestModelTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractVPAResults(t *testing.T) {
+	earlier := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	// Results set by an activity in the same process and results read from the serialized orchestration
+	deserialized := map[string]any{"state": "failed", "timestamp": later.Format(time.RFC3339), "error": "dataplane unreachable"}
+	outputs := map[string]any{
+		"agent.output":                       "value",
+		VPAResultKey("vpa-1", "keycloak"):    VPAResult{State: VPAResultSucceeded, Timestamp: earlier, Outputs: map[string]any{"clientId": "client"}},
+		VPAResultKey("vpa-1", "edcv"):        VPAResult{State: VPAResultSucceeded, Timestamp: later, Outputs: map[string]any{"url": "https://example.com"}},
+		VPAResultKey("vpa-2", "dataplane"):   deserialized,
+		VPAResultKey("vpa-3", "invalid"):     map[string]any{"state": "unknown"},
+		VPAResultsData + "/missing-activity": "value",
+	}
+
+	results, remaining := ExtractVPAResults(outputs)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, VPAResult{
+		State:     VPAResultSucceeded,
+		Timestamp: later,
+		Outputs:   map[string]any{"clientId": "client", "url": "https://example.com"},
+	}, results["vpa-1"])
+	assert.Equal(t, VPAResultFailed, results["vpa-2"].State)
+	assert.Equal(t, "dataplane unreachable", results["vpa-2"].Error)
+	assert.True(t, results["vpa-2"].Timestamp.Equal(later))
+
+	// Values that are not valid results are returned unchanged
+	assert.Equal(t, map[string]any{
+		"agent.output":                       "value",
+		VPAResultKey("vpa-3", "invalid"):     map[string]any{"state": "unknown"},
+		VPAResultsData + "/missing-activity": "value",
+	}, remaining)
+}
@@ -0,0 +1,130 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// CFMLeaderBucket is the KV bucket instances compete for leadership in
+const CFMLeaderBucket = "cfm-leaders"
+
+// SetupLeaderBucket opens the KV bucket leaders are elected in. If the bucket does not exist, it is created with the
+// given TTL, after which leadership that is not renewed expires.
+func SetupLeaderBucket(ctx context.Context, js jetstream.JetStream, ttl time.Duration) (jetstream.KeyValue, error) {
+	kv, err := js.KeyValue(ctx, CFMLeaderBucket)
+	if err == nil {
+		return kv, nil
+	}
+	if !errors.Is(err, jetstream.ErrBucketNotFound) {
+		return nil, fmt.Errorf("unable to access leader bucket: %w", err)
+	}
+
+	kv, err = js.CreateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: CFMLeaderBucket, TTL: ttl})
+	if errors.Is(err, jetstream.ErrBucketExists) {
+		// Created concurrently by another instance
+		return js.KeyValue(ctx, CFMLeaderBucket)
+	}
+	return kv, err
+}
+
+// LeaderElection elects a single leader among the instances competing for the same key. The leader holds the key and
+// renews it before the bucket TTL expires; other instances attempt to acquire the key when it expires or is released.
+//
+// The renewal interval must be shorter than the bucket TTL. Since leadership is only checked at the renewal interval,
+// a previous leader may briefly continue to act as leader after losing the key, so work performed by the leader must
+// be idempotent.
+type LeaderElection struct {
+	KV       jetstream.KeyValue
+	Key      string
+	ID       string
+	Interval time.Duration
+	Monitor  system.LogMonitor
+
+	mu       sync.Mutex
+	leader   bool
+	revision uint64
+}
+
+// IsLeader returns true if this instance holds the leadership.
+func (l *LeaderElection) IsLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader
+}
+
+// Start campaigns for leadership at the renewal interval until the context is canceled.
+func (l *LeaderElection) Start(ctx context.Context) {
+	l.Campaign(ctx)
+	go func() {
+		ticker := time.NewTicker(l.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.Campaign(ctx)
+			}
+		}
+	}()
+}
+
+// Campaign acquires the leadership if it is not held by another instance, or renews it if it is held by this instance.
+func (l *LeaderElection) Campaign(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.leader {
+		revision, err := l.KV.Update(ctx, l.Key, []byte(l.ID), l.revision)
+		if err == nil {
+			l.revision = revision
+			return
+		}
+		l.leader = false
+		l.Monitor.Warnf("Lost leadership of %s: %v", l.Key, err)
+		return
+	}
+
+	revision, err := l.KV.Create(ctx, l.Key, []byte(l.ID))
+	if err != nil {
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			l.Monitor.Warnf("Error acquiring leadership of %s: %v", l.Key, err)
+		}
+		return
+	}
+	l.leader = true
+	l.revision = revision
+	l.Monitor.Infof("Acquired leadership of %s", l.Key)
+}
+
+// Resign releases the leadership so that another instance can acquire it without waiting for the TTL to expire.
+func (l *LeaderElection) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.leader {
+		return nil
+	}
+	l.leader = false
+	if err := l.KV.Delete(ctx, l.Key, jetstream.LastRevision(l.revision)); err != nil {
+		return fmt.Errorf("failed to resign leadership of %s: %w", l.Key, err)
+	}
+	return nil
+}
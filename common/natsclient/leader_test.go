@@ -0,0 +1,114 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection_SingleLeader(t *testing.T) {
+	kv := &leaderKV{}
+	ctx := context.Background()
+	first := newTestElection(kv, "first")
+	second := newTestElection(kv, "second")
+
+	first.Campaign(ctx)
+	second.Campaign(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// The leader renews its leadership
+	first.Campaign(ctx)
+	assert.True(t, first.IsLeader())
+	assert.Equal(t, uint64(2), kv.revision)
+
+	// Another instance acquires the leadership once it is released
+	require.NoError(t, first.Resign(ctx))
+	assert.False(t, first.IsLeader())
+	second.Campaign(ctx)
+	assert.True(t, second.IsLeader())
+}
+
+func TestLeaderElection_LosesExpiredLeadership(t *testing.T) {
+	kv := &leaderKV{}
+	ctx := context.Background()
+	first := newTestElection(kv, "first")
+	second := newTestElection(kv, "second")
+
+	first.Campaign(ctx)
+	require.True(t, first.IsLeader())
+
+	// The key expires and is acquired by another instance before the leader renews it
+	kv.expire()
+	second.Campaign(ctx)
+	require.True(t, second.IsLeader())
+
+	first.Campaign(ctx)
+	assert.False(t, first.IsLeader())
+	assert.True(t, second.IsLeader())
+}
+
+func newTestElection(kv jetstream.KeyValue, id string) *LeaderElection {
+	return &LeaderElection{KV: kv, Key: "leader", ID: id, Monitor: system.NoopMonitor{}}
+}
+
+// leaderKV is a single-key KeyValue implementing the operations used by LeaderElection.
+type leaderKV struct {
+	jetstream.KeyValue
+	mu       sync.Mutex
+	value    string
+	revision uint64
+}
+
+func (kv *leaderKV) Create(_ context.Context, _ string, value []byte, _ ...jetstream.KVCreateOpt) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.value != "" {
+		return 0, jetstream.ErrKeyExists
+	}
+	kv.value = string(value)
+	kv.revision++
+	return kv.revision, nil
+}
+
+func (kv *leaderKV) Update(_ context.Context, _ string, value []byte, revision uint64) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.value == "" || revision != kv.revision {
+		return 0, jetstream.ErrKeyExists
+	}
+	kv.value = string(value)
+	kv.revision++
+	return kv.revision, nil
+}
+
+func (kv *leaderKV) Delete(_ context.Context, _ string, _ ...jetstream.KVDeleteOpt) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.value = ""
+	kv.revision++
+	return nil
+}
+
+func (kv *leaderKV) expire() {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.value = ""
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/nats-io/nats.go/jetstream"
 )
@@ -58,7 +59,13 @@ func (n *RetriableMessageProcessor[T]) ProcessLoop(ctx context.Context, consumer
 	}
 }
 
-func (n *RetriableMessageProcessor[T]) ProcessMessage(ctx context.Context, message jetstream.Msg) error {
+func (n *RetriableMessageProcessor[T]) ProcessMessage(ctx context.Context, message jetstream.Msg) (err error) {
+	ctx, span := StartConsumerSpan(ctx, message, fmt.Sprintf("process %T", *new(T)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	var payload T
 	if err := json.Unmarshal(message.Data(), &payload); err != nil {
 		err2 := AckMessage(message)
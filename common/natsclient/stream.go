@@ -17,6 +17,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 )
@@ -26,6 +27,11 @@ const CFMOrchestration = "cfm-orchestration"
 const CFMOrchestrationSubject = CFMSubjectPrefix + "." + CFMOrchestration
 const CFMOrchestrationResponse = "cfm-orchestration-response"
 const CFMOrchestrationResponseSubject = CFMSubjectPrefix + "." + CFMOrchestrationResponse
+const CFMOrchestrationProgress = "cfm-orchestration-progress"
+const CFMOrchestrationProgressSubject = CFMSubjectPrefix + "." + CFMOrchestrationProgress
+
+// CFMAgentBucket is the KV bucket agents register their activity types in
+const CFMAgentBucket = "cfm-agents"
 
 // SetupStream configures a JetStream stream used for component messaging. If the stream does not exist, it is created.
 func SetupStream(ctx context.Context, client *NatsClient, streamName string) (jetstream.Stream, error) {
@@ -47,6 +53,25 @@ func SetupStream(ctx context.Context, client *NatsClient, streamName string) (je
 	return nil, fmt.Errorf("unable to access NATS stream: %w", err)
 }
 
+// SetupAgentBucket opens the KV bucket agents register in. If the bucket does not exist, it is created with the given
+// TTL, after which registrations that are not refreshed expire.
+func SetupAgentBucket(ctx context.Context, js jetstream.JetStream, ttl time.Duration) (jetstream.KeyValue, error) {
+	kv, err := js.KeyValue(ctx, CFMAgentBucket)
+	if err == nil {
+		return kv, nil
+	}
+	if !errors.Is(err, jetstream.ErrBucketNotFound) {
+		return nil, fmt.Errorf("unable to access agent bucket: %w", err)
+	}
+
+	kv, err = js.CreateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: CFMAgentBucket, TTL: ttl})
+	if errors.Is(err, jetstream.ErrBucketExists) {
+		// Created concurrently by another agent
+		return js.KeyValue(ctx, CFMAgentBucket)
+	}
+	return kv, err
+}
+
 // SetupConsumer creates or updates a NATS JetStream consumer for an activity processor.
 func SetupConsumer(ctx context.Context, stream jetstream.Stream, subject string) (jetstream.Consumer, error) {
 	sanitizedSubject := strings.ReplaceAll(subject, ".", "-") // convert to `-` because NATs uses dot-notation to denote subject hierarchies
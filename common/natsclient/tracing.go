@@ -0,0 +1,87 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsclient
+
+import (
+	"context"
+
+	"github.com/metaform/connector-fabric-manager/common/tracing"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier adapts NATS message headers to the OpenTelemetry propagation carrier.
+type headerCarrier nats.Header
+
+func (c headerCarrier) Get(key string) string {
+	return nats.Header(c).Get(key)
+}
+
+func (c headerCarrier) Set(key string, value string) {
+	nats.Header(c).Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// NewTracedMsg creates a message that carries the trace context of the given context in its headers.
+func NewTracedMsg(ctx context.Context, subject string, data []byte) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	tracing.Inject(ctx, headerCarrier(msg.Header))
+	return msg
+}
+
+// PublishTraced publishes the payload in a producer span and carries the trace context in the message headers so that
+// consumers can continue the trace.
+func PublishTraced(ctx context.Context, client MsgClient, subject string, payload []byte) (*jetstream.PubAck, error) {
+	ctx, span := tracing.Start(ctx, "publish "+subject,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", subject)))
+	defer span.End()
+
+	ack, err := client.PublishMsg(ctx, NewTracedMsg(ctx, subject, payload))
+	tracing.RecordError(span, err)
+	return ack, err
+}
+
+// ExtractTraceContext returns a context containing the trace context carried in the message headers. The context is
+// returned unchanged if the message does not carry trace context.
+func ExtractTraceContext(ctx context.Context, message jetstream.Msg) context.Context {
+	headers := message.Headers()
+	if len(headers) == 0 {
+		return ctx
+	}
+	return tracing.Extract(ctx, headerCarrier(headers))
+}
+
+// StartConsumerSpan continues the trace carried in the message headers with a consumer span for processing the message.
+func StartConsumerSpan(
+	ctx context.Context,
+	message jetstream.Msg,
+	name string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("messaging.system", "nats")))
+	return tracing.Start(ExtractTraceContext(ctx, message), name, opts...)
+}
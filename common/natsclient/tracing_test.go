@@ -0,0 +1,83 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/tracing"
+	"github.com/metaform/connector-fabric-manager/common/tracing/tracingtest"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPublishTraced(t *testing.T) {
+	exporter, restore := tracingtest.InstallInMemoryExporter()
+	defer restore()
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	client := &publishRecorder{}
+	_, err := PublishTraced(ctx, client, CFMOrchestrationSubject, []byte("payload"))
+	require.NoError(t, err)
+	parent.End()
+
+	require.NotNil(t, client.published)
+	assert.Equal(t, CFMOrchestrationSubject, client.published.Subject)
+	assert.Equal(t, []byte("payload"), client.published.Data)
+	assert.NotEmpty(t, client.published.Header.Get("traceparent"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	producer := spans[0]
+	assert.Equal(t, "publish "+CFMOrchestrationSubject, producer.Name)
+	assert.Equal(t, trace.SpanKindProducer, producer.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), producer.Parent.SpanID())
+
+	// The consumer span continues the trace from the producer span
+	_, consumer := StartConsumerSpan(context.Background(), &headerMsg{headers: client.published.Header}, "process")
+	consumer.End()
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[2].SpanContext.TraceID())
+	assert.Equal(t, producer.SpanContext.SpanID(), spans[2].Parent.SpanID())
+	assert.Equal(t, trace.SpanKindConsumer, spans[2].SpanKind)
+}
+
+func TestExtractTraceContext_NoHeaders(t *testing.T) {
+	ctx := ExtractTraceContext(context.Background(), &headerMsg{})
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+type publishRecorder struct {
+	MsgClient
+	published *nats.Msg
+}
+
+func (p *publishRecorder) PublishMsg(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	p.published = msg
+	return &jetstream.PubAck{}, nil
+}
+
+type headerMsg struct {
+	jetstream.Msg
+	headers nats.Header
+}
+
+func (m *headerMsg) Headers() nats.Header {
+	return m.headers
+}
@@ -18,13 +18,18 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
 
+	"github.com/lib/pq"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/types"
 )
 
+// uniqueViolationCode is the PostgreSQL error code raised when an insert violates a unique constraint
+const uniqueViolationCode = "23505"
+
 // DatabaseRecord represents a complete database row with all column values
 type DatabaseRecord struct {
 	// Values contains all column values (key: column name, value: column value)
@@ -141,6 +146,10 @@ func (p *PostgresEntityStore[T]) Create(ctx context.Context, entity T) (T, error
 	).Scan(scanValues...)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+			return entity, types.ErrConflict
+		}
 		return entity, fmt.Errorf("failed to create entity: %w", err)
 	}
 
@@ -159,9 +168,12 @@ func (p *PostgresEntityStore[T]) Update(ctx context.Context, entity T) error {
 	values := make([]any, 0)
 	paramIndex := 1
 
+	// Entities with a version column are updated only if they were read at the stored version
+	versioned := slices.Contains(p.columnNames, "version")
+
 	// Build SET clauses for all columns (skip id)
 	for _, colName := range p.columnNames {
-		if colName == "id" {
+		if colName == "id" || (versioned && colName == "version") {
 			continue
 		}
 		if val, exists := record.Values[colName]; exists {
@@ -171,17 +183,25 @@ func (p *PostgresEntityStore[T]) Update(ctx context.Context, entity T) error {
 		}
 	}
 
+	if versioned {
+		setClauses = append(setClauses, "version = version + 1")
+	}
+
 	if len(setClauses) == 0 {
 		return fmt.Errorf("no columns to update")
 	}
 
-	// Add id to WHERE clause
+	// Add id and version to WHERE clause
 	values = append(values, entity.GetID())
-	idPlaceholder := fmt.Sprintf("$%d", paramIndex)
+	whereClause := fmt.Sprintf("id = $%d", paramIndex)
+	if versioned {
+		values = append(values, entity.GetVersion())
+		whereClause += fmt.Sprintf(" AND version = $%d", paramIndex+1)
+	}
 
 	result, err := getTxFromContext(ctx).ExecContext(ctx,
-		fmt.Sprintf("UPDATE %s SET %s WHERE id = %s",
-			p.tableName, strings.Join(setClauses, ", "), idPlaceholder),
+		fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			p.tableName, strings.Join(setClauses, ", "), whereClause),
 		values...,
 	)
 
@@ -195,9 +215,21 @@ func (p *PostgresEntityStore[T]) Update(ctx context.Context, entity T) error {
 	}
 
 	if rowsAffected == 0 {
+		if versioned {
+			exists, err := p.Exists(ctx, entity.GetID())
+			if err != nil {
+				return err
+			}
+			if exists {
+				return types.ErrVersionConflict
+			}
+		}
 		return types.ErrNotFound
 	}
 
+	if versioned {
+		entity.IncrementVersion()
+	}
 	return nil
 }
 
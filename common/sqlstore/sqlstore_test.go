@@ -276,6 +276,27 @@ func TestNewPostgresEntityStore_Create(t *testing.T) {
 	assert.Equal(t, "New Entity", created.Value)
 }
 
+func TestNewPostgresEntityStore_CreateDuplicate(t *testing.T) {
+	setupEntityTable(t)
+	defer CleanupTestData(t, testDB)
+
+	columnNames := []string{"id", "value", "version", "created_at", "metadata"}
+	estore := NewPostgresEntityStore("test_entities", columnNames, recordToEntity, entityToRecord, *createBuilder())
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, SQLTransactionKey, tx)
+
+	_, err = estore.Create(txCtx, &testEntity{ID: "duplicate-entity", Value: "First", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	_, err = estore.Create(txCtx, &testEntity{ID: "duplicate-entity", Value: "Second", CreatedAt: time.Now()})
+	require.ErrorIs(t, err, types.ErrConflict)
+}
+
 // TestNewPostgresEntityStore_Update tests updating an entity
 func TestNewPostgresEntityStore_Update(t *testing.T) {
 	setupEntityTable(t)
@@ -311,7 +332,6 @@ func TestNewPostgresEntityStore_Update(t *testing.T) {
 	txCtx := context.WithValue(ctx, SQLTransactionKey, tx)
 
 	entity.Value = "Updated"
-	entity.IncrementVersion()
 	entity.Metadata = map[string]any{"updated": true}
 	err = estore.Update(txCtx, entity)
 	require.NoError(t, err)
@@ -323,6 +343,48 @@ func TestNewPostgresEntityStore_Update(t *testing.T) {
 
 }
 
+// TestNewPostgresEntityStore_UpdateVersionConflict tests that an entity read at an outdated version is not updated
+func TestNewPostgresEntityStore_UpdateVersionConflict(t *testing.T) {
+	setupEntityTable(t)
+	defer CleanupTestData(t, testDB)
+
+	columnNames := []string{"id", "value", "version", "created_at", "metadata"}
+	estore := NewPostgresEntityStore("test_entities", columnNames, recordToEntity, entityToRecord, *createBuilder())
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, SQLTransactionKey, tx)
+
+	_, err = estore.Create(txCtx, &testEntity{ID: "conflict-entity", Value: "Original", Version: 1, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	first, err := estore.FindByID(txCtx, "conflict-entity")
+	require.NoError(t, err)
+	second, err := estore.FindByID(txCtx, "conflict-entity")
+	require.NoError(t, err)
+
+	first.Value = "First"
+	require.NoError(t, estore.Update(txCtx, first))
+	assert.Equal(t, int64(2), first.Version)
+
+	second.Value = "Second"
+	err = estore.Update(txCtx, second)
+	require.ErrorIs(t, err, types.ErrVersionConflict)
+	assert.ErrorIs(t, err, types.ErrConflict)
+	assert.Equal(t, int64(1), second.Version)
+
+	stored, err := estore.FindByID(txCtx, "conflict-entity")
+	require.NoError(t, err)
+	assert.Equal(t, "First", stored.Value)
+	assert.Equal(t, int64(2), stored.Version)
+
+	err = estore.Update(txCtx, &testEntity{ID: "missing-entity", Version: 1, CreatedAt: time.Now()})
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
 // TestNewPostgresEntityStore_Delete tests deleting an entity
 func TestNewPostgresEntityStore_Delete(t *testing.T) {
 	setupEntityTable(t)
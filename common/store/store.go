@@ -14,10 +14,12 @@ package store
 
 import (
 	"context"
+	"errors"
 	"iter"
 
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
 )
 
 const (
@@ -59,6 +61,58 @@ func (tf TrxFunc[T]) AndReturn(ctx context.Context, callback func(context.Contex
 	return result, callbackErr
 }
 
+type expectedVersionKey struct{}
+
+// expectedVersion is the version a request expects an entity to have.
+type expectedVersion struct {
+	id      string
+	version int64
+}
+
+// WithExpectedVersion returns a context that requires the entity with the given ID to be at the given version, for
+// example, when a request has an If-Match precondition.
+func WithExpectedVersion(ctx context.Context, id string, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, expectedVersion{id: id, version: version})
+}
+
+// CheckExpectedVersion returns types.ErrPreconditionFailed if the context requires a different version of the entity.
+// Operations call it after reading the entity they modify, in the same transaction that updates it.
+func CheckExpectedVersion(ctx context.Context, entity EntityType) error {
+	expected, ok := ctx.Value(expectedVersionKey{}).(expectedVersion)
+	if !ok || expected.id != entity.GetID() || expected.version == entity.GetVersion() {
+		return nil
+	}
+	return types.NewRecoverableWrappedError(types.ErrPreconditionFailed,
+		"entity %s is at version %d, expected version %d", entity.GetID(), entity.GetVersion(), expected.version)
+}
+
+// DefaultConflictAttempts is the number of times a ConflictRetryTransactionContext created with WithConflictRetry
+// executes a callback that fails with a version conflict.
+const DefaultConflictAttempts = 3
+
+// ConflictRetryTransactionContext executes callbacks in transactions of the wrapped context and re-executes a callback
+// in a new transaction if it fails with types.ErrVersionConflict. Callbacks must read the entities they update, so that
+// a retry applies its changes to the current version.
+type ConflictRetryTransactionContext struct {
+	TransactionContext
+	Attempts int
+}
+
+// WithConflictRetry returns a transaction context that retries callbacks failing with a version conflict.
+func WithConflictRetry(trxContext TransactionContext) TransactionContext {
+	return ConflictRetryTransactionContext{TransactionContext: trxContext, Attempts: DefaultConflictAttempts}
+}
+
+func (c ConflictRetryTransactionContext) Execute(ctx context.Context, callback func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < max(c.Attempts, 1); attempt++ {
+		if err = c.TransactionContext.Execute(ctx, callback); !errors.Is(err, types.ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
+
 type NoOpTransactionContext struct{}
 
 func (n NoOpTransactionContext) Execute(ctx context.Context, callback func(ctx context.Context) error) error {
@@ -15,8 +15,10 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -152,3 +154,75 @@ func TestAndReturn_CallbackReturnsNewInstance(t *testing.T) {
 	assert.Equal(t, "dynamic-id", result.ID)
 	assert.Equal(t, "dynamic-name", result.Name)
 }
+
+func TestConflictRetryTransactionContext(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries version conflicts", func(t *testing.T) {
+		calls := 0
+		err := WithConflictRetry(NoOpTransactionContext{}).Execute(ctx, func(ctx context.Context) error {
+			calls++
+			if calls < 2 {
+				return fmt.Errorf("error updating entity: %w", types.ErrVersionConflict)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("returns version conflict when attempts are exhausted", func(t *testing.T) {
+		calls := 0
+		err := WithConflictRetry(NoOpTransactionContext{}).Execute(ctx, func(ctx context.Context) error {
+			calls++
+			return types.ErrVersionConflict
+		})
+
+		require.ErrorIs(t, err, types.ErrVersionConflict)
+		assert.Equal(t, DefaultConflictAttempts, calls)
+	})
+
+	t.Run("does not retry other conflicts", func(t *testing.T) {
+		calls := 0
+		err := WithConflictRetry(NoOpTransactionContext{}).Execute(ctx, func(ctx context.Context) error {
+			calls++
+			return types.ErrConflict
+		})
+
+		require.ErrorIs(t, err, types.ErrConflict)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestCheckExpectedVersion(t *testing.T) {
+	entity := &versionedEntity{id: "entity-1", version: 2}
+
+	t.Run("no expected version", func(t *testing.T) {
+		require.NoError(t, CheckExpectedVersion(context.Background(), entity))
+	})
+
+	t.Run("matching version", func(t *testing.T) {
+		ctx := WithExpectedVersion(context.Background(), "entity-1", 2)
+		require.NoError(t, CheckExpectedVersion(ctx, entity))
+	})
+
+	t.Run("different version", func(t *testing.T) {
+		ctx := WithExpectedVersion(context.Background(), "entity-1", 1)
+		require.ErrorIs(t, CheckExpectedVersion(ctx, entity), types.ErrPreconditionFailed)
+	})
+
+	t.Run("version of another entity", func(t *testing.T) {
+		ctx := WithExpectedVersion(context.Background(), "entity-2", 1)
+		require.NoError(t, CheckExpectedVersion(ctx, entity))
+	})
+}
+
+type versionedEntity struct {
+	id      string
+	version int64
+}
+
+func (e *versionedEntity) GetID() string     { return e.id }
+func (e *versionedEntity) GetVersion() int64 { return e.version }
+func (e *versionedEntity) IncrementVersion() { e.version++ }
@@ -0,0 +1,61 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Package tracing provides OpenTelemetry tracing support for CFM components.
+//
+// Spans are created with the global tracer provider, which is installed by the tracing service assembly. When no
+// provider is installed, spans are not recorded but trace context received from other components is still propagated.
+// Trace context is propagated using the W3C Trace Context format.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/metaform/connector-fabric-manager"
+
+var propagator = propagation.TraceContext{}
+
+// Tracer returns the CFM tracer from the global tracer provider.
+func Tracer() oteltrace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Start creates a span and a context containing it.
+func Start(ctx context.Context, name string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// Inject writes the trace context of the span in the context to the carrier.
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
+
+// Extract returns a context containing the remote span context read from the carrier. The context is returned unchanged
+// if the carrier contains no trace context.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
+
+// RecordError records the error on the span and sets the span status to error.
+func RecordError(span oteltrace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
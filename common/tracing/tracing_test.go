@@ -0,0 +1,69 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	exporter, restore := tracingtest.InstallInMemoryExporter()
+	defer restore()
+
+	ctx, span := Start(context.Background(), "parent")
+	carrier := propagation.MapCarrier{}
+	Inject(ctx, carrier)
+	span.End()
+
+	require.NotEmpty(t, carrier.Get("traceparent"))
+
+	// A span started from the extracted context continues the trace
+	_, child := Start(Extract(context.Background(), carrier), "child")
+	child.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+	assert.True(t, spans[1].Parent.IsRemote())
+}
+
+func TestExtract_NoTraceContext(t *testing.T) {
+	ctx := Extract(context.Background(), propagation.MapCarrier{})
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestRecordError(t *testing.T) {
+	exporter, restore := tracingtest.InstallInMemoryExporter()
+	defer restore()
+
+	_, span := Start(context.Background(), "failing")
+	RecordError(span, nil)
+	RecordError(span, errors.New("failed"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "failed", spans[0].Status.Description)
+	require.Len(t, spans[0].Events, 1)
+}
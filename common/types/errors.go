@@ -15,6 +15,7 @@ package types
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -24,6 +25,17 @@ var (
 	ErrNotFound = NewRecoverableError("not found")
 	// ErrInvalidInput Sentinel error to indicate a wrong input, e.g., a string when a number was expected, or an empty string
 	ErrInvalidInput = NewRecoverableError("invalid input")
+	// ErrForbidden indicates that an operation is not permitted, e.g. when it uses a resource a tenant is not allowed to use
+	ErrForbidden = NewRecoverableError("forbidden")
+	// ErrVersionConflict indicates that an object was modified after it was read, e.g. by a concurrent update. It is an
+	// ErrConflict.
+	ErrVersionConflict = NewRecoverableWrappedError(ErrConflict, "version mismatch")
+	// ErrPreconditionFailed indicates that a request precondition does not hold, e.g. when an If-Match header does not
+	// match the current version of an object
+	ErrPreconditionFailed = NewRecoverableError("precondition failed")
+	// ErrUnavailable indicates that a request cannot be served at the moment and should be retried, e.g. when it must be
+	// handled by another instance
+	ErrUnavailable = NewRecoverableError("unavailable")
 )
 
 type RecoverableError interface {
@@ -59,6 +71,30 @@ func (e BadRequestError) Error() string       { return e.Message }
 func (e BadRequestError) IsClientError() bool { return true }
 func (e BadRequestError) Unwrap() error       { return e.Cause }
 
+// FieldError describes a validation failure for a single field. Field is a JSON Pointer to the offending value.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is a client error that carries field-level validation failures.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+func (e ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = fmt.Sprintf("%s: %s", field.Field, field.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(parts, "; "))
+}
+func (e ValidationError) IsClientError() bool { return true }
+
 type SystemError struct {
 	Message string
 	Cause   error
@@ -76,6 +112,10 @@ func NewClientError(message string, args ...any) error {
 	return BadRequestError{Message: fmt.Sprintf(message, args...)}
 }
 
+func NewValidationError(fields []FieldError, message string, args ...any) error {
+	return ValidationError{Message: fmt.Sprintf(message, args...), Fields: fields}
+}
+
 func NewFatalError(message string, args ...any) error {
 	return SystemError{Message: fmt.Sprintf(message, args...)}
 }
@@ -39,34 +39,34 @@ func CreateTestActivityDefinition(apiClient *ApiClient) error {
 }
 
 func CreateTestOrchestrationDefinitions(apiClient *ApiClient) error {
-	requestBody := pv1alpha1.OrchestrationDefinition{
-		Type: model.VPADeployType.String(),
-		Activities: []pv1alpha1.Activity{
-			{
-				ID:            "activity1",
-				Type:          "test-activity",
-				Discriminator: "deploy",
-			},
-		},
-	}
-
-	err := apiClient.PostToPManager("orchestration-definitions", requestBody)
-	if err != nil {
-		return err
+	definitions := []struct {
+		orchestrationType model.OrchestrationType
+		discriminator     string
+	}{
+		{model.VPADeployType, "deploy"},
+		{model.VPADisposeType, "dispose"},
+		{model.VPAUpdateType, "update"},
+		{model.VPAMigrateType, "migrate"},
+		{model.DataspaceDeployType, "deploy"},
+		{model.DataspaceDisposeType, "dispose"},
 	}
 
-	requestBody = pv1alpha1.OrchestrationDefinition{
-		Type: model.VPADisposeType.String(),
-		Activities: []pv1alpha1.Activity{
-			{
-				ID:            "activity1",
-				Type:          "test-activity",
-				Discriminator: "dispose",
+	for _, definition := range definitions {
+		requestBody := pv1alpha1.OrchestrationDefinition{
+			Type: definition.orchestrationType.String(),
+			Activities: []pv1alpha1.Activity{
+				{
+					ID:            "activity1",
+					Type:          "test-activity",
+					Discriminator: definition.discriminator,
+				},
 			},
-		},
+		}
+		if err := apiClient.PostToPManager("orchestration-definitions", requestBody); err != nil {
+			return err
+		}
 	}
-
-	return apiClient.PostToPManager("orchestration-definitions", requestBody)
+	return nil
 }
 
 func CreateCell(apiClient *ApiClient) (*tv1alpha1.Cell, error) {
@@ -121,10 +121,27 @@ func CreateDataspaceProfile(apiClient *ApiClient) (*tv1alpha1.DataspaceProfile,
 	return &profile, nil
 }
 
-func DeployDataspaceProfile(deployment tv1alpha1.NewDataspaceProfileDeployment, apiClient *ApiClient) error {
-	err := apiClient.PostToTManager(fmt.Sprintf("dataspace-profiles/%s/deployments", deployment.ProfileID), deployment)
+func DeployDataspaceProfile(deployment tv1alpha1.NewDataspaceProfileDeployment, apiClient *ApiClient) (*tv1alpha1.DataspaceDeployment, error) {
+	var result tv1alpha1.DataspaceDeployment
+	err := apiClient.PostToTManagerWithResponse(fmt.Sprintf("dataspace-profiles/%s/deployments", deployment.ProfileID), deployment, &result)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return &result, nil
+}
+
+// WaitDataspaceDeployment waits until the deployment of the dataspace profile reaches the given state.
+func WaitDataspaceDeployment(apiClient *ApiClient, profileID string, deploymentID string, state string) error {
+	var profile tv1alpha1.DataspaceProfile
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(100 * time.Millisecond) {
+		if err := apiClient.GetTManager(fmt.Sprintf("dataspace-profiles/%s", profileID), &profile); err != nil {
+			return err
+		}
+		for _, deployment := range profile.Deployments {
+			if deployment.ID == deploymentID && deployment.State == state {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("timeout waiting for deployment %s of dataspace profile %s to be %s", deploymentID, profileID, state)
 }
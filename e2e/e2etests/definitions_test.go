@@ -59,7 +59,7 @@ func Test_VerifyDefinitionOperations(t *testing.T) {
 	var orchestrationDefinitions []v1alpha1.OrchestrationDefinition
 	err = client.GetPManager("orchestration-definitions", &orchestrationDefinitions)
 	require.NoError(t, err)
-	assert.Equal(t, 2, len(orchestrationDefinitions))
+	assert.Equal(t, 6, len(orchestrationDefinitions))
 
 	for _, definition := range activityDefinitions {
 		// Verify delete returns error when definition is referenced by an orchestration definition
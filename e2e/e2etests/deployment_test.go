@@ -64,7 +64,9 @@ func Test_VerifyE2E(t *testing.T) {
 		ProfileID: dProfile.ID,
 		CellID:    cell.ID,
 	}
-	err = e2efixtures.DeployDataspaceProfile(deployment, client)
+	dDeployment, err := e2efixtures.DeployDataspaceProfile(deployment, client)
+	require.NoError(t, err)
+	err = e2efixtures.WaitDataspaceDeployment(client, dProfile.ID, dDeployment.ID, api.DeploymentStateActive.String())
 	require.NoError(t, err)
 
 	tenant, err := e2efixtures.CreateTenant(client, map[string]any{})
@@ -123,6 +125,7 @@ func Test_VerifyE2E(t *testing.T) {
 	require.NotNil(t, connectorVPA, "Expected to find a VPA with cfm.connector type")
 	require.NotNil(t, connectorVPA.Properties, "Connector VPA properties should not be nil")
 	require.Contains(t, connectorVPA.Properties, "connectorkey", "Connector VPA should contain 'connectorkey' property")
+	assert.Equal(t, string(model.ConnectorType), connectorVPA.Outputs["agent.test.vpa.type"], "Connector VPA should contain its own outputs")
 
 	// verify return of agent state data
 	stateData := statusProfile.Properties[model.VPAStateData].(map[string]any)
@@ -138,6 +141,29 @@ func Test_VerifyE2E(t *testing.T) {
 	require.Equal(t, 1, len(orchestrations), "Expected 1 orchestration to be created")
 	assert.Equal(t, papi.OrchestrationStateCompleted, papi.OrchestrationState(orchestrations[0].State))
 
+	// Update the connector VPA properties
+	update := v1alpha1.ParticipantProfileUpdate{
+		VPAProperties: map[string]map[string]any{string(model.ConnectorType): {"connectorkey": "updatedvalue"}},
+	}
+	err = client.PatchToTManager(fmt.Sprintf("tenants/%s/participant-profiles/%s", tenant.ID, participantProfile.ID), update)
+	require.NoError(t, err)
+
+	updated := false
+	for start := time.Now(); time.Since(start) < 5*time.Second && !updated; {
+		err = client.GetTManager(fmt.Sprintf("tenants/%s/participant-profiles/%s", tenant.ID, participantProfile.ID), &statusProfile)
+		require.NoError(t, err)
+		updated = true
+		for _, vpa := range statusProfile.VPAs {
+			if vpa.State != api.DeploymentStateActive.String() {
+				updated = false
+			}
+		}
+	}
+	require.True(t, updated, "Expected updated VPAs to be active")
+	stateData = statusProfile.Properties[model.VPAStateData].(map[string]any)
+	assert.Equal(t, true, stateData["agent.test.updated"])
+	assert.Equal(t, "test output", stateData["agent.test.output"])
+
 	// Dispose VPAs
 	err = client.DeleteToTManager(fmt.Sprintf("tenants/%s/participant-profiles/%s", tenant.ID, participantProfile.ID))
 	require.NoError(t, err)
@@ -156,4 +182,17 @@ func Test_VerifyE2E(t *testing.T) {
 		}
 	}
 	require.Equal(t, 3, disposeCount, "Expected 3 deployments to be disposed")
+
+	// Delete the tenant and its disposed participant
+	err = client.DeleteToTManager(fmt.Sprintf("tenants/%s?cascade=true", tenant.ID))
+	require.NoError(t, err)
+	var deletedTenant v1alpha1.Tenant
+	err = client.GetTManager(fmt.Sprintf("tenants/%s", tenant.ID), &deletedTenant)
+	require.Error(t, err, "Expected the tenant to be deleted")
+
+	// Undeploy the dataspace profile
+	err = client.DeleteToTManager(fmt.Sprintf("dataspace-profiles/%s/deployments/%s", dProfile.ID, dDeployment.ID))
+	require.NoError(t, err)
+	err = e2efixtures.WaitDataspaceDeployment(client, dProfile.ID, dDeployment.ID, api.DeploymentStateDisposed.String())
+	require.NoError(t, err)
 }
@@ -13,6 +13,8 @@
 package launcher
 
 import (
+	"time"
+
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/runtime"
 	"github.com/metaform/connector-fabric-manager/common/system"
@@ -52,16 +54,36 @@ func (t TestActivityProcessor) Process(ctx api.ActivityContext) api.ActivityResu
 		t.monitor.Infof("Processed dispose")
 		return api.ActivityResult{Result: api.ActivityResultComplete}
 	}
+	if ctx.Discriminator() == api.UpdateDiscriminator {
+		ctx.SetOutputValue("agent.test.updated", true)
+		t.monitor.Infof("Processed update")
+		return api.ActivityResult{Result: api.ActivityResultComplete}
+	}
+	if ctx.Discriminator() == api.MigrateDiscriminator {
+		// VPAs are migrated to the cells in their manifests; results are reported as for a deployment
+		ctx.SetOutputValue("agent.test.migrated", true)
+	}
 	ctx.SetOutputValue("agent.test.output", "test output")
 
 	var data TestAgentData
 	ctx.ReadValues(&data)
 	ctx.SetOutputValue("agent.test.credentials.received", len(data.CredentialSpecs) > 0)
+	for _, vpa := range data.VPAs {
+		api.SetVPAResult(ctx, vpa.ID, model.VPAResult{
+			State:     model.VPAResultSucceeded,
+			Timestamp: time.Now().UTC(),
+			Outputs: map[string]any{
+				"agent.test.vpa.type": vpa.VPAType.String(),
+				"agent.test.vpa.cell": vpa.ExternalCellID,
+			},
+		})
+	}
 
-	t.monitor.Infof("Processed deploy")
+	t.monitor.Infof("Processed %s", ctx.Discriminator())
 	return api.ActivityResult{Result: api.ActivityResultComplete}
 }
 
 type TestAgentData struct {
 	CredentialSpecs []model.CredentialSpec `json:"cfm.vpa.credentials"`
+	VPAs            []model.VPAManifest    `json:"cfm.vpa.data"`
 }
@@ -17,6 +17,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"iter"
 	"reflect"
 
@@ -29,10 +30,14 @@ import (
 )
 
 const (
-	ProvisionManagerKey  system.ServiceType = "pmapi:ProvisionManager"
-	DefinitionStoreKey   system.ServiceType = "pmapi:DefinitionStore"
-	OrchestratorKey      system.ServiceType = "pmapi:Orchestrator"
-	DefinitionManagerKey system.ServiceType = "pmapi:DefinitionManager"
+	ProvisionManagerKey     system.ServiceType = "pmapi:ProvisionManager"
+	DefinitionStoreKey      system.ServiceType = "pmapi:DefinitionStore"
+	OrchestratorKey         system.ServiceType = "pmapi:Orchestrator"
+	DefinitionManagerKey    system.ServiceType = "pmapi:DefinitionManager"
+	ActivityLeaseManagerKey system.ServiceType = "pmapi:ActivityLeaseManager"
+	AgentRegistryKey        system.ServiceType = "pmapi:AgentRegistry"
+	ScheduleManagerKey      system.ServiceType = "pmapi:ScheduleManager"
+	LeaderElectionKey       system.ServiceType = "pmapi:LeaderElection"
 )
 
 // ProvisionManager handles orchestration execution and resource management.
@@ -42,6 +47,15 @@ type ProvisionManager interface {
 	// If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
 	Start(ctx context.Context, manifest *model.OrchestrationManifest) (*Orchestration, error)
 
+	// StartIdempotent starts an orchestration like Start. Requests repeated with the same idempotency key return the
+	// orchestration started by the first request. Returns types.ErrConflict if the key was used with a different
+	// manifest. If the key is empty, the orchestration is started without an idempotency check.
+	StartIdempotent(ctx context.Context, idempotencyKey string, manifest *model.OrchestrationManifest) (*Orchestration, error)
+
+	// WaitForOrchestration waits up to the given timeout for the orchestration to complete or fail and returns its
+	// latest state. Returns types.ErrNotFound if the orchestration does not exist.
+	WaitForOrchestration(ctx context.Context, orchestrationID string, timeout time.Duration) (*Orchestration, error)
+
 	// Cancel terminates an orchestration execution.
 	// If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
 	Cancel(ctx context.Context, orchestrationID string) error
@@ -95,8 +109,27 @@ const (
 
 	DeployDiscriminator  Discriminator = "deploy"
 	DisposeDiscriminator Discriminator = "dispose"
+	UpdateDiscriminator  Discriminator = "update"
+	MigrateDiscriminator Discriminator = "migrate"
 )
 
+func (r ActivityResultType) String() string {
+	switch r {
+	case ActivityResultWait:
+		return "wait"
+	case ActivityResultComplete:
+		return "complete"
+	case ActivityResultSchedule:
+		return "schedule"
+	case ActivityResultRetryError:
+		return "retry"
+	case ActivityResultFatalError:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
 type ActivityResult struct {
 	Result           ActivityResultType
 	WaitOnReschedule time.Duration
@@ -143,10 +176,127 @@ type ActivityContext interface {
 
 	// Context returns the underlying context
 	Context() context.Context
+
+	// Heartbeat signals that the activity is still making progress. Long-running activities should call Heartbeat
+	// periodically to extend their lease; otherwise, the orchestration engine may consider the activity stalled.
+	Heartbeat() error
+
+	// ReportProgress records the completion percentage (0-100) of the activity and an optional status message. Reporting
+	// progress also signals a heartbeat.
+	ReportProgress(percent int, message string) error
+
+	// ReportVPAProgress records the completion percentage (0-100) of the activity for the given VPA and an optional
+	// status message. Activities that operate on several VPAs use it so that the progress of each VPA is tracked
+	// individually. Reporting progress also signals a heartbeat.
+	ReportVPAProgress(vpaID string, percent int, message string) error
+}
+
+// SetVPAResult reports the result of the current activity for a VPA. Results are returned in the orchestration
+// response keyed by VPA ID so that the outcome of each VPA can be tracked individually.
+func SetVPAResult(ctx ActivityContext, vpaID string, result model.VPAResult) {
+	ctx.SetOutputValue(model.VPAResultKey(vpaID, ctx.ID()), result)
+}
+
+// VPAManifests returns the manifests of the VPAs of the given type contained in the orchestration. VPAs of a participant
+// may be placed in different cells, so agents must use the cell of each manifest rather than assume a single cell.
+func VPAManifests(ctx ActivityContext, vpaType model.VPAType) ([]model.VPAManifest, error) {
+	var data struct {
+		VPAs []model.VPAManifest `json:"cfm.vpa.data"`
+	}
+	if err := ctx.ReadValues(&data); err != nil {
+		return nil, err
+	}
+	manifests := make([]model.VPAManifest, 0, len(data.VPAs))
+	for _, manifest := range data.VPAs {
+		if manifest.VPAType == vpaType {
+			manifests = append(manifests, manifest)
+		}
+	}
+	return manifests, nil
+}
+
+// ActivityLeaseManager leases activities to agents that execute them out-of-process, for example, over HTTP. Leased
+// activities are processed with the same semantics as activities executed by an ActivityProcessor: completion merges
+// processing and output data into the orchestration and advances it, retryable failures redeliver the activity, and
+// fatal failures put the orchestration into the error state.
+type ActivityLeaseManager interface {
+
+	// Lease returns the next activity of the given type, waiting up to the given duration for one to become available.
+	// Returns nil if no activity is available.
+	Lease(ctx context.Context, activityType ActivityType, wait time.Duration) (*ActivityLease, error)
+
+	// Heartbeat extends the lease and records the optional progress.
+	// Returns types.ErrNotFound if the lease does not exist or has expired, and types.ErrUnavailable if it was issued by
+	// another instance.
+	Heartbeat(ctx context.Context, leaseID string, progress *ActivityProgress) (*ActivityLease, error)
+
+	// Complete completes the leased activity, merging the given processing and output data into the orchestration.
+	// Returns types.ErrNotFound if the lease does not exist or has expired, and types.ErrUnavailable if it was issued by
+	// another instance.
+	Complete(ctx context.Context, leaseID string, processingData map[string]any, outputData map[string]any) error
+
+	// Fail fails the leased activity. Retryable failures cause the activity to be redelivered; otherwise, the
+	// orchestration is put into the error state.
+	// Returns types.ErrNotFound if the lease does not exist or has expired, and types.ErrUnavailable if it was issued by
+	// another instance.
+	Fail(ctx context.Context, leaseID string, retryable bool, reason string) error
+}
+
+// AgentRegistry provides the agents that have registered to execute activities.
+type AgentRegistry interface {
+
+	// GetAgents returns the registered agents.
+	GetAgents(ctx context.Context) ([]AgentRegistration, error)
+
+	// GetActivityTypes returns the activity types that have at least one registered agent.
+	GetActivityTypes(ctx context.Context) (map[ActivityType]struct{}, error)
+
+	// RegisterAgent registers an agent or refreshes its registration. Agents that do not register through NATS, for
+	// example, agents that lease activities over HTTP, use it to make their activity types available. Registrations
+	// expire unless they are refreshed.
+	RegisterAgent(ctx context.Context, registration *AgentRegistration) (*AgentRegistration, error)
+
+	// DeregisterAgent removes the registration of the agent with the given instance ID.
+	// Returns types.ErrNotFound if the agent is not registered.
+	DeregisterAgent(ctx context.Context, instanceID string) error
+}
+
+// ScheduleManager manages schedules that start orchestrations on a recurring basis.
+type ScheduleManager interface {
+
+	// CreateSchedule creates a schedule. Returns a client error if the cron expression or payload template is invalid
+	// or the orchestration type does not exist.
+	CreateSchedule(ctx context.Context, schedule *Schedule) (*Schedule, error)
+
+	// GetSchedule returns the schedule or types.ErrNotFound if it does not exist.
+	GetSchedule(ctx context.Context, scheduleID string) (*Schedule, error)
+
+	// GetSchedules returns all schedules.
+	GetSchedules(ctx context.Context) ([]Schedule, error)
+
+	// DeleteSchedule deletes the schedule and its run history.
+	DeleteSchedule(ctx context.Context, scheduleID string) error
+
+	// PauseSchedule stops the schedule from starting orchestrations until it is resumed.
+	PauseSchedule(ctx context.Context, scheduleID string) (*Schedule, error)
+
+	// ResumeSchedule resumes a paused schedule. Runs missed while the schedule was paused are skipped.
+	ResumeSchedule(ctx context.Context, scheduleID string) (*Schedule, error)
+
+	// GetScheduleRuns returns the past runs of the schedule, most recent first.
+	GetScheduleRuns(ctx context.Context, scheduleID string) ([]ScheduleRun, error)
+}
+
+// LeaderElection determines whether this instance is the leader among the provision manager instances. Work that must
+// only be performed by a single instance, such as starting scheduled orchestrations, is performed by the leader.
+type LeaderElection interface {
+	IsLeader() bool
 }
 
 type DefinitionManager interface {
-	CreateOrchestrationDefinition(ctx context.Context, definition *OrchestrationDefinition) (*OrchestrationDefinition, error)
+	// CreateOrchestrationDefinition stores the definition. If the missing agent policy is to warn, the activity types of
+	// the definition that have no registered agent are returned.
+	CreateOrchestrationDefinition(ctx context.Context, definition *OrchestrationDefinition) (*OrchestrationDefinition, []ActivityType, error)
 	DeleteOrchestrationDefinition(ctx context.Context, atype model.OrchestrationType) error
 	GetOrchestrationDefinitions(ctx context.Context) ([]OrchestrationDefinition, error)
 
@@ -161,6 +311,25 @@ type defaultActivityContext struct {
 	context        context.Context
 	processingData map[string]any
 	outputData     map[string]any
+	heartbeat      func() error
+	progress       func(vpaID string, percent int, message string) error
+}
+
+// ActivityContextOption configures optional ActivityContext behavior.
+type ActivityContextOption func(*defaultActivityContext)
+
+// WithHeartbeat sets the function invoked when the activity signals a heartbeat.
+func WithHeartbeat(heartbeat func() error) ActivityContextOption {
+	return func(d *defaultActivityContext) {
+		d.heartbeat = heartbeat
+	}
+}
+
+// WithProgressReporter sets the function invoked when the activity reports progress.
+func WithProgressReporter(progress func(vpaID string, percent int, message string) error) ActivityContextOption {
+	return func(d *defaultActivityContext) {
+		d.progress = progress
+	}
 }
 
 func NewActivityContext(
@@ -168,14 +337,19 @@ func NewActivityContext(
 	oID string,
 	activity Activity,
 	processingData map[string]any,
-	outputData map[string]any) ActivityContext {
-	return defaultActivityContext{
+	outputData map[string]any,
+	opts ...ActivityContextOption) ActivityContext {
+	activityContext := defaultActivityContext{
 		activity:       activity,
 		oID:            oID,
 		context:        ctx,
 		processingData: processingData,
 		outputData:     outputData,
 	}
+	for _, opt := range opts {
+		opt(&activityContext)
+	}
+	return activityContext
 }
 
 func (d defaultActivityContext) Context() context.Context {
@@ -240,3 +414,24 @@ func (d defaultActivityContext) SetOutputValue(key string, value any) {
 func (d defaultActivityContext) OutputValues() map[string]any {
 	return d.outputData
 }
+
+func (d defaultActivityContext) Heartbeat() error {
+	if d.heartbeat == nil {
+		return nil
+	}
+	return d.heartbeat()
+}
+
+func (d defaultActivityContext) ReportProgress(percent int, message string) error {
+	return d.ReportVPAProgress("", percent, message)
+}
+
+func (d defaultActivityContext) ReportVPAProgress(vpaID string, percent int, message string) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid progress percentage: %d", percent)
+	}
+	if d.progress == nil {
+		return nil
+	}
+	return d.progress(vpaID, percent, message)
+}
@@ -16,6 +16,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -237,3 +238,104 @@ func TestActivityContext_Delete(t *testing.T) {
 	_, exists = activityContext.Value("key")
 	assert.False(t, exists)
 }
+
+func TestActivityContext_Heartbeat(t *testing.T) {
+	activity := Activity{ID: "test-activity"}
+
+	// Without a heartbeat function, signaling is a no-op
+	activityContext := NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, map[string]any{})
+	require.NoError(t, activityContext.Heartbeat())
+
+	calls := 0
+	activityContext = NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, map[string]any{},
+		WithHeartbeat(func() error {
+			calls++
+			return nil
+		}))
+	require.NoError(t, activityContext.Heartbeat())
+	require.NoError(t, activityContext.Heartbeat())
+	assert.Equal(t, 2, calls)
+
+	activityContext = NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, map[string]any{},
+		WithHeartbeat(func() error {
+			return assert.AnError
+		}))
+	require.ErrorIs(t, activityContext.Heartbeat(), assert.AnError)
+}
+
+func TestActivityContext_ReportProgress(t *testing.T) {
+	activity := Activity{ID: "test-activity"}
+
+	var reportedVPA string
+	var reportedPercent int
+	var reportedMessage string
+	activityContext := NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, map[string]any{},
+		WithProgressReporter(func(vpaID string, percent int, message string) error {
+			reportedVPA = vpaID
+			reportedPercent = percent
+			reportedMessage = message
+			return nil
+		}))
+
+	require.NoError(t, activityContext.ReportProgress(50, "halfway"))
+	assert.Equal(t, "", reportedVPA)
+	assert.Equal(t, 50, reportedPercent)
+	assert.Equal(t, "halfway", reportedMessage)
+
+	require.NoError(t, activityContext.ReportVPAProgress("vpa1", 75, "configuring"))
+	assert.Equal(t, "vpa1", reportedVPA)
+	assert.Equal(t, 75, reportedPercent)
+	assert.Equal(t, "configuring", reportedMessage)
+	require.Error(t, activityContext.ReportVPAProgress("vpa1", 101, "invalid"))
+
+	require.Error(t, activityContext.ReportProgress(101, "invalid"))
+	require.Error(t, activityContext.ReportProgress(-1, "invalid"))
+
+	// Without a reporter, progress is ignored
+	activityContext = NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, map[string]any{})
+	require.NoError(t, activityContext.ReportProgress(10, "ignored"))
+}
+
+func TestSetVPAResult(t *testing.T) {
+	activity := Activity{ID: "test-activity"}
+	outputData := map[string]any{}
+	activityContext := NewActivityContext(context.TODO(), "test-oid", activity, map[string]any{}, outputData)
+
+	SetVPAResult(activityContext, "vpa-1", model.VPAResult{State: model.VPAResultFailed, Error: "failed"})
+
+	results, remaining := model.ExtractVPAResults(activityContext.OutputValues())
+	assert.Empty(t, remaining)
+	assert.Equal(t, model.VPAResult{State: model.VPAResultFailed, Error: "failed"}, results["vpa-1"])
+}
+
+func TestVPAManifests(t *testing.T) {
+	activity := Activity{ID: "test-activity"}
+	processingData := map[string]any{
+		model.VPAData: []model.VPAManifest{
+			{ID: "vpa-1", VPAType: model.ConnectorType, CellID: "cell-1", ExternalCellID: "external-1"},
+			{ID: "vpa-2", VPAType: model.DataPlaneType, CellID: "cell-2", ExternalCellID: "external-2"},
+			{ID: "vpa-3", VPAType: model.DataPlaneType, CellID: "cell-3", ExternalCellID: "external-3"},
+		},
+	}
+	activityContext := NewActivityContext(context.TODO(), "test-oid", activity, processingData, map[string]any{})
+
+	manifests, err := VPAManifests(activityContext, model.DataPlaneType)
+
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	assert.Equal(t, "external-2", manifests[0].ExternalCellID)
+	assert.Equal(t, "external-3", manifests[1].ExternalCellID)
+
+	manifests, err = VPAManifests(activityContext, model.CredentialServiceType)
+	require.NoError(t, err)
+	assert.Empty(t, manifests)
+}
+
+func TestActivityResultType_String(t *testing.T) {
+	assert.Equal(t, "wait", ActivityResultType(ActivityResultWait).String())
+	assert.Equal(t, "complete", ActivityResultType(ActivityResultComplete).String())
+	assert.Equal(t, "schedule", ActivityResultType(ActivityResultSchedule).String())
+	assert.Equal(t, "retry", ActivityResultType(ActivityResultRetryError).String())
+	assert.Equal(t, "fatal", ActivityResultType(ActivityResultFatalError).String())
+	assert.Equal(t, "unknown", ActivityResultType(42).String())
+}
@@ -0,0 +1,140 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+)
+
+// Schedule starts orchestrations of a type on a recurring cron schedule.
+//
+// String values in the payload template are rendered as Go templates for each run with a ScheduleRunData. For example,
+// "{{.ScheduledTime.Format \"2006-01-02\"}}" renders the date the run was scheduled for.
+type Schedule struct {
+	ID                string                  `json:"id"`
+	Version           int64                   `json:"version"`
+	CronExpression    string                  `json:"cronExpression"`
+	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
+	PayloadTemplate   map[string]any          `json:"payloadTemplate"`
+	Paused            bool                    `json:"paused"`
+	NextRun           time.Time               `json:"nextRun"`
+	LastRun           time.Time               `json:"lastRun"`
+	CreatedTimestamp  time.Time               `json:"createdTimestamp"`
+}
+
+func (s *Schedule) GetID() string {
+	return s.ID
+}
+
+func (s *Schedule) GetVersion() int64 {
+	return s.Version
+}
+
+func (s *Schedule) IncrementVersion() {
+	s.Version++
+}
+
+// RenderPayload renders the payload template for the run scheduled at the given time.
+func (s *Schedule) RenderPayload(scheduledTime time.Time) (map[string]any, error) {
+	data := ScheduleRunData{
+		ScheduleID:    s.ID,
+		RunID:         ScheduleRunID(s.ID, scheduledTime),
+		ScheduledTime: scheduledTime,
+	}
+	rendered, err := renderValue(s.PayloadTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	payload, _ := rendered.(map[string]any)
+	return payload, nil
+}
+
+// ScheduleRunData is the data available to payload templates.
+type ScheduleRunData struct {
+	ScheduleID    string
+	RunID         string
+	ScheduledTime time.Time
+}
+
+// ScheduleRun records an orchestration started by a schedule. The run ID is also the ID of the orchestration and is
+// derived from the schedule ID and the scheduled time so that a scheduled run is never started twice.
+type ScheduleRun struct {
+	ID               string    `json:"id"`
+	Version          int64     `json:"version"`
+	ScheduleID       string    `json:"scheduleId"`
+	ScheduledTime    time.Time `json:"scheduledTime"`
+	StartedTimestamp time.Time `json:"startedTimestamp"`
+	Error            string    `json:"error,omitempty"`
+}
+
+func (r *ScheduleRun) GetID() string {
+	return r.ID
+}
+
+func (r *ScheduleRun) GetVersion() int64 {
+	return r.Version
+}
+
+func (r *ScheduleRun) IncrementVersion() {
+	r.Version++
+}
+
+// ScheduleRunID returns the deterministic ID of the run of a schedule at the given time.
+func ScheduleRunID(scheduleID string, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%d", scheduleID, scheduledTime.Unix())
+}
+
+func renderValue(value any, data ScheduleRunData) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New("payload").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload template %q: %w", v, err)
+		}
+		var builder strings.Builder
+		if err = tmpl.Execute(&builder, data); err != nil {
+			return nil, fmt.Errorf("error rendering payload template %q: %w", v, err)
+		}
+		return builder.String(), nil
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, entry := range v {
+			rendered, err := renderValue(entry, data)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = rendered
+		}
+		return result, nil
+	case []any:
+		result := make([]any, len(v))
+		for i, entry := range v {
+			rendered, err := renderValue(entry, data)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = rendered
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
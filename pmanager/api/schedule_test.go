@@ -0,0 +1,60 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_RenderPayload(t *testing.T) {
+	scheduled := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	schedule := &Schedule{
+		ID: "rotate",
+		PayloadTemplate: map[string]any{
+			"realm":  "tenants",
+			"run":    "{{.RunID}}",
+			"nested": map[string]any{"date": "{{.ScheduledTime.Format \"2006-01-02\"}}"},
+			"list":   []any{"{{.ScheduleID}}", 42},
+		},
+	}
+
+	payload, err := schedule.RenderPayload(scheduled)
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"realm":  "tenants",
+		"run":    "rotate-1748772000",
+		"nested": map[string]any{"date": "2025-06-01"},
+		"list":   []any{"rotate", 42},
+	}, payload)
+	// The template is not modified
+	require.Equal(t, "{{.RunID}}", schedule.PayloadTemplate["run"])
+}
+
+func TestSchedule_RenderPayload_Invalid(t *testing.T) {
+	schedule := &Schedule{ID: "rotate", PayloadTemplate: map[string]any{"run": "{{.RunID"}}
+
+	_, err := schedule.RenderPayload(time.Now())
+
+	require.ErrorContains(t, err, "invalid payload template")
+}
+
+func TestSchedule_RenderPayload_Empty(t *testing.T) {
+	payload, err := (&Schedule{ID: "rotate"}).RenderPayload(time.Now())
+
+	require.NoError(t, err)
+	require.Empty(t, payload)
+}
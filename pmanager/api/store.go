@@ -24,6 +24,9 @@ import (
 
 const (
 	OrchestrationIndexKey system.ServiceType = "pmstore:OrchestrationIndex"
+	ScheduleStoreKey      system.ServiceType = "pmstore:ScheduleStore"
+	ScheduleRunStoreKey   system.ServiceType = "pmstore:ScheduleRunStore"
+	IdempotencyStoreKey   system.ServiceType = "pmstore:IdempotencyStore"
 )
 
 // DefinitionStore manages OrchestrationDefinition and ActivityDefinitions.
@@ -91,3 +94,37 @@ func (o *OrchestrationEntry) GetVersion() int64 {
 func (o *OrchestrationEntry) IncrementVersion() {
 	o.Version++
 }
+
+// IdempotencyState is the state of an idempotency key.
+type IdempotencyState string
+
+const (
+	// IdempotencyStatePending indicates the key is reserved and the orchestration is being started.
+	IdempotencyStatePending IdempotencyState = "pending"
+	// IdempotencyStateStarted indicates the orchestration recorded for the key was started.
+	IdempotencyStateStarted IdempotencyState = "started"
+)
+
+// IdempotencyRecord maps an idempotency key to the orchestration started by the first request that used the key. The
+// record is created in the pending state before the orchestration is started so that concurrent requests with the same
+// key start it only once. The request hash is used to detect keys reused with a different request.
+type IdempotencyRecord struct {
+	ID               string           `json:"id"`
+	Version          int64            `json:"version"`
+	RequestHash      string           `json:"requestHash"`
+	OrchestrationID  string           `json:"orchestrationId"`
+	State            IdempotencyState `json:"state"`
+	CreatedTimestamp time.Time        `json:"createdTimestamp"`
+}
+
+func (r *IdempotencyRecord) GetID() string {
+	return r.ID
+}
+
+func (r *IdempotencyRecord) GetVersion() int64 {
+	return r.Version
+}
+
+func (r *IdempotencyRecord) IncrementVersion() {
+	r.Version++
+}
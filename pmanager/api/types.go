@@ -35,18 +35,36 @@ const (
 // Orchestration is a collection of activities that are executed to allocate resources in the system. Activities are
 // organized into parallel execution steps based on dependencies.
 //
-// As actions are completed, the orchestration system will update the Completed map.
+// As actions are completed, the orchestration system will update the Completed map. Activities that are being executed
+// and have signaled a heartbeat are tracked in the Heartbeats map with the time of their last heartbeat. Progress reported
+// by activities is tracked in the Progress map, and progress reported for individual VPAs in the VPAProgress map keyed by
+// VPA ID and activity ID.
 type Orchestration struct {
-	ID                string                  `json:"id"`
-	CorrelationID     string                  `json:"correlationId"`
-	State             OrchestrationState      `json:"state"`
-	StateTimestamp    time.Time               `json:"stateTimestamp"`
-	CreatedTimestamp  time.Time               `json:"createdTimestamp"`
-	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
-	Steps             []OrchestrationStep     `json:"steps"`
-	ProcessingData    map[string]any          `json:"processingData"`
-	OutputData        map[string]any          `json:"outputData"`
-	Completed         map[string]struct{}     `json:"completed"`
+	ID                string                                 `json:"id"`
+	CorrelationID     string                                 `json:"correlationId"`
+	State             OrchestrationState                     `json:"state"`
+	StateTimestamp    time.Time                              `json:"stateTimestamp"`
+	CreatedTimestamp  time.Time                              `json:"createdTimestamp"`
+	OrchestrationType model.OrchestrationType                `json:"orchestrationType"`
+	Steps             []OrchestrationStep                    `json:"steps"`
+	ProcessingData    map[string]any                         `json:"processingData"`
+	OutputData        map[string]any                         `json:"outputData"`
+	Completed         map[string]struct{}                    `json:"completed"`
+	Heartbeats        map[string]time.Time                   `json:"heartbeats,omitempty"`
+	Progress          map[string]ActivityProgress            `json:"progress,omitempty"`
+	VPAProgress       map[string]map[string]ActivityProgress `json:"vpaProgress,omitempty"`
+
+	// StalledActivities contains the IDs of pending activities whose type has no registered agent. It is computed when
+	// the orchestration is read and is not persisted.
+	StalledActivities []string `json:"-"`
+}
+
+// ActivityProgress is the last progress reported by an activity. VPAID is set if the progress was reported for a VPA.
+type ActivityProgress struct {
+	VPAID     string    `json:"vpaId,omitempty"`
+	Percent   int       `json:"percent"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 func (o *Orchestration) SetState(state OrchestrationState) {
@@ -54,6 +72,212 @@ func (o *Orchestration) SetState(state OrchestrationState) {
 	o.StateTimestamp = time.Now()
 }
 
+// RecordHeartbeat records the time of the last heartbeat for the given activity.
+func (o *Orchestration) RecordHeartbeat(activityID string, timestamp time.Time) {
+	if o.Heartbeats == nil {
+		o.Heartbeats = make(map[string]time.Time)
+	}
+	o.Heartbeats[activityID] = timestamp
+}
+
+// ClearHeartbeat removes the heartbeat entry for the given activity.
+func (o *Orchestration) ClearHeartbeat(activityID string) {
+	delete(o.Heartbeats, activityID)
+}
+
+// RecordProgress records the progress reported by the given activity.
+func (o *Orchestration) RecordProgress(activityID string, progress ActivityProgress) {
+	if progress.VPAID != "" {
+		if o.VPAProgress == nil {
+			o.VPAProgress = make(map[string]map[string]ActivityProgress)
+		}
+		if o.VPAProgress[progress.VPAID] == nil {
+			o.VPAProgress[progress.VPAID] = make(map[string]ActivityProgress)
+		}
+		o.VPAProgress[progress.VPAID][activityID] = progress
+		return
+	}
+	if o.Progress == nil {
+		o.Progress = make(map[string]ActivityProgress)
+	}
+	o.Progress[activityID] = progress
+}
+
+// OverallProgress returns the completion percentage of the orchestration. Completed activities count as 100 percent,
+// activities that reported progress count with their last reported value, and all other activities count as 0. The
+// value of an activity that only reported progress for VPAs is the average over those VPAs.
+func (o *Orchestration) OverallProgress() int {
+	total := 0
+	sum := 0
+	for _, step := range o.Steps {
+		for _, activity := range step.Activities {
+			total++
+			if _, completed := o.Completed[activity.ID]; completed {
+				sum += 100
+			} else if progress, found := o.Progress[activity.ID]; found {
+				sum += progress.Percent
+			} else {
+				sum += o.averageVPAProgress(activity.ID)
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// VPAPercents returns the completion percentage of the orchestration for each of its VPAs. Activities that reported
+// progress or a result for specific VPAs only count for those VPAs, while all other activities count for every VPA.
+// Completed activities count as 100 percent, activities that reported progress count with the last value reported for
+// the VPA or, if none, for the activity, and all other activities count as 0.
+func (o *Orchestration) VPAPercents() map[string]int {
+	vpaIDs := o.vpaIDs()
+	if len(vpaIDs) == 0 {
+		return nil
+	}
+
+	// Determine the VPAs each activity has reported progress or a result for
+	served := make(map[string]map[string]struct{})
+	serve := func(activityID string, vpaID string) {
+		if served[activityID] == nil {
+			served[activityID] = make(map[string]struct{})
+		}
+		served[activityID][vpaID] = struct{}{}
+	}
+	for vpaID, progress := range o.VPAProgress {
+		for activityID := range progress {
+			serve(activityID, vpaID)
+		}
+	}
+	for key := range o.OutputData {
+		if vpaID, activityID, ok := model.ParseVPAResultKey(key); ok {
+			serve(activityID, vpaID)
+		}
+	}
+
+	percents := make(map[string]int, len(vpaIDs))
+	for _, vpaID := range vpaIDs {
+		total := 0
+		sum := 0
+		for _, step := range o.Steps {
+			for _, activity := range step.Activities {
+				if vpas, scoped := served[activity.ID]; scoped {
+					if _, found := vpas[vpaID]; !found {
+						continue
+					}
+				}
+				total++
+				if _, completed := o.Completed[activity.ID]; completed {
+					sum += 100
+				} else if progress, found := o.VPAProgress[vpaID][activity.ID]; found {
+					sum += progress.Percent
+				} else if progress, found := o.Progress[activity.ID]; found {
+					sum += progress.Percent
+				}
+			}
+		}
+		if total > 0 {
+			percents[vpaID] = sum / total
+		} else {
+			percents[vpaID] = 0
+		}
+	}
+	return percents
+}
+
+// averageVPAProgress returns the average progress the activity reported for VPAs, or 0 if it has not reported any.
+func (o *Orchestration) averageVPAProgress(activityID string) int {
+	count := 0
+	sum := 0
+	for _, progress := range o.VPAProgress {
+		if entry, found := progress[activityID]; found {
+			count++
+			sum += entry.Percent
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / count
+}
+
+// vpaIDs returns the IDs of the VPAs contained in the orchestration data and of the VPAs progress was reported for.
+func (o *Orchestration) vpaIDs() []string {
+	ids := make([]string, 0)
+	// The VPA data is typed when the orchestration is instantiated and decoded as generic JSON when it is read back
+	switch vpas := o.ProcessingData[model.VPAData].(type) {
+	case []model.VPAManifest:
+		for _, manifest := range vpas {
+			ids = append(ids, manifest.ID)
+		}
+	case []any:
+		for _, vpa := range vpas {
+			if manifest, ok := vpa.(map[string]any); ok {
+				if id, ok := manifest["id"].(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	for vpaID := range o.VPAProgress {
+		if !slices.Contains(ids, vpaID) {
+			ids = append(ids, vpaID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// StaleActivities returns the IDs of activities whose last heartbeat is older than the given timeout.
+func (o *Orchestration) StaleActivities(now time.Time, timeout time.Duration) []string {
+	stale := make([]string, 0)
+	for activityID, timestamp := range o.Heartbeats {
+		if now.Sub(timestamp) > timeout {
+			stale = append(stale, activityID)
+		}
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// GetActivity returns the activity with the given ID.
+func (o *Orchestration) GetActivity(activityID string) (*Activity, bool) {
+	for _, step := range o.Steps {
+		for _, activity := range step.Activities {
+			if activity.ID == activityID {
+				return &activity, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// IsTerminal returns true if the orchestration has completed or errored.
+func (o *Orchestration) IsTerminal() bool {
+	return o.State == OrchestrationStateCompleted || o.State == OrchestrationStateErrored
+}
+
+// PendingActivities returns the activities of the current step that have not completed. Returns an empty slice if the
+// orchestration has completed or errored.
+func (o *Orchestration) PendingActivities() []Activity {
+	if o.IsTerminal() {
+		return []Activity{}
+	}
+	for _, step := range o.Steps {
+		pending := make([]Activity, 0, len(step.Activities))
+		for _, activity := range step.Activities {
+			if _, completed := o.Completed[activity.ID]; !completed {
+				pending = append(pending, activity)
+			}
+		}
+		if len(pending) > 0 {
+			return pending
+		}
+	}
+	return []Activity{}
+}
+
 // CanProceedToNextStep returns if the orchestration is able to proceed to the next step or must wait.
 func (o *Orchestration) CanProceedToNextStep(activityId string) (bool, error) {
 	step, err := o.GetStepForActivity(activityId)
@@ -122,6 +346,28 @@ type Activity struct {
 	DependsOn     []string       `json:"dependsOn"`
 }
 
+// ActivityLease is an activity leased to an agent for execution. The lease must be completed, failed, or extended by a
+// heartbeat before it expires; otherwise, the activity is redelivered.
+type ActivityLease struct {
+	ID                string                  `json:"id"`
+	OrchestrationID   string                  `json:"orchestrationId"`
+	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
+	Activity          Activity                `json:"activity"`
+	ProcessingData    map[string]any          `json:"processingData"`
+	OutputData        map[string]any          `json:"outputData"`
+	ExpiresAt         time.Time               `json:"expiresAt"`
+}
+
+// AgentRegistration is the registration of a running agent instance and the activity types it executes.
+type AgentRegistration struct {
+	InstanceID    string         `json:"instanceId"`
+	Name          string         `json:"name"`
+	Version       string         `json:"version"`
+	ActivityTypes []ActivityType `json:"activityTypes"`
+	StartedAt     time.Time      `json:"startedAt"`
+	Heartbeat     time.Time      `json:"heartbeat"`
+}
+
 // ActivityMessage used to enqueue an activity for processing.
 type ActivityMessage struct {
 	OrchestrationID string   `json:"orchestrationID"`
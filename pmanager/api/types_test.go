@@ -16,7 +16,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/assert"
 )
@@ -690,3 +692,111 @@ func TestMappingEntry_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestOrchestration_Heartbeats(t *testing.T) {
+	now := time.Now()
+	orchestration := &Orchestration{}
+
+	orchestration.RecordHeartbeat("act1", now.Add(-10*time.Minute))
+	orchestration.RecordHeartbeat("act2", now.Add(-time.Second))
+	orchestration.RecordHeartbeat("act3", now.Add(-6*time.Minute))
+
+	assert.DeepEqual(t, orchestration.StaleActivities(now, 5*time.Minute), []string{"act1", "act3"})
+
+	orchestration.ClearHeartbeat("act1")
+	assert.DeepEqual(t, orchestration.StaleActivities(now, 5*time.Minute), []string{"act3"})
+	assert.Equal(t, len(orchestration.StaleActivities(now, time.Hour)), 0)
+}
+
+func TestOrchestration_GetActivity(t *testing.T) {
+	orchestration := &Orchestration{
+		Steps: []OrchestrationStep{
+			{Activities: []Activity{{ID: "act1", Type: "test"}}},
+			{Activities: []Activity{{ID: "act2", Type: "test2"}}},
+		},
+	}
+
+	activity, found := orchestration.GetActivity("act2")
+	require.True(t, found)
+	assert.Equal(t, activity.Type, ActivityType("test2"))
+
+	_, found = orchestration.GetActivity("missing")
+	require.False(t, found)
+}
+
+func TestOrchestration_OverallProgress(t *testing.T) {
+	orchestration := &Orchestration{
+		Steps: []OrchestrationStep{
+			{Activities: []Activity{{ID: "act1"}, {ID: "act2"}}},
+			{Activities: []Activity{{ID: "act3"}, {ID: "act4"}}},
+		},
+		Completed: map[string]struct{}{"act1": {}},
+	}
+	assert.Equal(t, orchestration.OverallProgress(), 25)
+
+	orchestration.RecordProgress("act2", ActivityProgress{Percent: 60, Message: "configuring", Timestamp: time.Now()})
+	assert.Equal(t, orchestration.OverallProgress(), 40)
+	assert.Equal(t, orchestration.Progress["act2"].Message, "configuring")
+
+	assert.Equal(t, (&Orchestration{}).OverallProgress(), 0)
+}
+
+func TestOrchestration_VPAPercents(t *testing.T) {
+	orchestration := &Orchestration{
+		Steps: []OrchestrationStep{
+			{Activities: []Activity{{ID: "edc"}, {ID: "keycloak"}}},
+			{Activities: []Activity{{ID: "register"}}},
+		},
+		ProcessingData: map[string]any{
+			model.VPAData: []any{
+				map[string]any{"id": "vpa1", "vpaType": "cfm.connector", "cellId": "cell1"},
+				map[string]any{"id": "vpa2", "vpaType": "cfm.credentialservice", "cellId": "cell1"},
+			},
+		},
+		OutputData: map[string]any{
+			model.VPAResultKey("vpa2", "keycloak"): model.VPAResult{State: model.VPAResultSucceeded},
+		},
+		Completed: map[string]struct{}{"keycloak": {}},
+	}
+	orchestration.RecordProgress("edc", ActivityProgress{VPAID: "vpa1", Percent: 50, Timestamp: time.Now()})
+	assert.Equal(t, orchestration.VPAProgress["vpa1"]["edc"].VPAID, "vpa1")
+	assert.Equal(t, len(orchestration.Progress), 0)
+
+	// The edc activity only counts for vpa1 and the keycloak activity only for vpa2; register counts for both
+	assert.DeepEqual(t, orchestration.VPAPercents(), map[string]int{"vpa1": 25, "vpa2": 50})
+	assert.Equal(t, orchestration.OverallProgress(), 50)
+
+	orchestration.Completed["edc"] = struct{}{}
+	orchestration.RecordProgress("register", ActivityProgress{Percent: 40, Timestamp: time.Now()})
+	assert.DeepEqual(t, orchestration.VPAPercents(), map[string]int{"vpa1": 70, "vpa2": 70})
+
+	assert.Assert(t, (&Orchestration{}).VPAPercents() == nil)
+
+	// VPA data of an orchestration that was instantiated and not read back is typed
+	orchestration.ProcessingData[model.VPAData] = []model.VPAManifest{{ID: "vpa1"}, {ID: "vpa2"}}
+	assert.DeepEqual(t, orchestration.VPAPercents(), map[string]int{"vpa1": 70, "vpa2": 70})
+}
+
+func TestOrchestration_PendingActivities(t *testing.T) {
+	orchestration := Orchestration{
+		State:     OrchestrationStateRunning,
+		Completed: map[string]struct{}{"A1": {}, "B1": {}},
+		Steps: []OrchestrationStep{
+			{Activities: []Activity{{ID: "A1"}}},
+			{Activities: []Activity{{ID: "B1"}, {ID: "B2"}}},
+			{Activities: []Activity{{ID: "C1"}}},
+		},
+	}
+
+	pending := orchestration.PendingActivities()
+	require.Len(t, pending, 1)
+	require.Equal(t, "B2", pending[0].ID)
+
+	orchestration.Completed["B2"] = struct{}{}
+	pending = orchestration.PendingActivities()
+	require.Len(t, pending, 1)
+	require.Equal(t, "C1", pending[0].ID)
+
+	orchestration.State = OrchestrationStateErrored
+	require.Empty(t, orchestration.PendingActivities())
+}
@@ -36,6 +36,9 @@ func main() {
 	generateOrchestrationEndpoints(r)
 	generateOrchestrationDefinitionEndpoints(r)
 	generateActivityDefinitionEndpoints(r)
+	generateActivityLeaseEndpoints(r)
+	generateAgentEndpoints(r)
+	generateScheduleEndpoints(r)
 
 	if _, err := os.Stat(docsDir); os.IsNotExist(err) {
 		if err := os.Mkdir(docsDir, 0755); err != nil {
@@ -53,9 +56,11 @@ func generateOrchestrationEndpoints(r spec.Generator) {
 
 	orchestrations.Post("",
 		option.Summary("Execute an Orchestration"),
-		option.Description("Execute an Orchestration"),
-		option.Request(model.OrchestrationManifest{}),
-		option.Response(http.StatusAccepted, nil),
+		option.Description("Execute an Orchestration. Requests repeated with the same Idempotency-Key return the Orchestration started by the first request; reusing a key with a different manifest returns 409. If wait is set, the request blocks until the Orchestration completes or fails and returns 200 with its final state, or 202 if it is still running when the wait expires."),
+		option.Request(new(CreateOrchestrationRequest)),
+		option.Response(http.StatusOK, v1alpha1.Orchestration{}),
+		option.Response(http.StatusAccepted, v1alpha1.Orchestration{}),
+		option.Response(http.StatusConflict, nil),
 	)
 
 	orchestrations.Post("query",
@@ -109,7 +114,7 @@ func generateOrchestrationDefinitionEndpoints(r spec.Generator) {
 		option.Summary("Create an Orchestration Definition"),
 		option.Description("Create a new Orchestration Definition"),
 		option.Request(v1alpha1.OrchestrationDefinition{}),
-		option.Response(http.StatusCreated, nil),
+		option.Response(http.StatusCreated, v1alpha1.OrchestrationDefinitionCreated{}),
 	)
 
 	orchestration.Delete("/{type}",
@@ -120,6 +125,141 @@ func generateOrchestrationDefinitionEndpoints(r spec.Generator) {
 
 }
 
+func generateActivityLeaseEndpoints(r spec.Generator) {
+	leases := r.Group("/api/v1alpha1/activity-leases")
+
+	leases.Post("",
+		option.Summary("Lease an Activity"),
+		option.Description("Lease the next Activity of the given type, waiting up to the given number of seconds for one to become available. Returns 204 if no Activity is available."),
+		option.Request(v1alpha1.ActivityLeaseRequest{}),
+		option.Response(http.StatusCreated, v1alpha1.ActivityLease{}),
+		option.Response(http.StatusNoContent, nil),
+	)
+
+	leases.Post("/{id}/heartbeat",
+		option.Summary("Heartbeat an Activity Lease"),
+		option.Description("Extend an Activity Lease and optionally report progress"),
+		option.Request(new(ActivityLeaseHeartbeatRequest)),
+		option.Response(http.StatusOK, v1alpha1.ActivityLease{}),
+	)
+
+	leases.Post("/{id}/complete",
+		option.Summary("Complete a leased Activity"),
+		option.Description("Complete a leased Activity, merging the processing and output data into the Orchestration"),
+		option.Request(new(ActivityLeaseCompletionRequest)),
+		option.Response(http.StatusOK, nil),
+	)
+
+	leases.Post("/{id}/fail",
+		option.Summary("Fail a leased Activity"),
+		option.Description("Fail a leased Activity. Retryable failures redeliver the Activity; otherwise, the Orchestration is put into the error state."),
+		option.Request(new(ActivityLeaseFailureRequest)),
+		option.Response(http.StatusOK, nil),
+	)
+}
+
+func generateAgentEndpoints(r spec.Generator) {
+	agents := r.Group("/api/v1alpha1/agents")
+
+	agents.Get("",
+		option.Summary("Get Agents"),
+		option.Description("Returns the registered agents and the activity types they execute"),
+		option.Response(http.StatusOK, []v1alpha1.Agent{}),
+	)
+
+	agents.Put("/{id}",
+		option.Summary("Register an Agent"),
+		option.Description("Register an agent that does not register through NATS, for example, an agent that leases Activities over HTTP, or refresh its registration. Registrations expire unless they are refreshed within the registration TTL."),
+		option.Request(new(AgentRegistrationRequest)),
+		option.Response(http.StatusOK, v1alpha1.Agent{}),
+	)
+
+	agents.Delete("/{id}",
+		option.Summary("Deregister an Agent"),
+		option.Description("Remove the registration of an agent"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusNoContent, nil),
+	)
+}
+
+func generateScheduleEndpoints(r spec.Generator) {
+	schedules := r.Group("/api/v1alpha1/schedules")
+
+	schedules.Get("",
+		option.Summary("Get Schedules"),
+		option.Description("Returns all Schedules"),
+		option.Response(http.StatusOK, []v1alpha1.Schedule{}),
+	)
+
+	schedules.Post("",
+		option.Summary("Create a Schedule"),
+		option.Description("Create a Schedule that starts Orchestrations of a type on a recurring cron schedule. String values in the payload template are rendered as Go templates with the ScheduleID, RunID, and ScheduledTime of each run."),
+		option.Request(v1alpha1.NewSchedule{}),
+		option.Response(http.StatusCreated, v1alpha1.Schedule{}),
+	)
+
+	schedules.Get("/{id}",
+		option.Summary("Get a Schedule"),
+		option.Description("Retrieve a Schedule by ID"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.Schedule{}),
+	)
+
+	schedules.Delete("/{id}",
+		option.Summary("Delete a Schedule"),
+		option.Description("Delete a Schedule and its run history"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, nil),
+	)
+
+	schedules.Post("/{id}/pause",
+		option.Summary("Pause a Schedule"),
+		option.Description("Stop a Schedule from starting Orchestrations until it is resumed"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.Schedule{}),
+	)
+
+	schedules.Post("/{id}/resume",
+		option.Summary("Resume a Schedule"),
+		option.Description("Resume a paused Schedule. Runs missed while the Schedule was paused are skipped."),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.Schedule{}),
+	)
+
+	schedules.Get("/{id}/runs",
+		option.Summary("Get Schedule runs"),
+		option.Description("Returns the past runs of a Schedule, most recent first"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, []v1alpha1.ScheduleRun{}),
+	)
+}
+
+type CreateOrchestrationRequest struct {
+	IdempotencyKey string `header:"Idempotency-Key" description:"Key used to de-duplicate repeated requests"`
+	Wait           string `query:"wait" description:"Time to wait for the Orchestration to complete, for example, 30s (maximum 60s)"`
+	model.OrchestrationManifest
+}
+
+type AgentRegistrationRequest struct {
+	IDParam
+	v1alpha1.AgentRegistration
+}
+
+type ActivityLeaseHeartbeatRequest struct {
+	IDParam
+	v1alpha1.ActivityLeaseHeartbeat
+}
+
+type ActivityLeaseCompletionRequest struct {
+	IDParam
+	v1alpha1.ActivityLeaseCompletion
+}
+
+type ActivityLeaseFailureRequest struct {
+	IDParam
+	v1alpha1.ActivityLeaseFailure
+}
+
 type TypeParam struct {
 	ID string `path:"type" required:"true"`
 }
@@ -16,7 +16,9 @@ import (
 	"fmt"
 
 	"github.com/metaform/connector-fabric-manager/assembly/httpclient"
+	"github.com/metaform/connector-fabric-manager/assembly/metrics"
 	"github.com/metaform/connector-fabric-manager/assembly/routing"
+	"github.com/metaform/connector-fabric-manager/assembly/tracing"
 	"github.com/metaform/connector-fabric-manager/common/runtime"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
@@ -69,6 +71,8 @@ func Launch(shutdown <-chan struct{}) {
 
 	assembler.Register(&httpclient.HttpClientServiceAssembly{})
 	assembler.Register(&routing.RouterServiceAssembly{})
+	assembler.Register(&metrics.MetricsServiceAssembly{})
+	assembler.Register(&tracing.TracingServiceAssembly{ServiceName: logPrefix})
 	assembler.Register(&handler.HandlerServiceAssembly{})
 
 	if vConfig.IsSet(postgresKey) {
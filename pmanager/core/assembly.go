@@ -13,42 +13,124 @@
 package core
 
 import (
+	"context"
+	"time"
+
 	store "github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 )
 
+const (
+	missingAgentPolicyKey = "agent.missing.policy"
+	scheduleIntervalKey   = "schedule.interval"
+	idempotencyTTLKey     = "idempotency.ttl"
+	idempotencyPurgeKey   = "idempotency.purge.interval"
+)
+
 type PMCoreServiceAssembly struct {
 	system.DefaultServiceAssembly
+	scheduler       *scheduler
+	purger          *idempotencyPurger
+	schedulerCancel context.CancelFunc
 }
 
-func (m PMCoreServiceAssembly) Name() string {
+func (m *PMCoreServiceAssembly) Name() string {
 	return "Provision Manager Core"
 }
 
-func (m PMCoreServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.ProvisionManagerKey, api.DefinitionManagerKey}
+func (m *PMCoreServiceAssembly) Provides() []system.ServiceType {
+	return []system.ServiceType{api.ProvisionManagerKey, api.DefinitionManagerKey, api.ScheduleManagerKey}
 }
 
-func (m PMCoreServiceAssembly) Requires() []system.ServiceType {
-	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestratorKey, store.TransactionContextKey}
+func (m *PMCoreServiceAssembly) Requires() []system.ServiceType {
+	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestratorKey, api.ScheduleStoreKey, api.ScheduleRunStoreKey, api.IdempotencyStoreKey, store.TransactionContextKey}
 }
 
-func (m PMCoreServiceAssembly) Init(context *system.InitContext) error {
+func (m *PMCoreServiceAssembly) Init(context *system.InitContext) error {
+	missingAgentPolicy, err := ParseMissingAgentPolicy(context.GetConfigStrOrDefault(missingAgentPolicyKey, string(MissingAgentPolicyWarn)))
+	if err != nil {
+		return err
+	}
+
+	var agentRegistry api.AgentRegistry
+	if registry, found := context.Registry.ResolveOptional(api.AgentRegistryKey); found {
+		agentRegistry = registry.(api.AgentRegistry)
+	}
+
 	definitionStore := context.Registry.Resolve(api.DefinitionStoreKey).(api.DefinitionStore)
 	transactionContext := context.Registry.Resolve(store.TransactionContextKey).(store.TransactionContext)
 
-	context.Registry.Register(api.ProvisionManagerKey, provisionManager{
-		orchestrator: context.Registry.Resolve(api.OrchestratorKey).(api.Orchestrator),
-		index:        context.Registry.Resolve(api.OrchestrationIndexKey).(store.EntityStore[*api.OrchestrationEntry]),
-		store:        definitionStore,
-		trxContext:   transactionContext,
-		monitor:      context.LogMonitor,
-	})
+	idempotencyStore := context.Registry.Resolve(api.IdempotencyStoreKey).(store.EntityStore[*api.IdempotencyRecord])
+	idempotencyTTL := time.Duration(context.GetConfigIntOrDefault(idempotencyTTLKey, int(defaultIdempotencyTTL.Seconds()))) * time.Second
+	provisionManager := provisionManager{
+		orchestrator:     context.Registry.Resolve(api.OrchestratorKey).(api.Orchestrator),
+		index:            context.Registry.Resolve(api.OrchestrationIndexKey).(store.EntityStore[*api.OrchestrationEntry]),
+		idempotencyStore: idempotencyStore,
+		idempotencyTTL:   idempotencyTTL,
+		waitInterval:     defaultWaitInterval,
+		store:            definitionStore,
+		trxContext:       transactionContext,
+		agentRegistry:    agentRegistry,
+		monitor:          context.LogMonitor,
+	}
+	context.Registry.Register(api.ProvisionManagerKey, provisionManager)
 
 	context.Registry.Register(api.DefinitionManagerKey, definitionManager{
-		trxContext: transactionContext,
-		store:      definitionStore,
+		trxContext:         transactionContext,
+		store:              definitionStore,
+		agentRegistry:      agentRegistry,
+		missingAgentPolicy: missingAgentPolicy,
+		monitor:            context.LogMonitor,
+	})
+
+	schedules := context.Registry.Resolve(api.ScheduleStoreKey).(store.EntityStore[*api.Schedule])
+	runs := context.Registry.Resolve(api.ScheduleRunStoreKey).(store.EntityStore[*api.ScheduleRun])
+	context.Registry.Register(api.ScheduleManagerKey, scheduleManager{
+		trxContext:      transactionContext,
+		definitionStore: definitionStore,
+		schedules:       schedules,
+		runs:            runs,
+		now:             time.Now,
 	})
+
+	var leaderElection api.LeaderElection
+	if election, found := context.Registry.ResolveOptional(api.LeaderElectionKey); found {
+		leaderElection = election.(api.LeaderElection)
+	}
+	m.scheduler = &scheduler{
+		provisionManager: provisionManager,
+		schedules:        schedules,
+		runs:             runs,
+		trxContext:       store.WithConflictRetry(transactionContext),
+		leaderElection:   leaderElection,
+		interval:         time.Duration(context.GetConfigIntOrDefault(scheduleIntervalKey, int(defaultSchedulerInterval.Seconds()))) * time.Second,
+		monitor:          context.LogMonitor,
+		now:              time.Now,
+	}
+	m.purger = &idempotencyPurger{
+		trxContext:       transactionContext,
+		idempotencyStore: idempotencyStore,
+		leaderElection:   leaderElection,
+		ttl:              idempotencyTTL,
+		interval:         time.Duration(context.GetConfigIntOrDefault(idempotencyPurgeKey, int(defaultIdempotencyPurgeInterval.Seconds()))) * time.Second,
+		monitor:          context.LogMonitor,
+		now:              time.Now,
+	}
+	return nil
+}
+
+func (m *PMCoreServiceAssembly) Start(_ *system.StartContext) error {
+	schedulerContext, cancel := context.WithCancel(context.Background())
+	m.schedulerCancel = cancel
+	m.scheduler.start(schedulerContext)
+	m.purger.start(schedulerContext)
+	return nil
+}
+
+func (m *PMCoreServiceAssembly) Shutdown() error {
+	if m.schedulerCancel != nil {
+		m.schedulerCancel()
+	}
 	return nil
 }
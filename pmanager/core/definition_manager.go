@@ -15,21 +15,55 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 )
 
+// MissingAgentPolicy determines how orchestration definitions that reference activity types without a registered agent
+// are handled.
+type MissingAgentPolicy string
+
+const (
+	MissingAgentPolicyIgnore MissingAgentPolicy = "ignore"
+	MissingAgentPolicyWarn   MissingAgentPolicy = "warn"
+	MissingAgentPolicyReject MissingAgentPolicy = "reject"
+)
+
+// ParseMissingAgentPolicy converts the given value to a MissingAgentPolicy.
+func ParseMissingAgentPolicy(value string) (MissingAgentPolicy, error) {
+	switch MissingAgentPolicy(strings.ToLower(value)) {
+	case MissingAgentPolicyIgnore:
+		return MissingAgentPolicyIgnore, nil
+	case MissingAgentPolicyWarn:
+		return MissingAgentPolicyWarn, nil
+	case MissingAgentPolicyReject:
+		return MissingAgentPolicyReject, nil
+	default:
+		return "", fmt.Errorf("invalid missing agent policy: %s", value)
+	}
+}
+
 type definitionManager struct {
-	trxContext store.TransactionContext
-	store      api.DefinitionStore
+	trxContext         store.TransactionContext
+	store              api.DefinitionStore
+	agentRegistry      api.AgentRegistry
+	missingAgentPolicy MissingAgentPolicy
+	monitor            system.LogMonitor
 }
 
-func (d definitionManager) CreateOrchestrationDefinition(ctx context.Context, definition *api.OrchestrationDefinition) (*api.OrchestrationDefinition, error) {
-	return store.Trx[api.OrchestrationDefinition](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.OrchestrationDefinition, error) {
+func (d definitionManager) CreateOrchestrationDefinition(
+	ctx context.Context,
+	definition *api.OrchestrationDefinition) (*api.OrchestrationDefinition, []api.ActivityType, error) {
+
+	var missingAgents []api.ActivityType
+	persisted, err := store.Trx[api.OrchestrationDefinition](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.OrchestrationDefinition, error) {
 		var missingErrors []error
 
 		// Verify that all referenced activities exist
@@ -47,12 +81,56 @@ func (d definitionManager) CreateOrchestrationDefinition(ctx context.Context, de
 			return nil, errors.Join(missingErrors...)
 		}
 
+		missing, err := d.checkAgents(ctx, definition)
+		if err != nil {
+			return nil, err
+		}
+
 		persisted, err := d.store.StoreOrchestrationDefinition(ctx, definition)
 		if err != nil {
 			return nil, err
 		}
+		missingAgents = missing
 		return persisted, nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return persisted, missingAgents, nil
+}
+
+// checkAgents verifies that the activity types referenced by the definition have a registered agent, applying the
+// missing agent policy. If the policy is to warn, the activity types without an agent are returned.
+func (d definitionManager) checkAgents(ctx context.Context, definition *api.OrchestrationDefinition) ([]api.ActivityType, error) {
+	if d.agentRegistry == nil || d.missingAgentPolicy == MissingAgentPolicyIgnore {
+		return nil, nil
+	}
+
+	available, err := d.agentRegistry.GetActivityTypes(ctx)
+	if err != nil {
+		// Agent availability is advisory, do not block definitions when it cannot be determined
+		d.monitor.Warnf("Unable to verify agents for orchestration definition %s: %v", definition.Type, err)
+		return nil, nil
+	}
+
+	var missing []api.ActivityType
+	var names []string
+	for _, activity := range definition.Activities {
+		if _, found := available[activity.Type]; !found && !slices.Contains(missing, activity.Type) {
+			missing = append(missing, activity.Type)
+			names = append(names, activity.Type.String())
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	if d.missingAgentPolicy == MissingAgentPolicyReject {
+		return nil, types.NewClientError("no agent registered for activity types: %s", strings.Join(names, ", "))
+	}
+	d.monitor.Warnf("Orchestration definition %s references activity types without a registered agent: %s",
+		definition.Type, strings.Join(names, ", "))
+	return missing, nil
 }
 
 func (d definitionManager) DeleteOrchestrationDefinition(
@@ -61,12 +139,15 @@ func (d definitionManager) DeleteOrchestrationDefinition(
 	orchestrationType model.OrchestrationType) error {
 
 	return d.trxContext.Execute(ctx, func(ctx context.Context) error {
-		exists, err := d.store.ExistsOrchestrationDefinition(ctx, orchestrationType)
+		definition, err := d.store.FindOrchestrationDefinition(ctx, orchestrationType)
 		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				return err
+			}
 			return types.NewRecoverableWrappedError(err, "failed to check orchestration definition for type %s", orchestrationType)
 		}
-		if !exists {
-			return types.ErrNotFound
+		if err = store.CheckExpectedVersion(ctx, definition); err != nil {
+			return err
 		}
 
 		deleted, err := d.store.DeleteOrchestrationDefinition(ctx, orchestrationType)
@@ -105,12 +186,15 @@ func (d definitionManager) CreateActivityDefinition(ctx context.Context, definit
 
 func (d definitionManager) DeleteActivityDefinition(ctx context.Context, atype api.ActivityType) error {
 	return d.trxContext.Execute(ctx, func(ctx context.Context) error {
-		exists, err := d.store.ExistsActivityDefinition(ctx, atype)
+		definition, err := d.store.FindActivityDefinition(ctx, atype)
 		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				return err
+			}
 			return types.NewRecoverableWrappedError(err, "failed to check activity definition for type %s", atype)
 		}
-		if !exists {
-			return types.ErrNotFound
+		if err = store.CheckExpectedVersion(ctx, definition); err != nil {
+			return err
 		}
 		referenced, err := d.store.ActivityDefinitionReferences(ctx, atype)
 
@@ -21,10 +21,13 @@ import (
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	cstore "github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 	"github.com/metaform/connector-fabric-manager/pmanager/memorystore"
+	"github.com/metaform/connector-fabric-manager/pmanager/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,7 +65,7 @@ func TestDefinitionManager_CreateOrchestrationDefinition_Success(t *testing.T) {
 		},
 	}
 
-	result, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.NoError(t, err, "CreateOrchestrationDefinition should succeed")
 	assert.NotNil(t, result, "Result should not be nil")
@@ -95,7 +98,7 @@ func TestDefinitionManager_CreateOrchestrationDefinition_MissingActivityDefiniti
 		},
 	}
 
-	result, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.Error(t, err, "CreateOrchestrationDefinition should fail when activity definition is missing")
 	assert.Nil(t, result, "Result should be nil on error")
@@ -107,6 +110,73 @@ func TestDefinitionManager_CreateOrchestrationDefinition_MissingActivityDefiniti
 		"Error message should mention the missing activity type")
 }
 
+func TestDefinitionManager_CreateOrchestrationDefinition_MissingAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    MissingAgentPolicy
+		expectErr bool
+		missing   []api.ActivityType
+	}{
+		{name: "warn", policy: MissingAgentPolicyWarn, missing: []api.ActivityType{"agentless-activity"}},
+		{name: "ignore", policy: MissingAgentPolicyIgnore},
+		{name: "reject", policy: MissingAgentPolicyReject, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := memorystore.NewDefinitionStore()
+			_, err := store.StoreActivityDefinition(ctx, &api.ActivityDefinition{Type: "test-activity"})
+			require.NoError(t, err)
+			_, err = store.StoreActivityDefinition(ctx, &api.ActivityDefinition{Type: "agentless-activity"})
+			require.NoError(t, err)
+
+			registry := mocks.NewMockAgentRegistry(t)
+			registry.EXPECT().GetActivityTypes(mock.Anything).
+				Return(map[api.ActivityType]struct{}{"test-activity": {}}, nil).Maybe()
+
+			manager := definitionManager{
+				trxContext:         cstore.NoOpTransactionContext{},
+				store:              store,
+				agentRegistry:      registry,
+				missingAgentPolicy: tt.policy,
+				monitor:            system.NoopMonitor{},
+			}
+
+			definition := &api.OrchestrationDefinition{
+				Type: "test-orchestration",
+				Activities: []api.Activity{
+					{ID: "activity-1", Type: "test-activity"},
+					{ID: "activity-2", Type: "agentless-activity"},
+				},
+			}
+
+			result, missing, err := manager.CreateOrchestrationDefinition(ctx, definition)
+			if tt.expectErr {
+				require.Error(t, err)
+				var clientErr types.ClientError
+				require.True(t, errors.As(err, &clientErr), "Error should be a ClientError")
+				assert.Contains(t, err.Error(), "agentless-activity")
+				assert.NotContains(t, err.Error(), "test-activity")
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, result)
+			assert.Equal(t, tt.missing, missing)
+		})
+	}
+}
+
+func TestParseMissingAgentPolicy(t *testing.T) {
+	policy, err := ParseMissingAgentPolicy("Reject")
+	require.NoError(t, err)
+	assert.Equal(t, MissingAgentPolicyReject, policy)
+
+	_, err = ParseMissingAgentPolicy("invalid")
+	require.Error(t, err)
+}
+
 func TestDefinitionManager_CreateOrchestrationDefinition_MultipleMissingActivityDefinitions(t *testing.T) {
 	store := memorystore.NewDefinitionStore()
 	manager := definitionManager{
@@ -152,7 +222,7 @@ func TestDefinitionManager_CreateOrchestrationDefinition_MultipleMissingActivity
 		},
 	}
 
-	result, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.Error(t, err, "CreateOrchestrationDefinition should fail when multiple activity definitions are missing")
 	assert.Nil(t, result, "Result should be nil on error")
@@ -178,7 +248,7 @@ func TestDefinitionManager_CreateOrchestrationDefinition_EmptyActivities(t *test
 		Activities: []api.Activity{}, // Empty activities slice
 	}
 
-	result, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.NoError(t, err, "CreateOrchestrationDefinition should succeed with empty activities")
 	assert.NotNil(t, result, "Result should not be nil")
@@ -205,7 +275,7 @@ func TestDefinitionManager_CreateOrchestrationDefinition_StoreError(t *testing.T
 	require.NoError(t, err, "First store should succeed")
 
 	// Attempt to store the same orchestration definition again
-	result, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.Error(t, err, "CreateOrchestrationDefinition should fail on duplicate")
 	assert.Nil(t, result, "Result should be nil on error")
@@ -318,7 +388,7 @@ func TestDefinitionManager_Integration_CompleteWorkflow(t *testing.T) {
 		},
 	}
 
-	result3, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
+	result3, _, err := manager.CreateOrchestrationDefinition(ctx, orchestrationDef)
 
 	require.NoError(t, err, "Should create orchestration definition")
 	assert.NotNil(t, result3, "Result should not be nil")
@@ -390,6 +460,25 @@ func TestDefinitionManager_DeleteOrchestrationDefinition_NotFound(t *testing.T)
 	assert.True(t, errors.Is(err, types.ErrNotFound), "Error should be ErrNotFound")
 }
 
+func TestDefinitionManager_DeleteOrchestrationDefinition_PreconditionFailed(t *testing.T) {
+	store := memorystore.NewDefinitionStore()
+	manager := definitionManager{
+		trxContext: cstore.NoOpTransactionContext{},
+		store:      store,
+	}
+
+	ctx := context.Background()
+	definition, err := store.StoreOrchestrationDefinition(ctx, &api.OrchestrationDefinition{Type: "test-orchestration"})
+	require.NoError(t, err)
+
+	err = manager.DeleteOrchestrationDefinition(cstore.WithExpectedVersion(ctx, "test-orchestration", definition.Version+1), definition.Type)
+
+	assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+	exists, err := store.ExistsOrchestrationDefinition(ctx, definition.Type)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
 func TestDefinitionManager_DeleteOrchestrationDefinition_ExistsCheckError(t *testing.T) {
 	mockStore := newMockDefinitionStore()
 	mockStore.simulateError("existsOrchestration")
@@ -606,6 +695,25 @@ func TestDefinitionManager_DeleteActivityDefinition_ReferencedByOrchestration(t
 	assert.True(t, exists, "Activity definition should still exist after failed deletion")
 }
 
+func TestDefinitionManager_DeleteActivityDefinition_PreconditionFailed(t *testing.T) {
+	store := memorystore.NewDefinitionStore()
+	manager := definitionManager{
+		trxContext: cstore.NoOpTransactionContext{},
+		store:      store,
+	}
+
+	ctx := context.Background()
+	definition, err := store.StoreActivityDefinition(ctx, &api.ActivityDefinition{Type: "test-activity"})
+	require.NoError(t, err)
+
+	err = manager.DeleteActivityDefinition(cstore.WithExpectedVersion(ctx, "test-activity", definition.Version+1), definition.Type)
+
+	assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+	exists, err := store.ExistsActivityDefinition(ctx, definition.Type)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
 func TestDefinitionManager_DeleteActivityDefinition_ExistsCheckError(t *testing.T) {
 	mockStore := newMockDefinitionStore()
 	mockStore.simulateError("existsActivity")
@@ -847,16 +955,28 @@ func (m *mockDefinitionStore) setDeleteActivityDefinitionReturned(returned bool)
 	m.state["deleteActivityReturned"] = returned
 }
 
-func (m *mockDefinitionStore) FindOrchestrationDefinition(context.Context, model.OrchestrationType) (*api.OrchestrationDefinition, error) {
-	return nil, types.ErrNotFound
+func (m *mockDefinitionStore) FindOrchestrationDefinition(_ context.Context, orchestrationType model.OrchestrationType) (*api.OrchestrationDefinition, error) {
+	if err, exists := m.simulatedErrors["existsOrchestration"]; exists {
+		return nil, err
+	}
+	if !m.state["orchestrationExists"] {
+		return nil, types.ErrNotFound
+	}
+	return &api.OrchestrationDefinition{Type: orchestrationType}, nil
 }
 
 func (m *mockDefinitionStore) FindOrchestrationDefinitionsByPredicate(context.Context, query.Predicate) iter.Seq2[api.OrchestrationDefinition, error] {
 	return nil
 }
 
-func (m *mockDefinitionStore) FindActivityDefinition(context.Context, api.ActivityType) (*api.ActivityDefinition, error) {
-	return nil, types.ErrNotFound
+func (m *mockDefinitionStore) FindActivityDefinition(_ context.Context, activityType api.ActivityType) (*api.ActivityDefinition, error) {
+	if err, exists := m.simulatedErrors["existsActivity"]; exists {
+		return nil, err
+	}
+	if !m.state["activityExists"] {
+		return nil, types.ErrNotFound
+	}
+	return &api.ActivityDefinition{Type: activityType}, nil
 }
 
 func (m *mockDefinitionStore) FindActivityDefinitionsByPredicate(context.Context, query.Predicate) iter.Seq2[api.ActivityDefinition, error] {
@@ -0,0 +1,82 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+)
+
+const defaultIdempotencyPurgeInterval = time.Hour
+
+// idempotencyPurger removes idempotency records that are older than the idempotency TTL. Expired records are replaced
+// when their key is reused, but keys are usually not reused, so the records must be removed. Only the leader purges
+// records when a leader election is configured.
+type idempotencyPurger struct {
+	trxContext       store.TransactionContext
+	idempotencyStore store.EntityStore[*api.IdempotencyRecord]
+	leaderElection   api.LeaderElection
+	ttl              time.Duration
+	interval         time.Duration
+	monitor          system.LogMonitor
+	now              func() time.Time
+}
+
+// start purges expired records at the purge interval until the context is canceled.
+func (p *idempotencyPurger) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if p.leaderElection != nil && !p.leaderElection.IsLeader() {
+					continue
+				}
+				if err := p.purge(ctx); err != nil {
+					p.monitor.Warnf("Error purging idempotency records: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// purge removes the records created before the TTL.
+func (p *idempotencyPurger) purge(ctx context.Context) error {
+	cutoff := p.now().Add(-p.ttl)
+	return p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		var expired []string
+		for record, err := range p.idempotencyStore.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			if record.CreatedTimestamp.Before(cutoff) {
+				expired = append(expired, record.ID)
+			}
+		}
+		for _, id := range expired {
+			if err := p.idempotencyStore.Delete(ctx, id); err != nil && !errors.Is(err, types.ErrNotFound) {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmemorystore "github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyPurger_Purge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	purger := newTestIdempotencyPurger(now)
+	createIdempotencyRecord(t, purger, "expired", now.Add(-25*time.Hour))
+	createIdempotencyRecord(t, purger, "recent", now.Add(-time.Hour))
+
+	require.NoError(t, purger.purge(ctx))
+
+	_, err := purger.idempotencyStore.FindByID(ctx, "recent")
+	require.NoError(t, err)
+	count, err := purger.idempotencyStore.GetAllCount(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestIdempotencyPurger_NotLeader(t *testing.T) {
+	now := time.Now()
+	purger := newTestIdempotencyPurger(now)
+	purger.interval = 10 * time.Millisecond
+	purger.leaderElection = fixedLeaderElection(false)
+	createIdempotencyRecord(t, purger, "expired", now.Add(-25*time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	purger.start(ctx)
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	_, err := purger.idempotencyStore.FindByID(context.Background(), "expired")
+	require.NoError(t, err)
+}
+
+func newTestIdempotencyPurger(now time.Time) *idempotencyPurger {
+	return &idempotencyPurger{
+		trxContext:       store.NoOpTransactionContext{},
+		idempotencyStore: cmemorystore.NewInMemoryEntityStore[*api.IdempotencyRecord](),
+		ttl:              defaultIdempotencyTTL,
+		interval:         time.Hour,
+		monitor:          system.NoopMonitor{},
+		now:              func() time.Time { return now },
+	}
+}
+
+func createIdempotencyRecord(t *testing.T, purger *idempotencyPurger, key string, created time.Time) {
+	_, err := purger.idempotencyStore.Create(context.Background(), &api.IdempotencyRecord{
+		ID:               key,
+		OrchestrationID:  "orchestration-" + key,
+		State:            api.IdempotencyStateStarted,
+		CreatedTimestamp: created,
+	})
+	require.NoError(t, err)
+}
+
+// fixedLeaderElection reports a fixed leadership state.
+type fixedLeaderElection bool
+
+func (f fixedLeaderElection) IsLeader() bool {
+	return bool(f)
+}
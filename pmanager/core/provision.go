@@ -14,8 +14,12 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"iter"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
@@ -25,12 +29,25 @@ import (
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 )
 
+const (
+	defaultIdempotencyTTL = 24 * time.Hour
+	defaultWaitInterval   = 250 * time.Millisecond
+
+	// idempotencyPendingTimeout is the time after which a key reserved by a request that did not start the
+	// orchestration, for example, because the instance stopped, can be used by a retry
+	idempotencyPendingTimeout = time.Minute
+)
+
 type provisionManager struct {
-	orchestrator api.Orchestrator
-	store        api.DefinitionStore
-	index        store.EntityStore[*api.OrchestrationEntry]
-	trxContext   store.TransactionContext
-	monitor      system.LogMonitor
+	orchestrator     api.Orchestrator
+	store            api.DefinitionStore
+	index            store.EntityStore[*api.OrchestrationEntry]
+	idempotencyStore store.EntityStore[*api.IdempotencyRecord]
+	idempotencyTTL   time.Duration
+	waitInterval     time.Duration
+	trxContext       store.TransactionContext
+	agentRegistry    api.AgentRegistry
+	monitor          system.LogMonitor
 }
 
 func (p provisionManager) Start(ctx context.Context, manifest *model.OrchestrationManifest) (*api.Orchestration, error) {
@@ -86,13 +103,212 @@ func (p provisionManager) Start(ctx context.Context, manifest *model.Orchestrati
 	return orchestration, nil
 }
 
+func (p provisionManager) StartIdempotent(
+	ctx context.Context,
+	idempotencyKey string,
+	manifest *model.OrchestrationManifest) (*api.Orchestration, error) {
+	if idempotencyKey == "" {
+		return p.Start(ctx, manifest)
+	}
+
+	requestHash, err := hashManifest(manifest)
+	if err != nil {
+		return nil, types.NewClientWrappedError(err, "invalid manifest")
+	}
+
+	// Reserve the key before starting the orchestration so that concurrent requests with the key start it only once
+	record := &api.IdempotencyRecord{
+		ID:               idempotencyKey,
+		RequestHash:      requestHash,
+		OrchestrationID:  manifest.ID,
+		State:            api.IdempotencyStatePending,
+		CreatedTimestamp: time.Now(),
+	}
+	existing, err := p.reserveIdempotencyKey(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.RequestHash != requestHash {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "idempotency key %s was used with a different request", idempotencyKey)
+		}
+		orchestration, err := p.GetOrchestration(ctx, existing.OrchestrationID)
+		if err != nil {
+			return nil, err
+		}
+		if orchestration != nil {
+			return orchestration, nil
+		}
+		if existing.State == api.IdempotencyStatePending && time.Since(existing.CreatedTimestamp) < idempotencyPendingTimeout {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "a request with idempotency key %s is in progress", idempotencyKey)
+		}
+		// The orchestration was removed or the request that reserved the key did not start it, start it again
+		if err = p.replaceIdempotencyRecord(ctx, existing, record); err != nil {
+			return nil, err
+		}
+	}
+
+	orchestration, err := p.Start(ctx, manifest)
+	if err != nil {
+		p.releaseIdempotencyKey(ctx, record)
+		return nil, err
+	}
+
+	record.OrchestrationID = orchestration.ID
+	record.State = api.IdempotencyStateStarted
+	err = p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		return p.idempotencyStore.Update(ctx, record)
+	})
+	if err != nil {
+		// The orchestration was started, a retry with the same key finds it by the reserved orchestration ID
+		p.monitor.Warnf("Unable to record idempotency key %s for orchestration %s: %v", idempotencyKey, orchestration.ID, err)
+	}
+	return orchestration, nil
+}
+
+// reserveIdempotencyKey creates the pending record for the key. If the key is already in use, the existing record is
+// returned. Expired records are replaced.
+func (p provisionManager) reserveIdempotencyKey(ctx context.Context, record *api.IdempotencyRecord) (*api.IdempotencyRecord, error) {
+	err := p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		_, err := p.idempotencyStore.Create(ctx, record)
+		return err
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, types.ErrConflict) {
+		return nil, types.NewFatalWrappedError(err, "error reserving idempotency key %s", record.ID)
+	}
+
+	// The key is in use. It is read in a separate transaction since the failed insert aborts the transaction.
+	var existing *api.IdempotencyRecord
+	err = p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		found, err := p.idempotencyStore.FindByID(ctx, record.ID)
+		if err != nil {
+			return err
+		}
+		existing = found
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, types.ErrNotFound) {
+			// Released by a concurrent request that failed to start the orchestration
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "a request with idempotency key %s is in progress", record.ID)
+		}
+		return nil, types.NewFatalWrappedError(err, "error reading idempotency key %s", record.ID)
+	}
+	if time.Since(existing.CreatedTimestamp) <= p.idempotencyTTL {
+		return existing, nil
+	}
+	return nil, p.replaceIdempotencyRecord(ctx, existing, record)
+}
+
+// replaceIdempotencyRecord replaces the existing record for the key. If a concurrent request replaced the record first,
+// a conflict is returned.
+func (p provisionManager) replaceIdempotencyRecord(ctx context.Context, existing *api.IdempotencyRecord, record *api.IdempotencyRecord) error {
+	record.Version = existing.Version
+	err := p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		return p.idempotencyStore.Update(ctx, record)
+	})
+	if err != nil {
+		if errors.Is(err, types.ErrConflict) || errors.Is(err, types.ErrNotFound) {
+			return types.NewRecoverableWrappedError(types.ErrConflict, "a request with idempotency key %s is in progress", record.ID)
+		}
+		return types.NewFatalWrappedError(err, "error reserving idempotency key %s", record.ID)
+	}
+	return nil
+}
+
+// releaseIdempotencyKey removes the pending record for the key after the orchestration could not be started so that
+// the request can be retried.
+func (p provisionManager) releaseIdempotencyKey(ctx context.Context, record *api.IdempotencyRecord) {
+	err := p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		return p.idempotencyStore.Delete(ctx, record.ID)
+	})
+	if err != nil {
+		p.monitor.Warnf("Unable to release idempotency key %s: %v", record.ID, err)
+	}
+}
+
+func (p provisionManager) WaitForOrchestration(
+	ctx context.Context,
+	orchestrationID string,
+	timeout time.Duration) (*api.Orchestration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(p.waitInterval)
+	defer ticker.Stop()
+	for {
+		orchestration, err := p.orchestrator.GetOrchestration(ctx, orchestrationID)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if err == nil {
+			if orchestration == nil {
+				return nil, types.NewRecoverableWrappedError(types.ErrNotFound, "orchestration %s not found", orchestrationID)
+			}
+			if orchestration.IsTerminal() {
+				return orchestration, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			// Return the latest state using a context that is not expired
+			return p.GetOrchestration(context.WithoutCancel(ctx), orchestrationID)
+		case <-ticker.C:
+		}
+	}
+}
+
+// hashManifest returns a hash of the manifest used to detect reuse of an idempotency key with a different request. The
+// manifest ID is excluded so that a retry with a regenerated ID is treated as the same request.
+func hashManifest(manifest *model.OrchestrationManifest) (string, error) {
+	withoutID := *manifest
+	withoutID.ID = ""
+	serialized, err := json.Marshal(withoutID)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(serialized)
+	return hex.EncodeToString(hash[:]), nil
+}
+
 func (p provisionManager) Cancel(ctx context.Context, orchestrationID string) error {
 	//TODO implement me
 	panic("implement me")
 }
 
 func (p provisionManager) GetOrchestration(ctx context.Context, orchestrationID string) (*api.Orchestration, error) {
-	return p.orchestrator.GetOrchestration(ctx, orchestrationID)
+	orchestration, err := p.orchestrator.GetOrchestration(ctx, orchestrationID)
+	if err != nil || orchestration == nil {
+		return orchestration, err
+	}
+	p.flagStalledActivities(ctx, orchestration)
+	return orchestration, nil
+}
+
+// flagStalledActivities records the pending activities of the orchestration whose type has no registered agent.
+func (p provisionManager) flagStalledActivities(ctx context.Context, orchestration *api.Orchestration) {
+	if p.agentRegistry == nil {
+		return
+	}
+	pending := orchestration.PendingActivities()
+	if len(pending) == 0 {
+		return
+	}
+
+	available, err := p.agentRegistry.GetActivityTypes(ctx)
+	if err != nil {
+		p.monitor.Warnf("Unable to determine agents for orchestration %s: %v", orchestration.ID, err)
+		return
+	}
+	for _, activity := range pending {
+		if _, found := available[activity.Type]; !found {
+			orchestration.StalledActivities = append(orchestration.StalledActivities, activity.ID)
+		}
+	}
 }
 
 func (p provisionManager) QueryOrchestrations(
@@ -16,7 +16,10 @@ import (
 	"context"
 	"errors"
 	"iter"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
@@ -27,6 +30,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	cmemorystore "github.com/metaform/connector-fabric-manager/common/memorystore"
 	cmocks "github.com/metaform/connector-fabric-manager/common/mocks"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 	"github.com/metaform/connector-fabric-manager/pmanager/memorystore"
@@ -611,6 +615,55 @@ func TestQueryOrchestrations_ComplexPredicate(t *testing.T) {
 	mockEntityStore.AssertExpectations(t)
 }
 
+func TestProvisionManager_GetOrchestration_FlagsStalledActivities(t *testing.T) {
+	ctx := context.Background()
+	orchestration := &api.Orchestration{
+		ID:        "orch-1",
+		State:     api.OrchestrationStateInitialized,
+		Completed: map[string]struct{}{"activity1": {}},
+		Steps: []api.OrchestrationStep{
+			{Activities: []api.Activity{{ID: "activity1", Type: "test-activity"}}},
+			{Activities: []api.Activity{
+				{ID: "activity2", Type: "test-activity"},
+				{ID: "activity3", Type: "agentless-activity"},
+			}},
+			{Activities: []api.Activity{{ID: "activity4", Type: "other-agentless-activity"}}},
+		},
+	}
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(ctx, "orch-1").Return(orchestration, nil)
+	registry := mocks.NewMockAgentRegistry(t)
+	registry.EXPECT().GetActivityTypes(ctx).Return(map[api.ActivityType]struct{}{"test-activity": {}}, nil)
+
+	pm := provisionManager{orchestrator: mockOrch, agentRegistry: registry, monitor: system.NoopMonitor{}}
+
+	result, err := pm.GetOrchestration(ctx, "orch-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"activity3"}, result.StalledActivities)
+}
+
+func TestProvisionManager_GetOrchestration_CompletedNotStalled(t *testing.T) {
+	ctx := context.Background()
+	orchestration := &api.Orchestration{
+		ID:    "orch-1",
+		State: api.OrchestrationStateCompleted,
+		Steps: []api.OrchestrationStep{
+			{Activities: []api.Activity{{ID: "activity1", Type: "agentless-activity"}}},
+		},
+	}
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(ctx, "orch-1").Return(orchestration, nil)
+
+	// The registry is not consulted for terminal orchestrations
+	pm := provisionManager{orchestrator: mockOrch, agentRegistry: mocks.NewMockAgentRegistry(t), monitor: system.NoopMonitor{}}
+
+	result, err := pm.GetOrchestration(ctx, "orch-1")
+	require.NoError(t, err)
+	assert.Empty(t, result.StalledActivities)
+}
+
 // Helper function to create a test orchestration definition
 func createTestOrchestrationDefinition(orchestrationType string) *api.OrchestrationDefinition {
 	return &api.OrchestrationDefinition{
@@ -623,3 +676,239 @@ func createTestOrchestrationDefinition(orchestrationType string) *api.Orchestrat
 		},
 	}
 }
+
+func TestProvisionManager_StartIdempotent(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	started := &api.Orchestration{ID: "orchestration-1", State: api.OrchestrationStateInitialized}
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(nil, nil).Once()
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(nil).Once()
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(started, nil)
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+	manifest := &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type", Payload: map[string]any{"key": "value"}}
+
+	result, err := pm.StartIdempotent(ctx, "key-1", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "orchestration-1", result.ID)
+
+	// The repeated request returns the stored orchestration without starting it again
+	result, err = pm.StartIdempotent(ctx, "key-1", manifest)
+	require.NoError(t, err)
+	assert.Same(t, started, result)
+
+	// Reusing the key with a different request is a conflict
+	changed := &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type", Payload: map[string]any{"key": "other"}}
+	_, err = pm.StartIdempotent(ctx, "key-1", changed)
+	require.ErrorIs(t, err, types.ErrConflict)
+}
+
+func TestProvisionManager_StartIdempotent_ExpiredKey(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-2").Return(nil, nil)
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(nil)
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+	_, err := pm.idempotencyStore.Create(ctx, &api.IdempotencyRecord{
+		ID:               "key-1",
+		RequestHash:      "previous",
+		OrchestrationID:  "orchestration-1",
+		CreatedTimestamp: time.Now().Add(-2 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	result, err := pm.StartIdempotent(ctx, "key-1", &model.OrchestrationManifest{ID: "orchestration-2", OrchestrationType: "test-type"})
+	require.NoError(t, err)
+	assert.Equal(t, "orchestration-2", result.ID)
+
+	record, err := pm.idempotencyStore.FindByID(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "orchestration-2", record.OrchestrationID)
+}
+
+func TestProvisionManager_StartIdempotent_RegeneratedID(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	started := &api.Orchestration{ID: "orchestration-1", State: api.OrchestrationStateInitialized}
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(nil, nil).Once()
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(nil).Once()
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(started, nil)
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+
+	_, err := pm.StartIdempotent(ctx, "key-1", &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type"})
+	require.NoError(t, err)
+
+	// A retry that regenerated the manifest ID is the same request and returns the orchestration started first
+	result, err := pm.StartIdempotent(ctx, "key-1", &model.OrchestrationManifest{ID: "orchestration-2", OrchestrationType: "test-type"})
+	require.NoError(t, err)
+	assert.Same(t, started, result)
+}
+
+func TestProvisionManager_StartIdempotent_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	var executed atomic.Int32
+	started := &api.Orchestration{ID: "orchestration-1", State: api.OrchestrationStateInitialized}
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").RunAndReturn(func(context.Context, string) (*api.Orchestration, error) {
+		if executed.Load() > 0 {
+			return started, nil
+		}
+		return nil, nil
+	})
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).RunAndReturn(func(context.Context, *api.Orchestration) error {
+		time.Sleep(20 * time.Millisecond)
+		executed.Add(1)
+		return nil
+	})
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+	manifest := &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type"}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := pm.StartIdempotent(ctx, "key-1", manifest)
+			if err != nil {
+				// Requests arriving while the orchestration is being started are rejected as in progress
+				assert.ErrorIs(t, err, types.ErrConflict)
+				return
+			}
+			assert.Equal(t, "orchestration-1", result.ID)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), executed.Load())
+	record, err := pm.idempotencyStore.FindByID(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, api.IdempotencyStateStarted, record.State)
+}
+
+func TestProvisionManager_StartIdempotent_StartFailureReleasesKey(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(nil, nil)
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(errors.New("unavailable")).Once()
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(nil).Once()
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+	manifest := &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type"}
+
+	_, err := pm.StartIdempotent(ctx, "key-1", manifest)
+	require.Error(t, err)
+
+	// The retry is not rejected as in progress since the key was released
+	result, err := pm.StartIdempotent(ctx, "key-1", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "orchestration-1", result.ID)
+}
+
+func TestProvisionManager_StartIdempotent_AbandonedKey(t *testing.T) {
+	ctx := context.Background()
+	definitionStore := memorystore.NewDefinitionStore()
+	_, _ = definitionStore.StoreOrchestrationDefinition(ctx, createTestOrchestrationDefinition("test-type"))
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(nil, nil)
+	mockOrch.EXPECT().Execute(mock.Anything, mock.AnythingOfType("*api.Orchestration")).Return(nil).Once()
+
+	pm := newIdempotentProvisionManager(mockOrch, definitionStore)
+	manifest := &model.OrchestrationManifest{ID: "orchestration-1", OrchestrationType: "test-type"}
+	requestHash, err := hashManifest(manifest)
+	require.NoError(t, err)
+
+	record := &api.IdempotencyRecord{
+		ID:               "key-1",
+		RequestHash:      requestHash,
+		OrchestrationID:  "orchestration-1",
+		State:            api.IdempotencyStatePending,
+		CreatedTimestamp: time.Now(),
+	}
+	_, err = pm.idempotencyStore.Create(ctx, record)
+	require.NoError(t, err)
+
+	// The key is reserved by a request that is still starting the orchestration
+	_, err = pm.StartIdempotent(ctx, "key-1", manifest)
+	require.ErrorIs(t, err, types.ErrConflict)
+
+	// The request that reserved the key did not start the orchestration in time
+	record.CreatedTimestamp = time.Now().Add(-2 * idempotencyPendingTimeout)
+	require.NoError(t, pm.idempotencyStore.Update(ctx, record))
+
+	result, err := pm.StartIdempotent(ctx, "key-1", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "orchestration-1", result.ID)
+}
+
+func TestProvisionManager_WaitForOrchestration(t *testing.T) {
+	running := &api.Orchestration{ID: "orchestration-1", State: api.OrchestrationStateInitialized}
+	completed := &api.Orchestration{
+		ID:         "orchestration-1",
+		State:      api.OrchestrationStateCompleted,
+		OutputData: map[string]any{"result": "done"},
+	}
+
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(running, nil).Twice()
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(completed, nil).Once()
+
+	pm := newIdempotentProvisionManager(mockOrch, memorystore.NewDefinitionStore())
+
+	result, err := pm.WaitForOrchestration(context.Background(), "orchestration-1", 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, api.OrchestrationStateCompleted, result.State)
+	assert.Equal(t, "done", result.OutputData["result"])
+}
+
+func TestProvisionManager_WaitForOrchestration_Timeout(t *testing.T) {
+	running := &api.Orchestration{ID: "orchestration-1", State: api.OrchestrationStateInitialized}
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "orchestration-1").Return(running, nil)
+
+	pm := newIdempotentProvisionManager(mockOrch, memorystore.NewDefinitionStore())
+
+	result, err := pm.WaitForOrchestration(context.Background(), "orchestration-1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, result.IsTerminal())
+}
+
+func TestProvisionManager_WaitForOrchestration_NotFound(t *testing.T) {
+	mockOrch := mocks.NewMockOrchestrator(t)
+	mockOrch.EXPECT().GetOrchestration(mock.Anything, "unknown").Return(nil, nil)
+
+	pm := newIdempotentProvisionManager(mockOrch, memorystore.NewDefinitionStore())
+
+	_, err := pm.WaitForOrchestration(context.Background(), "unknown", time.Second)
+	require.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func newIdempotentProvisionManager(orchestrator api.Orchestrator, definitionStore api.DefinitionStore) *provisionManager {
+	return &provisionManager{
+		orchestrator:     orchestrator,
+		store:            definitionStore,
+		idempotencyStore: cmemorystore.NewInMemoryEntityStore[*api.IdempotencyRecord](),
+		idempotencyTTL:   time.Hour,
+		waitInterval:     5 * time.Millisecond,
+		monitor:          &system.NoopMonitor{},
+		trxContext:       store.NoOpTransactionContext{},
+	}
+}
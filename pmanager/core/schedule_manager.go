@@ -0,0 +1,186 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/robfig/cron/v3"
+)
+
+type scheduleManager struct {
+	trxContext      store.TransactionContext
+	definitionStore api.DefinitionStore
+	schedules       store.EntityStore[*api.Schedule]
+	runs            store.EntityStore[*api.ScheduleRun]
+	now             func() time.Time
+}
+
+func (s scheduleManager) CreateSchedule(ctx context.Context, schedule *api.Schedule) (*api.Schedule, error) {
+	if schedule.ID == "" {
+		return nil, types.NewClientError("Missing required field: id")
+	}
+	if schedule.OrchestrationType == "" {
+		return nil, types.NewClientError("Missing required field: orchestrationType")
+	}
+	cronSchedule, err := parseCronExpression(schedule.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+	// Render the template once to detect errors before the first run
+	if _, err = schedule.RenderPayload(s.now()); err != nil {
+		return nil, types.NewClientError("%s", err.Error())
+	}
+
+	return store.Trx[api.Schedule](s.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Schedule, error) {
+		exists, err := s.definitionStore.ExistsOrchestrationDefinition(ctx, schedule.OrchestrationType)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, types.NewClientError("orchestration type '%s' not found", schedule.OrchestrationType)
+		}
+
+		now := s.now()
+		schedule.CreatedTimestamp = now
+		schedule.NextRun = cronSchedule.Next(now)
+		schedule.LastRun = time.Time{}
+		return s.schedules.Create(ctx, schedule)
+	})
+}
+
+func (s scheduleManager) GetSchedule(ctx context.Context, scheduleID string) (*api.Schedule, error) {
+	return store.Trx[api.Schedule](s.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Schedule, error) {
+		return s.schedules.FindByID(ctx, scheduleID)
+	})
+}
+
+func (s scheduleManager) GetSchedules(ctx context.Context) ([]api.Schedule, error) {
+	schedules := make([]api.Schedule, 0)
+	err := s.trxContext.Execute(ctx, func(ctx context.Context) error {
+		for schedule, err := range s.schedules.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			schedules = append(schedules, *schedule)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s scheduleManager) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	return s.trxContext.Execute(ctx, func(ctx context.Context) error {
+		schedule, err := s.schedules.FindByID(ctx, scheduleID)
+		if err != nil {
+			return err
+		}
+		if err = store.CheckExpectedVersion(ctx, schedule); err != nil {
+			return err
+		}
+		if err = s.schedules.Delete(ctx, scheduleID); err != nil {
+			return err
+		}
+		return s.runs.DeleteByPredicate(ctx, query.Eq("scheduleId", scheduleID))
+	})
+}
+
+func (s scheduleManager) PauseSchedule(ctx context.Context, scheduleID string) (*api.Schedule, error) {
+	return s.update(ctx, scheduleID, func(schedule *api.Schedule) error {
+		schedule.Paused = true
+		return nil
+	})
+}
+
+func (s scheduleManager) ResumeSchedule(ctx context.Context, scheduleID string) (*api.Schedule, error) {
+	return s.update(ctx, scheduleID, func(schedule *api.Schedule) error {
+		if !schedule.Paused {
+			return nil
+		}
+		cronSchedule, err := parseCronExpression(schedule.CronExpression)
+		if err != nil {
+			return err
+		}
+		schedule.Paused = false
+		// Skip the runs missed while paused
+		schedule.NextRun = cronSchedule.Next(s.now())
+		return nil
+	})
+}
+
+func (s scheduleManager) GetScheduleRuns(ctx context.Context, scheduleID string) ([]api.ScheduleRun, error) {
+	runs := make([]api.ScheduleRun, 0)
+	err := s.trxContext.Execute(ctx, func(ctx context.Context) error {
+		exists, err := s.schedules.Exists(ctx, scheduleID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return types.ErrNotFound
+		}
+		for run, err := range s.runs.FindByPredicate(ctx, query.Eq("scheduleId", scheduleID)) {
+			if err != nil {
+				return err
+			}
+			runs = append(runs, *run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ScheduledTime.After(runs[j].ScheduledTime)
+	})
+	return runs, nil
+}
+
+func (s scheduleManager) update(ctx context.Context, scheduleID string, updateFn func(*api.Schedule) error) (*api.Schedule, error) {
+	return store.Trx[api.Schedule](s.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Schedule, error) {
+		schedule, err := s.schedules.FindByID(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		if err = store.CheckExpectedVersion(ctx, schedule); err != nil {
+			return nil, err
+		}
+		if err = updateFn(schedule); err != nil {
+			return nil, err
+		}
+		if err = s.schedules.Update(ctx, schedule); err != nil {
+			return nil, err
+		}
+		return schedule, nil
+	})
+}
+
+// parseCronExpression parses a standard five-field cron expression. Descriptors such as @daily are also supported.
+func parseCronExpression(expression string) (cron.Schedule, error) {
+	if expression == "" {
+		return nil, types.NewClientError("Missing required field: cronExpression")
+	}
+	cronSchedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, types.NewClientError("invalid cron expression '%s': %v", expression, err)
+	}
+	return cronSchedule, nil
+}
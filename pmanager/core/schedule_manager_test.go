@@ -0,0 +1,180 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	pmemorystore "github.com/metaform/connector-fabric-manager/pmanager/memorystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scheduleTestTime = time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+
+func TestScheduleManager_CreateSchedule(t *testing.T) {
+	manager := newTestScheduleManager(t)
+
+	schedule, err := manager.CreateSchedule(context.Background(), &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		PayloadTemplate:   map[string]any{"run": "{{.RunID}}"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, scheduleTestTime, schedule.CreatedTimestamp)
+	assert.Equal(t, time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), schedule.NextRun)
+	assert.True(t, schedule.LastRun.IsZero())
+
+	_, err = manager.CreateSchedule(context.Background(), &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+	})
+	require.ErrorIs(t, err, types.ErrConflict)
+}
+
+func TestScheduleManager_CreateSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *api.Schedule
+		expected string
+	}{
+		{
+			name:     "missing id",
+			schedule: &api.Schedule{CronExpression: "@daily", OrchestrationType: "rotate-credentials"},
+			expected: "id",
+		},
+		{
+			name:     "invalid cron expression",
+			schedule: &api.Schedule{ID: "s", CronExpression: "every hour", OrchestrationType: "rotate-credentials"},
+			expected: "invalid cron expression",
+		},
+		{
+			name: "invalid payload template",
+			schedule: &api.Schedule{ID: "s", CronExpression: "@daily", OrchestrationType: "rotate-credentials",
+				PayloadTemplate: map[string]any{"run": "{{.Unknown}}"}},
+			expected: "payload template",
+		},
+		{
+			name:     "unknown orchestration type",
+			schedule: &api.Schedule{ID: "s", CronExpression: "@daily", OrchestrationType: "unknown"},
+			expected: "orchestration type 'unknown' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestScheduleManager(t)
+			_, err := manager.CreateSchedule(context.Background(), tt.schedule)
+			require.Error(t, err)
+			assert.True(t, types.IsClientError(err))
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestScheduleManager_PauseResume(t *testing.T) {
+	manager := newTestScheduleManager(t)
+	ctx := context.Background()
+	_, err := manager.CreateSchedule(ctx, &api.Schedule{ID: "rotate", CronExpression: "0 * * * *", OrchestrationType: "rotate-credentials"})
+	require.NoError(t, err)
+
+	paused, err := manager.PauseSchedule(ctx, "rotate")
+	require.NoError(t, err)
+	assert.True(t, paused.Paused)
+
+	// Runs missed while paused are skipped
+	manager.now = func() time.Time { return scheduleTestTime.Add(5 * time.Hour) }
+	resumed, err := manager.ResumeSchedule(ctx, "rotate")
+	require.NoError(t, err)
+	assert.False(t, resumed.Paused)
+	assert.Equal(t, time.Date(2025, 6, 1, 16, 0, 0, 0, time.UTC), resumed.NextRun)
+
+	_, err = manager.PauseSchedule(ctx, "unknown")
+	require.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestScheduleManager_ExpectedVersion(t *testing.T) {
+	manager := newTestScheduleManager(t)
+	ctx := context.Background()
+	_, err := manager.CreateSchedule(ctx, &api.Schedule{ID: "rotate", CronExpression: "0 * * * *", OrchestrationType: "rotate-credentials"})
+	require.NoError(t, err)
+	schedule, err := manager.GetSchedule(ctx, "rotate")
+	require.NoError(t, err)
+
+	paused, err := manager.PauseSchedule(store.WithExpectedVersion(ctx, "rotate", schedule.Version), "rotate")
+	require.NoError(t, err)
+	assert.Equal(t, schedule.Version+1, paused.Version)
+
+	_, err = manager.ResumeSchedule(store.WithExpectedVersion(ctx, "rotate", schedule.Version), "rotate")
+	require.ErrorIs(t, err, types.ErrPreconditionFailed)
+
+	err = manager.DeleteSchedule(store.WithExpectedVersion(ctx, "rotate", schedule.Version), "rotate")
+	require.ErrorIs(t, err, types.ErrPreconditionFailed)
+
+	require.NoError(t, manager.DeleteSchedule(store.WithExpectedVersion(ctx, "rotate", paused.Version), "rotate"))
+}
+
+func TestScheduleManager_GetScheduleRuns(t *testing.T) {
+	manager := newTestScheduleManager(t)
+	ctx := context.Background()
+	_, err := manager.CreateSchedule(ctx, &api.Schedule{ID: "rotate", CronExpression: "0 * * * *", OrchestrationType: "rotate-credentials"})
+	require.NoError(t, err)
+
+	for i := range 3 {
+		scheduled := scheduleTestTime.Add(time.Duration(i) * time.Hour)
+		_, err = manager.runs.Create(ctx, &api.ScheduleRun{ID: api.ScheduleRunID("rotate", scheduled), ScheduleID: "rotate", ScheduledTime: scheduled})
+		require.NoError(t, err)
+	}
+	_, err = manager.runs.Create(ctx, &api.ScheduleRun{ID: "other-1", ScheduleID: "other", ScheduledTime: scheduleTestTime})
+	require.NoError(t, err)
+
+	runs, err := manager.GetScheduleRuns(ctx, "rotate")
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, scheduleTestTime.Add(2*time.Hour), runs[0].ScheduledTime)
+	assert.Equal(t, scheduleTestTime, runs[2].ScheduledTime)
+
+	_, err = manager.GetScheduleRuns(ctx, "unknown")
+	require.ErrorIs(t, err, types.ErrNotFound)
+
+	// Deleting the schedule removes its runs
+	require.NoError(t, manager.DeleteSchedule(ctx, "rotate"))
+	count, err := manager.runs.GetAllCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func newTestScheduleManager(t *testing.T) *scheduleManager {
+	definitionStore := pmemorystore.NewDefinitionStore()
+	_, err := definitionStore.StoreOrchestrationDefinition(context.Background(), &api.OrchestrationDefinition{
+		Type:       "rotate-credentials",
+		Activities: []api.Activity{{ID: "rotate", Type: "rotate-secret"}},
+	})
+	require.NoError(t, err)
+	return &scheduleManager{
+		trxContext:      store.NoOpTransactionContext{},
+		definitionStore: definitionStore,
+		schedules:       memorystore.NewInMemoryEntityStore[*api.Schedule](),
+		runs:            memorystore.NewInMemoryEntityStore[*api.ScheduleRun](),
+		now:             func() time.Time { return scheduleTestTime },
+	}
+}
@@ -0,0 +1,148 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+)
+
+const defaultSchedulerInterval = 10 * time.Second
+
+// scheduler starts the orchestrations of due schedules. Only the leader runs schedules when a leader election is
+// configured. Orchestration IDs are derived from the schedule and the scheduled time, so a run started concurrently by
+// a previous leader is de-duplicated by the provision manager.
+type scheduler struct {
+	provisionManager api.ProvisionManager
+	schedules        store.EntityStore[*api.Schedule]
+	runs             store.EntityStore[*api.ScheduleRun]
+	trxContext       store.TransactionContext
+	leaderElection   api.LeaderElection
+	interval         time.Duration
+	monitor          system.LogMonitor
+	now              func() time.Time
+}
+
+// start runs due schedules at the scheduler interval until the context is canceled.
+func (s *scheduler) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.leaderElection != nil && !s.leaderElection.IsLeader() {
+					continue
+				}
+				if err := s.runDue(ctx); err != nil {
+					s.monitor.Warnf("Error running schedules: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// runDue starts an orchestration for each active schedule whose next run is due.
+func (s *scheduler) runDue(ctx context.Context) error {
+	now := s.now()
+	var due []*api.Schedule
+	err := s.trxContext.Execute(ctx, func(ctx context.Context) error {
+		for schedule, err := range s.schedules.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			if !schedule.Paused && !schedule.NextRun.After(now) {
+				due = append(due, schedule)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range due {
+		if err = s.run(ctx, schedule, now); err != nil {
+			s.monitor.Warnf("Error running schedule %s: %v", schedule.ID, err)
+		}
+	}
+	return nil
+}
+
+// run starts the orchestration for the next run of the schedule, records the run, and advances the schedule. Runs
+// missed while no instance was running are skipped. Runs rejected as invalid are recorded with the error; if the
+// orchestration cannot be started for other reasons, the schedule is not advanced and the run is retried.
+func (s *scheduler) run(ctx context.Context, schedule *api.Schedule, now time.Time) error {
+	cronSchedule, err := parseCronExpression(schedule.CronExpression)
+	if err != nil {
+		return err
+	}
+
+	scheduledTime := schedule.NextRun
+	run := &api.ScheduleRun{
+		ID:               api.ScheduleRunID(schedule.ID, scheduledTime),
+		ScheduleID:       schedule.ID,
+		ScheduledTime:    scheduledTime,
+		StartedTimestamp: now,
+	}
+
+	payload, err := schedule.RenderPayload(scheduledTime)
+	if err == nil {
+		_, err = s.provisionManager.Start(ctx, &model.OrchestrationManifest{
+			ID:                run.ID,
+			CorrelationID:     schedule.ID,
+			OrchestrationType: schedule.OrchestrationType,
+			Payload:           payload,
+		})
+		if err != nil && !types.IsClientError(err) {
+			return err
+		}
+	}
+	if err != nil {
+		run.Error = err.Error()
+		s.monitor.Warnf("Scheduled run %s failed to start: %v", run.ID, err)
+	} else {
+		s.monitor.Infof("Started scheduled run %s", run.ID)
+	}
+
+	return s.trxContext.Execute(ctx, func(ctx context.Context) error {
+		if _, err := s.runs.Create(ctx, run); err != nil && !errors.Is(err, types.ErrConflict) {
+			return err
+		}
+
+		// Re-read the schedule since it may have been paused or deleted while the run was started
+		current, err := s.schedules.FindByID(ctx, schedule.ID)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !current.NextRun.Equal(scheduledTime) {
+			// Advanced by another instance or resumed
+			return nil
+		}
+		current.LastRun = scheduledTime
+		current.NextRun = cronSchedule.Next(now)
+		return s.schedules.Update(ctx, current)
+	})
+}
@@ -0,0 +1,176 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunDue(t *testing.T) {
+	starter := &recordingProvisionManager{}
+	s := newTestScheduler(starter)
+	ctx := context.Background()
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		PayloadTemplate:   map[string]any{"run": "{{.RunID}}", "date": "{{.ScheduledTime.Format \"2006-01-02\"}}"},
+		NextRun:           scheduleTestTime,
+	})
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "paused",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		Paused:            true,
+		NextRun:           scheduleTestTime,
+	})
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "later",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		NextRun:           scheduleTestTime.Add(time.Hour),
+	})
+
+	require.NoError(t, s.runDue(ctx))
+
+	runID := api.ScheduleRunID("rotate", scheduleTestTime)
+	require.Len(t, starter.manifests, 1)
+	manifest := starter.manifests[0]
+	assert.Equal(t, runID, manifest.ID)
+	assert.Equal(t, "rotate", manifest.CorrelationID)
+	assert.Equal(t, model.OrchestrationType("rotate-credentials"), manifest.OrchestrationType)
+	assert.Equal(t, map[string]any{"run": runID, "date": "2025-06-01"}, manifest.Payload)
+
+	run, err := s.runs.FindByID(ctx, runID)
+	require.NoError(t, err)
+	assert.Equal(t, "rotate", run.ScheduleID)
+	assert.Empty(t, run.Error)
+
+	schedule, err := s.schedules.FindByID(ctx, "rotate")
+	require.NoError(t, err)
+	assert.True(t, schedule.LastRun.Equal(scheduleTestTime))
+	assert.True(t, schedule.NextRun.Equal(time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)))
+
+	// The schedule is not due again until the next run
+	require.NoError(t, s.runDue(ctx))
+	assert.Len(t, starter.manifests, 1)
+}
+
+func TestScheduler_RunDue_SkipsMissedRuns(t *testing.T) {
+	starter := &recordingProvisionManager{}
+	s := newTestScheduler(starter)
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		NextRun:           scheduleTestTime.Add(-5 * time.Hour),
+	})
+
+	require.NoError(t, s.runDue(context.Background()))
+
+	require.Len(t, starter.manifests, 1)
+	schedule, err := s.schedules.FindByID(context.Background(), "rotate")
+	require.NoError(t, err)
+	assert.True(t, schedule.NextRun.Equal(time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)))
+}
+
+func TestScheduler_RunDue_SystemErrorRetries(t *testing.T) {
+	starter := &recordingProvisionManager{err: types.NewFatalError("NATS unavailable")}
+	s := newTestScheduler(starter)
+	ctx := context.Background()
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		NextRun:           scheduleTestTime,
+	})
+
+	require.NoError(t, s.runDue(ctx))
+
+	count, err := s.runs.GetAllCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// The same run is retried with the same orchestration ID
+	starter.err = nil
+	require.NoError(t, s.runDue(ctx))
+	require.Len(t, starter.manifests, 2)
+	assert.Equal(t, starter.manifests[0].ID, starter.manifests[1].ID)
+	_, err = s.runs.FindByID(ctx, api.ScheduleRunID("rotate", scheduleTestTime))
+	require.NoError(t, err)
+}
+
+func TestScheduler_RunDue_RecordsFailedRun(t *testing.T) {
+	starter := &recordingProvisionManager{err: types.NewClientError("orchestration type 'rotate-credentials' not found")}
+	s := newTestScheduler(starter)
+	ctx := context.Background()
+	createTestSchedule(t, s, &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		NextRun:           scheduleTestTime,
+	})
+
+	require.NoError(t, s.runDue(ctx))
+
+	run, err := s.runs.FindByID(ctx, api.ScheduleRunID("rotate", scheduleTestTime))
+	require.NoError(t, err)
+	assert.Contains(t, run.Error, "not found")
+
+	// Non-recoverable failures advance the schedule
+	schedule, err := s.schedules.FindByID(ctx, "rotate")
+	require.NoError(t, err)
+	assert.True(t, schedule.NextRun.After(scheduleTestTime))
+}
+
+func newTestScheduler(provisionManager api.ProvisionManager) *scheduler {
+	return &scheduler{
+		provisionManager: provisionManager,
+		schedules:        memorystore.NewInMemoryEntityStore[*api.Schedule](),
+		runs:             memorystore.NewInMemoryEntityStore[*api.ScheduleRun](),
+		trxContext:       store.NoOpTransactionContext{},
+		monitor:          system.NoopMonitor{},
+		now:              func() time.Time { return scheduleTestTime },
+	}
+}
+
+func createTestSchedule(t *testing.T, s *scheduler, schedule *api.Schedule) {
+	_, err := s.schedules.Create(context.Background(), schedule)
+	require.NoError(t, err)
+}
+
+// recordingProvisionManager records the manifests of started orchestrations.
+type recordingProvisionManager struct {
+	api.ProvisionManager
+	manifests []*model.OrchestrationManifest
+	err       error
+}
+
+func (p *recordingProvisionManager) Start(_ context.Context, manifest *model.OrchestrationManifest) (*api.Orchestration, error) {
+	p.manifests = append(p.manifests, manifest)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &api.Orchestration{ID: manifest.ID}, nil
+}
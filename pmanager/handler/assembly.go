@@ -36,7 +36,7 @@ func (h *HandlerServiceAssembly) Name() string {
 }
 
 func (h *HandlerServiceAssembly) Requires() []system.ServiceType {
-	return []system.ServiceType{routing.RouterKey, api.ProvisionManagerKey, api.DefinitionStoreKey}
+	return []system.ServiceType{routing.RouterKey, api.ProvisionManagerKey, api.DefinitionStoreKey, api.ActivityLeaseManagerKey, api.AgentRegistryKey, api.ScheduleManagerKey}
 }
 
 func (h *HandlerServiceAssembly) Init(context *system.InitContext) error {
@@ -45,8 +45,11 @@ func (h *HandlerServiceAssembly) Init(context *system.InitContext) error {
 
 	provisionManager := context.Registry.Resolve(api.ProvisionManagerKey).(api.ProvisionManager)
 	definitionManager := context.Registry.Resolve(api.DefinitionManagerKey).(api.DefinitionManager)
+	leaseManager := context.Registry.Resolve(api.ActivityLeaseManagerKey).(api.ActivityLeaseManager)
+	agentRegistry := context.Registry.Resolve(api.AgentRegistryKey).(api.AgentRegistry)
+	scheduleManager := context.Registry.Resolve(api.ScheduleManagerKey).(api.ScheduleManager)
 	txContext := context.Registry.Resolve(store.TransactionContextKey).(store.TransactionContext)
-	handler := NewHandler(provisionManager, definitionManager, txContext, context.LogMonitor)
+	handler := NewHandler(provisionManager, definitionManager, leaseManager, agentRegistry, scheduleManager, txContext, context.LogMonitor)
 
 	router.Route("/api/v1alpha1", func(r chi.Router) {
 		h.registerV1Alpha1(r, handler)
@@ -60,9 +63,34 @@ func (h *HandlerServiceAssembly) registerV1Alpha1(router chi.Router, handler *PM
 	h.registerOrchestrationDefinitionRoutes(router, handler)
 
 	h.registerOrchestrationRoutes(router, handler)
+	h.registerActivityLeaseRoutes(router, handler)
+	h.registerScheduleRoutes(router, handler)
+	h.registerAgentRoutes(router, handler)
 	router.Get("/health", handler.health)
 }
 
+func (h *HandlerServiceAssembly) registerAgentRoutes(router chi.Router, handler *PMHandler) {
+	router.Route("/agents", func(r chi.Router) {
+		r.Get("/", handler.getAgents)
+		r.Route("/{instanceID}", func(r chi.Router) {
+			r.Put("/", func(w http.ResponseWriter, req *http.Request) {
+				instanceID, found := handler.ExtractPathVariable(w, req, "instanceID")
+				if !found {
+					return
+				}
+				handler.registerAgent(w, req, instanceID)
+			})
+			r.Delete("/", func(w http.ResponseWriter, req *http.Request) {
+				instanceID, found := handler.ExtractPathVariable(w, req, "instanceID")
+				if !found {
+					return
+				}
+				handler.deregisterAgent(w, req, instanceID)
+			})
+		})
+	})
+}
+
 func (h *HandlerServiceAssembly) registerOrchestrationRoutes(router chi.Router, handler *PMHandler) {
 	router.Route("/orchestrations", func(r chi.Router) {
 		r.Post("/", handler.createOrchestration)
@@ -120,3 +148,76 @@ func (h *HandlerServiceAssembly) registerOrchestrationDefinitionRoutes(router ch
 		})
 	})
 }
+
+func (h *HandlerServiceAssembly) registerActivityLeaseRoutes(router chi.Router, handler *PMHandler) {
+	router.Route("/activity-leases", func(r chi.Router) {
+		r.Post("/", handler.leaseActivity)
+		r.Route("/{leaseID}", func(r chi.Router) {
+			r.Post("/heartbeat", func(w http.ResponseWriter, req *http.Request) {
+				leaseID, found := handler.ExtractPathVariable(w, req, "leaseID")
+				if !found {
+					return
+				}
+				handler.heartbeatActivityLease(w, req, leaseID)
+			})
+			r.Post("/complete", func(w http.ResponseWriter, req *http.Request) {
+				leaseID, found := handler.ExtractPathVariable(w, req, "leaseID")
+				if !found {
+					return
+				}
+				handler.completeActivityLease(w, req, leaseID)
+			})
+			r.Post("/fail", func(w http.ResponseWriter, req *http.Request) {
+				leaseID, found := handler.ExtractPathVariable(w, req, "leaseID")
+				if !found {
+					return
+				}
+				handler.failActivityLease(w, req, leaseID)
+			})
+		})
+	})
+}
+
+func (h *HandlerServiceAssembly) registerScheduleRoutes(router chi.Router, handler *PMHandler) {
+	router.Route("/schedules", func(r chi.Router) {
+		r.Get("/", handler.getSchedules)
+		r.Post("/", handler.createSchedule)
+		r.Route("/{scheduleID}", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, req *http.Request) {
+				scheduleID, found := handler.ExtractPathVariable(w, req, "scheduleID")
+				if !found {
+					return
+				}
+				handler.getSchedule(w, req, scheduleID)
+			})
+			r.Delete("/", func(w http.ResponseWriter, req *http.Request) {
+				scheduleID, found := handler.ExtractPathVariable(w, req, "scheduleID")
+				if !found {
+					return
+				}
+				handler.deleteSchedule(w, req, scheduleID)
+			})
+			r.Post("/pause", func(w http.ResponseWriter, req *http.Request) {
+				scheduleID, found := handler.ExtractPathVariable(w, req, "scheduleID")
+				if !found {
+					return
+				}
+				handler.pauseSchedule(w, req, scheduleID)
+			})
+			r.Post("/resume", func(w http.ResponseWriter, req *http.Request) {
+				scheduleID, found := handler.ExtractPathVariable(w, req, "scheduleID")
+				if !found {
+					return
+				}
+				handler.resumeSchedule(w, req, scheduleID)
+			})
+			r.Get("/runs", func(w http.ResponseWriter, req *http.Request) {
+				scheduleID, found := handler.ExtractPathVariable(w, req, "scheduleID")
+				if !found {
+					return
+				}
+				handler.getScheduleRuns(w, req, scheduleID)
+			})
+		})
+	})
+}
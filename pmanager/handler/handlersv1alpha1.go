@@ -14,25 +14,40 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/handler"
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 	"github.com/metaform/connector-fabric-manager/pmanager/model/v1alpha1"
 )
 
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	waitParam            = "wait"
+	maxWait              = 60 * time.Second
+)
+
 type PMHandler struct {
 	handler.HttpHandler
 	provisionManager  api.ProvisionManager
 	definitionManager api.DefinitionManager
+	leaseManager      api.ActivityLeaseManager
+	agentRegistry     api.AgentRegistry
+	scheduleManager   api.ScheduleManager
 	txContext         store.TransactionContext
 }
 
 func NewHandler(
 	provisionManager api.ProvisionManager,
 	definitionManager api.DefinitionManager,
+	leaseManager api.ActivityLeaseManager,
+	agentRegistry api.AgentRegistry,
+	scheduleManager api.ScheduleManager,
 	txContext store.TransactionContext,
 	monitor system.LogMonitor) *PMHandler {
 	return &PMHandler{
@@ -41,6 +56,9 @@ func NewHandler(
 		},
 		provisionManager:  provisionManager,
 		definitionManager: definitionManager,
+		leaseManager:      leaseManager,
+		agentRegistry:     agentRegistry,
+		scheduleManager:   scheduleManager,
 		txContext:         txContext,
 	}
 }
@@ -74,13 +92,17 @@ func (h *PMHandler) createOrchestrationDefinition(w http.ResponseWriter, req *ht
 		return
 	}
 
-	_, err := h.definitionManager.CreateOrchestrationDefinition(req.Context(), v1alpha1.ToAPIOrchestrationDefinition(&definition))
+	created, missingAgents, err := h.definitionManager.CreateOrchestrationDefinition(req.Context(), v1alpha1.ToAPIOrchestrationDefinition(&definition))
 	if err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
-	h.Created(w)
+	response := v1alpha1.OrchestrationDefinitionCreated{Type: created.Type.String()}
+	for _, activityType := range missingAgents {
+		response.MissingAgentTypes = append(response.MissingAgentTypes, activityType.String())
+	}
+	h.ResponseCreated(w, response)
 }
 
 func (h *PMHandler) createOrchestration(w http.ResponseWriter, req *http.Request) {
@@ -93,12 +115,50 @@ func (h *PMHandler) createOrchestration(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	orchestration, err := h.provisionManager.Start(req.Context(), &manifest)
+	wait, err := parseWait(req.URL.Query().Get(waitParam))
 	if err != nil {
 		h.HandleError(w, err)
 		return
 	}
-	h.ResponseAccepted(w, orchestration)
+
+	orchestration, err := h.provisionManager.StartIdempotent(req.Context(), req.Header.Get(idempotencyKeyHeader), &manifest)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if wait > 0 && !orchestration.IsTerminal() {
+		orchestration, err = h.provisionManager.WaitForOrchestration(req.Context(), orchestration.ID, wait)
+		if err != nil {
+			h.HandleError(w, err)
+			return
+		}
+	}
+	if wait > 0 && orchestration.IsTerminal() {
+		h.ResponseOK(w, v1alpha1.ToOrchestration(orchestration))
+		return
+	}
+	h.ResponseAccepted(w, v1alpha1.ToOrchestration(orchestration))
+}
+
+// parseWait parses the time to wait for an orchestration to complete, either as a duration such as 30s or as a number
+// of seconds. Returns zero if no wait is requested.
+func parseWait(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, types.NewClientError("invalid wait parameter: %s", value)
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait < 0 || wait > maxWait {
+		return 0, types.NewClientError("wait parameter must be between 0s and %s", maxWait)
+	}
+	return wait, nil
 }
 
 func (h *PMHandler) health(w http.ResponseWriter, _ *http.Request) {
@@ -111,7 +171,12 @@ func (h *PMHandler) deleteOrchestrationDefinition(w http.ResponseWriter, req *ht
 		return
 	}
 
-	err := h.definitionManager.DeleteOrchestrationDefinition(req.Context(), model.OrchestrationType(oType))
+	ctx, ok := h.ReadIfMatch(w, req, oType)
+	if !ok {
+		return
+	}
+
+	err := h.definitionManager.DeleteOrchestrationDefinition(ctx, model.OrchestrationType(oType))
 	if err != nil {
 		h.HandleError(w, err)
 		return
@@ -125,7 +190,12 @@ func (h *PMHandler) deleteActivityDefinition(w http.ResponseWriter, req *http.Re
 		return
 	}
 
-	err := h.definitionManager.DeleteActivityDefinition(req.Context(), api.ActivityType(aType))
+	ctx, ok := h.ReadIfMatch(w, req, aType)
+	if !ok {
+		return
+	}
+
+	err := h.definitionManager.DeleteActivityDefinition(ctx, api.ActivityType(aType))
 	if err != nil {
 		h.HandleError(w, err)
 		return
@@ -179,6 +249,55 @@ func (h *PMHandler) getActivityDefinitions(w http.ResponseWriter, req *http.Requ
 	h.ResponseOK(w, converted)
 }
 
+func (h *PMHandler) getAgents(w http.ResponseWriter, req *http.Request) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+	agents, err := h.agentRegistry.GetAgents(req.Context())
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+	converted := make([]v1alpha1.Agent, len(agents))
+	for i, agent := range agents {
+		converted[i] = v1alpha1.ToAgent(&agent)
+	}
+
+	h.ResponseOK(w, converted)
+}
+
+func (h *PMHandler) registerAgent(w http.ResponseWriter, req *http.Request, instanceID string) {
+	if h.InvalidMethod(w, req, http.MethodPut) {
+		return
+	}
+
+	var registration v1alpha1.AgentRegistration
+	if !h.ReadPayload(w, req, &registration) {
+		return
+	}
+
+	registered, err := h.agentRegistry.RegisterAgent(req.Context(), v1alpha1.ToAPIAgentRegistration(instanceID, &registration))
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseOK(w, v1alpha1.ToAgent(registered))
+}
+
+func (h *PMHandler) deregisterAgent(w http.ResponseWriter, req *http.Request, instanceID string) {
+	if h.InvalidMethod(w, req, http.MethodDelete) {
+		return
+	}
+
+	if err := h.agentRegistry.DeregisterAgent(req.Context(), instanceID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.NoContent(w)
+}
+
 func (h *PMHandler) getOrchestrationDefinitions(w http.ResponseWriter, req *http.Request) {
 	if h.InvalidMethod(w, req, http.MethodGet) {
 		return
@@ -195,3 +314,207 @@ func (h *PMHandler) getOrchestrationDefinitions(w http.ResponseWriter, req *http
 
 	h.ResponseOK(w, converted)
 }
+
+func (h *PMHandler) leaseActivity(w http.ResponseWriter, req *http.Request) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var request v1alpha1.ActivityLeaseRequest
+	if !h.ReadPayload(w, req, &request) {
+		return
+	}
+
+	wait := time.Duration(request.WaitSeconds) * time.Second
+	lease, err := h.leaseManager.Lease(req.Context(), api.ActivityType(request.ActivityType), wait)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+	if lease == nil {
+		h.NoContent(w)
+		return
+	}
+
+	h.ResponseCreated(w, v1alpha1.ToActivityLease(lease))
+}
+
+func (h *PMHandler) heartbeatActivityLease(w http.ResponseWriter, req *http.Request, leaseID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var heartbeat v1alpha1.ActivityLeaseHeartbeat
+	if req.ContentLength != 0 && !h.ReadPayload(w, req, &heartbeat) {
+		return
+	}
+
+	lease, err := h.leaseManager.Heartbeat(req.Context(), leaseID, v1alpha1.ToAPIActivityProgress(&heartbeat))
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseOK(w, v1alpha1.ToActivityLease(lease))
+}
+
+func (h *PMHandler) completeActivityLease(w http.ResponseWriter, req *http.Request, leaseID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var completion v1alpha1.ActivityLeaseCompletion
+	if req.ContentLength != 0 && !h.ReadPayload(w, req, &completion) {
+		return
+	}
+
+	err := h.leaseManager.Complete(req.Context(), leaseID, completion.ProcessingData, completion.OutputData)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.OK(w)
+}
+
+func (h *PMHandler) failActivityLease(w http.ResponseWriter, req *http.Request, leaseID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var failure v1alpha1.ActivityLeaseFailure
+	if !h.ReadPayload(w, req, &failure) {
+		return
+	}
+
+	err := h.leaseManager.Fail(req.Context(), leaseID, failure.Retryable, failure.Error)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.OK(w)
+}
+
+func (h *PMHandler) createSchedule(w http.ResponseWriter, req *http.Request) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var schedule v1alpha1.NewSchedule
+	if !h.ReadPayload(w, req, &schedule) {
+		return
+	}
+
+	created, err := h.scheduleManager.CreateSchedule(req.Context(), v1alpha1.ToAPISchedule(&schedule))
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, created.Version)
+	h.ResponseCreated(w, v1alpha1.ToSchedule(created))
+}
+
+func (h *PMHandler) getSchedules(w http.ResponseWriter, req *http.Request) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+	schedules, err := h.scheduleManager.GetSchedules(req.Context())
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+	converted := make([]v1alpha1.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		converted[i] = v1alpha1.ToSchedule(&schedule)
+	}
+
+	h.ResponseOK(w, converted)
+}
+
+func (h *PMHandler) getSchedule(w http.ResponseWriter, req *http.Request, scheduleID string) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+	schedule, err := h.scheduleManager.GetSchedule(req.Context(), scheduleID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, schedule.Version)
+	h.ResponseOK(w, v1alpha1.ToSchedule(schedule))
+}
+
+func (h *PMHandler) deleteSchedule(w http.ResponseWriter, req *http.Request, scheduleID string) {
+	if h.InvalidMethod(w, req, http.MethodDelete) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, scheduleID)
+	if !ok {
+		return
+	}
+
+	err := h.scheduleManager.DeleteSchedule(ctx, scheduleID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.OK(w)
+}
+
+func (h *PMHandler) pauseSchedule(w http.ResponseWriter, req *http.Request, scheduleID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+	ctx, ok := h.ReadIfMatch(w, req, scheduleID)
+	if !ok {
+		return
+	}
+	schedule, err := h.scheduleManager.PauseSchedule(ctx, scheduleID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, schedule.Version)
+	h.ResponseOK(w, v1alpha1.ToSchedule(schedule))
+}
+
+func (h *PMHandler) resumeSchedule(w http.ResponseWriter, req *http.Request, scheduleID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+	ctx, ok := h.ReadIfMatch(w, req, scheduleID)
+	if !ok {
+		return
+	}
+	schedule, err := h.scheduleManager.ResumeSchedule(ctx, scheduleID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, schedule.Version)
+	h.ResponseOK(w, v1alpha1.ToSchedule(schedule))
+}
+
+func (h *PMHandler) getScheduleRuns(w http.ResponseWriter, req *http.Request, scheduleID string) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+	runs, err := h.scheduleManager.GetScheduleRuns(req.Context(), scheduleID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+	converted := make([]v1alpha1.ScheduleRun, len(runs))
+	for i, run := range runs {
+		converted[i] = v1alpha1.ToScheduleRun(&run)
+	}
+
+	h.ResponseOK(w, converted)
+}
@@ -0,0 +1,77 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPMHandler_DeleteDefinition_IfMatch(t *testing.T) {
+	h := NewHandler(nil, versionedDefinitionManager{}, nil, nil, nil, store.NoOpTransactionContext{}, system.NoopMonitor{})
+
+	tests := []struct {
+		name   string
+		handle func(w http.ResponseWriter, req *http.Request)
+	}{
+		{
+			name:   "orchestration definition",
+			handle: func(w http.ResponseWriter, req *http.Request) { h.deleteOrchestrationDefinition(w, req, "test-type") },
+		},
+		{
+			name:   "activity definition",
+			handle: func(w http.ResponseWriter, req *http.Request) { h.deleteActivityDefinition(w, req, "test-type") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for ifMatch, status := range map[string]int{
+				"":    http.StatusOK,
+				`"2"`: http.StatusOK,
+				`"1"`: http.StatusPreconditionFailed,
+			} {
+				req := httptest.NewRequest(http.MethodDelete, "/", nil)
+				if ifMatch != "" {
+					req.Header.Set("If-Match", ifMatch)
+				}
+				w := httptest.NewRecorder()
+
+				tt.handle(w, req)
+
+				assert.Equal(t, status, w.Code, "If-Match %q", ifMatch)
+			}
+		})
+	}
+}
+
+// versionedDefinitionManager deletes definitions that are at version 2.
+type versionedDefinitionManager struct {
+	api.DefinitionManager
+}
+
+func (versionedDefinitionManager) DeleteOrchestrationDefinition(ctx context.Context, oType model.OrchestrationType) error {
+	return store.CheckExpectedVersion(ctx, &api.OrchestrationDefinition{Type: oType, Version: 2})
+}
+
+func (versionedDefinitionManager) DeleteActivityDefinition(ctx context.Context, aType api.ActivityType) error {
+	return store.CheckExpectedVersion(ctx, &api.ActivityDefinition{Type: aType, Version: 2})
+}
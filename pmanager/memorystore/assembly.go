@@ -27,7 +27,7 @@ func (m MemoryStoreServiceAssembly) Name() string {
 }
 
 func (m MemoryStoreServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestrationIndexKey}
+	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestrationIndexKey, api.ScheduleStoreKey, api.ScheduleRunStoreKey, api.IdempotencyStoreKey}
 }
 
 func (m MemoryStoreServiceAssembly) Init(context *system.InitContext) error {
@@ -35,5 +35,8 @@ func (m MemoryStoreServiceAssembly) Init(context *system.InitContext) error {
 	context.Registry.Register(
 		api.OrchestrationIndexKey,
 		memorystore.NewInMemoryEntityStore[*api.OrchestrationEntry]())
+	context.Registry.Register(api.ScheduleStoreKey, memorystore.NewInMemoryEntityStore[*api.Schedule]())
+	context.Registry.Register(api.ScheduleRunStoreKey, memorystore.NewInMemoryEntityStore[*api.ScheduleRun]())
+	context.Registry.Register(api.IdempotencyStoreKey, memorystore.NewInMemoryEntityStore[*api.IdempotencyRecord]())
 	return nil
 }
@@ -0,0 +1,260 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	api "github.com/metaform/connector-fabric-manager/pmanager/api"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAgentRegistry is an autogenerated mock type for the AgentRegistry type
+type MockAgentRegistry struct {
+	mock.Mock
+}
+
+type MockAgentRegistry_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAgentRegistry) EXPECT() *MockAgentRegistry_Expecter {
+	return &MockAgentRegistry_Expecter{mock: &_m.Mock}
+}
+
+// DeregisterAgent provides a mock function with given fields: ctx, instanceID
+func (_m *MockAgentRegistry) DeregisterAgent(ctx context.Context, instanceID string) error {
+	ret := _m.Called(ctx, instanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeregisterAgent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAgentRegistry_DeregisterAgent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeregisterAgent'
+type MockAgentRegistry_DeregisterAgent_Call struct {
+	*mock.Call
+}
+
+// DeregisterAgent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - instanceID string
+func (_e *MockAgentRegistry_Expecter) DeregisterAgent(ctx interface{}, instanceID interface{}) *MockAgentRegistry_DeregisterAgent_Call {
+	return &MockAgentRegistry_DeregisterAgent_Call{Call: _e.mock.On("DeregisterAgent", ctx, instanceID)}
+}
+
+func (_c *MockAgentRegistry_DeregisterAgent_Call) Run(run func(ctx context.Context, instanceID string)) *MockAgentRegistry_DeregisterAgent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAgentRegistry_DeregisterAgent_Call) Return(_a0 error) *MockAgentRegistry_DeregisterAgent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAgentRegistry_DeregisterAgent_Call) RunAndReturn(run func(context.Context, string) error) *MockAgentRegistry_DeregisterAgent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActivityTypes provides a mock function with given fields: ctx
+func (_m *MockAgentRegistry) GetActivityTypes(ctx context.Context) (map[api.ActivityType]struct{}, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActivityTypes")
+	}
+
+	var r0 map[api.ActivityType]struct{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[api.ActivityType]struct{}, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[api.ActivityType]struct{}); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[api.ActivityType]struct{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAgentRegistry_GetActivityTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActivityTypes'
+type MockAgentRegistry_GetActivityTypes_Call struct {
+	*mock.Call
+}
+
+// GetActivityTypes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAgentRegistry_Expecter) GetActivityTypes(ctx interface{}) *MockAgentRegistry_GetActivityTypes_Call {
+	return &MockAgentRegistry_GetActivityTypes_Call{Call: _e.mock.On("GetActivityTypes", ctx)}
+}
+
+func (_c *MockAgentRegistry_GetActivityTypes_Call) Run(run func(ctx context.Context)) *MockAgentRegistry_GetActivityTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAgentRegistry_GetActivityTypes_Call) Return(_a0 map[api.ActivityType]struct{}, _a1 error) *MockAgentRegistry_GetActivityTypes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAgentRegistry_GetActivityTypes_Call) RunAndReturn(run func(context.Context) (map[api.ActivityType]struct{}, error)) *MockAgentRegistry_GetActivityTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAgents provides a mock function with given fields: ctx
+func (_m *MockAgentRegistry) GetAgents(ctx context.Context) ([]api.AgentRegistration, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgents")
+	}
+
+	var r0 []api.AgentRegistration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]api.AgentRegistration, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []api.AgentRegistration); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.AgentRegistration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAgentRegistry_GetAgents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAgents'
+type MockAgentRegistry_GetAgents_Call struct {
+	*mock.Call
+}
+
+// GetAgents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAgentRegistry_Expecter) GetAgents(ctx interface{}) *MockAgentRegistry_GetAgents_Call {
+	return &MockAgentRegistry_GetAgents_Call{Call: _e.mock.On("GetAgents", ctx)}
+}
+
+func (_c *MockAgentRegistry_GetAgents_Call) Run(run func(ctx context.Context)) *MockAgentRegistry_GetAgents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAgentRegistry_GetAgents_Call) Return(_a0 []api.AgentRegistration, _a1 error) *MockAgentRegistry_GetAgents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAgentRegistry_GetAgents_Call) RunAndReturn(run func(context.Context) ([]api.AgentRegistration, error)) *MockAgentRegistry_GetAgents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterAgent provides a mock function with given fields: ctx, registration
+func (_m *MockAgentRegistry) RegisterAgent(ctx context.Context, registration *api.AgentRegistration) (*api.AgentRegistration, error) {
+	ret := _m.Called(ctx, registration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterAgent")
+	}
+
+	var r0 *api.AgentRegistration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *api.AgentRegistration) (*api.AgentRegistration, error)); ok {
+		return rf(ctx, registration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *api.AgentRegistration) *api.AgentRegistration); ok {
+		r0 = rf(ctx, registration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.AgentRegistration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *api.AgentRegistration) error); ok {
+		r1 = rf(ctx, registration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAgentRegistry_RegisterAgent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterAgent'
+type MockAgentRegistry_RegisterAgent_Call struct {
+	*mock.Call
+}
+
+// RegisterAgent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registration *api.AgentRegistration
+func (_e *MockAgentRegistry_Expecter) RegisterAgent(ctx interface{}, registration interface{}) *MockAgentRegistry_RegisterAgent_Call {
+	return &MockAgentRegistry_RegisterAgent_Call{Call: _e.mock.On("RegisterAgent", ctx, registration)}
+}
+
+func (_c *MockAgentRegistry_RegisterAgent_Call) Run(run func(ctx context.Context, registration *api.AgentRegistration)) *MockAgentRegistry_RegisterAgent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*api.AgentRegistration))
+	})
+	return _c
+}
+
+func (_c *MockAgentRegistry_RegisterAgent_Call) Return(_a0 *api.AgentRegistration, _a1 error) *MockAgentRegistry_RegisterAgent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAgentRegistry_RegisterAgent_Call) RunAndReturn(run func(context.Context, *api.AgentRegistration) (*api.AgentRegistration, error)) *MockAgentRegistry_RegisterAgent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAgentRegistry creates a new instance of MockAgentRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAgentRegistry(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAgentRegistry {
+	mock := &MockAgentRegistry{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -45,6 +45,13 @@ type OrchestrationDefinition struct {
 	Activities  []Activity     `json:"activities" validate:"required,min=1"`
 }
 
+// OrchestrationDefinitionCreated is returned when an orchestration definition is created. MissingAgentTypes contains
+// the activity types of the definition that have no registered agent.
+type OrchestrationDefinitionCreated struct {
+	Type              string   `json:"type"`
+	MissingAgentTypes []string `json:"missingAgentTypes,omitempty"`
+}
+
 type OrchestrationEntry struct {
 	ID                string                  `json:"id"`
 	CorrelationID     string                  `json:"correlationId"`
@@ -55,18 +62,101 @@ type OrchestrationEntry struct {
 }
 
 type Orchestration struct {
+	ID                string                                 `json:"id"`
+	CorrelationID     string                                 `json:"correlationId"`
+	State             int                                    `json:"state"`
+	StateTimestamp    time.Time                              `json:"stateTimestamp"`
+	CreatedTimestamp  time.Time                              `json:"createdTimestamp"`
+	OrchestrationType model.OrchestrationType                `json:"orchestrationType"`
+	Steps             []OrchestrationStep                    `json:"steps"`
+	ProcessingData    map[string]any                         `json:"processingData"`
+	OutputData        map[string]any                         `json:"outputData"`
+	Completed         map[string]struct{}                    `json:"completed"`
+	Progress          map[string]ActivityProgress            `json:"progress,omitempty"`
+	VPAProgress       map[string]map[string]ActivityProgress `json:"vpaProgress,omitempty"`
+	StalledActivities []string                               `json:"stalledActivities,omitempty"`
+}
+
+type ActivityProgress struct {
+	Percent   int       `json:"percent"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type OrchestrationStep struct {
+	Activities []Activity `json:"activities"`
+}
+
+type ActivityLeaseRequest struct {
+	ActivityType string `json:"activityType" validate:"required,modeltype"`
+	WaitSeconds  int    `json:"waitSeconds,omitempty" validate:"min=0,max=60"`
+}
+
+type ActivityLease struct {
 	ID                string                  `json:"id"`
-	CorrelationID     string                  `json:"correlationId"`
-	State             int                     `json:"state"`
-	StateTimestamp    time.Time               `json:"stateTimestamp"`
-	CreatedTimestamp  time.Time               `json:"createdTimestamp"`
+	OrchestrationID   string                  `json:"orchestrationId"`
 	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
-	Steps             []OrchestrationStep     `json:"steps"`
+	Activity          Activity                `json:"activity"`
 	ProcessingData    map[string]any          `json:"processingData"`
 	OutputData        map[string]any          `json:"outputData"`
-	Completed         map[string]struct{}     `json:"completed"`
+	ExpiresAt         time.Time               `json:"expiresAt"`
 }
 
-type OrchestrationStep struct {
-	Activities []Activity `json:"activities"`
+type ActivityLeaseHeartbeat struct {
+	VPAID   string `json:"vpaId,omitempty"`
+	Percent *int   `json:"percent,omitempty" validate:"omitempty,min=0,max=100"`
+	Message string `json:"message,omitempty"`
+}
+
+type ActivityLeaseCompletion struct {
+	ProcessingData map[string]any `json:"processingData,omitempty"`
+	OutputData     map[string]any `json:"outputData,omitempty"`
+}
+
+type ActivityLeaseFailure struct {
+	Retryable bool   `json:"retryable"`
+	Error     string `json:"error" validate:"required"`
+}
+
+type Agent struct {
+	InstanceID    string    `json:"instanceId"`
+	Name          string    `json:"name"`
+	Version       string    `json:"version,omitempty"`
+	ActivityTypes []string  `json:"activityTypes"`
+	StartedAt     time.Time `json:"startedAt"`
+	Heartbeat     time.Time `json:"heartbeat"`
+}
+
+type AgentRegistration struct {
+	Name          string   `json:"name" validate:"required"`
+	Version       string   `json:"version,omitempty"`
+	ActivityTypes []string `json:"activityTypes" validate:"required,min=1,dive,modeltype"`
+}
+
+type NewSchedule struct {
+	ID                string         `json:"id" validate:"required"`
+	CronExpression    string         `json:"cronExpression" validate:"required"`
+	OrchestrationType string         `json:"orchestrationType" validate:"required,modeltype"`
+	PayloadTemplate   map[string]any `json:"payloadTemplate,omitempty"`
+	Paused            bool           `json:"paused,omitempty"`
+}
+
+type Schedule struct {
+	ID                string                  `json:"id"`
+	CronExpression    string                  `json:"cronExpression"`
+	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
+	PayloadTemplate   map[string]any          `json:"payloadTemplate,omitempty"`
+	Paused            bool                    `json:"paused"`
+	NextRun           time.Time               `json:"nextRun"`
+	LastRun           *time.Time              `json:"lastRun,omitempty"`
+	CreatedTimestamp  time.Time               `json:"createdTimestamp"`
+}
+
+type ScheduleRun struct {
+	ID               string    `json:"id"`
+	ScheduleID       string    `json:"scheduleId"`
+	OrchestrationID  string    `json:"orchestrationId"`
+	ScheduledTime    time.Time `json:"scheduledTime"`
+	StartedTimestamp time.Time `json:"startedTimestamp"`
+	Error            string    `json:"error,omitempty"`
 }
@@ -13,6 +13,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 )
@@ -133,6 +135,127 @@ func ToOrchestration(orchestration *api.Orchestration) Orchestration {
 		Steps:             toSteps(orchestration.Steps),
 		OutputData:        orchestration.OutputData,
 		Completed:         orchestration.Completed,
+		Progress:          toProgress(orchestration.Progress),
+		VPAProgress:       toVPAProgress(orchestration.VPAProgress),
+		StalledActivities: orchestration.StalledActivities,
+	}
+}
+
+func toProgress(progress map[string]api.ActivityProgress) map[string]ActivityProgress {
+	if len(progress) == 0 {
+		return nil
+	}
+	result := make(map[string]ActivityProgress, len(progress))
+	for activityID, entry := range progress {
+		result[activityID] = ActivityProgress{
+			Percent:   entry.Percent,
+			Message:   entry.Message,
+			Timestamp: entry.Timestamp,
+		}
+	}
+	return result
+}
+
+func toVPAProgress(progress map[string]map[string]api.ActivityProgress) map[string]map[string]ActivityProgress {
+	if len(progress) == 0 {
+		return nil
+	}
+	result := make(map[string]map[string]ActivityProgress, len(progress))
+	for vpaID, entries := range progress {
+		result[vpaID] = toProgress(entries)
+	}
+	return result
+}
+
+func ToActivityLease(lease *api.ActivityLease) ActivityLease {
+	return ActivityLease{
+		ID:                lease.ID,
+		OrchestrationID:   lease.OrchestrationID,
+		OrchestrationType: lease.OrchestrationType,
+		Activity:          toActivities([]api.Activity{lease.Activity})[0],
+		ProcessingData:    lease.ProcessingData,
+		OutputData:        lease.OutputData,
+		ExpiresAt:         lease.ExpiresAt,
+	}
+}
+
+// ToAPIActivityProgress converts the heartbeat to the progress it reports. Returns nil if no progress is reported.
+func ToAPIActivityProgress(heartbeat *ActivityLeaseHeartbeat) *api.ActivityProgress {
+	if heartbeat.Percent == nil {
+		return nil
+	}
+	return &api.ActivityProgress{
+		VPAID:     heartbeat.VPAID,
+		Percent:   *heartbeat.Percent,
+		Message:   heartbeat.Message,
+		Timestamp: time.Now(),
+	}
+}
+
+func ToAgent(registration *api.AgentRegistration) Agent {
+	activityTypes := make([]string, len(registration.ActivityTypes))
+	for i, activityType := range registration.ActivityTypes {
+		activityTypes[i] = activityType.String()
+	}
+	return Agent{
+		InstanceID:    registration.InstanceID,
+		Name:          registration.Name,
+		Version:       registration.Version,
+		ActivityTypes: activityTypes,
+		StartedAt:     registration.StartedAt,
+		Heartbeat:     registration.Heartbeat,
+	}
+}
+
+func ToAPIAgentRegistration(instanceID string, registration *AgentRegistration) *api.AgentRegistration {
+	activityTypes := make([]api.ActivityType, len(registration.ActivityTypes))
+	for i, activityType := range registration.ActivityTypes {
+		activityTypes[i] = api.ActivityType(activityType)
+	}
+	return &api.AgentRegistration{
+		InstanceID:    instanceID,
+		Name:          registration.Name,
+		Version:       registration.Version,
+		ActivityTypes: activityTypes,
+	}
+}
+
+func ToAPISchedule(schedule *NewSchedule) *api.Schedule {
+	return &api.Schedule{
+		ID:                schedule.ID,
+		CronExpression:    schedule.CronExpression,
+		OrchestrationType: model.OrchestrationType(schedule.OrchestrationType),
+		PayloadTemplate:   schedule.PayloadTemplate,
+		Paused:            schedule.Paused,
+	}
+}
+
+func ToSchedule(schedule *api.Schedule) Schedule {
+	result := Schedule{
+		ID:                schedule.ID,
+		CronExpression:    schedule.CronExpression,
+		OrchestrationType: schedule.OrchestrationType,
+		PayloadTemplate:   schedule.PayloadTemplate,
+		Paused:            schedule.Paused,
+		NextRun:           schedule.NextRun,
+		CreatedTimestamp:  schedule.CreatedTimestamp,
+	}
+	if !schedule.LastRun.IsZero() {
+		lastRun := schedule.LastRun
+		result.LastRun = &lastRun
+	}
+	return result
+}
+
+// ToScheduleRun converts a run. The ID of the orchestration started by the run is the run ID.
+func ToScheduleRun(run *api.ScheduleRun) ScheduleRun {
+	return ScheduleRun{
+		ID:               run.ID,
+		ScheduleID:       run.ScheduleID,
+		OrchestrationID:  run.ID,
+		ScheduledTime:    run.ScheduledTime,
+		StartedTimestamp: run.StartedTimestamp,
+		Error:            run.Error,
 	}
 }
 
@@ -300,6 +300,9 @@ func TestToOrchestration(t *testing.T) {
 		ProcessingData:    map[string]any{"key1": "value1"},
 		OutputData:        map[string]any{"key2": "value2"},
 		Completed:         map[string]struct{}{"activity1": {}},
+		Progress: map[string]api.ActivityProgress{
+			"activity-1": {Percent: 40, Message: "issuing credentials", Timestamp: now},
+		},
 		Steps: []api.OrchestrationStep{
 			{
 				Activities: []api.Activity{
@@ -333,6 +336,7 @@ func TestToOrchestration(t *testing.T) {
 	assert.Equal(t, "test.activity", result.Steps[0].Activities[0].Type)
 	assert.Equal(t, 1, len(result.Steps[0].Activities[0].Inputs))
 	assert.Equal(t, "src1", result.Steps[0].Activities[0].Inputs[0].Source)
+	assert.Equal(t, ActivityProgress{Percent: 40, Message: "issuing credentials", Timestamp: now}, result.Progress["activity-1"])
 }
 
 func TestToActivityDefinition_WithValidDefinition(t *testing.T) {
@@ -775,3 +779,95 @@ func TestToOrchestrationDefinition_ActivityWithNoDependencies(t *testing.T) {
 	assert.Len(t, result.Activities[0].DependsOn, 0)
 	assert.Len(t, result.Activities[1].DependsOn, 0)
 }
+
+func TestToActivityLease(t *testing.T) {
+	expiresAt := time.Now().Add(30 * time.Second)
+	lease := &api.ActivityLease{
+		ID:                "lease1",
+		OrchestrationID:   "orchestration1",
+		OrchestrationType: model.VPADeployType,
+		Activity:          api.Activity{ID: "activity1", Type: "test.activity", DependsOn: []string{"activity0"}},
+		ProcessingData:    map[string]any{"key": "value"},
+		OutputData:        map[string]any{"output": "value"},
+		ExpiresAt:         expiresAt,
+	}
+
+	result := ToActivityLease(lease)
+
+	assert.Equal(t, "lease1", result.ID)
+	assert.Equal(t, "orchestration1", result.OrchestrationID)
+	assert.Equal(t, model.VPADeployType, result.OrchestrationType)
+	assert.Equal(t, "activity1", result.Activity.ID)
+	assert.Equal(t, "test.activity", result.Activity.Type)
+	assert.Equal(t, []string{"activity0"}, result.Activity.DependsOn)
+	assert.Equal(t, "value", result.ProcessingData["key"])
+	assert.Equal(t, "value", result.OutputData["output"])
+	assert.Equal(t, expiresAt, result.ExpiresAt)
+}
+
+func TestToAPIActivityProgress(t *testing.T) {
+	assert.Nil(t, ToAPIActivityProgress(&ActivityLeaseHeartbeat{Message: "ignored"}))
+
+	percent := 60
+	progress := ToAPIActivityProgress(&ActivityLeaseHeartbeat{Percent: &percent, Message: "working"})
+	require.NotNil(t, progress)
+	assert.Equal(t, 60, progress.Percent)
+	assert.Equal(t, "working", progress.Message)
+	assert.False(t, progress.Timestamp.IsZero())
+}
+
+func TestToAgent(t *testing.T) {
+	now := time.Now()
+	registration := &api.AgentRegistration{
+		InstanceID:    "instance1",
+		Name:          "Test Agent",
+		Version:       "1.0.0",
+		ActivityTypes: []api.ActivityType{"test-activity", "other-activity"},
+		StartedAt:     now.Add(-time.Minute),
+		Heartbeat:     now,
+	}
+
+	result := ToAgent(registration)
+
+	assert.Equal(t, "instance1", result.InstanceID)
+	assert.Equal(t, "Test Agent", result.Name)
+	assert.Equal(t, "1.0.0", result.Version)
+	assert.Equal(t, []string{"test-activity", "other-activity"}, result.ActivityTypes)
+	assert.Equal(t, now.Add(-time.Minute), result.StartedAt)
+	assert.Equal(t, now, result.Heartbeat)
+}
+
+func TestToSchedule(t *testing.T) {
+	now := time.Now()
+	schedule := &api.Schedule{
+		ID:                "rotate",
+		CronExpression:    "@daily",
+		OrchestrationType: "rotate-credentials",
+		PayloadTemplate:   map[string]any{"run": "{{.RunID}}"},
+		NextRun:           now.Add(time.Hour),
+		CreatedTimestamp:  now,
+	}
+
+	result := ToSchedule(schedule)
+	assert.Equal(t, "rotate", result.ID)
+	assert.Equal(t, "@daily", result.CronExpression)
+	assert.Equal(t, model.OrchestrationType("rotate-credentials"), result.OrchestrationType)
+	assert.Equal(t, schedule.PayloadTemplate, result.PayloadTemplate)
+	assert.Equal(t, now.Add(time.Hour), result.NextRun)
+	assert.Nil(t, result.LastRun, "a schedule that has not run has no last run")
+
+	schedule.LastRun = now
+	result = ToSchedule(schedule)
+	require.NotNil(t, result.LastRun)
+	assert.Equal(t, now, *result.LastRun)
+}
+
+func TestToScheduleRun(t *testing.T) {
+	now := time.Now()
+	run := ToScheduleRun(&api.ScheduleRun{ID: "rotate-1", ScheduleID: "rotate", ScheduledTime: now, StartedTimestamp: now, Error: "failed"})
+
+	assert.Equal(t, "rotate-1", run.ID)
+	assert.Equal(t, "rotate-1", run.OrchestrationID)
+	assert.Equal(t, "rotate", run.ScheduleID)
+	assert.Equal(t, "failed", run.Error)
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
@@ -30,16 +31,19 @@ const (
 // agentServiceAssembly provides common functionality for NATS-based agents
 type agentServiceAssembly struct {
 	agentName        string
-	activityType     string
+	version          string
+	registrationTTL  time.Duration
+	activities       []agentActivity
 	uri              string
 	bucket           string
 	streamName       string
 	assemblyProvider func() []system.ServiceAssembly
-	newProcessor     func(ctx *AgentContext) api.ActivityProcessor
 	requires         []system.ServiceType
 	system.DefaultServiceAssembly
 
 	natsClient *natsclient.NatsClient
+	executors  []*natsorchestration.NatsActivityExecutor
+	registrar  *agentRegistrar
 	cancel     context.CancelFunc
 }
 
@@ -58,7 +62,7 @@ func (a *agentServiceAssembly) Start(startCtx *system.StartContext) error {
 		return fmt.Errorf("failed to create NATS client: %w", err)
 	}
 
-	if err = a.setupConsumer(a.natsClient); err != nil {
+	if err = a.setupConsumers(a.natsClient); err != nil {
 		return fmt.Errorf("failed to create setup agent consumer: %w", err)
 	}
 
@@ -68,32 +72,96 @@ func (a *agentServiceAssembly) Start(startCtx *system.StartContext) error {
 		Config:   startCtx.Config,
 	}
 
-	executor := &natsorchestration.NatsActivityExecutor{
-		Client:            natsclient.NewMsgClient(a.natsClient),
-		StreamName:        a.streamName,
-		ActivityType:      a.activityType,
-		ActivityProcessor: a.newProcessor(actx),
-		Monitor:           startCtx.LogMonitor,
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	a.cancel = cancel
 
-	return executor.Execute(ctx)
+	// The executors share the NATS connection
+	msgClient := natsclient.NewMsgClient(a.natsClient)
+	for _, activity := range a.activities {
+		executor := &natsorchestration.NatsActivityExecutor{
+			Client:            msgClient,
+			StreamName:        a.streamName,
+			ActivityType:      activity.activityType,
+			ActivityProcessor: activity.newProcessor(actx),
+			Monitor:           startCtx.LogMonitor,
+			Concurrency:       activity.pool.Concurrency,
+			BatchSize:         activity.pool.BatchSize,
+			MaxInFlight:       activity.pool.MaxInFlight,
+			ShutdownTimeout:   activity.pool.ShutdownTimeout,
+		}
+		if err = executor.Execute(ctx); err != nil {
+			return fmt.Errorf("failed to start executor for %s: %w", activity.activityType, err)
+		}
+		a.executors = append(a.executors, executor)
+	}
+
+	return a.register(ctx, startCtx.LogMonitor)
 }
 
 func (a *agentServiceAssembly) Shutdown() error {
+	if a.registrar != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		if err := a.registrar.deregister(ctx); err != nil {
+			a.registrar.monitor.Warnf("Error deregistering agent: %v", err)
+		}
+		cancel()
+	}
+
 	if a.cancel != nil {
 		a.cancel()
 	}
 
+	// Wait for in-flight activities before closing the connection they use
+	for _, executor := range a.executors {
+		executor.Wait()
+	}
+
 	if a.natsClient != nil {
 		a.natsClient.Connection.Close()
 	}
 	return nil
 }
 
-func (a *agentServiceAssembly) setupConsumer(natsClient *natsclient.NatsClient) error {
+// register registers the agent and its activity types so that the Provision Manager can discover them. The registration
+// is refreshed until the context is canceled.
+func (a *agentServiceAssembly) register(ctx context.Context, monitor system.LogMonitor) error {
+	ttl := a.registrationTTL
+	if ttl <= 0 {
+		ttl = defaultRegistrationTTL
+	}
+
+	setupCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	kv, err := natsclient.SetupAgentBucket(setupCtx, a.natsClient.JetStream, ttl)
+	if err != nil {
+		return fmt.Errorf("failed to set up agent registration bucket: %w", err)
+	}
+
+	activityTypes := make([]api.ActivityType, len(a.activities))
+	for i, activity := range a.activities {
+		activityTypes[i] = api.ActivityType(activity.activityType)
+	}
+	a.registrar = &agentRegistrar{
+		kv: kv,
+		registration: api.AgentRegistration{
+			InstanceID:    uuid.New().String(),
+			Name:          a.agentName,
+			Version:       a.version,
+			ActivityTypes: activityTypes,
+			StartedAt:     time.Now(),
+		},
+		// Refresh well within the TTL so that a single missed refresh does not expire the registration
+		interval: ttl / 3,
+		monitor:  monitor,
+	}
+	if err = a.registrar.register(setupCtx); err != nil {
+		return err
+	}
+	a.registrar.start(ctx)
+	return nil
+}
+
+func (a *agentServiceAssembly) setupConsumers(natsClient *natsclient.NatsClient) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -103,10 +171,10 @@ func (a *agentServiceAssembly) setupConsumer(natsClient *natsclient.NatsClient)
 		return fmt.Errorf("error setting up agent stream: %w", err)
 	}
 
-	_, err = natsclient.SetupConsumer(ctx, stream, a.activityType)
-
-	if err != nil {
-		return fmt.Errorf("error setting up agent consumer: %w", err)
+	for _, activity := range a.activities {
+		if _, err = natsclient.SetupConsumer(ctx, stream, activity.activityType); err != nil {
+			return fmt.Errorf("error setting up agent consumer for %s: %w", activity.activityType, err)
+		}
 	}
 
 	return nil
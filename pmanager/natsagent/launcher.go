@@ -14,7 +14,11 @@ package natsagent
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/metaform/connector-fabric-manager/assembly/metrics"
+	"github.com/metaform/connector-fabric-manager/assembly/tracing"
 	"github.com/metaform/connector-fabric-manager/common/runtime"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
@@ -22,17 +26,64 @@ import (
 )
 
 const (
-	uriKey    = "uri"
-	bucketKey = "bucket"
-	streamKey = "stream"
+	uriKey             = "uri"
+	bucketKey          = "bucket"
+	streamKey          = "stream"
+	concurrencyKey     = "concurrency"
+	batchSizeKey       = "batch.size"
+	maxInFlightKey     = "max.inflight"
+	shutdownTimeoutKey = "shutdown.timeout"
+	registrationTTLKey = "registration.ttl"
+	metricsPortKey     = "metrics.port"
 )
 
 type LauncherConfig struct {
-	AgentName        string
+	AgentName string
+
+	// Version is the agent version reported in the agent registration.
+	Version string
+
 	ConfigPrefix     string
 	ActivityType     string
 	AssemblyProvider func() []system.ServiceAssembly
 	NewProcessor     func(ctx *AgentContext) api.ActivityProcessor
+
+	// Activities maps activity types to their processors for agents that host multiple activity types. A consumer is
+	// created for each type, sharing the NATS connection and service assemblies of the agent. Can be combined with
+	// ActivityType and NewProcessor.
+	Activities map[string]ActivityConfig
+
+	// Concurrency is the number of activities processed concurrently for each activity type. Defaults to 1. Can be
+	// overridden with the 'concurrency' configuration key.
+	Concurrency int
+
+	// BatchSize is the maximum number of activity messages fetched at a time. Defaults to 1. Can be overridden with the
+	// 'batch.size' configuration key.
+	BatchSize int
+
+	// MaxInFlight is the maximum number of fetched activity messages that have not completed processing. Defaults to
+	// the greater of Concurrency and BatchSize. Can be overridden with the 'max.inflight' configuration key.
+	MaxInFlight int
+
+	// ShutdownTimeout is the time to wait for in-flight activities to complete on shutdown before they are rejected for
+	// redelivery. Defaults to 30 seconds. Can be overridden in seconds with the 'shutdown.timeout' configuration key.
+	ShutdownTimeout time.Duration
+
+	// MetricsPort is the port the Prometheus metrics endpoint is served on. The endpoint is disabled by default, since
+	// agents running on the same host would otherwise compete for the same port. Can be set or overridden with the
+	// 'metrics.port' configuration key, for example, with the <PREFIX>_METRICS_PORT environment variable. A value of zero
+	// or less disables the endpoint.
+	MetricsPort int
+}
+
+// ActivityConfig configures the processing of an activity type hosted by an agent. Pool settings that are not set
+// default to the settings of the LauncherConfig.
+type ActivityConfig struct {
+	NewProcessor    func(ctx *AgentContext) api.ActivityProcessor
+	Concurrency     int
+	BatchSize       int
+	MaxInFlight     int
+	ShutdownTimeout time.Duration
 }
 
 type AgentContext struct {
@@ -54,8 +105,27 @@ type agentConfig struct {
 	VConfig    *viper.Viper
 }
 
+// poolConfig contains the executor worker pool settings
+type poolConfig struct {
+	Concurrency     int
+	BatchSize       int
+	MaxInFlight     int
+	ShutdownTimeout time.Duration
+}
+
+// agentActivity is an activity type hosted by an agent
+type agentActivity struct {
+	activityType string
+	newProcessor func(ctx *AgentContext) api.ActivityProcessor
+	pool         poolConfig
+}
+
 func LaunchAgent(shutdown <-chan struct{}, config LauncherConfig) {
 	cfg := loadAgentConfig(config.AgentName, config.ConfigPrefix)
+	activities, err := collectActivities(config, loadPoolConfig(cfg.VConfig, config))
+	if err != nil {
+		panic(fmt.Errorf("error loading agent configuration: %w", err))
+	}
 
 	mode := runtime.LoadMode()
 
@@ -77,17 +147,24 @@ func LaunchAgent(shutdown <-chan struct{}, config LauncherConfig) {
 
 	agentAssembly := &agentServiceAssembly{
 		agentName:        config.AgentName,
-		activityType:     config.ActivityType,
+		version:          config.Version,
+		registrationTTL:  time.Duration(cfg.VConfig.GetInt(registrationTTLKey)) * time.Second,
+		activities:       activities,
 		uri:              cfg.URI,
 		bucket:           cfg.Bucket,
 		streamName:       cfg.StreamName,
-		newProcessor:     config.NewProcessor,
 		requires:         requires,
 		assemblyProvider: config.AssemblyProvider,
 	}
 
 	assembler.Register(agentAssembly)
 
+	assembler.Register(&tracing.TracingServiceAssembly{ServiceName: config.ConfigPrefix})
+
+	if metricsPort := loadMetricsPort(cfg.VConfig, config); metricsPort > 0 {
+		assembler.Register(&metrics.MetricsServerServiceAssembly{Port: metricsPort})
+	}
+
 	runtime.AssembleAndLaunch(assembler, cfg.Name, monitor, shutdown)
 }
 
@@ -112,3 +189,84 @@ func loadAgentConfig(name string, configPrefix string) *agentConfig {
 		VConfig:    vConfig,
 	}
 }
+
+// loadPoolConfig returns the worker pool settings of the launcher configuration, overridden by configuration values
+// if set.
+func loadPoolConfig(vConfig *viper.Viper, config LauncherConfig) poolConfig {
+	shutdownTimeout := config.ShutdownTimeout
+	if vConfig.IsSet(shutdownTimeoutKey) {
+		shutdownTimeout = time.Duration(vConfig.GetInt(shutdownTimeoutKey)) * time.Second
+	}
+	return poolConfig{
+		Concurrency:     getIntOrDefault(vConfig, concurrencyKey, config.Concurrency),
+		BatchSize:       getIntOrDefault(vConfig, batchSizeKey, config.BatchSize),
+		MaxInFlight:     getIntOrDefault(vConfig, maxInFlightKey, config.MaxInFlight),
+		ShutdownTimeout: shutdownTimeout,
+	}
+}
+
+// loadMetricsPort returns the port of the metrics endpoint. A value of zero or less disables the endpoint.
+func loadMetricsPort(vConfig *viper.Viper, config LauncherConfig) int {
+	return getIntOrDefault(vConfig, metricsPortKey, config.MetricsPort)
+}
+
+func getIntOrDefault(vConfig *viper.Viper, key string, defaultValue int) int {
+	if vConfig.IsSet(key) {
+		return vConfig.GetInt(key)
+	}
+	return defaultValue
+}
+
+// collectActivities returns the activity types hosted by the agent, sorted by type. Pool settings of each type default to
+// the given settings.
+func collectActivities(config LauncherConfig, defaults poolConfig) ([]agentActivity, error) {
+	activities := make([]agentActivity, 0, len(config.Activities)+1)
+	if config.ActivityType != "" {
+		if config.NewProcessor == nil {
+			return nil, fmt.Errorf("no processor specified for activity type %s", config.ActivityType)
+		}
+		activities = append(activities, agentActivity{
+			activityType: config.ActivityType,
+			newProcessor: config.NewProcessor,
+			pool:         defaults,
+		})
+	}
+
+	types := make([]string, 0, len(config.Activities))
+	for activityType := range config.Activities {
+		types = append(types, activityType)
+	}
+	sort.Strings(types)
+
+	for _, activityType := range types {
+		if activityType == config.ActivityType {
+			return nil, fmt.Errorf("duplicate activity type: %s", activityType)
+		}
+		activityConfig := config.Activities[activityType]
+		if activityConfig.NewProcessor == nil {
+			return nil, fmt.Errorf("no processor specified for activity type %s", activityType)
+		}
+		activities = append(activities, agentActivity{
+			activityType: activityType,
+			newProcessor: activityConfig.NewProcessor,
+			pool: poolConfig{
+				Concurrency:     valueOrDefault(activityConfig.Concurrency, defaults.Concurrency),
+				BatchSize:       valueOrDefault(activityConfig.BatchSize, defaults.BatchSize),
+				MaxInFlight:     valueOrDefault(activityConfig.MaxInFlight, defaults.MaxInFlight),
+				ShutdownTimeout: valueOrDefault(activityConfig.ShutdownTimeout, defaults.ShutdownTimeout),
+			},
+		})
+	}
+
+	if len(activities) == 0 {
+		return nil, fmt.Errorf("no activity types specified for agent %s", config.AgentName)
+	}
+	return activities, nil
+}
+
+func valueOrDefault[T int | time.Duration](value T, defaultValue T) T {
+	if value > 0 {
+		return value
+	}
+	return defaultValue
+}
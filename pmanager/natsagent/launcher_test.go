@@ -15,6 +15,7 @@ package natsagent
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
@@ -185,4 +186,103 @@ type MockActivityProcessor struct{}
 
 func (m *MockActivityProcessor) Process(activityContext api.ActivityContext) api.ActivityResult {
 	return api.ActivityResult{Result: api.ActivityResultComplete}
-}
\ No newline at end of file
+}
+
+func Test_loadPoolConfig(t *testing.T) {
+	_ = os.Setenv("TESTAGENT_CONCURRENCY", "8")
+	_ = os.Setenv("TESTAGENT_SHUTDOWN_TIMEOUT", "5")
+
+	t.Cleanup(func() {
+		_ = os.Unsetenv("TESTAGENT_CONCURRENCY")
+		_ = os.Unsetenv("TESTAGENT_SHUTDOWN_TIMEOUT")
+	})
+
+	config := LauncherConfig{
+		ConfigPrefix:    "testagent",
+		Concurrency:     2,
+		BatchSize:       4,
+		MaxInFlight:     6,
+		ShutdownTimeout: time.Minute,
+	}
+
+	pool := loadPoolConfig(system.LoadConfigOrPanic(config.ConfigPrefix), config)
+
+	// Configuration values override the launcher configuration
+	require.Equal(t, 8, pool.Concurrency)
+	require.Equal(t, 5*time.Second, pool.ShutdownTimeout)
+	require.Equal(t, 4, pool.BatchSize)
+	require.Equal(t, 6, pool.MaxInFlight)
+}
+
+func Test_collectActivities(t *testing.T) {
+	newProcessor := func(ctx *AgentContext) api.ActivityProcessor {
+		return &MockActivityProcessor{}
+	}
+	defaults := poolConfig{Concurrency: 2, BatchSize: 4, MaxInFlight: 8, ShutdownTimeout: time.Minute}
+
+	config := LauncherConfig{
+		AgentName:    "TestAgent",
+		ActivityType: "test-activity",
+		NewProcessor: newProcessor,
+		Activities: map[string]ActivityConfig{
+			"test-delete-activity": {NewProcessor: newProcessor, Concurrency: 5},
+			"test-create-activity": {NewProcessor: newProcessor, ShutdownTimeout: time.Second},
+		},
+	}
+
+	activities, err := collectActivities(config, defaults)
+	require.NoError(t, err)
+	require.Len(t, activities, 3)
+
+	require.Equal(t, "test-activity", activities[0].activityType)
+	require.Equal(t, defaults, activities[0].pool)
+
+	require.Equal(t, "test-create-activity", activities[1].activityType)
+	require.Equal(t, poolConfig{Concurrency: 2, BatchSize: 4, MaxInFlight: 8, ShutdownTimeout: time.Second}, activities[1].pool)
+
+	require.Equal(t, "test-delete-activity", activities[2].activityType)
+	require.Equal(t, poolConfig{Concurrency: 5, BatchSize: 4, MaxInFlight: 8, ShutdownTimeout: time.Minute}, activities[2].pool)
+}
+
+func Test_collectActivities_Invalid(t *testing.T) {
+	newProcessor := func(ctx *AgentContext) api.ActivityProcessor {
+		return &MockActivityProcessor{}
+	}
+
+	_, err := collectActivities(LauncherConfig{AgentName: "TestAgent"}, poolConfig{})
+	require.Error(t, err, "an agent must host at least one activity type")
+
+	_, err = collectActivities(LauncherConfig{
+		ActivityType: "test-activity",
+		NewProcessor: newProcessor,
+		Activities:   map[string]ActivityConfig{"test-activity": {NewProcessor: newProcessor}},
+	}, poolConfig{})
+	require.Error(t, err, "activity types must be unique")
+
+	_, err = collectActivities(LauncherConfig{
+		Activities: map[string]ActivityConfig{"test-activity": {}},
+	}, poolConfig{})
+	require.Error(t, err, "activity types require a processor")
+}
+
+func Test_loadMetricsPort(t *testing.T) {
+	vConfig := system.LoadConfigOrPanic("testagent")
+	// The endpoint is disabled by default so that co-located agents do not compete for a port
+	require.Equal(t, 0, loadMetricsPort(vConfig, LauncherConfig{}))
+	require.Equal(t, 9191, loadMetricsPort(vConfig, LauncherConfig{MetricsPort: 9191}))
+	require.Equal(t, -1, loadMetricsPort(vConfig, LauncherConfig{MetricsPort: -1}))
+
+	_ = os.Setenv("TESTAGENT_METRICS_PORT", "0")
+	t.Cleanup(func() {
+		_ = os.Unsetenv("TESTAGENT_METRICS_PORT")
+	})
+
+	// The configuration value overrides the launcher configuration and disables the endpoint
+	vConfig = system.LoadConfigOrPanic("testagent")
+	require.Equal(t, 0, loadMetricsPort(vConfig, LauncherConfig{MetricsPort: 9191}))
+
+	// The configuration value enables the endpoint
+	_ = os.Setenv("TESTAGENT_METRICS_PORT", "9292")
+	vConfig = system.LoadConfigOrPanic("testagent")
+	require.Equal(t, 9292, loadMetricsPort(vConfig, LauncherConfig{}))
+}
@@ -0,0 +1,72 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const defaultRegistrationTTL = 30 * time.Second
+
+// agentRegistrar registers the agent and its activity types in the agent KV bucket and refreshes the registration
+// periodically. Registrations that are not refreshed expire after the bucket TTL.
+type agentRegistrar struct {
+	kv           jetstream.KeyValue
+	registration api.AgentRegistration
+	interval     time.Duration
+	monitor      system.LogMonitor
+}
+
+func (r *agentRegistrar) register(ctx context.Context) error {
+	r.registration.Heartbeat = time.Now()
+	serialized, err := json.Marshal(r.registration)
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent registration: %w", err)
+	}
+	if _, err = r.kv.Put(ctx, r.registration.InstanceID, serialized); err != nil {
+		return fmt.Errorf("failed to register agent %s: %w", r.registration.InstanceID, err)
+	}
+	return nil
+}
+
+// start refreshes the registration until the context is canceled.
+func (r *agentRegistrar) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.register(ctx); err != nil {
+					r.monitor.Warnf("Error refreshing agent registration: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *agentRegistrar) deregister(ctx context.Context) error {
+	if err := r.kv.Delete(ctx, r.registration.InstanceID); err != nil {
+		return fmt.Errorf("failed to deregister agent %s: %w", r.registration.InstanceID, err)
+	}
+	return nil
+}
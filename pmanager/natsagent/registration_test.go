@@ -0,0 +1,103 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsagent
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentRegistrar_RegisterAndDeregister(t *testing.T) {
+	kv := &registrationKV{entries: make(map[string][]byte)}
+	registrar := &agentRegistrar{
+		kv: kv,
+		registration: api.AgentRegistration{
+			InstanceID:    "instance-1",
+			Name:          "Test Agent",
+			Version:       "1.0.0",
+			ActivityTypes: []api.ActivityType{"test-activity"},
+		},
+		interval: time.Millisecond,
+		monitor:  system.NoopMonitor{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, registrar.register(ctx))
+
+	first := kv.registration(t, "instance-1")
+	assert.Equal(t, "Test Agent", first.Name)
+	assert.Equal(t, "1.0.0", first.Version)
+	assert.Equal(t, []api.ActivityType{"test-activity"}, first.ActivityTypes)
+	assert.False(t, first.Heartbeat.IsZero())
+
+	// The registration heartbeat is refreshed periodically
+	registrar.start(ctx)
+	require.Eventually(t, func() bool {
+		return kv.registration(t, "instance-1").Heartbeat.After(first.Heartbeat)
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	require.NoError(t, registrar.deregister(context.Background()))
+	assert.Empty(t, kv.keys())
+}
+
+type registrationKV struct {
+	jetstream.KeyValue
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func (k *registrationKV) Put(ctx context.Context, key string, value []byte) (uint64, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	k.entries[key] = value
+	return 1, nil
+}
+
+func (k *registrationKV) Delete(_ context.Context, key string, _ ...jetstream.KVDeleteOpt) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.entries, key)
+	return nil
+}
+
+func (k *registrationKV) registration(t *testing.T, key string) api.AgentRegistration {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var registration api.AgentRegistration
+	require.NoError(t, json.Unmarshal(k.entries[key], &registration))
+	return registration
+}
+
+func (k *registrationKV) keys() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	keys := make([]string, 0, len(k.entries))
+	for key := range k.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
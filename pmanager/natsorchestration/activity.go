@@ -15,24 +15,55 @@ package natsorchestration
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/metaform/connector-fabric-manager/common/metrics"
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	fetchMaxWait           = time.Second
+)
+
+// NatsActivityExecutor consumes activity messages of a single type and dispatches them to its ActivityProcessor.
+//
+// Messages are processed by Concurrency workers. The executor fetches up to BatchSize messages at a time and holds at
+// most MaxInFlight fetched messages that have not completed processing. When the context passed to Execute is canceled,
+// the executor stops fetching and waits up to ShutdownTimeout for in-flight messages to complete. Messages that have not
+// completed by then are rejected so that they are redelivered.
 type NatsActivityExecutor struct {
 	Client            natsclient.MsgClient
 	StreamName        string
 	ActivityType      string
 	ActivityProcessor api.ActivityProcessor
 	Monitor           system.LogMonitor
+
+	// Concurrency is the number of workers processing messages. Defaults to 1.
+	Concurrency int
+
+	// BatchSize is the maximum number of messages requested in a single fetch. Defaults to 1.
+	BatchSize int
+
+	// MaxInFlight is the maximum number of fetched messages that have not completed processing. Defaults to the
+	// greater of Concurrency and BatchSize.
+	MaxInFlight int
+
+	// ShutdownTimeout is the time to wait for in-flight messages to complete on shutdown. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+
+	stopped chan struct{}
 }
 
 // Execute starts a goroutine to process messages from the activity queue.
@@ -48,35 +79,144 @@ func (e *NatsActivityExecutor) Execute(ctx context.Context) error {
 		return fmt.Errorf("error connecting to consumer %s: %w", consumerName, err)
 	}
 
+	e.stopped = make(chan struct{})
 	go func() {
+		defer close(e.stopped)
 		err := e.processLoop(ctx, consumer)
-		if err != nil {
+		if err != nil && !errors.Is(err, context.Canceled) {
 			e.Monitor.Warnf("Error processing message: %v", err)
 		}
 	}()
 	return nil
 }
 
+// Wait blocks until the executor has stopped after the context passed to Execute is canceled, including waiting for
+// in-flight messages. Returns immediately if the executor was not started.
+func (e *NatsActivityExecutor) Wait() {
+	if e.stopped != nil {
+		<-e.stopped
+	}
+}
+
 // processLoop handles the main loop for consuming and processing messages from a JetStream consumer.
-// It runs continuously until the provided context is canceled or an error occurs.
-// Returns an error if message fetching or processing fails.
+// It runs continuously until the provided context is canceled or an error occurs, then drains in-flight messages.
+// Returns an error if message fetching fails.
 func (e *NatsActivityExecutor) processLoop(ctx context.Context, consumer jetstream.Consumer) error {
+	concurrency, batchSize, maxInFlight := e.poolSize()
+
+	// In-flight messages are processed to completion on shutdown, so they must not use the canceled context
+	processCtx := context.WithoutCancel(ctx)
+	tracker := newInFlightTracker()
+	slots := make(chan struct{}, maxInFlight)
+	messages := make(chan jetstream.Msg, maxInFlight)
+
+	var workers sync.WaitGroup
+	for range concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for message := range messages {
+				if tracker.start(message) {
+					if err := e.processMessage(processCtx, message); err != nil {
+						e.Monitor.Warnf("Error processing message: %v", err)
+					}
+					tracker.done(message)
+				}
+				<-slots
+			}
+		}()
+	}
+
+	err := e.fetchLoop(ctx, consumer, batchSize, slots, messages, tracker)
+	close(messages)
+	e.drain(&workers, tracker)
+	return err
+}
+
+// fetchLoop fetches messages and dispatches them to the workers until the context is canceled. Only as many messages
+// are fetched as there are free in-flight slots.
+func (e *NatsActivityExecutor) fetchLoop(
+	ctx context.Context,
+	consumer jetstream.Consumer,
+	batchSize int,
+	slots chan struct{},
+	messages chan<- jetstream.Msg,
+	tracker *inFlightTracker) error {
 	for {
+		// Wait for at least one free slot, then claim as many as are available up to the batch size
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		default:
-			messageBatch, err := consumer.Fetch(1, jetstream.FetchMaxWait(time.Second))
-			if err != nil {
-				return err
+		case slots <- struct{}{}:
+		}
+		claimed := 1
+	claim:
+		for claimed < batchSize {
+			select {
+			case slots <- struct{}{}:
+				claimed++
+			default:
+				break claim
 			}
+		}
 
-			for message := range messageBatch.Messages() {
-				if err = e.processMessage(ctx, message); err != nil {
-					e.Monitor.Warnf("Error processing message: %v", err)
-				}
+		messageBatch, err := consumer.Fetch(claimed, jetstream.FetchMaxWait(fetchMaxWait))
+		if err != nil {
+			releaseSlots(slots, claimed)
+			return err
+		}
+
+		for message := range messageBatch.Messages() {
+			tracker.add(message)
+			messages <- message
+			claimed--
+		}
+		releaseSlots(slots, claimed)
+	}
+}
+
+// drain waits for the workers to process in-flight messages up to the shutdown timeout. Messages that have not been
+// started by then are rejected so that they are redelivered. Messages that are still being processed are redelivered
+// when their acknowledgement deadline passes if they do not complete.
+func (e *NatsActivityExecutor) drain(workers *sync.WaitGroup, tracker *inFlightTracker) {
+	finished := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(finished)
+	}()
+
+	shutdownTimeout := e.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(shutdownTimeout):
+		queued, processing := tracker.abandon()
+		for _, message := range queued {
+			if err := message.Nak(); err != nil {
+				e.Monitor.Warnf("Failed to NAK queued message on shutdown: %v", err)
 			}
 		}
+		e.Monitor.Warnf("Shutdown timeout exceeded for %s, rejected %d queued messages, %d messages still processing",
+			e.ActivityType, len(queued), processing)
+	}
+}
+
+func (e *NatsActivityExecutor) poolSize() (concurrency int, batchSize int, maxInFlight int) {
+	concurrency = max(e.Concurrency, 1)
+	batchSize = max(e.BatchSize, 1)
+	maxInFlight = e.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = max(concurrency, batchSize)
+	}
+	return concurrency, min(batchSize, maxInFlight), maxInFlight
+}
+
+func releaseSlots(slots <-chan struct{}, count int) {
+	for range count {
+		<-slots
 	}
 }
 
@@ -85,30 +225,107 @@ func (e *NatsActivityExecutor) processLoop(ctx context.Context, consumer jetstre
 // orchestration can proceed, and the original message is acknowledged.
 //
 // Returns an error if message processing fails.
-func (e *NatsActivityExecutor) processMessage(ctx context.Context, message jetstream.Msg) error {
+func (e *NatsActivityExecutor) processMessage(ctx context.Context, message jetstream.Msg) (err error) {
+	// The span is carried by the activity context so that processors can create child spans
+	ctx, span := natsclient.StartConsumerSpan(ctx, message, "process activity "+e.ActivityType)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	delivery, err := e.readDelivery(ctx, message)
+	if err != nil || delivery == nil {
+		return err
+	}
+	span.SetAttributes(activityAttributes(delivery)...)
+
+	activityContext := e.newActivityContext(ctx, delivery)
+
+	e.Monitor.Debugf("Received activity message %s for orchestration %s", delivery.activity.ID, delivery.orchestration.ID)
+	start := time.Now()
+	result := e.ActivityProcessor.Process(activityContext)
+	metrics.ActivityProcessed(delivery.activity.Type.String(), result.Result.String(), time.Since(start))
+	span.SetAttributes(attribute.String("cfm.activity.result", result.Result.String()))
+
+	return e.handleResult(activityContext, delivery, result)
+}
+
+// activityDelivery is a received activity message and the orchestration state read when it was received.
+type activityDelivery struct {
+	message       jetstream.Msg
+	activity      api.Activity
+	orchestration api.Orchestration
+	revision      uint64
+}
+
+// activityAttributes returns the span attributes identifying the activity of the delivery.
+func activityAttributes(delivery *activityDelivery) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("cfm.orchestration.id", delivery.orchestration.ID),
+		attribute.String("cfm.orchestration.type", delivery.orchestration.OrchestrationType.String()),
+		attribute.String("cfm.activity.id", delivery.activity.ID),
+		attribute.String("cfm.activity.type", delivery.activity.Type.String()),
+	}
+}
+
+// readDelivery decodes the activity message and reads the current orchestration state. If the message must not be
+// processed because it is malformed, the activity has already completed, or the orchestration has failed, the message
+// is acknowledged and nil is returned.
+func (e *NatsActivityExecutor) readDelivery(ctx context.Context, message jetstream.Msg) (*activityDelivery, error) {
 	var oMessage api.ActivityMessage
 	if err := json.Unmarshal(message.Data(), &oMessage); err != nil {
 		ackErr := natsclient.AckMessage(message)
 		if ackErr != nil {
 			e.Monitor.Warnf("Failed to ACK message: %v", ackErr)
 		}
-		return fmt.Errorf("failed to unmarshal orchestration message: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal orchestration message: %w", err)
 	}
 
 	orchestration, revision, err := ReadOrchestration(ctx, oMessage.OrchestrationID, e.Client)
 	if err != nil {
-		return fmt.Errorf("failed to read orchestration data: %w", err)
+		return nil, fmt.Errorf("failed to read orchestration data: %w", err)
+	}
+
+	if _, completed := orchestration.Completed[oMessage.Activity.ID]; completed || orchestration.State == api.OrchestrationStateErrored {
+		// The activity was re-enqueued after it completed or the orchestration failed; drop the message
+		e.Monitor.Debugf("Skipping activity message %s for orchestration %s", oMessage.Activity.ID, oMessage.OrchestrationID)
+		return nil, natsclient.AckMessage(message)
 	}
 
-	activityContext := api.NewActivityContext(
+	if metadata, err := message.Metadata(); err == nil && metadata.NumDelivered > 1 {
+		metrics.MessageRedelivered(oMessage.Activity.Type.String())
+	}
+
+	return &activityDelivery{
+		message:       message,
+		activity:      oMessage.Activity,
+		orchestration: orchestration,
+		revision:      revision,
+	}, nil
+}
+
+// newActivityContext creates the context passed to the activity processor for the delivery.
+func (e *NatsActivityExecutor) newActivityContext(ctx context.Context, delivery *activityDelivery) api.ActivityContext {
+	orchestration := delivery.orchestration
+	return api.NewActivityContext(
 		ctx,
 		orchestration.ID,
-		oMessage.Activity,
+		delivery.activity,
 		orchestration.ProcessingData,
-		orchestration.OutputData)
+		orchestration.OutputData,
+		api.WithHeartbeat(e.heartbeat(ctx, delivery.message, orchestration.ID, delivery.activity.ID)),
+		api.WithProgressReporter(e.progress(ctx, delivery.message, orchestration.ID, delivery.activity)))
+}
 
-	e.Monitor.Debugf("Received activity message %s for orchestration %s", oMessage.Activity.ID, oMessage.OrchestrationID)
-	result := e.ActivityProcessor.Process(activityContext)
+// handleResult updates the orchestration state based on the activity result and acknowledges, rejects, or reschedules
+// the message.
+func (e *NatsActivityExecutor) handleResult(
+	activityContext api.ActivityContext,
+	delivery *activityDelivery,
+	result api.ActivityResult) error {
+	orchestration := delivery.orchestration
+	revision := delivery.revision
+	message := delivery.message
 
 	switch result.Result {
 	case api.ActivityResultRetryError:
@@ -126,16 +343,74 @@ func (e *NatsActivityExecutor) processMessage(ctx context.Context, message jetst
 		// This ensures processing data is saved for the next invocation
 		e.persistState(activityContext, orchestration, revision)
 		if err := message.NakWithDelay(result.WaitOnReschedule); err != nil {
-			return fmt.Errorf("failed to reschedule schedule activity %s: %w", oMessage.OrchestrationID, err)
+			return fmt.Errorf("failed to reschedule schedule activity %s: %w", orchestration.ID, err)
 		}
 		return nil
 	}
 
+	oMessage := api.ActivityMessage{OrchestrationID: orchestration.ID, Activity: delivery.activity}
 	return e.processOnActivityCompletion(activityContext, orchestration, revision, message, oMessage)
 }
 
+// heartbeat returns a function that extends the message acknowledgement deadline and records the heartbeat time in the
+// orchestration so that stalled activities can be detected.
+func (e *NatsActivityExecutor) heartbeat(
+	ctx context.Context,
+	message jetstream.Msg,
+	orchestrationID string,
+	activityID string) func() error {
+	return func() error {
+		_, err := e.signalProgress(ctx, message, orchestrationID, activityID, func(o *api.Orchestration) {
+			o.RecordHeartbeat(activityID, time.Now())
+		})
+		return err
+	}
+}
+
+// progress returns a function that records the progress reported by the activity in the orchestration and publishes it
+// to the progress subject. Reporting progress also signals a heartbeat.
+func (e *NatsActivityExecutor) progress(
+	ctx context.Context,
+	message jetstream.Msg,
+	orchestrationID string,
+	activity api.Activity) func(string, int, string) error {
+	return func(vpaID string, percent int, statusMessage string) error {
+		progress := api.ActivityProgress{VPAID: vpaID, Percent: percent, Message: statusMessage, Timestamp: time.Now()}
+		orchestration, err := e.signalProgress(ctx, message, orchestrationID, activity.ID, func(o *api.Orchestration) {
+			o.RecordHeartbeat(activity.ID, progress.Timestamp)
+			o.RecordProgress(activity.ID, progress)
+		})
+		if err != nil {
+			return err
+		}
+		return publishOrchestrationProgress(ctx, orchestration, activity, progress, e.Client)
+	}
+}
+
+// signalProgress extends the message acknowledgement deadline and applies the update to the orchestration.
+func (e *NatsActivityExecutor) signalProgress(
+	ctx context.Context,
+	message jetstream.Msg,
+	orchestrationID string,
+	activityID string,
+	updateFn func(*api.Orchestration)) (api.Orchestration, error) {
+	if err := message.InProgress(); err != nil {
+		return api.Orchestration{}, fmt.Errorf("failed to signal progress for activity %s: %w", activityID, err)
+	}
+	orchestration, revision, err := ReadOrchestration(ctx, orchestrationID, e.Client)
+	if err != nil {
+		return api.Orchestration{}, err
+	}
+	orchestration, _, err = UpdateOrchestration(ctx, orchestration, revision, e.Client, updateFn)
+	if err != nil {
+		return api.Orchestration{}, fmt.Errorf("failed to record progress for activity %s: %w", activityID, err)
+	}
+	return orchestration, nil
+}
+
 func (e *NatsActivityExecutor) persistState(activityContext api.ActivityContext, orchestration api.Orchestration, revision uint64) {
 	if _, _, err := UpdateOrchestration(activityContext.Context(), orchestration, revision, e.Client, func(o *api.Orchestration) {
+		o.ClearHeartbeat(activityContext.ID())
 		for key, value := range activityContext.Values() {
 			o.ProcessingData[key] = value
 		}
@@ -164,6 +439,7 @@ func (e *NatsActivityExecutor) processOnActivityCompletion(
 			o.OutputData[key] = value
 		}
 		o.Completed[oMessage.Activity.ID] = struct{}{} // Mark current activity as completed
+		o.ClearHeartbeat(oMessage.Activity.ID)
 	})
 	if err != nil {
 		err = natsclient.NakError(message, err)
@@ -209,7 +485,9 @@ func (e *NatsActivityExecutor) handleOrchestrationCompletion(
 	revision uint64,
 	message jetstream.Msg) error {
 	// Mark as completed
+	alreadyCompleted := false
 	_, _, err := UpdateOrchestration(activityContext.Context(), orchestration, revision, e.Client, func(o *api.Orchestration) {
+		alreadyCompleted = o.State == api.OrchestrationStateCompleted
 		o.SetState(api.OrchestrationStateCompleted)
 	})
 	if err != nil {
@@ -217,6 +495,9 @@ func (e *NatsActivityExecutor) handleOrchestrationCompletion(
 		err = natsclient.NakError(message, err)
 		return fmt.Errorf("failed to mark orchestration %s as completed: %v", orchestration.ID, err)
 	}
+	if !alreadyCompleted {
+		metrics.OrchestrationCompleted(orchestration.OrchestrationType.String())
+	}
 
 	err = e.publishResponse(activityContext, orchestration)
 	if err != nil {
@@ -227,22 +508,67 @@ func (e *NatsActivityExecutor) handleOrchestrationCompletion(
 }
 
 func (e *NatsActivityExecutor) publishResponse(activityContext api.ActivityContext, orchestration api.Orchestration) error {
+	return publishOrchestrationResponse(activityContext.Context(), orchestration, true, "", e.Client)
+}
+
+// publishOrchestrationResponse publishes the outcome of the orchestration to the response subject. Results reported by
+// activities for individual VPAs are returned separately from the other output values.
+func publishOrchestrationResponse(
+	ctx context.Context,
+	orchestration api.Orchestration,
+	success bool,
+	errorDetail string,
+	client natsclient.MsgClient) error {
+	vpaResults, properties := model.ExtractVPAResults(orchestration.OutputData)
 	response := &model.OrchestrationResponse{
 		ID:                uuid.New().String(),
 		ManifestID:        orchestration.ID,
 		CorrelationID:     orchestration.CorrelationID,
-		Success:           true,
+		Success:           success,
+		ErrorDetail:       errorDetail,
 		OrchestrationType: orchestration.OrchestrationType,
-		Properties:        orchestration.OutputData,
+		Properties:        properties,
+		VPAResults:        vpaResults,
 	}
 	ser, err := json.Marshal(response)
 	if err != nil {
 		return fmt.Errorf("failed to marshal orchestration response: %w", err)
 	}
-	_, err = e.Client.Publish(activityContext.Context(), natsclient.CFMOrchestrationResponseSubject, ser)
+	_, err = natsclient.PublishTraced(ctx, client, natsclient.CFMOrchestrationResponseSubject, ser)
 	return err
 }
 
+// publishOrchestrationProgress publishes the progress reported by an activity to the progress subject.
+func publishOrchestrationProgress(
+	ctx context.Context,
+	orchestration api.Orchestration,
+	activity api.Activity,
+	progress api.ActivityProgress,
+	client natsclient.MsgClient) error {
+	message := &model.OrchestrationProgress{
+		ID:                   uuid.New().String(),
+		ManifestID:           orchestration.ID,
+		CorrelationID:        orchestration.CorrelationID,
+		OrchestrationType:    orchestration.OrchestrationType,
+		ActivityID:           activity.ID,
+		ActivityType:         activity.Type.String(),
+		Percent:              progress.Percent,
+		OrchestrationPercent: orchestration.OverallProgress(),
+		VPAID:                progress.VPAID,
+		VPAPercents:          orchestration.VPAPercents(),
+		Message:              progress.Message,
+		Timestamp:            progress.Timestamp,
+	}
+	ser, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal orchestration progress: %w", err)
+	}
+	if _, err = natsclient.PublishTraced(ctx, client, natsclient.CFMOrchestrationProgressSubject, ser); err != nil {
+		return fmt.Errorf("failed to publish orchestration progress: %w", err)
+	}
+	return nil
+}
+
 // handleRetryError handles retriable errors by persisting the orchestration state and re-delivering the message using a Nak.
 func (e *NatsActivityExecutor) handleRetryError(
 	activityContext api.ActivityContext,
@@ -270,16 +596,21 @@ func (e *NatsActivityExecutor) handleFatalError(
 	resultErr error,
 	message jetstream.Msg) error {
 	// Update the orchestration before acking back. If the update fails, just log it to ensure the ack is sent to avoid message re-delivery
+	alreadyErrored := false
 	if _, _, err := UpdateOrchestration(activityContext.Context(), orchestration, revision, e.Client, func(o *api.Orchestration) {
+		alreadyErrored = o.State == api.OrchestrationStateErrored
 		for key, value := range activityContext.Values() {
 			orchestration.ProcessingData[key] = value
 		}
 		for key, value := range activityContext.OutputValues() {
 			orchestration.OutputData[key] = value
 		}
+		o.ClearHeartbeat(activityContext.ID())
 		o.SetState(api.OrchestrationStateErrored)
 	}); err != nil {
 		e.Monitor.Warnf("Failed to mark orchestration %s as fatal: %v", orchestration.ID, err)
+	} else if !alreadyErrored {
+		metrics.OrchestrationErrored(orchestration.OrchestrationType.String())
 	}
 
 	if err := message.Ack(); err != nil {
@@ -0,0 +1,305 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/mocks"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNatsActivityExecutor_ConcurrentWorkers(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createPoolOrchestration(3)
+	newStoredOrchestration(t, client, orchestration)
+
+	messages := createPoolMessages(t, orchestration.ID, 3)
+	consumer := newPoolConsumer(messages)
+
+	// Each activity blocks until all three are running, which only succeeds if they are processed concurrently
+	var running sync.WaitGroup
+	running.Add(3)
+	processor := &poolProcessor{process: func(api.ActivityContext) api.ActivityResult {
+		running.Done()
+		running.Wait()
+		return api.ActivityResult{Result: api.ActivityResultWait}
+	}}
+
+	executor := newPoolExecutor(client, processor)
+	executor.Concurrency = 3
+	executor.BatchSize = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runProcessLoop(ctx, executor, consumer)
+
+	require.Eventually(t, func() bool {
+		return allAcked(messages)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-stopped
+	assert.Equal(t, int32(3), processor.invocations.Load())
+}
+
+func TestNatsActivityExecutor_MaxInFlight(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createPoolOrchestration(5)
+	newStoredOrchestration(t, client, orchestration)
+
+	messages := createPoolMessages(t, orchestration.ID, 5)
+	consumer := newPoolConsumer(messages)
+
+	var inFlight, maxObserved atomic.Int32
+	processor := &poolProcessor{process: func(api.ActivityContext) api.ActivityResult {
+		current := inFlight.Add(1)
+		for {
+			observed := maxObserved.Load()
+			if current <= observed || maxObserved.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return api.ActivityResult{Result: api.ActivityResultWait}
+	}}
+
+	executor := newPoolExecutor(client, processor)
+	executor.Concurrency = 4
+	executor.BatchSize = 5
+	executor.MaxInFlight = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runProcessLoop(ctx, executor, consumer)
+
+	require.Eventually(t, func() bool {
+		return allAcked(messages)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-stopped
+	assert.LessOrEqual(t, maxObserved.Load(), int32(2))
+	for _, requested := range consumer.requestedBatches() {
+		assert.LessOrEqual(t, requested, 2)
+	}
+}
+
+func TestNatsActivityExecutor_GracefulShutdown(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createPoolOrchestration(1)
+	newStoredOrchestration(t, client, orchestration)
+
+	messages := createPoolMessages(t, orchestration.ID, 1)
+	consumer := newPoolConsumer(messages)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	processor := &poolProcessor{process: func(api.ActivityContext) api.ActivityResult {
+		close(started)
+		<-release
+		return api.ActivityResult{Result: api.ActivityResultWait}
+	}}
+
+	executor := newPoolExecutor(client, processor)
+	executor.ShutdownTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runProcessLoop(ctx, executor, consumer)
+
+	<-started
+	cancel()
+
+	// The executor waits for the in-flight activity to complete
+	select {
+	case <-stopped:
+		t.Fatal("executor stopped before the in-flight activity completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+	assert.True(t, messages[0].isAcked())
+	assert.False(t, messages[0].isNaked())
+}
+
+func TestNatsActivityExecutor_ShutdownTimeoutNaksQueued(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createPoolOrchestration(2)
+	newStoredOrchestration(t, client, orchestration)
+
+	messages := createPoolMessages(t, orchestration.ID, 2)
+	consumer := newPoolConsumer(messages)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	defer close(release)
+	processor := &poolProcessor{process: func(api.ActivityContext) api.ActivityResult {
+		started <- struct{}{}
+		<-release
+		return api.ActivityResult{Result: api.ActivityResultWait}
+	}}
+
+	// A single worker processes the first message while the second is queued
+	executor := newPoolExecutor(client, processor)
+	executor.BatchSize = 2
+	executor.ShutdownTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runProcessLoop(ctx, executor, consumer)
+
+	<-started
+	cancel()
+	<-stopped
+
+	// The executing message is not rejected since it would be redelivered while the activity is still running
+	assert.False(t, messages[0].isNaked())
+	assert.False(t, messages[0].isAcked())
+	assert.True(t, messages[1].isNaked())
+	assert.Equal(t, int32(1), processor.invocations.Load(), "queued message must not be processed after shutdown")
+}
+
+func TestNatsActivityExecutor_PoolSizeDefaults(t *testing.T) {
+	executor := &NatsActivityExecutor{}
+	concurrency, batchSize, maxInFlight := executor.poolSize()
+	assert.Equal(t, 1, concurrency)
+	assert.Equal(t, 1, batchSize)
+	assert.Equal(t, 1, maxInFlight)
+
+	executor = &NatsActivityExecutor{Concurrency: 4, BatchSize: 10}
+	concurrency, batchSize, maxInFlight = executor.poolSize()
+	assert.Equal(t, 4, concurrency)
+	assert.Equal(t, 10, batchSize)
+	assert.Equal(t, 10, maxInFlight)
+
+	// The batch size is limited by the in-flight limit
+	executor = &NatsActivityExecutor{Concurrency: 2, BatchSize: 10, MaxInFlight: 3}
+	_, batchSize, maxInFlight = executor.poolSize()
+	assert.Equal(t, 3, batchSize)
+	assert.Equal(t, 3, maxInFlight)
+}
+
+func newPoolExecutor(client *mocks.MockMsgClient, processor api.ActivityProcessor) *NatsActivityExecutor {
+	return &NatsActivityExecutor{
+		Client:            client,
+		ActivityType:      leaseActivityType.String(),
+		ActivityProcessor: processor,
+		Monitor:           system.NoopMonitor{},
+	}
+}
+
+func runProcessLoop(ctx context.Context, executor *NatsActivityExecutor, consumer jetstream.Consumer) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		_ = executor.processLoop(ctx, consumer)
+	}()
+	return stopped
+}
+
+// createPoolOrchestration creates an orchestration with a single step containing the given number of parallel activities.
+func createPoolOrchestration(activities int) api.Orchestration {
+	orchestration := createLeaseOrchestration()
+	orchestration.Steps[0].Activities = make([]api.Activity, activities)
+	for i := range activities {
+		orchestration.Steps[0].Activities[i] = api.Activity{ID: fmt.Sprintf("A%d", i+1), Type: leaseActivityType}
+	}
+	return orchestration
+}
+
+func createPoolMessages(t *testing.T, orchestrationID string, count int) []*leaseMessage {
+	messages := make([]*leaseMessage, count)
+	for i := range count {
+		messages[i] = newLeaseMessage(t, orchestrationID, fmt.Sprintf("A%d", i+1))
+	}
+	return messages
+}
+
+func allAcked(messages []*leaseMessage) bool {
+	for _, message := range messages {
+		if !message.isAcked() {
+			return false
+		}
+	}
+	return true
+}
+
+type poolProcessor struct {
+	process     func(api.ActivityContext) api.ActivityResult
+	invocations atomic.Int32
+}
+
+func (p *poolProcessor) Process(activityContext api.ActivityContext) api.ActivityResult {
+	p.invocations.Add(1)
+	return p.process(activityContext)
+}
+
+// poolConsumer returns queued messages from Fetch and records the requested batch sizes.
+type poolConsumer struct {
+	jetstream.Consumer
+	mu        sync.Mutex
+	messages  []*leaseMessage
+	requested []int
+}
+
+func newPoolConsumer(messages []*leaseMessage) *poolConsumer {
+	return &poolConsumer{messages: messages}
+}
+
+func (c *poolConsumer) Fetch(batch int, _ ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	c.mu.Lock()
+	c.requested = append(c.requested, batch)
+	count := min(batch, len(c.messages))
+	fetched := c.messages[:count]
+	c.messages = c.messages[count:]
+	c.mu.Unlock()
+
+	if count == 0 {
+		// Simulate the fetch wait when no messages are available
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	channel := make(chan jetstream.Msg, count)
+	for _, message := range fetched {
+		channel <- message
+	}
+	close(channel)
+	return &poolBatch{messages: channel}, nil
+}
+
+func (c *poolConsumer) requestedBatches() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int(nil), c.requested...)
+}
+
+type poolBatch struct {
+	jetstream.MessageBatch
+	messages chan jetstream.Msg
+}
+
+func (b *poolBatch) Messages() <-chan jetstream.Msg {
+	return b.messages
+}
+
+func (b *poolBatch) Error() error {
+	return nil
+}
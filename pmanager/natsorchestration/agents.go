@@ -0,0 +1,152 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const defaultAgentRegistrationTTL = 30 * time.Second
+
+// NatsAgentRegistry reads agent registrations from the agent KV bucket. Agents refresh their registrations periodically,
+// and registrations of agents that stop are removed by the bucket TTL. TTL is used if the bucket does not exist when an
+// agent is registered.
+type NatsAgentRegistry struct {
+	JetStream jetstream.JetStream
+	TTL       time.Duration
+	Monitor   system.LogMonitor
+}
+
+func (r *NatsAgentRegistry) GetAgents(ctx context.Context) ([]api.AgentRegistration, error) {
+	kv, err := r.JetStream.KeyValue(ctx, natsclient.CFMAgentBucket)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrBucketNotFound) {
+			// No agent has registered yet
+			return []api.AgentRegistration{}, nil
+		}
+		return nil, fmt.Errorf("unable to access agent bucket: %w", err)
+	}
+
+	lister, err := kv.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing agents: %w", err)
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer lister.Stop()
+
+	agents := make([]api.AgentRegistration, 0)
+	for key := range lister.Keys() {
+		entry, err := kv.Get(ctx, key)
+		if err != nil {
+			if errors.Is(err, jetstream.ErrKeyNotFound) {
+				// Deregistered or expired after listing
+				continue
+			}
+			return nil, fmt.Errorf("error reading agent %s: %w", key, err)
+		}
+		var registration api.AgentRegistration
+		if err = json.Unmarshal(entry.Value(), &registration); err != nil {
+			r.Monitor.Warnf("Skipping invalid agent registration %s: %v", key, err)
+			continue
+		}
+		agents = append(agents, registration)
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		if agents[i].Name != agents[j].Name {
+			return agents[i].Name < agents[j].Name
+		}
+		return agents[i].InstanceID < agents[j].InstanceID
+	})
+	return agents, nil
+}
+
+func (r *NatsAgentRegistry) GetActivityTypes(ctx context.Context) (map[api.ActivityType]struct{}, error) {
+	agents, err := r.GetAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activityTypes := make(map[api.ActivityType]struct{})
+	for _, agent := range agents {
+		for _, activityType := range agent.ActivityTypes {
+			activityTypes[activityType] = struct{}{}
+		}
+	}
+	return activityTypes, nil
+}
+
+func (r *NatsAgentRegistry) RegisterAgent(ctx context.Context, registration *api.AgentRegistration) (*api.AgentRegistration, error) {
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = defaultAgentRegistrationTTL
+	}
+	kv, err := natsclient.SetupAgentBucket(ctx, r.JetStream, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up agent bucket: %w", err)
+	}
+
+	registered := *registration
+	registered.Heartbeat = time.Now()
+	registered.StartedAt = registered.Heartbeat
+	entry, err := kv.Get(ctx, registration.InstanceID)
+	switch {
+	case err == nil:
+		// Refresh, keep the time the agent was first registered
+		var existing api.AgentRegistration
+		if json.Unmarshal(entry.Value(), &existing) == nil && !existing.StartedAt.IsZero() {
+			registered.StartedAt = existing.StartedAt
+		}
+	case !errors.Is(err, jetstream.ErrKeyNotFound):
+		return nil, fmt.Errorf("error reading agent %s: %w", registration.InstanceID, err)
+	}
+
+	serialized, err := json.Marshal(registered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal agent registration: %w", err)
+	}
+	if _, err = kv.Put(ctx, registered.InstanceID, serialized); err != nil {
+		return nil, fmt.Errorf("failed to register agent %s: %w", registered.InstanceID, err)
+	}
+	return &registered, nil
+}
+
+func (r *NatsAgentRegistry) DeregisterAgent(ctx context.Context, instanceID string) error {
+	kv, err := r.JetStream.KeyValue(ctx, natsclient.CFMAgentBucket)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrBucketNotFound) {
+			return types.NewRecoverableWrappedError(types.ErrNotFound, "agent %s not found", instanceID)
+		}
+		return fmt.Errorf("unable to access agent bucket: %w", err)
+	}
+	if _, err = kv.Get(ctx, instanceID); err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return types.NewRecoverableWrappedError(types.ErrNotFound, "agent %s not found", instanceID)
+		}
+		return fmt.Errorf("error reading agent %s: %w", instanceID, err)
+	}
+	if err = kv.Delete(ctx, instanceID); err != nil {
+		return fmt.Errorf("failed to deregister agent %s: %w", instanceID, err)
+	}
+	return nil
+}
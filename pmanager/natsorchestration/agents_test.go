@@ -0,0 +1,208 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNatsAgentRegistry_GetAgents(t *testing.T) {
+	kv := &agentKV{entries: map[string][]byte{
+		"instance-2": marshalAgent(t, api.AgentRegistration{
+			InstanceID:    "instance-2",
+			Name:          "Keycloak Agent",
+			ActivityTypes: []api.ActivityType{"keycloak-activity"},
+		}),
+		"instance-1": marshalAgent(t, api.AgentRegistration{
+			InstanceID:    "instance-1",
+			Name:          "EDC-V Agent",
+			Version:       "1.0.0",
+			ActivityTypes: []api.ActivityType{"edcv-activity", "keycloak-activity"},
+			Heartbeat:     time.Now(),
+		}),
+		"invalid": []byte("invalid"),
+	}}
+	registry := &NatsAgentRegistry{JetStream: &agentJetStream{kv: kv}, Monitor: system.NoopMonitor{}}
+
+	agents, err := registry.GetAgents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, agents, 2)
+	assert.Equal(t, "instance-1", agents[0].InstanceID)
+	assert.Equal(t, "1.0.0", agents[0].Version)
+	assert.Equal(t, "instance-2", agents[1].InstanceID)
+
+	activityTypes, err := registry.GetActivityTypes(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, activityTypes, 2)
+	assert.Contains(t, activityTypes, api.ActivityType("edcv-activity"))
+	assert.Contains(t, activityTypes, api.ActivityType("keycloak-activity"))
+}
+
+func TestNatsAgentRegistry_NoBucket(t *testing.T) {
+	registry := &NatsAgentRegistry{JetStream: &agentJetStream{}, Monitor: system.NoopMonitor{}}
+
+	agents, err := registry.GetAgents(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+
+	activityTypes, err := registry.GetActivityTypes(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, activityTypes)
+}
+
+func TestNatsAgentRegistry_RegisterAgent(t *testing.T) {
+	ctx := context.Background()
+	kv := &agentKV{entries: map[string][]byte{
+		"nats-agent": marshalAgent(t, api.AgentRegistration{
+			InstanceID:    "nats-agent",
+			Name:          "EDC-V Agent",
+			ActivityTypes: []api.ActivityType{"edcv-activity"},
+		}),
+	}}
+	registry := &NatsAgentRegistry{JetStream: &agentJetStream{kv: kv}, Monitor: system.NoopMonitor{}}
+
+	// An agent that leases activities over HTTP registers its activity types so that definitions using them are accepted
+	registered, err := registry.RegisterAgent(ctx, &api.AgentRegistration{
+		InstanceID:    "http-agent",
+		Name:          "HTTP Agent",
+		Version:       "1.0.0",
+		ActivityTypes: []api.ActivityType{"http-activity"},
+	})
+	require.NoError(t, err)
+	assert.False(t, registered.StartedAt.IsZero())
+	assert.Equal(t, registered.StartedAt, registered.Heartbeat)
+
+	activityTypes, err := registry.GetActivityTypes(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, activityTypes, api.ActivityType("edcv-activity"))
+	assert.Contains(t, activityTypes, api.ActivityType("http-activity"))
+
+	// Refreshing the registration keeps the time the agent was first registered
+	refreshed, err := registry.RegisterAgent(ctx, &api.AgentRegistration{
+		InstanceID:    "http-agent",
+		Name:          "HTTP Agent",
+		Version:       "1.0.0",
+		ActivityTypes: []api.ActivityType{"http-activity"},
+	})
+	require.NoError(t, err)
+	assert.True(t, refreshed.StartedAt.Equal(registered.StartedAt))
+	assert.False(t, refreshed.Heartbeat.Before(registered.Heartbeat))
+
+	require.NoError(t, registry.DeregisterAgent(ctx, "http-agent"))
+	activityTypes, err = registry.GetActivityTypes(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, activityTypes, api.ActivityType("http-activity"))
+
+	require.ErrorIs(t, registry.DeregisterAgent(ctx, "http-agent"), types.ErrNotFound)
+}
+
+func TestNatsAgentRegistry_RegisterAgentCreatesBucket(t *testing.T) {
+	js := &agentJetStream{}
+	registry := &NatsAgentRegistry{JetStream: js, TTL: time.Minute, Monitor: system.NoopMonitor{}}
+
+	_, err := registry.RegisterAgent(context.Background(), &api.AgentRegistration{
+		InstanceID:    "http-agent",
+		Name:          "HTTP Agent",
+		ActivityTypes: []api.ActivityType{"http-activity"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, js.createdTTL)
+
+	agents, err := registry.GetAgents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "http-agent", agents[0].InstanceID)
+}
+
+func marshalAgent(t *testing.T, registration api.AgentRegistration) []byte {
+	data, err := json.Marshal(registration)
+	require.NoError(t, err)
+	return data
+}
+
+type agentJetStream struct {
+	jetstream.JetStream
+	kv         *agentKV
+	createdTTL time.Duration
+}
+
+func (j *agentJetStream) CreateKeyValue(_ context.Context, config jetstream.KeyValueConfig) (jetstream.KeyValue, error) {
+	if j.kv != nil {
+		return nil, jetstream.ErrBucketExists
+	}
+	j.createdTTL = config.TTL
+	j.kv = &agentKV{entries: make(map[string][]byte)}
+	return j.kv, nil
+}
+
+func (j *agentJetStream) KeyValue(_ context.Context, bucket string) (jetstream.KeyValue, error) {
+	if j.kv == nil || bucket != natsclient.CFMAgentBucket {
+		return nil, jetstream.ErrBucketNotFound
+	}
+	return j.kv, nil
+}
+
+type agentKV struct {
+	jetstream.KeyValue
+	entries map[string][]byte
+}
+
+func (k *agentKV) ListKeys(_ context.Context, _ ...jetstream.WatchOpt) (jetstream.KeyLister, error) {
+	keys := make(chan string, len(k.entries))
+	for key := range k.entries {
+		keys <- key
+	}
+	close(keys)
+	return &agentKeyLister{keys: keys}, nil
+}
+
+func (k *agentKV) Put(_ context.Context, key string, value []byte) (uint64, error) {
+	k.entries[key] = value
+	return 1, nil
+}
+
+func (k *agentKV) Delete(_ context.Context, key string, _ ...jetstream.KVDeleteOpt) error {
+	delete(k.entries, key)
+	return nil
+}
+
+func (k *agentKV) Get(_ context.Context, key string) (jetstream.KeyValueEntry, error) {
+	value, found := k.entries[key]
+	if !found {
+		return nil, jetstream.ErrKeyNotFound
+	}
+	return heartbeatEntry{value: value, revision: 1}, nil
+}
+
+type agentKeyLister struct {
+	keys chan string
+}
+
+func (l *agentKeyLister) Keys() <-chan string {
+	return l.keys
+}
+
+func (l *agentKeyLister) Stop() error {
+	return nil
+}
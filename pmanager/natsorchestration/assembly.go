@@ -15,7 +15,9 @@ package natsorchestration
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
@@ -24,7 +26,15 @@ import (
 )
 
 const (
-	setupStreamKey = "setupStream"
+	setupStreamKey       = "setupStream"
+	heartbeatTimeoutKey  = "heartbeat.timeout"
+	heartbeatIntervalKey = "heartbeat.interval"
+	heartbeatPolicyKey   = "heartbeat.policy"
+	leaderTTLKey         = "leader.ttl"
+	agentTTLKey          = "agent.ttl"
+
+	defaultLeaderTTL = 15 * time.Second
+	leaderKey        = "pmanager"
 )
 
 type natsOrchestratorServiceAssembly struct {
@@ -33,8 +43,10 @@ type natsOrchestratorServiceAssembly struct {
 	streamName string
 	natsClient *natsclient.NatsClient
 	system.DefaultServiceAssembly
-	processCancel context.CancelFunc
-	subscription  *nats.Subscription
+	processCancel    context.CancelFunc
+	subscription     *nats.Subscription
+	heartbeatMonitor *HeartbeatMonitor
+	leaderElection   *natsclient.LeaderElection
 }
 
 func NewOrchestratorServiceAssembly(uri string, bucket string, streamName string) system.ServiceAssembly {
@@ -50,7 +62,7 @@ func (a *natsOrchestratorServiceAssembly) Name() string {
 }
 
 func (a *natsOrchestratorServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.OrchestratorKey, natsclient.NatsClientKey}
+	return []system.ServiceType{api.OrchestratorKey, api.ActivityLeaseManagerKey, api.AgentRegistryKey, api.LeaderElectionKey, natsclient.NatsClientKey}
 }
 
 func (d *natsOrchestratorServiceAssembly) Requires() []system.ServiceType {
@@ -96,14 +108,61 @@ func (a *natsOrchestratorServiceAssembly) Init(ctx *system.InitContext) error {
 	client := natsclient.NewMsgClient(natsClient)
 	orchestrator := NewNatsOrchestrator(client, ctx.LogMonitor)
 	ctx.Registry.Register(api.OrchestratorKey, orchestrator)
+	ctx.Registry.Register(api.ActivityLeaseManagerKey, NewNatsActivityLeaseManager(client, a.streamName, ctx.LogMonitor))
+	ctx.Registry.Register(api.AgentRegistryKey, &NatsAgentRegistry{
+		JetStream: natsClient.JetStream,
+		TTL:       time.Duration(ctx.GetConfigIntOrDefault(agentTTLKey, int(defaultAgentRegistrationTTL.Seconds()))) * time.Second,
+		Monitor:   ctx.LogMonitor,
+	})
+
+	leaderTTL := time.Duration(ctx.GetConfigIntOrDefault(leaderTTLKey, int(defaultLeaderTTL.Seconds()))) * time.Second
+	leaderKV, err := natsclient.SetupLeaderBucket(natsContext, natsClient.JetStream, leaderTTL)
+	if err != nil {
+		return fmt.Errorf("error initializing NATS leader bucket: %w", err)
+	}
+	a.leaderElection = &natsclient.LeaderElection{
+		KV:       leaderKV,
+		Key:      leaderKey,
+		ID:       uuid.New().String(),
+		Interval: leaderTTL / 3,
+		Monitor:  ctx.LogMonitor,
+	}
+	ctx.Registry.Register(api.LeaderElectionKey, a.leaderElection)
+
+	policy, err := ParseStaleActivityPolicy(ctx.GetConfigStrOrDefault(heartbeatPolicyKey, string(StaleActivityPolicyFail)))
+	if err != nil {
+		return err
+	}
+	a.heartbeatMonitor = &HeartbeatMonitor{
+		Client:         client,
+		Index:          index,
+		Timeout:        time.Duration(ctx.GetConfigIntOrDefault(heartbeatTimeoutKey, int(defaultHeartbeatTimeout.Seconds()))) * time.Second,
+		Interval:       time.Duration(ctx.GetConfigIntOrDefault(heartbeatIntervalKey, int(defaultHeartbeatInterval.Seconds()))) * time.Second,
+		Policy:         policy,
+		LeaderElection: a.leaderElection,
+		Monitor:        ctx.LogMonitor,
+	}
 
 	return nil
 }
 
+func (a *natsOrchestratorServiceAssembly) Start(_ *system.StartContext) error {
+	processContext, cancel := context.WithCancel(context.Background())
+	a.processCancel = cancel
+	a.heartbeatMonitor.Start(processContext)
+	a.leaderElection.Start(processContext)
+	return nil
+}
+
 func (a *natsOrchestratorServiceAssembly) Shutdown() error {
 	if a.processCancel != nil {
 		a.processCancel()
 	}
+	if a.leaderElection != nil {
+		if err := a.leaderElection.Resign(context.Background()); err != nil {
+			a.leaderElection.Monitor.Warnf("Error resigning leadership: %v", err)
+		}
+	}
 	if a.subscription != nil {
 		_ = a.subscription.Unsubscribe()
 	}
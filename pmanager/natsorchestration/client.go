@@ -15,12 +15,14 @@ package natsorchestration
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/metaform/connector-fabric-manager/common/metrics"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
-	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 // EnqueueActivityMessages enqueues the given activities for processing.
@@ -40,11 +42,7 @@ func EnqueueActivityMessages(ctx context.Context, orchestrationID string, activi
 
 		// Strip out periods since they denote a subject hierarchy for NATS
 		subject := natsclient.CFMSubjectPrefix + "." + strings.ReplaceAll(activity.Type.String(), ".", "-")
-		msg := &nats.Msg{
-			Subject: subject,
-			Data:    payload,
-		}
-		_, err = client.PublishMsg(ctx, msg)
+		_, err = natsclient.PublishTraced(ctx, client, subject, payload)
 		if err != nil {
 			return fmt.Errorf("error publishing to stream: %w", err)
 		}
@@ -86,6 +84,9 @@ func UpdateOrchestration(
 		if err == nil {
 			break
 		}
+		if isRevisionConflict(err) {
+			metrics.OrchestrationUpdateConflict()
+		}
 		orchestration, revision, err = ReadOrchestration(ctx, orchestration.ID, client)
 		if err != nil {
 			return api.Orchestration{}, 0, fmt.Errorf("failed to read orchestration data for update: %w", err)
@@ -93,3 +94,13 @@ func UpdateOrchestration(
 	}
 	return orchestration, revision, nil
 }
+
+// isRevisionConflict returns true if the update failed because the orchestration was updated after the revision was
+// read.
+func isRevisionConflict(err error) bool {
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return true
+	}
+	var apiErr *jetstream.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == jetstream.JSErrCodeStreamWrongLastSequence
+}
@@ -0,0 +1,194 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/metrics"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// StaleActivityPolicy determines how the HeartbeatMonitor handles activities that have stopped heartbeating.
+type StaleActivityPolicy string
+
+const (
+	// StaleActivityPolicyFail marks the orchestration as errored and publishes a failure response.
+	StaleActivityPolicyFail StaleActivityPolicy = "fail"
+
+	// StaleActivityPolicyRequeue re-enqueues the activity so that it can be picked up by another agent.
+	StaleActivityPolicyRequeue StaleActivityPolicy = "requeue"
+
+	defaultHeartbeatTimeout  = 5 * time.Minute
+	defaultHeartbeatInterval = 30 * time.Second
+)
+
+// ParseStaleActivityPolicy converts the given value to a StaleActivityPolicy.
+func ParseStaleActivityPolicy(value string) (StaleActivityPolicy, error) {
+	switch StaleActivityPolicy(strings.ToLower(value)) {
+	case StaleActivityPolicyFail:
+		return StaleActivityPolicyFail, nil
+	case StaleActivityPolicyRequeue:
+		return StaleActivityPolicyRequeue, nil
+	default:
+		return "", fmt.Errorf("invalid stale activity policy: %s", value)
+	}
+}
+
+// HeartbeatMonitor periodically checks running orchestrations for activities whose last heartbeat is older than the
+// configured timeout. Stale activities are handled according to the configured StaleActivityPolicy. If a
+// LeaderElection is set, only the leader checks heartbeats so that stale activities are handled once.
+type HeartbeatMonitor struct {
+	Client         natsclient.MsgClient
+	Index          store.EntityStore[*api.OrchestrationEntry]
+	Timeout        time.Duration
+	Interval       time.Duration
+	Policy         StaleActivityPolicy
+	LeaderElection api.LeaderElection
+	Monitor        system.LogMonitor
+}
+
+// Start runs the monitor in a goroutine until the given context is canceled.
+func (m *HeartbeatMonitor) Start(ctx context.Context) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if m.LeaderElection != nil && !m.LeaderElection.IsLeader() {
+					continue
+				}
+				if err := m.CheckHeartbeats(ctx); err != nil {
+					m.Monitor.Warnf("Error checking activity heartbeats: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// CheckHeartbeats inspects all non-terminal orchestrations and handles activities that have stopped heartbeating.
+func (m *HeartbeatMonitor) CheckHeartbeats(ctx context.Context) error {
+	ids := make([]string, 0)
+	predicate := query.Lt("state", api.OrchestrationStateCompleted)
+	for entry, err := range m.Index.FindByPredicate(ctx, predicate) {
+		if err != nil {
+			return fmt.Errorf("error querying orchestrations: %w", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := m.checkOrchestration(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *HeartbeatMonitor) checkOrchestration(ctx context.Context, orchestrationID string) error {
+	orchestration, revision, err := ReadOrchestration(ctx, orchestrationID, m.Client)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	stale := orchestration.StaleActivities(time.Now(), m.timeout())
+	if len(stale) == 0 {
+		return nil
+	}
+
+	switch m.Policy {
+	case StaleActivityPolicyRequeue:
+		return m.requeue(ctx, orchestration, revision, stale)
+	default:
+		return m.fail(ctx, orchestration, revision, stale)
+	}
+}
+
+// requeue clears the heartbeat entries of the stale activities and enqueues them again.
+func (m *HeartbeatMonitor) requeue(ctx context.Context, orchestration api.Orchestration, revision uint64, stale []string) error {
+	orchestration, _, err := UpdateOrchestration(ctx, orchestration, revision, m.Client, func(o *api.Orchestration) {
+		for _, activityID := range stale {
+			o.ClearHeartbeat(activityID)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear heartbeats for orchestration %s: %w", orchestration.ID, err)
+	}
+	if orchestration.State == api.OrchestrationStateErrored || orchestration.State == api.OrchestrationStateCompleted {
+		return nil
+	}
+
+	activities := make([]api.Activity, 0, len(stale))
+	for _, activityID := range stale {
+		if _, completed := orchestration.Completed[activityID]; completed {
+			continue
+		}
+		if activity, found := orchestration.GetActivity(activityID); found {
+			activities = append(activities, *activity)
+		}
+	}
+	m.Monitor.Warnf("Re-enqueuing stalled activities %v for orchestration %s", stale, orchestration.ID)
+	return EnqueueActivityMessages(ctx, orchestration.ID, activities, m.Client)
+}
+
+// fail marks the orchestration as errored and publishes a failure response.
+func (m *HeartbeatMonitor) fail(ctx context.Context, orchestration api.Orchestration, revision uint64, stale []string) error {
+	alreadyTerminal := false
+	orchestration, _, err := UpdateOrchestration(ctx, orchestration, revision, m.Client, func(o *api.Orchestration) {
+		for _, activityID := range stale {
+			o.ClearHeartbeat(activityID)
+		}
+		alreadyTerminal = o.State == api.OrchestrationStateErrored || o.State == api.OrchestrationStateCompleted
+		if !alreadyTerminal {
+			o.SetState(api.OrchestrationStateErrored)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark orchestration %s as errored: %w", orchestration.ID, err)
+	}
+	if alreadyTerminal {
+		return nil
+	}
+	metrics.OrchestrationErrored(orchestration.OrchestrationType.String())
+
+	m.Monitor.Warnf("Failing orchestration %s: activities %v stopped heartbeating", orchestration.ID, stale)
+	detail := fmt.Sprintf("activities stopped heartbeating: %s", strings.Join(stale, ", "))
+	return publishOrchestrationResponse(ctx, orchestration, false, detail, m.Client)
+}
+
+func (m *HeartbeatMonitor) timeout() time.Duration {
+	if m.Timeout <= 0 {
+		return defaultHeartbeatTimeout
+	}
+	return m.Timeout
+}
@@ -0,0 +1,208 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/mocks"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatMonitor_FailPolicy(t *testing.T) {
+	ctx := context.Background()
+	orchestration := createHeartbeatOrchestration(time.Now().Add(-10 * time.Minute))
+
+	client := mocks.NewMockMsgClient(t)
+	client.EXPECT().Get(mock.Anything, orchestration.ID).Return(newHeartbeatEntry(t, orchestration, 5), nil)
+
+	var updated api.Orchestration
+	client.EXPECT().Update(mock.Anything, orchestration.ID, mock.Anything, uint64(5)).
+		RunAndReturn(func(_ context.Context, _ string, data []byte, _ uint64) (uint64, error) {
+			require.NoError(t, json.Unmarshal(data, &updated))
+			return 6, nil
+		})
+
+	var response model.OrchestrationResponse
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationResponseSubject)).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			require.NoError(t, json.Unmarshal(msg.Data, &response))
+			return &jetstream.PubAck{}, nil
+		})
+
+	errored := metricValue(t, "cfm_orchestrations_errored_total", map[string]string{"type": orchestration.OrchestrationType.String()})
+
+	monitor := newTestHeartbeatMonitor(t, client, orchestration, StaleActivityPolicyFail)
+	require.NoError(t, monitor.CheckHeartbeats(ctx))
+
+	assert.Equal(t, api.OrchestrationStateErrored, updated.State)
+	assert.Empty(t, updated.Heartbeats)
+	assert.Equal(t, errored+1, metricValue(t, "cfm_orchestrations_errored_total", map[string]string{"type": orchestration.OrchestrationType.String()}))
+	assert.False(t, response.Success)
+	assert.Equal(t, orchestration.ID, response.ManifestID)
+	assert.Equal(t, orchestration.CorrelationID, response.CorrelationID)
+	assert.Contains(t, response.ErrorDetail, "A1")
+}
+
+func TestHeartbeatMonitor_RequeuePolicy(t *testing.T) {
+	ctx := context.Background()
+	orchestration := createHeartbeatOrchestration(time.Now().Add(-10 * time.Minute))
+
+	client := mocks.NewMockMsgClient(t)
+	client.EXPECT().Get(mock.Anything, orchestration.ID).Return(newHeartbeatEntry(t, orchestration, 5), nil)
+
+	var updated api.Orchestration
+	client.EXPECT().Update(mock.Anything, orchestration.ID, mock.Anything, uint64(5)).
+		RunAndReturn(func(_ context.Context, _ string, data []byte, _ uint64) (uint64, error) {
+			require.NoError(t, json.Unmarshal(data, &updated))
+			return 6, nil
+		})
+
+	var message api.ActivityMessage
+	client.EXPECT().PublishMsg(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			assert.Equal(t, "event.test-heartbeat-activity", msg.Subject)
+			require.NoError(t, json.Unmarshal(msg.Data, &message))
+			return &jetstream.PubAck{}, nil
+		})
+
+	monitor := newTestHeartbeatMonitor(t, client, orchestration, StaleActivityPolicyRequeue)
+	require.NoError(t, monitor.CheckHeartbeats(ctx))
+
+	assert.Equal(t, api.OrchestrationStateRunning, updated.State)
+	assert.Empty(t, updated.Heartbeats)
+	assert.Equal(t, orchestration.ID, message.OrchestrationID)
+	assert.Equal(t, "A1", message.Activity.ID)
+}
+
+func TestHeartbeatMonitor_ActiveHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	orchestration := createHeartbeatOrchestration(time.Now())
+
+	client := mocks.NewMockMsgClient(t)
+	client.EXPECT().Get(mock.Anything, orchestration.ID).Return(newHeartbeatEntry(t, orchestration, 5), nil)
+
+	monitor := newTestHeartbeatMonitor(t, client, orchestration, StaleActivityPolicyFail)
+	require.NoError(t, monitor.CheckHeartbeats(ctx))
+
+	client.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHeartbeatMonitor_NotLeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	orchestration := createHeartbeatOrchestration(time.Now().Add(-10 * time.Minute))
+
+	// The client has no expectations, so any access to the orchestration fails the test
+	client := mocks.NewMockMsgClient(t)
+
+	monitor := newTestHeartbeatMonitor(t, client, orchestration, StaleActivityPolicyFail)
+	monitor.Interval = 10 * time.Millisecond
+	monitor.LeaderElection = fixedLeaderElection(false)
+	monitor.Start(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestParseStaleActivityPolicy(t *testing.T) {
+	policy, err := ParseStaleActivityPolicy("Requeue")
+	require.NoError(t, err)
+	assert.Equal(t, StaleActivityPolicyRequeue, policy)
+
+	policy, err = ParseStaleActivityPolicy("fail")
+	require.NoError(t, err)
+	assert.Equal(t, StaleActivityPolicyFail, policy)
+
+	_, err = ParseStaleActivityPolicy("invalid")
+	require.Error(t, err)
+}
+
+func newTestHeartbeatMonitor(
+	t *testing.T,
+	client natsclient.MsgClient,
+	orchestration api.Orchestration,
+	policy StaleActivityPolicy) *HeartbeatMonitor {
+	index := memorystore.NewInMemoryEntityStore[*api.OrchestrationEntry]()
+	_, err := index.Create(context.Background(), createEntry(orchestration))
+	require.NoError(t, err)
+
+	// Terminal orchestrations must not be checked
+	completed := createWatcherOrchestration("completed-orchestration", "correlation", api.OrchestrationStateCompleted)
+	_, err = index.Create(context.Background(), createEntry(completed))
+	require.NoError(t, err)
+
+	return &HeartbeatMonitor{
+		Client:  client,
+		Index:   index,
+		Timeout: 5 * time.Minute,
+		Policy:  policy,
+		Monitor: system.NoopMonitor{},
+	}
+}
+
+func createHeartbeatOrchestration(lastHeartbeat time.Time) api.Orchestration {
+	return api.Orchestration{
+		ID:                "heartbeat-orchestration",
+		CorrelationID:     "heartbeat-correlation",
+		State:             api.OrchestrationStateRunning,
+		OrchestrationType: model.VPADeployType,
+		ProcessingData:    make(map[string]any),
+		OutputData:        make(map[string]any),
+		Completed:         make(map[string]struct{}),
+		Heartbeats:        map[string]time.Time{"A1": lastHeartbeat},
+		Steps: []api.OrchestrationStep{
+			{
+				Activities: []api.Activity{{ID: "A1", Type: "test.heartbeat.activity"}},
+			},
+		},
+	}
+}
+
+type heartbeatEntry struct {
+	jetstream.KeyValueEntry
+	value    []byte
+	revision uint64
+}
+
+func newHeartbeatEntry(t *testing.T, orchestration api.Orchestration, revision uint64) jetstream.KeyValueEntry {
+	value, err := json.Marshal(orchestration)
+	require.NoError(t, err)
+	return heartbeatEntry{value: value, revision: revision}
+}
+
+func (e heartbeatEntry) Value() []byte {
+	return e.value
+}
+
+func (e heartbeatEntry) Revision() uint64 {
+	return e.revision
+}
+
+type fixedLeaderElection bool
+
+func (l fixedLeaderElection) IsLeader() bool {
+	return bool(l)
+}
@@ -0,0 +1,74 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"sync"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// inFlightTracker tracks fetched messages that have not completed processing so that messages that have not been
+// started can be rejected when the executor shuts down before they are processed.
+type inFlightTracker struct {
+	mu        sync.Mutex
+	messages  map[jetstream.Msg]bool // true once a worker has started processing the message
+	abandoned bool
+}
+
+func newInFlightTracker() *inFlightTracker {
+	return &inFlightTracker{messages: make(map[jetstream.Msg]bool)}
+}
+
+func (t *inFlightTracker) add(message jetstream.Msg) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages[message] = false
+}
+
+// start returns false if the message was rejected on shutdown and must not be processed.
+func (t *inFlightTracker) start(message jetstream.Msg) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, found := t.messages[message]; !found || t.abandoned {
+		return false
+	}
+	t.messages[message] = true
+	return true
+}
+
+func (t *inFlightTracker) done(message jetstream.Msg) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.messages, message)
+}
+
+// abandon stops processing of messages that have not started and returns them together with the number of messages
+// that are still being processed. Messages being processed are not returned: rejecting them would redeliver an activity
+// that is still executing, so they are left to complete or to be redelivered once their acknowledgement deadline passes.
+func (t *inFlightTracker) abandon() ([]jetstream.Msg, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.abandoned = true
+	queued := make([]jetstream.Msg, 0, len(t.messages))
+	processing := 0
+	for message, started := range t.messages {
+		if started {
+			processing++
+		} else {
+			queued = append(queued, message)
+		}
+	}
+	t.messages = make(map[jetstream.Msg]bool)
+	return queued, processing
+}
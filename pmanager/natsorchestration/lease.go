@@ -0,0 +1,316 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultLeaseDuration = 30 * time.Second
+
+	// minLeaseFetchWait is the minimum time a lease request waits for an activity message, so that queued messages are
+	// leased when no wait is requested.
+	minLeaseFetchWait = 100 * time.Millisecond
+
+	// leaseRedeliveryDelay is the delay before a message that could not be read is redelivered, so that lease requests
+	// do not spin on it.
+	leaseRedeliveryDelay = 5 * time.Second
+)
+
+// NatsActivityLeaseManager leases activity messages to out-of-process agents. Leases are backed by the same durable
+// consumers used by NatsActivityExecutor and are processed with the same orchestration semantics. A lease is valid for
+// the consumer acknowledgement deadline and is extended by each heartbeat.
+//
+// Leases are held in memory by the provision manager instance that issued them, and lease IDs are prefixed with the ID
+// of that instance. Agents must send heartbeats and complete or fail leased activities through the same instance.
+// Requests for leases issued by another instance, or by this instance before it was restarted, return
+// types.ErrUnavailable so that agents can retry them until the lease expires.
+type NatsActivityLeaseManager struct {
+	client     natsclient.MsgClient
+	streamName string
+	instanceID string
+	executor   *NatsActivityExecutor
+	monitor    system.LogMonitor
+
+	mu        sync.Mutex
+	consumers map[api.ActivityType]jetstream.Consumer
+	leases    map[string]*activityLease
+}
+
+// activityLease is an outstanding lease on an activity message.
+type activityLease struct {
+	delivery  *activityDelivery
+	duration  time.Duration
+	expiresAt time.Time
+}
+
+func NewNatsActivityLeaseManager(client natsclient.MsgClient, streamName string, monitor system.LogMonitor) *NatsActivityLeaseManager {
+	return &NatsActivityLeaseManager{
+		client:     client,
+		streamName: streamName,
+		instanceID: uuid.New().String(),
+		executor:   &NatsActivityExecutor{Client: client, StreamName: streamName, Monitor: monitor},
+		monitor:    monitor,
+		consumers:  make(map[api.ActivityType]jetstream.Consumer),
+		leases:     make(map[string]*activityLease),
+	}
+}
+
+func (m *NatsActivityLeaseManager) Lease(ctx context.Context, activityType api.ActivityType, wait time.Duration) (*api.ActivityLease, error) {
+	consumer, err := m.consumer(ctx, activityType)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+	for attempt := 0; ; attempt++ {
+		// Fetch at least once, even if no wait is requested, but do not fetch again after skipped messages once the
+		// deadline has passed or the request was canceled
+		if attempt > 0 && (ctx.Err() != nil || time.Now().After(deadline)) {
+			return nil, nil
+		}
+		message, err := consumer.Next(jetstream.FetchMaxWait(max(time.Until(deadline), minLeaseFetchWait)))
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("error fetching activity message for %s: %w", activityType, err)
+		}
+
+		delivery, err := m.executor.readDelivery(ctx, message)
+		if err != nil {
+			m.monitor.Warnf("Error reading activity message for %s: %v", activityType, rejectMessage(message, err))
+			continue
+		}
+		if delivery == nil {
+			// Activity completed or orchestration failed
+			continue
+		}
+
+		lease := &activityLease{
+			delivery: delivery,
+			duration: leaseDuration(consumer),
+		}
+		lease.expiresAt = time.Now().Add(lease.duration)
+
+		id := m.instanceID + "." + uuid.New().String()
+		m.mu.Lock()
+		m.pruneExpired()
+		m.leases[id] = lease
+		m.mu.Unlock()
+
+		m.monitor.Debugf("Leased activity %s for orchestration %s", delivery.activity.ID, delivery.orchestration.ID)
+		return toActivityLease(id, lease), nil
+	}
+}
+
+// rejectMessage terminates a message whose orchestration no longer exists and redelivers other messages that could not
+// be read after a delay.
+func rejectMessage(message jetstream.Msg, err error) error {
+	var rejectErr error
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		rejectErr = message.Term()
+	} else {
+		rejectErr = message.NakWithDelay(leaseRedeliveryDelay)
+	}
+	if rejectErr != nil {
+		err = errors.Join(err, rejectErr)
+	}
+	return err
+}
+
+func (m *NatsActivityLeaseManager) Heartbeat(ctx context.Context, leaseID string, progress *api.ActivityProgress) (*api.ActivityLease, error) {
+	lease, err := m.getLease(leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := lease.delivery
+	if progress != nil {
+		err = m.executor.progress(ctx, delivery.message, delivery.orchestration.ID, delivery.activity)(progress.VPAID, progress.Percent, progress.Message)
+	} else {
+		err = m.executor.heartbeat(ctx, delivery.message, delivery.orchestration.ID, delivery.activity.ID)()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	lease.expiresAt = time.Now().Add(lease.duration)
+	m.mu.Unlock()
+	return toActivityLease(leaseID, lease), nil
+}
+
+func (m *NatsActivityLeaseManager) Complete(
+	ctx context.Context,
+	leaseID string,
+	processingData map[string]any,
+	outputData map[string]any) error {
+	return m.release(ctx, leaseID, func(activityContext api.ActivityContext) api.ActivityResult {
+		for key, value := range processingData {
+			activityContext.SetValue(key, value)
+		}
+		for key, value := range outputData {
+			activityContext.SetOutputValue(key, value)
+		}
+		return api.ActivityResult{Result: api.ActivityResultComplete}
+	})
+}
+
+func (m *NatsActivityLeaseManager) Fail(ctx context.Context, leaseID string, retryable bool, reason string) error {
+	return m.release(ctx, leaseID, func(_ api.ActivityContext) api.ActivityResult {
+		if retryable {
+			return api.ActivityResult{Result: api.ActivityResultRetryError, Error: errors.New(reason)}
+		}
+		return api.ActivityResult{Result: api.ActivityResultFatalError, Error: errors.New(reason)}
+	})
+}
+
+// release removes the lease and applies the result to the orchestration the same way NatsActivityExecutor applies the
+// result returned by an activity processor.
+func (m *NatsActivityLeaseManager) release(
+	ctx context.Context,
+	leaseID string,
+	resultFn func(api.ActivityContext) api.ActivityResult) (err error) {
+	m.mu.Lock()
+	lease, found := m.leases[leaseID]
+	if found {
+		delete(m.leases, leaseID)
+	}
+	m.mu.Unlock()
+	if !found || lease.expired(time.Now()) {
+		return m.leaseError(leaseID)
+	}
+
+	// Continue the orchestration trace carried by the activity message and link the request that released the lease
+	ctx, span := natsclient.StartConsumerSpan(ctx, lease.delivery.message,
+		"process leased activity "+lease.delivery.activity.Type.String(),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(activityAttributes(lease.delivery)...))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// Re-read the orchestration since heartbeats and other activities may have updated it after the lease was issued
+	orchestration, revision, err := ReadOrchestration(ctx, lease.delivery.orchestration.ID, m.client)
+	if err != nil {
+		return fmt.Errorf("failed to read orchestration data: %w", err)
+	}
+	delivery := &activityDelivery{
+		message:       lease.delivery.message,
+		activity:      lease.delivery.activity,
+		orchestration: orchestration,
+		revision:      revision,
+	}
+
+	activityContext := m.executor.newActivityContext(ctx, delivery)
+	result := resultFn(activityContext)
+	err = m.executor.handleResult(activityContext, delivery, result)
+	if err != nil && result.Result != api.ActivityResultComplete {
+		// Retry and fatal results always report the activity failure, which the agent already knows about
+		m.monitor.Infof("Leased activity %s for orchestration %s failed: %v", delivery.activity.ID, orchestration.ID, err)
+		return nil
+	}
+	return err
+}
+
+func (m *NatsActivityLeaseManager) consumer(ctx context.Context, activityType api.ActivityType) (jetstream.Consumer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if consumer, found := m.consumers[activityType]; found {
+		return consumer, nil
+	}
+
+	stream, err := m.client.Stream(ctx, m.streamName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening stream: %w", err)
+	}
+	consumer, err := natsclient.SetupConsumer(ctx, stream, activityType.String())
+	if err != nil {
+		return nil, fmt.Errorf("error creating consumer for %s: %w", activityType, err)
+	}
+	m.consumers[activityType] = consumer
+	return consumer, nil
+}
+
+func (m *NatsActivityLeaseManager) getLease(leaseID string) (*activityLease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lease, found := m.leases[leaseID]
+	if !found || lease.expired(time.Now()) {
+		delete(m.leases, leaseID)
+		return nil, m.leaseError(leaseID)
+	}
+	return lease, nil
+}
+
+// leaseError returns the error for a lease that is not held by this instance. Leases issued by another instance are
+// reported as unavailable, since the request may succeed when it is routed to that instance.
+func (m *NatsActivityLeaseManager) leaseError(leaseID string) error {
+	if instanceID, _, found := strings.Cut(leaseID, "."); found && instanceID != m.instanceID {
+		return types.NewRecoverableWrappedError(types.ErrUnavailable, "lease %s was issued by another instance", leaseID)
+	}
+	return types.NewRecoverableWrappedError(types.ErrNotFound, "lease %s not found or expired", leaseID)
+}
+
+// pruneExpired removes expired leases. Their messages are redelivered by the consumer once the acknowledgement deadline
+// passes. Must be called with the lock held.
+func (m *NatsActivityLeaseManager) pruneExpired() {
+	now := time.Now()
+	for id, lease := range m.leases {
+		if lease.expired(now) {
+			delete(m.leases, id)
+		}
+	}
+}
+
+func (l *activityLease) expired(now time.Time) bool {
+	return now.After(l.expiresAt)
+}
+
+func leaseDuration(consumer jetstream.Consumer) time.Duration {
+	if info := consumer.CachedInfo(); info != nil && info.Config.AckWait > 0 {
+		return info.Config.AckWait
+	}
+	return defaultLeaseDuration
+}
+
+func toActivityLease(id string, lease *activityLease) *api.ActivityLease {
+	delivery := lease.delivery
+	return &api.ActivityLease{
+		ID:                id,
+		OrchestrationID:   delivery.orchestration.ID,
+		OrchestrationType: delivery.orchestration.OrchestrationType,
+		Activity:          delivery.activity,
+		ProcessingData:    delivery.orchestration.ProcessingData,
+		OutputData:        delivery.orchestration.OutputData,
+		ExpiresAt:         lease.expiresAt,
+	}
+}
@@ -0,0 +1,486 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/mocks"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const leaseActivityType = api.ActivityType("test.lease.activity")
+
+func TestLeaseManager_LeaseAndComplete(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	var response model.OrchestrationResponse
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationResponseSubject)).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			require.NoError(t, json.Unmarshal(msg.Data, &response))
+			return &jetstream.PubAck{}, nil
+		})
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.Equal(t, stored.orchestration.ID, lease.OrchestrationID)
+	assert.Equal(t, "A1", lease.Activity.ID)
+	assert.Equal(t, "input", lease.ProcessingData["key"])
+	assert.True(t, lease.ExpiresAt.After(time.Now()))
+
+	err = manager.Complete(ctx, lease.ID, map[string]any{"processed": "yes"}, map[string]any{"output": "value"})
+	require.NoError(t, err)
+
+	assert.True(t, message.isAcked())
+	assert.Equal(t, api.OrchestrationStateCompleted, stored.orchestration.State)
+	assert.Equal(t, "yes", stored.orchestration.ProcessingData["processed"])
+	assert.Equal(t, "value", stored.orchestration.OutputData["output"])
+	assert.True(t, response.Success)
+	assert.Equal(t, "value", response.Properties["output"])
+
+	// The lease is released on completion
+	err = manager.Complete(ctx, lease.ID, nil, nil)
+	require.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestLeaseManager_NoActivityAvailable(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	manager := newTestLeaseManager(client, &leaseConsumer{})
+
+	lease, err := manager.Lease(context.Background(), leaseActivityType, time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+}
+
+func TestLeaseManager_SkipsCompletedActivity(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createLeaseOrchestration()
+	orchestration.Completed["A1"] = struct{}{}
+	newStoredOrchestration(t, client, orchestration)
+
+	message := newLeaseMessage(t, orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(context.Background(), leaseActivityType, time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+	assert.True(t, message.isAcked())
+}
+
+func TestLeaseManager_TerminatesMessageOfMissingOrchestration(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	client.EXPECT().Get(mock.Anything, "missing").Return(nil, jetstream.ErrKeyNotFound)
+
+	// The consumer redelivers the message immediately, the lease request returns once the wait expires
+	message := newLeaseMessage(t, "missing", "A1")
+	manager := newTestLeaseManager(client, &redeliveringConsumer{message: message})
+
+	lease, err := manager.Lease(context.Background(), leaseActivityType, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+	assert.True(t, message.isTermed())
+	assert.False(t, message.isNaked())
+}
+
+func TestLeaseManager_DelaysRedeliveryOfUnreadableMessage(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	client.EXPECT().Get(mock.Anything, "unreadable").Return(nil, errors.New("connection closed"))
+
+	message := newLeaseMessage(t, "unreadable", "A1")
+	manager := newTestLeaseManager(client, &redeliveringConsumer{message: message})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// The request is canceled before the wait expires
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+	assert.Equal(t, leaseRedeliveryDelay, message.nakDelay())
+	assert.False(t, message.isTermed())
+}
+
+func TestLeaseManager_HeartbeatWithProgress(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	var progress model.OrchestrationProgress
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationProgressSubject)).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			require.NoError(t, json.Unmarshal(msg.Data, &progress))
+			return &jetstream.PubAck{}, nil
+		})
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+
+	extended, err := manager.Heartbeat(ctx, lease.ID, &api.ActivityProgress{Percent: 40, Message: "working"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, message.inProgress)
+	assert.False(t, extended.ExpiresAt.Before(lease.ExpiresAt))
+	assert.Contains(t, stored.orchestration.Heartbeats, "A1")
+	assert.Equal(t, 40, stored.orchestration.Progress["A1"].Percent)
+	assert.Equal(t, "working", progress.Message)
+}
+
+func TestLeaseManager_HeartbeatWithVPAProgress(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createLeaseOrchestration()
+	orchestration.ProcessingData[model.VPAData] = []any{
+		map[string]any{"id": "vpa1", "vpaType": "cfm.connector", "cellId": "cell1"},
+		map[string]any{"id": "vpa2", "vpaType": "cfm.connector", "cellId": "cell1"},
+	}
+	stored := newStoredOrchestration(t, client, orchestration)
+
+	var progress model.OrchestrationProgress
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationProgressSubject)).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			require.NoError(t, json.Unmarshal(msg.Data, &progress))
+			return &jetstream.PubAck{}, nil
+		})
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+
+	_, err = manager.Heartbeat(ctx, lease.ID, &api.ActivityProgress{VPAID: "vpa2", Percent: 80, Message: "working"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 80, stored.orchestration.VPAProgress["vpa2"]["A1"].Percent)
+	assert.Equal(t, "vpa2", progress.VPAID)
+	assert.Equal(t, map[string]int{"vpa1": 0, "vpa2": 80}, progress.VPAPercents)
+	assert.Equal(t, 80, progress.OrchestrationPercent)
+}
+
+func TestLeaseManager_FailRetryable(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.Fail(ctx, lease.ID, true, "temporarily unavailable"))
+
+	assert.True(t, message.isNaked())
+	assert.False(t, message.isAcked())
+	assert.Equal(t, api.OrchestrationStateRunning, stored.orchestration.State)
+}
+
+func TestLeaseManager_FailFatal(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.Fail(ctx, lease.ID, false, "invalid configuration"))
+
+	assert.True(t, message.isAcked())
+	assert.Equal(t, api.OrchestrationStateErrored, stored.orchestration.State)
+}
+
+func TestLeaseManager_UnknownLease(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestLeaseManager(mocks.NewMockMsgClient(t), &leaseConsumer{})
+
+	_, err := manager.Heartbeat(ctx, "unknown", nil)
+	require.ErrorIs(t, err, types.ErrNotFound)
+
+	require.ErrorIs(t, manager.Complete(ctx, "unknown", nil, nil), types.ErrNotFound)
+	require.ErrorIs(t, manager.Fail(ctx, "unknown", false, "error"), types.ErrNotFound)
+}
+
+func TestLeaseManager_LeaseWithoutWait(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+
+	// Queued messages are leased even if no wait is requested
+	lease, err := manager.Lease(ctx, leaseActivityType, 0)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.Equal(t, "A1", lease.Activity.ID)
+
+	lease, err = manager.Lease(ctx, leaseActivityType, 0)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+}
+
+func TestLeaseManager_LeaseFromOtherInstance(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	issuer := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}})
+	lease, err := issuer.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+
+	// Another instance, or the issuing instance after a restart, reports the lease as unavailable so it is retried
+	other := newTestLeaseManager(client, &leaseConsumer{})
+	_, err = other.Heartbeat(ctx, lease.ID, nil)
+	require.ErrorIs(t, err, types.ErrUnavailable)
+	require.ErrorIs(t, other.Complete(ctx, lease.ID, nil, nil), types.ErrUnavailable)
+	require.ErrorIs(t, other.Fail(ctx, lease.ID, false, "error"), types.ErrUnavailable)
+	assert.False(t, message.isAcked())
+
+	// Leases issued by the instance itself that are no longer held are not found
+	_, err = issuer.Heartbeat(ctx, issuer.instanceID+".unknown", nil)
+	require.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestLeaseManager_ExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	manager := newTestLeaseManager(client, &leaseConsumer{messages: []jetstream.Msg{message}, ackWait: time.Millisecond})
+
+	lease, err := manager.Lease(ctx, leaseActivityType, time.Second)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	require.ErrorIs(t, manager.Complete(ctx, lease.ID, nil, nil), types.ErrNotFound)
+	assert.False(t, message.isAcked())
+}
+
+func newTestLeaseManager(client natsclient.MsgClient, consumer jetstream.Consumer) *NatsActivityLeaseManager {
+	manager := NewNatsActivityLeaseManager(client, "test-stream", system.NoopMonitor{})
+	manager.consumers[leaseActivityType] = consumer
+	return manager
+}
+
+// publishedTo matches messages published to the subject.
+func publishedTo(subject string) any {
+	return mock.MatchedBy(func(msg *nats.Msg) bool {
+		return msg.Subject == subject
+	})
+}
+
+func createLeaseOrchestration() api.Orchestration {
+	return api.Orchestration{
+		ID:                "lease-orchestration",
+		CorrelationID:     "lease-correlation",
+		State:             api.OrchestrationStateRunning,
+		OrchestrationType: model.VPADeployType,
+		ProcessingData:    map[string]any{"key": "input"},
+		OutputData:        make(map[string]any),
+		Completed:         make(map[string]struct{}),
+		Steps: []api.OrchestrationStep{
+			{
+				Activities: []api.Activity{{ID: "A1", Type: leaseActivityType}},
+			},
+		},
+	}
+}
+
+// storedOrchestration simulates the orchestration KV entry, enforcing optimistic concurrency on updates.
+type storedOrchestration struct {
+	mu            sync.Mutex
+	orchestration api.Orchestration
+	revision      uint64
+}
+
+func newStoredOrchestration(t *testing.T, client *mocks.MockMsgClient, orchestration api.Orchestration) *storedOrchestration {
+	stored := &storedOrchestration{orchestration: orchestration, revision: 1}
+	client.EXPECT().Get(mock.Anything, orchestration.ID).
+		RunAndReturn(func(_ context.Context, _ string) (jetstream.KeyValueEntry, error) {
+			stored.mu.Lock()
+			defer stored.mu.Unlock()
+			return newHeartbeatEntry(t, stored.orchestration, stored.revision), nil
+		}).Maybe()
+	client.EXPECT().Update(mock.Anything, orchestration.ID, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, data []byte, revision uint64) (uint64, error) {
+			stored.mu.Lock()
+			defer stored.mu.Unlock()
+			if revision != stored.revision {
+				return 0, &jetstream.APIError{ErrorCode: jetstream.JSErrCodeStreamWrongLastSequence, Description: "wrong last sequence"}
+			}
+			var updated api.Orchestration
+			require.NoError(t, json.Unmarshal(data, &updated))
+			stored.orchestration = updated
+			stored.revision++
+			return stored.revision, nil
+		}).Maybe()
+	return stored
+}
+
+type leaseConsumer struct {
+	jetstream.Consumer
+	messages []jetstream.Msg
+	ackWait  time.Duration
+}
+
+func (c *leaseConsumer) Next(_ ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	if len(c.messages) == 0 {
+		return nil, nats.ErrTimeout
+	}
+	message := c.messages[0]
+	c.messages = c.messages[1:]
+	return message, nil
+}
+
+func (c *leaseConsumer) CachedInfo() *jetstream.ConsumerInfo {
+	return &jetstream.ConsumerInfo{Config: jetstream.ConsumerConfig{AckWait: c.ackWait}}
+}
+
+// redeliveringConsumer returns the same message on every fetch, as JetStream does for a message that is rejected
+// without a delay.
+type redeliveringConsumer struct {
+	jetstream.Consumer
+	message jetstream.Msg
+}
+
+func (c *redeliveringConsumer) Next(_ ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	return c.message, nil
+}
+
+func (c *redeliveringConsumer) CachedInfo() *jetstream.ConsumerInfo {
+	return &jetstream.ConsumerInfo{}
+}
+
+type leaseMessage struct {
+	jetstream.Msg
+	mu         sync.Mutex
+	data       []byte
+	headers    nats.Header
+	delivered  uint64
+	acked      bool
+	naked      bool
+	delay      time.Duration
+	termed     bool
+	inProgress int
+}
+
+func newLeaseMessage(t *testing.T, orchestrationID string, activityID string) *leaseMessage {
+	data, err := json.Marshal(api.ActivityMessage{
+		OrchestrationID: orchestrationID,
+		Activity:        api.Activity{ID: activityID, Type: leaseActivityType},
+	})
+	require.NoError(t, err)
+	return &leaseMessage{data: data}
+}
+
+func (m *leaseMessage) Data() []byte {
+	return m.data
+}
+
+func (m *leaseMessage) Headers() nats.Header {
+	return m.headers
+}
+
+func (m *leaseMessage) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{NumDelivered: max(m.delivered, 1)}, nil
+}
+
+func (m *leaseMessage) Ack() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acked = true
+	return nil
+}
+
+func (m *leaseMessage) Nak() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.naked = true
+	return nil
+}
+
+func (m *leaseMessage) NakWithDelay(delay time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delay = delay
+	return nil
+}
+
+func (m *leaseMessage) Term() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.termed = true
+	return nil
+}
+
+func (m *leaseMessage) InProgress() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inProgress++
+	return nil
+}
+
+func (m *leaseMessage) isAcked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.acked
+}
+
+func (m *leaseMessage) isNaked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.naked
+}
+
+func (m *leaseMessage) nakDelay() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.delay
+}
+
+func (m *leaseMessage) isTermed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.termed
+}
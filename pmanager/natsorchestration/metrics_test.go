@@ -0,0 +1,112 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/metrics"
+	"github.com/metaform/connector-fabric-manager/common/mocks"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNatsActivityExecutor_RecordsMetrics(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationResponseSubject)).
+		Return(&jetstream.PubAck{}, nil)
+
+	activityType := leaseActivityType.String()
+	orchestrationType := model.VPADeployType.String()
+	redeliveries := metricValue(t, "cfm_nats_redeliveries_total", map[string]string{"type": activityType})
+	completed := metricValue(t, "cfm_orchestrations_completed_total", map[string]string{"type": orchestrationType})
+	processed := metricValue(t, "cfm_activity_duration_seconds", map[string]string{"type": activityType, "result": "complete"})
+
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	message.delivered = 2
+
+	processor := &poolProcessor{process: func(api.ActivityContext) api.ActivityResult {
+		return api.ActivityResult{Result: api.ActivityResultComplete}
+	}}
+	executor := newPoolExecutor(client, processor)
+	require.NoError(t, executor.processMessage(context.Background(), message))
+
+	assert.Equal(t, redeliveries+1, metricValue(t, "cfm_nats_redeliveries_total", map[string]string{"type": activityType}))
+	assert.Equal(t, completed+1, metricValue(t, "cfm_orchestrations_completed_total", map[string]string{"type": orchestrationType}))
+	assert.Equal(t, processed+1, metricValue(t, "cfm_activity_duration_seconds", map[string]string{"type": activityType, "result": "complete"}))
+}
+
+func TestUpdateOrchestration_RecordsConflicts(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+	conflicts := metricValue(t, "cfm_orchestration_update_conflicts_total", nil)
+
+	// Updating with a stale revision conflicts once, then succeeds with the re-read revision
+	_, _, err := UpdateOrchestration(context.Background(), stored.orchestration, 0, client, func(o *api.Orchestration) {
+		o.ProcessingData["updated"] = true
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, conflicts+1, metricValue(t, "cfm_orchestration_update_conflicts_total", nil))
+	assert.Equal(t, true, stored.orchestration.ProcessingData["updated"])
+}
+
+func TestUpdateOrchestration_DoesNotRecordOtherErrorsAsConflicts(t *testing.T) {
+	client := mocks.NewMockMsgClient(t)
+	orchestration := createLeaseOrchestration()
+	client.EXPECT().Update(mock.Anything, orchestration.ID, mock.Anything, uint64(1)).Return(0, nats.ErrTimeout).Once()
+	stored := newStoredOrchestration(t, client, orchestration)
+	conflicts := metricValue(t, "cfm_orchestration_update_conflicts_total", nil)
+
+	// The update times out, then succeeds with the re-read revision
+	_, _, err := UpdateOrchestration(context.Background(), stored.orchestration, 1, client, func(o *api.Orchestration) {
+		o.ProcessingData["updated"] = true
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, conflicts, metricValue(t, "cfm_orchestration_update_conflicts_total", nil))
+	assert.Equal(t, true, stored.orchestration.ProcessingData["updated"])
+}
+
+// metricValue returns the value of the counter or the sample count of the histogram with the given name and labels, or 0
+// if it has not been recorded.
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metric:
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if labels[pair.GetName()] != pair.GetValue() {
+					continue metric
+				}
+			}
+			if m.GetHistogram() != nil {
+				return float64(m.GetHistogram().GetSampleCount())
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
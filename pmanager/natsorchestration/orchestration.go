@@ -19,11 +19,15 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/metaform/connector-fabric-manager/common/metrics"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // NatsOrchestrator is responsible for executing an orchestration using NATS for reliable messaging. For each
@@ -61,7 +65,15 @@ func (o *NatsOrchestrator) GetOrchestration(ctx context.Context, id string) (*ap
 // A Jetstream KV entry is used to maintain durable state and is updated as the orchestration progresses. This
 // state is passed to the executors, which access and update it.
 
-func (o *NatsOrchestrator) Execute(ctx context.Context, orchestration *api.Orchestration) error {
+func (o *NatsOrchestrator) Execute(ctx context.Context, orchestration *api.Orchestration) (err error) {
+	ctx, span := tracing.Start(ctx, "execute orchestration", trace.WithAttributes(
+		attribute.String("cfm.orchestration.id", orchestration.ID),
+		attribute.String("cfm.orchestration.type", orchestration.OrchestrationType.String())))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	// TODO validate orchestration - this should include a check to see if there are no steps or steps with no activities
 
 	serializedOrchestration, err := json.Marshal(orchestration)
@@ -90,6 +102,7 @@ func (o *NatsOrchestrator) Execute(ctx context.Context, orchestration *api.Orche
 	if err != nil {
 		return err
 	}
+	metrics.OrchestrationStarted(orchestration.OrchestrationType.String())
 	return nil
 }
 
@@ -0,0 +1,78 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natsorchestration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/mocks"
+	"github.com/metaform/connector-fabric-manager/common/natsclient"
+	"github.com/metaform/connector-fabric-manager/common/tracing"
+	"github.com/metaform/connector-fabric-manager/common/tracing/tracingtest"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNatsActivityExecutor_ContinuesTrace(t *testing.T) {
+	exporter, restore := tracingtest.InstallInMemoryExporter()
+	defer restore()
+
+	client := mocks.NewMockMsgClient(t)
+	stored := newStoredOrchestration(t, client, createLeaseOrchestration())
+
+	var response *nats.Msg
+	client.EXPECT().PublishMsg(mock.Anything, publishedTo(natsclient.CFMOrchestrationResponseSubject)).
+		RunAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			response = msg
+			return &jetstream.PubAck{}, nil
+		})
+
+	// The activity message carries the trace context of the orchestrator
+	parentCtx, parent := tracing.Start(context.Background(), "execute orchestration")
+	parent.End()
+	message := newLeaseMessage(t, stored.orchestration.ID, "A1")
+	message.headers = natsclient.NewTracedMsg(parentCtx, "event.test-lease-activity", nil).Header
+
+	var activitySpan trace.SpanContext
+	processor := &poolProcessor{process: func(activityContext api.ActivityContext) api.ActivityResult {
+		activitySpan = trace.SpanContextFromContext(activityContext.Context())
+		return api.ActivityResult{Result: api.ActivityResultComplete}
+	}}
+	executor := newPoolExecutor(client, processor)
+	require.NoError(t, executor.processMessage(context.Background(), message))
+
+	// The activity context carries the span of the activity, which continues the orchestration trace
+	traceID := parent.SpanContext().TraceID()
+	require.True(t, activitySpan.IsValid())
+	assert.Equal(t, traceID, activitySpan.TraceID())
+
+	// The response is published with the trace context
+	require.NotNil(t, response)
+	assert.Contains(t, response.Header.Get("traceparent"), traceID.String())
+
+	var processSpan bool
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "process activity "+leaseActivityType.String() {
+			processSpan = true
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+			assert.Equal(t, trace.SpanKindConsumer, span.SpanKind)
+		}
+	}
+	assert.True(t, processSpan)
+}
@@ -66,6 +66,7 @@ func (w *OrchestrationIndexWatcher) onMessage(data []byte, msg MessageAck) {
 				currentEntry.State == api.OrchestrationStateErrored {
 				return nil
 			}
+			entry.Version = currentEntry.Version
 			entry.State = orchestration.State
 			entry.StateTimestamp = orchestration.StateTimestamp
 			if w.index.Update(ctx, entry) != nil {
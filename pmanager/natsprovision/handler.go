@@ -62,7 +62,7 @@ func newNatsProvisionHandler(
 						if err != nil {
 							return types.NewRecoverableError("failed to marshal response: %s", err.Error())
 						}
-						_, err = client.Publish(ctx, natsclient.CFMOrchestrationResponseSubject, ser)
+						_, err = natsclient.PublishTraced(ctx, client, natsclient.CFMOrchestrationResponseSubject, ser)
 						if err != nil {
 							return types.NewRecoverableError("failed to publish response: %s", err.Error())
 						}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"iter"
 	"testing"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/mocks"
 	"github.com/metaform/connector-fabric-manager/common/model"
@@ -27,6 +28,7 @@ import (
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -84,9 +86,11 @@ func TestNatsProvisionHandler_Dispatcher_ValidResponseStructure(t *testing.T) {
 	mockProvisionManager.On("Start", ctx, &manifest).Return(nil, nonRecoverableErr)
 
 	var capturedPayload []byte
-	mockClient.EXPECT().Publish(ctx, natsclient.CFMOrchestrationResponseSubject, mock.AnythingOfType("[]uint8")).
-		Run(func(ctx context.Context, subject string, payload []byte, opts ...jetstream.PublishOpt) {
-			capturedPayload = payload
+	mockClient.EXPECT().PublishMsg(mock.Anything, mock.MatchedBy(func(msg *nats.Msg) bool {
+		return msg.Subject == natsclient.CFMOrchestrationResponseSubject
+	})).
+		Run(func(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) {
+			capturedPayload = msg.Data
 		}).Return(pubAck, nil)
 
 	err := handler.RetriableMessageProcessor.Dispatcher(ctx, manifest)
@@ -141,7 +145,9 @@ func TestNatsProvisionHandler_Dispatcher_ErrorTypes(t *testing.T) {
 
 			if tc.shouldPublish {
 				pubAck := &jetstream.PubAck{}
-				mockClient.EXPECT().Publish(ctx, natsclient.CFMOrchestrationResponseSubject, mock.Anything).Return(pubAck, nil)
+				mockClient.EXPECT().PublishMsg(mock.Anything, mock.MatchedBy(func(msg *nats.Msg) bool {
+					return msg.Subject == natsclient.CFMOrchestrationResponseSubject
+				})).Return(pubAck, nil)
 			}
 
 			err := handler.RetriableMessageProcessor.Dispatcher(ctx, manifest)
@@ -171,6 +177,22 @@ func (m *MockProvisionManager) Start(ctx context.Context, manifest *model.Orches
 	return args.Get(0).(*api.Orchestration), args.Error(1)
 }
 
+func (m *MockProvisionManager) StartIdempotent(ctx context.Context, idempotencyKey string, manifest *model.OrchestrationManifest) (*api.Orchestration, error) {
+	args := m.Called(ctx, idempotencyKey, manifest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.Orchestration), args.Error(1)
+}
+
+func (m *MockProvisionManager) WaitForOrchestration(ctx context.Context, id string, timeout time.Duration) (*api.Orchestration, error) {
+	args := m.Called(ctx, id, timeout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.Orchestration), args.Error(1)
+}
+
 func (m *MockProvisionManager) Cancel(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -38,12 +38,15 @@ func (a *PostgresServiceAssembly) Name() string {
 }
 
 func (a *PostgresServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestrationIndexKey, store.TransactionContextKey}
+	return []system.ServiceType{api.DefinitionStoreKey, api.OrchestrationIndexKey, api.ScheduleStoreKey, api.ScheduleRunStoreKey, api.IdempotencyStoreKey, store.TransactionContextKey}
 }
 
 func (a *PostgresServiceAssembly) Init(context *system.InitContext) error {
 	context.Registry.Register(api.DefinitionStoreKey, newPostgresDefinitionStore())
 	context.Registry.Register(api.OrchestrationIndexKey, newOrchestrationEntryStore())
+	context.Registry.Register(api.ScheduleStoreKey, newScheduleStore())
+	context.Registry.Register(api.ScheduleRunStoreKey, newScheduleRunStore())
+	context.Registry.Register(api.IdempotencyStoreKey, newIdempotencyStore())
 
 	if !context.Config.IsSet(dsnKey) {
 		return fmt.Errorf("missing Postgres DSN configuration: %s", dsnKey)
@@ -89,6 +92,24 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	err = createSchedulesTable(db)
+
+	if err != nil {
+		return err
+	}
+
+	err = createScheduleRunsTable(db)
+
+	if err != nil {
+		return err
+	}
+
+	err = createIdempotencyRecordsTable(db)
+
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -160,12 +160,14 @@ func (p *PostgresDefinitionStore) StoreOrchestrationDefinition(
 	definition *api.OrchestrationDefinition,
 ) (*api.OrchestrationDefinition, error) {
 	// Check if it exists
-	exists, err := p.orchestrationStore.Exists(ctx, definition.GetID())
-	if err != nil {
+	existing, err := p.orchestrationStore.FindByID(ctx, definition.GetID())
+	if err != nil && !errors.Is(err, types.ErrNotFound) {
 		return nil, err
 	}
 
-	if exists {
+	if err == nil {
+		// The definition replaces the stored definition regardless of the version it was read at
+		definition.Version = existing.Version
 		err = p.orchestrationStore.Update(ctx, definition)
 		if err != nil {
 			return nil, err
@@ -182,12 +184,14 @@ func (p *PostgresDefinitionStore) StoreActivityDefinition(
 	definition *api.ActivityDefinition,
 ) (*api.ActivityDefinition, error) {
 	// Check if it exists
-	exists, err := p.activityStore.Exists(ctx, definition.GetID())
-	if err != nil {
+	existing, err := p.activityStore.FindByID(ctx, definition.GetID())
+	if err != nil && !errors.Is(err, types.ErrNotFound) {
 		return nil, err
 	}
 
-	if exists {
+	if err == nil {
+		// The definition replaces the stored definition regardless of the version it was read at
+		definition.Version = existing.Version
 		err = p.activityStore.Update(ctx, definition)
 		if err != nil {
 			return nil, err
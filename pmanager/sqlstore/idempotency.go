@@ -0,0 +1,96 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/sqlstore"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+)
+
+func newIdempotencyStore() store.EntityStore[*api.IdempotencyRecord] {
+	columnNames := []string{"id", "version", "request_hash", "orchestration_id", "state", "created_timestamp"}
+	builder := sqlstore.NewPostgresJSONBBuilder().
+		WithFieldMappings(map[string]string{
+			"requestHash":      "request_hash",
+			"orchestrationId":  "orchestration_id",
+			"createdTimestamp": "created_timestamp"})
+
+	return sqlstore.NewPostgresEntityStore[*api.IdempotencyRecord](
+		cfmIdempotencyRecordsTable,
+		columnNames,
+		recordToIdempotencyRecord,
+		idempotencyRecordToRecord,
+		builder,
+	)
+}
+
+func recordToIdempotencyRecord(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.IdempotencyRecord, error) {
+	idempotencyRecord := &api.IdempotencyRecord{}
+	if id, ok := record.Values["id"].(string); ok {
+		idempotencyRecord.ID = id
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record id reading record")
+	}
+
+	if version, ok := record.Values["version"].(int64); ok {
+		idempotencyRecord.Version = version
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record version reading record")
+	}
+
+	if hash, ok := record.Values["request_hash"].(string); ok {
+		idempotencyRecord.RequestHash = hash
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record request_hash reading record")
+	}
+
+	if orchestrationID, ok := record.Values["orchestration_id"].(string); ok {
+		idempotencyRecord.OrchestrationID = orchestrationID
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record orchestration_id reading record")
+	}
+
+	if state, ok := record.Values["state"].(string); ok {
+		idempotencyRecord.State = api.IdempotencyState(state)
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record state reading record")
+	}
+
+	if timestamp, ok := record.Values["created_timestamp"].(time.Time); ok {
+		idempotencyRecord.CreatedTimestamp = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid idempotency record created_timestamp reading record")
+	}
+
+	return idempotencyRecord, nil
+}
+
+func idempotencyRecordToRecord(idempotencyRecord *api.IdempotencyRecord) (*sqlstore.DatabaseRecord, error) {
+	record := &sqlstore.DatabaseRecord{
+		Values: make(map[string]any),
+	}
+
+	record.Values["id"] = idempotencyRecord.ID
+	record.Values["version"] = idempotencyRecord.Version
+	record.Values["request_hash"] = idempotencyRecord.RequestHash
+	record.Values["orchestration_id"] = idempotencyRecord.OrchestrationID
+	record.Values["state"] = string(idempotencyRecord.State)
+	record.Values["created_timestamp"] = idempotencyRecord.CreatedTimestamp
+
+	return record, nil
+}
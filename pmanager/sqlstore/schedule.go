@@ -0,0 +1,201 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/sqlstore"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+)
+
+func newScheduleStore() store.EntityStore[*api.Schedule] {
+	columnNames := []string{"id", "version", "cron_expression", "orchestration_type", "payload_template", "paused",
+		"next_run", "last_run", "created_timestamp"}
+	builder := sqlstore.NewPostgresJSONBBuilder().
+		WithFieldMappings(map[string]string{
+			"cronExpression":    "cron_expression",
+			"orchestrationType": "orchestration_type",
+			"payloadTemplate":   "payload_template",
+			"nextRun":           "next_run",
+			"lastRun":           "last_run",
+			"createdTimestamp":  "created_timestamp"})
+
+	return sqlstore.NewPostgresEntityStore[*api.Schedule](
+		cfmSchedulesTable,
+		columnNames,
+		recordToSchedule,
+		scheduleToRecord,
+		builder,
+	)
+}
+
+func newScheduleRunStore() store.EntityStore[*api.ScheduleRun] {
+	columnNames := []string{"id", "version", "schedule_id", "scheduled_time", "started_timestamp", "error"}
+	builder := sqlstore.NewPostgresJSONBBuilder().
+		WithFieldMappings(map[string]string{
+			"scheduleId":       "schedule_id",
+			"scheduledTime":    "scheduled_time",
+			"startedTimestamp": "started_timestamp"})
+
+	return sqlstore.NewPostgresEntityStore[*api.ScheduleRun](
+		cfmScheduleRunsTable,
+		columnNames,
+		recordToScheduleRun,
+		scheduleRunToRecord,
+		builder,
+	)
+}
+
+func recordToSchedule(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.Schedule, error) {
+	schedule := &api.Schedule{}
+	if id, ok := record.Values["id"].(string); ok {
+		schedule.ID = id
+	} else {
+		return nil, fmt.Errorf("invalid schedule id reading record")
+	}
+
+	if version, ok := record.Values["version"].(int64); ok {
+		schedule.Version = version
+	} else {
+		return nil, fmt.Errorf("invalid schedule version reading record")
+	}
+
+	if expression, ok := record.Values["cron_expression"].(string); ok {
+		schedule.CronExpression = expression
+	} else {
+		return nil, fmt.Errorf("invalid schedule cron_expression reading record")
+	}
+
+	if otype, ok := record.Values["orchestration_type"].(string); ok {
+		schedule.OrchestrationType = model.OrchestrationType(otype)
+	} else {
+		return nil, fmt.Errorf("invalid schedule orchestration_type reading record")
+	}
+
+	if bytes, ok := record.Values["payload_template"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &schedule.PayloadTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if paused, ok := record.Values["paused"].(bool); ok {
+		schedule.Paused = paused
+	} else {
+		return nil, fmt.Errorf("invalid schedule paused reading record")
+	}
+
+	if timestamp, ok := record.Values["next_run"].(time.Time); ok {
+		schedule.NextRun = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid schedule next_run reading record")
+	}
+
+	if timestamp, ok := record.Values["last_run"].(time.Time); ok {
+		schedule.LastRun = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid schedule last_run reading record")
+	}
+
+	if timestamp, ok := record.Values["created_timestamp"].(time.Time); ok {
+		schedule.CreatedTimestamp = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid schedule created_timestamp reading record")
+	}
+
+	return schedule, nil
+}
+
+func scheduleToRecord(schedule *api.Schedule) (*sqlstore.DatabaseRecord, error) {
+	record := &sqlstore.DatabaseRecord{
+		Values: make(map[string]any),
+	}
+
+	record.Values["id"] = schedule.ID
+	record.Values["version"] = schedule.Version
+	record.Values["cron_expression"] = schedule.CronExpression
+	record.Values["orchestration_type"] = schedule.OrchestrationType
+	record.Values["paused"] = schedule.Paused
+	record.Values["next_run"] = schedule.NextRun
+	record.Values["last_run"] = schedule.LastRun
+	record.Values["created_timestamp"] = schedule.CreatedTimestamp
+
+	if schedule.PayloadTemplate != nil {
+		bytes, err := json.Marshal(schedule.PayloadTemplate)
+		if err != nil {
+			return record, err
+		}
+		record.Values["payload_template"] = bytes
+	}
+
+	return record, nil
+}
+
+func recordToScheduleRun(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.ScheduleRun, error) {
+	run := &api.ScheduleRun{}
+	if id, ok := record.Values["id"].(string); ok {
+		run.ID = id
+	} else {
+		return nil, fmt.Errorf("invalid schedule run id reading record")
+	}
+
+	if version, ok := record.Values["version"].(int64); ok {
+		run.Version = version
+	} else {
+		return nil, fmt.Errorf("invalid schedule run version reading record")
+	}
+
+	if scheduleID, ok := record.Values["schedule_id"].(string); ok {
+		run.ScheduleID = scheduleID
+	} else {
+		return nil, fmt.Errorf("invalid schedule run schedule_id reading record")
+	}
+
+	if timestamp, ok := record.Values["scheduled_time"].(time.Time); ok {
+		run.ScheduledTime = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid schedule run scheduled_time reading record")
+	}
+
+	if timestamp, ok := record.Values["started_timestamp"].(time.Time); ok {
+		run.StartedTimestamp = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid schedule run started_timestamp reading record")
+	}
+
+	if runError, ok := record.Values["error"].(string); ok {
+		run.Error = runError
+	}
+
+	return run, nil
+}
+
+func scheduleRunToRecord(run *api.ScheduleRun) (*sqlstore.DatabaseRecord, error) {
+	record := &sqlstore.DatabaseRecord{
+		Values: make(map[string]any),
+	}
+
+	record.Values["id"] = run.ID
+	record.Values["version"] = run.Version
+	record.Values["schedule_id"] = run.ScheduleID
+	record.Values["scheduled_time"] = run.ScheduledTime
+	record.Values["started_timestamp"] = run.StartedTimestamp
+	record.Values["error"] = run.Error
+
+	return record, nil
+}
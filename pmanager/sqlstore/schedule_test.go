@@ -0,0 +1,107 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/sqlstore"
+	"github.com/metaform/connector-fabric-manager/pmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleStore_CreateAndFind(t *testing.T) {
+	setupScheduleTables(t, testDB)
+	defer cleanupScheduleTestData(t, testDB)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	schedule := &api.Schedule{
+		ID:                "rotate-secrets",
+		Version:           1,
+		CronExpression:    "0 * * * *",
+		OrchestrationType: "rotate-credentials",
+		PayloadTemplate:   map[string]any{"runId": "{{.RunID}}"},
+		NextRun:           now.Add(time.Hour),
+		CreatedTimestamp:  now,
+	}
+
+	estore := newScheduleStore()
+	txCtx, tx := beginScheduleTx(t)
+	defer tx.Rollback()
+
+	_, err := estore.Create(txCtx, schedule)
+	require.NoError(t, err)
+
+	schedule.Paused = true
+	require.NoError(t, estore.Update(txCtx, schedule))
+
+	retrieved, err := estore.FindByID(txCtx, "rotate-secrets")
+	require.NoError(t, err)
+	assert.Equal(t, "0 * * * *", retrieved.CronExpression)
+	assert.Equal(t, "rotate-credentials", retrieved.OrchestrationType.String())
+	assert.Equal(t, map[string]any{"runId": "{{.RunID}}"}, retrieved.PayloadTemplate)
+	assert.True(t, retrieved.Paused)
+	assert.True(t, retrieved.NextRun.Equal(now.Add(time.Hour)))
+	assert.True(t, retrieved.LastRun.IsZero())
+}
+
+func TestScheduleRunStore_FindBySchedule(t *testing.T) {
+	setupScheduleTables(t, testDB)
+	defer cleanupScheduleTestData(t, testDB)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	estore := newScheduleRunStore()
+	txCtx, tx := beginScheduleTx(t)
+	defer tx.Rollback()
+
+	for _, run := range []*api.ScheduleRun{
+		{ID: api.ScheduleRunID("s1", now), ScheduleID: "s1", ScheduledTime: now, StartedTimestamp: now},
+		{ID: api.ScheduleRunID("s1", now.Add(time.Hour)), ScheduleID: "s1", ScheduledTime: now.Add(time.Hour), StartedTimestamp: now, Error: "failed"},
+		{ID: api.ScheduleRunID("s2", now), ScheduleID: "s2", ScheduledTime: now, StartedTimestamp: now},
+	} {
+		_, err := estore.Create(txCtx, run)
+		require.NoError(t, err)
+	}
+
+	var runs []*api.ScheduleRun
+	for run, err := range estore.FindByPredicate(txCtx, query.Eq("scheduleId", "s1")) {
+		require.NoError(t, err)
+		runs = append(runs, run)
+	}
+	require.Len(t, runs, 2)
+	for _, run := range runs {
+		assert.Equal(t, "s1", run.ScheduleID)
+	}
+}
+
+func beginScheduleTx(t *testing.T) (context.Context, *sql.Tx) {
+	ctx := context.Background()
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	return context.WithValue(ctx, sqlstore.SQLTransactionKey, tx), tx
+}
+
+func setupScheduleTables(t *testing.T, db *sql.DB) {
+	require.NoError(t, createSchedulesTable(db))
+	require.NoError(t, createScheduleRunsTable(db))
+}
+
+func cleanupScheduleTestData(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("DROP TABLE IF EXISTS schedules, schedule_runs CASCADE")
+	require.NoError(t, err)
+}
@@ -21,6 +21,9 @@ const (
 	cfmOrchestrationEntriesTable     = "orchestration_entries"
 	cfmOrchestrationDefinitionsTable = "orchestration_definitions"
 	cfmActivityDefinitionsTable      = "activity_definitions"
+	cfmSchedulesTable                = "schedules"
+	cfmScheduleRunsTable             = "schedule_runs"
+	cfmIdempotencyRecordsTable       = "idempotency_records"
 )
 
 // Note fields are quoted to avoid some IDEs (Goland) reformatting them to uppercase
@@ -69,3 +72,49 @@ func createActivityDefinitionsTable(db *sql.DB) error {
 	`, cfmActivityDefinitionsTable))
 	return err
 }
+
+func createSchedulesTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR(255) PRIMARY KEY,
+			version BIGINT NOT NULL,
+			cron_expression VARCHAR(255) NOT NULL,
+			orchestration_type VARCHAR(255) NOT NULL,
+			payload_template JSONB,
+			paused BOOLEAN DEFAULT FALSE,
+			next_run TIMESTAMP NOT NULL,
+			last_run TIMESTAMP NOT NULL,
+			created_timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`, cfmSchedulesTable))
+	return err
+}
+
+func createScheduleRunsTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR(255) PRIMARY KEY,
+			version BIGINT NOT NULL,
+			schedule_id VARCHAR(255) NOT NULL,
+			scheduled_time TIMESTAMP NOT NULL,
+			started_timestamp TIMESTAMP NOT NULL,
+			"error" TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_schedule_runs_schedule_id ON schedule_runs(schedule_id)
+	`, cfmScheduleRunsTable))
+	return err
+}
+
+func createIdempotencyRecordsTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR(255) PRIMARY KEY,
+			version BIGINT NOT NULL,
+			request_hash VARCHAR(64) NOT NULL,
+			orchestration_id VARCHAR(255) NOT NULL,
+			state VARCHAR(32) NOT NULL,
+			created_timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`, cfmIdempotencyRecordsTable))
+	return err
+}
@@ -14,6 +14,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/system"
@@ -22,6 +23,8 @@ import (
 const (
 	ProvisionHandlerRegistryKey system.ServiceType = "tmapi:ProvisionHandlerRegistry"
 	ProvisionClientKey          system.ServiceType = "tmapi:ProvisionClient"
+	OrchestrationClientKey      system.ServiceType = "tmapi:OrchestrationClient"
+	LeaderElectionKey           system.ServiceType = "tmapi:LeaderElection"
 )
 
 type VPAPropMap = map[model.VPAType]map[string]any
@@ -38,9 +41,58 @@ type ProvisionClient interface {
 // If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
 type ProvisionCallbackHandler func(context.Context, model.OrchestrationResponse) error
 
+// ProvisionProgressHandler is called when an activity reports progress while an orchestration is executing.
+// If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
+type ProvisionProgressHandler func(context.Context, model.OrchestrationProgress) error
+
 // ProvisionHandlerRegistry registers orchestration handlers by type.
 type ProvisionHandlerRegistry interface {
 	Register(orchestrationType model.OrchestrationType, handler ProvisionCallbackHandler)
+
+	// RegisterProgress registers a handler for progress reported by orchestrations of the given type.
+	RegisterProgress(orchestrationType model.OrchestrationType, handler ProvisionProgressHandler)
+}
+
+// OrchestrationState is the state of an orchestration in the provision manager.
+type OrchestrationState uint
+
+const (
+	OrchestrationStateInitialized OrchestrationState = 0
+	OrchestrationStateRunning     OrchestrationState = 1
+	OrchestrationStateCompleted   OrchestrationState = 2
+	OrchestrationStateErrored     OrchestrationState = 3
+)
+
+// Terminal returns true if the orchestration has completed or errored.
+func (s OrchestrationState) Terminal() bool {
+	return s == OrchestrationStateCompleted || s == OrchestrationStateErrored
+}
+
+// OrchestrationStatus is the state of an orchestration reported by the provision manager. OutputData is only returned
+// when a single orchestration is requested.
+type OrchestrationStatus struct {
+	ID                string                  `json:"id"`
+	CorrelationID     string                  `json:"correlationId"`
+	State             OrchestrationState      `json:"state"`
+	CreatedTimestamp  time.Time               `json:"createdTimestamp"`
+	OrchestrationType model.OrchestrationType `json:"orchestrationType"`
+	OutputData        map[string]any          `json:"outputData,omitempty"`
+}
+
+// OrchestrationClient queries the provision manager for orchestrations.
+type OrchestrationClient interface {
+	// QueryOrchestrations returns the orchestrations started for manifests with the given correlation ID.
+	QueryOrchestrations(ctx context.Context, correlationID string) ([]OrchestrationStatus, error)
+
+	// GetOrchestration returns the orchestration with the given ID, including its output data.
+	// Returns types.ErrNotFound if the orchestration does not exist.
+	GetOrchestration(ctx context.Context, orchestrationID string) (*OrchestrationStatus, error)
+}
+
+// LeaderElection determines whether this instance is the leader among the tenant manager instances. Work that must
+// only be performed by a single instance, such as reconciling participant profiles, is performed by the leader.
+type LeaderElection interface {
+	IsLeader() bool
 }
 
 func ToVPAMap(vpaProperties map[string]map[string]any) *VPAPropMap {
@@ -13,13 +13,89 @@
 package api
 
 import (
+	"maps"
+
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/system"
 )
 
 const (
-	CellSelectorKey system.ServiceType = "tmapi:CellSelector"
+	CellSelectorKey    system.ServiceType = "tmapi:CellSelector"
+	VPATypeRegistryKey system.ServiceType = "tmapi:VPATypeRegistry"
+)
+
+const (
+	// CellCapacityProperty is the cell property containing the maximum number of VPAs the cell can host.
+	CellCapacityProperty = "capacity"
+	// CellLabelsProperty is the cell property containing a map of labels, such as region or compliance zone, that are
+	// matched against placement constraints.
+	CellLabelsProperty = "labels"
 )
 
-// CellSelector selects a cell for resource deployment.
-type CellSelector func(model.OrchestrationType, []Cell, []DataspaceProfile) (*Cell, error)
+// CellSelector selects a cell for resource deployment and returns the reason the cell was chosen.
+type CellSelector func(request CellSelectionRequest) (*Cell, CellSelection, error)
+
+// CellSelectionRequest contains the input to a CellSelector. A cell is selected for the VPAs of a single type.
+type CellSelectionRequest struct {
+	OrchestrationType model.OrchestrationType
+	VPAType           model.VPAType
+	Cells             []Cell
+	DataspaceProfiles []DataspaceProfile
+	Placement         CellPlacement
+	// VPACounts is the number of VPAs deployed to each cell, keyed by cell ID, including VPAs of the participant that
+	// have already been placed.
+	VPACounts map[string]int
+	// VPACount is the number of VPAs of the type to place in the selected cell.
+	VPACount int
+}
+
+// CellPlacement constrains the cells VPAs can be placed in. A cell must have all required labels; cells with more
+// preferred labels are favored by selectors that support affinity.
+type CellPlacement struct {
+	RequiredLabels  map[string]string `json:"requiredLabels,omitempty"`
+	PreferredLabels map[string]string `json:"preferredLabels,omitempty"`
+}
+
+// ParticipantPlacement constrains the cells the VPAs of a participant can be placed in. The embedded constraints apply to
+// all VPAs; VPAs contains constraints for VPAs of a given type, which take precedence for the same label.
+type ParticipantPlacement struct {
+	CellPlacement
+	VPAs map[model.VPAType]CellPlacement `json:"vpas,omitempty"`
+}
+
+// ForType returns the placement constraints for VPAs of the given type.
+func (p ParticipantPlacement) ForType(vpaType model.VPAType) CellPlacement {
+	typePlacement, found := p.VPAs[vpaType]
+	if !found {
+		return p.CellPlacement
+	}
+	return CellPlacement{
+		RequiredLabels:  mergeLabels(p.RequiredLabels, typePlacement.RequiredLabels),
+		PreferredLabels: mergeLabels(p.PreferredLabels, typePlacement.PreferredLabels),
+	}
+}
+
+func mergeLabels(labels map[string]string, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return labels
+	}
+	merged := make(map[string]string, len(labels)+len(overrides))
+	maps.Copy(merged, labels)
+	maps.Copy(merged, overrides)
+	return merged
+}
+
+// CellSelection records the strategy used to select the cell of a VPA and why the cell was chosen.
+type CellSelection struct {
+	Strategy string `json:"strategy"`
+	Reason   string `json:"reason"`
+}
+
+// VPATypeRegistry holds the VPA types that can be declared in a dataspace profile VPA topology. The built-in types are
+// registered by default; extensions register additional types during initialization.
+type VPATypeRegistry interface {
+	Register(vpaType model.VPAType)
+	IsRegistered(vpaType model.VPAType) bool
+	// Types returns the registered types in registration order.
+	Types() []model.VPAType
+}
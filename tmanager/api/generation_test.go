@@ -0,0 +1,48 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package api
+
+import (
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParticipantPlacement_ForType(t *testing.T) {
+	placement := ParticipantPlacement{
+		CellPlacement: CellPlacement{
+			RequiredLabels:  map[string]string{"region": "eu", "tier": "core"},
+			PreferredLabels: map[string]string{"zone": "a"},
+		},
+		VPAs: map[model.VPAType]CellPlacement{
+			model.DataPlaneType: {RequiredLabels: map[string]string{"tier": "edge"}},
+		},
+	}
+
+	t.Run("returns participant placement for types without constraints", func(t *testing.T) {
+		assert.Equal(t, placement.CellPlacement, placement.ForType(model.ConnectorType))
+	})
+
+	t.Run("type constraints take precedence for the same label", func(t *testing.T) {
+		assert.Equal(t, CellPlacement{
+			RequiredLabels:  map[string]string{"region": "eu", "tier": "edge"},
+			PreferredLabels: map[string]string{"zone": "a"},
+		}, placement.ForType(model.DataPlaneType))
+	})
+
+	t.Run("does not modify the participant placement", func(t *testing.T) {
+		placement.ForType(model.DataPlaneType)
+		assert.Equal(t, map[string]string{"region": "eu", "tier": "core"}, placement.RequiredLabels)
+	})
+}
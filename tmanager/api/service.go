@@ -26,6 +26,7 @@ const (
 	ParticipantProfileServiceKey system.ServiceType = "tmapi:ParticipantProfileService"
 	DataspaceProfileServiceKey   system.ServiceType = "tmapi:DataspaceProfileService"
 	CellServiceKey               system.ServiceType = "tmapi:CellService"
+	CellEventRegistryKey         system.ServiceType = "tmapi:CellEventRegistry"
 )
 
 // TenantService performs tenant operations.
@@ -33,7 +34,23 @@ type TenantService interface {
 	GetTenant(ctx context.Context, tenantID string) (*Tenant, error)
 	CreateTenant(ctx context.Context, tenant *Tenant) (*Tenant, error)
 	DeleteTenant(ctx context.Context, tenantID string) error
-	PatchTenant(ctx context.Context, id string, properties map[string]any, remove []string) error
+
+	// DeleteTenantCascade marks the tenant as disposing and sends a dispose orchestration for each of its participants.
+	// Participants are deleted when their disposal completes, and the tenant is deleted when it has no participants.
+	// Calling it again for a tenant that is disposing retries participants that failed or were not disposed.
+	DeleteTenantCascade(ctx context.Context, tenantID string) (*TenantDeletionStatus, error)
+
+	// GetDeletionStatus returns the progress of a cascading tenant deletion.
+	// Returns types.ErrNotFound if the tenant does not exist, including after the deletion completed, or if the tenant is
+	// not being deleted.
+	GetDeletionStatus(ctx context.Context, tenantID string) (*TenantDeletionStatus, error)
+	PatchTenant(ctx context.Context, id string, properties map[string]any, remove []string) (*Tenant, error)
+
+	// UpdateQuota replaces the quota of a tenant. A nil quota removes all limits.
+	UpdateQuota(ctx context.Context, tenantID string, quota *TenantQuota) (*Tenant, error)
+
+	// GetUsage returns the resources used by a tenant together with its quota.
+	GetUsage(ctx context.Context, tenantID string) (*TenantUsage, error)
 	GetTenants(ctx context.Context, options store.PaginationOptions) iter.Seq2[*Tenant, error]
 	GetTenantsCount(ctx context.Context) (int64, error)
 	QueryTenants(ctx context.Context, predicate query.Predicate, options store.PaginationOptions) iter.Seq2[*Tenant, error]
@@ -46,6 +63,9 @@ type ParticipantProfileService interface {
 	QueryProfiles(ctx context.Context, predicate query.Predicate, options store.PaginationOptions) iter.Seq2[*ParticipantProfile, error]
 	QueryProfilesCount(ctx context.Context, predicate query.Predicate) (int64, error)
 	DeployProfile(ctx context.Context, tenantID string, deployment *NewParticipantProfileDeployment) (*ParticipantProfile, error)
+	UpdateProfile(ctx context.Context, tenantID string, participantID string, update *ParticipantProfileUpdate) (*ParticipantProfile, error)
+	RetryProfile(ctx context.Context, tenantID string, participantID string) (*ParticipantProfile, error)
+	GetPropertySchema(ctx context.Context, dataspaceProfileIDs []string) (map[string]any, error)
 	DisposeProfile(ctx context.Context, tenantID string, participantID string) error
 }
 
@@ -54,13 +74,54 @@ type DataspaceProfileService interface {
 	GetProfile(ctx context.Context, profileID string) (*DataspaceProfile, error)
 	CreateProfile(ctx context.Context, profile *DataspaceProfile) (*DataspaceProfile, error)
 	DeleteProfile(ctx context.Context, profileID string) error
-	DeployProfile(ctx context.Context, profileID string, cellID string) error
+
+	// DeployProfile deploys the profile to the cell by sending a deployment orchestration. The returned deployment is
+	// pending until the orchestration completes.
+	// Returns types.ErrConflict if the profile is already deployed to the cell.
+	DeployProfile(ctx context.Context, profileID string, cellID string) (*DataspaceDeployment, error)
+
+	// DisposeDeployment removes the deployment of the profile from its cell by sending a dispose orchestration.
+	// Returns types.ErrConflict if the deployment is not active or in the error state.
+	DisposeDeployment(ctx context.Context, profileID string, deploymentID string) error
+
 	ListProfiles(ctx context.Context) ([]DataspaceProfile, error)
 }
 
 // CellService performs cell operations.
 type CellService interface {
 	RecordExternalDeployment(ctx context.Context, cell *Cell) (*Cell, error)
+
+	// DeleteCell deletes the cell.
+	// Returns types.ErrConflict if a VPA that is not disposed is placed in or being migrated from the cell.
 	DeleteCell(ctx context.Context, cellID string) error
+
 	ListCells(ctx context.Context) ([]Cell, error)
+
+	// CordonCell excludes the cell from the selection of cells for new VPAs. VPAs placed in the cell are not affected.
+	CordonCell(ctx context.Context, cellID string) (*Cell, error)
+
+	// UncordonCell makes the cell available for the selection of cells for new VPAs.
+	// Returns types.ErrConflict if VPAs are being migrated from the cell.
+	UncordonCell(ctx context.Context, cellID string) (*Cell, error)
+
+	// DrainCell cordons the cell and migrates the active and offline VPAs placed in it to other cells using a migrate orchestration
+	// for each participant. VPAs of participants that have operations in progress are not migrated and can be migrated
+	// by draining the cell again.
+	DrainCell(ctx context.Context, cellID string) (*CellDrainStatus, error)
+
+	// GetDrainStatus returns the progress of the last drain of the cell.
+	// Returns types.ErrNotFound if the cell has not been drained.
+	GetDrainStatus(ctx context.Context, cellID string) (*CellDrainStatus, error)
+
+	// RecordHeartbeat records a heartbeat sent by the cell. If the cell is offline, it and the VPAs placed in it are
+	// marked active.
+	RecordHeartbeat(ctx context.Context, cellID string, heartbeat CellHeartbeat) (*Cell, error)
+}
+
+// CellEventListener is notified of cell availability transitions.
+type CellEventListener func(ctx context.Context, event CellEvent)
+
+// CellEventRegistry registers listeners for cell events.
+type CellEventRegistry interface {
+	Register(listener CellEventListener)
 }
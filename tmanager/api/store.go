@@ -21,4 +21,5 @@ const (
 	ParticipantProfileStoreKey system.ServiceType = "tmstore:ParticipantProfileStore"
 	CellStoreKey               system.ServiceType = "tmstore:CellStore"
 	DataspaceProfileStoreKey   system.ServiceType = "tmstore:DataspaceProfileStore"
+	OutboxStoreKey             system.ServiceType = "tmstore:OutboxStore"
 )
@@ -52,6 +52,65 @@ type DeployableEntity struct {
 type Tenant struct {
 	Entity
 	Properties Properties `json:"properties"`
+	// Deletion is set while the tenant is disposing its participants before it is deleted.
+	Deletion *TenantDeletion `json:"deletion,omitempty"`
+	// Quota limits the resources of the tenant. A tenant without a quota is not limited.
+	Quota *TenantQuota `json:"quota,omitempty"`
+}
+
+// TenantQuota limits the resources a tenant can use. Limits that are not set are not enforced.
+type TenantQuota struct {
+	// MaxParticipants is the maximum number of participants that are not disposed.
+	MaxParticipants *int `json:"maxParticipants,omitempty"`
+	// MaxVPAs is the maximum number of VPAs that are not disposed, keyed by VPA type.
+	MaxVPAs map[model.VPAType]int `json:"maxVPAs,omitempty"`
+	// AllowedDataspaceProfiles contains the IDs of the dataspace profiles participants can be members of. All profiles
+	// are allowed if empty.
+	AllowedDataspaceProfiles []string `json:"allowedDataspaceProfiles,omitempty"`
+	// AllowedCells contains the IDs of the cells VPAs can be placed in. All cells are allowed if empty.
+	AllowedCells []string `json:"allowedCells,omitempty"`
+}
+
+// TenantUsage reports the resources used by a tenant. Participants and VPAs that have been disposed are not counted.
+type TenantUsage struct {
+	TenantID     string                `json:"tenantId"`
+	Participants int                   `json:"participants"`
+	VPAs         map[model.VPAType]int `json:"vpas"`
+	// DataspaceProfiles is the number of participants that are members of each dataspace profile.
+	DataspaceProfiles map[string]int `json:"dataspaceProfiles"`
+	// Cells is the number of VPAs placed in each cell.
+	Cells map[string]int `json:"cells"`
+	Quota *TenantQuota   `json:"quota,omitempty"`
+}
+
+// TenantDeletion records the cascading deletion of a tenant.
+type TenantDeletion struct {
+	StartTimestamp time.Time `json:"startTimestamp"`
+	// Participants is the number of participants the tenant had when the deletion started.
+	Participants int `json:"participants"`
+	// Failures contains the errors of participants that could not be disposed, keyed by participant ID.
+	Failures map[string]string `json:"failures,omitempty"`
+}
+
+// TenantDeletionStatus reports the progress of a cascading tenant deletion. Deleted participants have been disposed and
+// removed, disposing participants are in progress, failed participants could not be disposed and remaining
+// participants have not been disposed yet.
+type TenantDeletionStatus struct {
+	TenantID       string               `json:"tenantId"`
+	StartTimestamp time.Time            `json:"startTimestamp"`
+	Participants   int                  `json:"participants"`
+	Deleted        int                  `json:"deleted"`
+	Disposing      int                  `json:"disposing"`
+	Remaining      int                  `json:"remaining"`
+	Failed         []ParticipantFailure `json:"failed"`
+	// Complete is true when all participants and the tenant have been deleted.
+	Complete bool `json:"complete"`
+}
+
+// ParticipantFailure describes a participant that could not be disposed.
+type ParticipantFailure struct {
+	ParticipantID string `json:"participantId"`
+	Error         string `json:"error"`
 }
 
 // ParticipantProfile represents a participant in a dataspace. A participant can be an entire organization, in which case
@@ -70,12 +129,20 @@ type ParticipantProfile struct {
 }
 
 type NewParticipantProfileDeployment struct {
-	Identifier          string              `json:"identifier" required:"true"`
-	CellID              string              `json:"cellId" required:"true"`
-	DataspaceProfileIDs []string            `json:"dataspaceProfileIds,omitempty"`
-	ParticipantRoles    map[string][]string `json:"participantRoles,omitempty"`
-	VPAProperties       VPAPropMap          `json:"vpaProperties,omitempty"`
-	Properties          map[string]any      `json:"properties,omitempty"`
+	Identifier          string               `json:"identifier" required:"true"`
+	CellID              string               `json:"cellId" required:"true"`
+	DataspaceProfileIDs []string             `json:"dataspaceProfileIds,omitempty"`
+	ParticipantRoles    map[string][]string  `json:"participantRoles,omitempty"`
+	VPAProperties       VPAPropMap           `json:"vpaProperties,omitempty"`
+	Properties          map[string]any       `json:"properties,omitempty"`
+	Placement           ParticipantPlacement `json:"placement,omitempty"`
+}
+
+// ParticipantProfileUpdate changes the properties of a deployed participant profile. Properties are merged into the
+// existing VPA and profile properties; a nil value removes the property.
+type ParticipantProfileUpdate struct {
+	VPAProperties VPAPropMap     `json:"vpaProperties,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
 }
 
 // DataspaceProfile represents a specific dataspace, protocol, and policies tuple. For example, The Foo Dataspace that
@@ -91,16 +158,62 @@ type DataspaceProfile struct {
 type DataspaceSpec struct {
 	ProtocolStack   []string               `json:"protocolStack"`
 	CredentialSpecs []model.CredentialSpec `json:"credentialSpecs"`
+	PropertySchemas *PropertySchemas       `json:"propertySchemas,omitempty"`
+	VPATopology     []VPASpec              `json:"vpaTopology,omitempty"`
+}
+
+// VPASpec declares VPAs of a given type to instantiate when a participant profile is deployed to the dataspace. Count
+// defaults to one. Properties are defaults that are overridden by the VPA properties of the deployment request.
+type VPASpec struct {
+	Type       model.VPAType  `json:"type"`
+	Count      int            `json:"count,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// PropertySchemas declares JSON Schemas that participant profile properties must conform to when a profile is deployed
+// to the dataspace. Participant applies to the profile properties; VPAs applies to the properties of each VPA type.
+type PropertySchemas struct {
+	Participant map[string]any                   `json:"participant,omitempty"`
+	VPAs        map[model.VPAType]map[string]any `json:"vpas,omitempty"`
 }
 
 // VirtualParticipantAgent is a runtime context deployed when a participant profile is provisioned to a cell. A runtime
 // context could be a connector, credential service, or another component.
 type VirtualParticipantAgent struct {
 	DeployableEntity
-	Type           model.VPAType `json:"type"`
-	CellID         string        `json:"cellId"`
-	ExternalCellID string        `json:"externalCellId"`
-	Properties     Properties    `json:"properties"`
+	Type           model.VPAType       `json:"type"`
+	CellID         string              `json:"cellId"`
+	ExternalCellID string              `json:"externalCellId"`
+	Properties     Properties          `json:"properties"`
+	Outputs        Properties          `json:"outputs,omitempty"`
+	ErrorDetail    string              `json:"errorDetail,omitempty"`
+	Progress       *DeploymentProgress `json:"progress,omitempty"`
+	CellSelection  *CellSelection      `json:"cellSelection,omitempty"`
+	// Migration is set while the VPA is moved from another cell to its cell. It is retained if the migration fails so
+	// that it can be retried.
+	Migration *VPAMigration `json:"migration,omitempty"`
+	// Operation is the orchestration type of the operation last performed on the VPA. It is used to retry the
+	// operation if it fails.
+	Operation model.OrchestrationType `json:"operation,omitempty"`
+	// ManifestID is the ID of the manifest sent for the operation last performed on the VPA. It is used to reconcile the
+	// operation with the provision manager.
+	ManifestID string `json:"manifestId,omitempty"`
+}
+
+// VPAMigration identifies the cell a VPA is being migrated from.
+type VPAMigration struct {
+	SourceCellID         string    `json:"sourceCellId"`
+	SourceExternalCellID string    `json:"sourceExternalCellId"`
+	StartTimestamp       time.Time `json:"startTimestamp"`
+}
+
+// DeploymentProgress is the progress of an in-flight deployment operation as reported by the provision manager.
+type DeploymentProgress struct {
+	Percent      int       `json:"percent"`
+	Message      string    `json:"message,omitempty"`
+	ActivityID   string    `json:"activityId,omitempty"`
+	ActivityType string    `json:"activityType,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
 }
 
 // DataspaceDeployment is runtime capabilities and configuration deployed when a dataspace profile to a cell.
@@ -109,6 +222,11 @@ type DataspaceDeployment struct {
 	CellID         string     `json:"cellId,omitempty"`
 	ExternalCellID string     `json:"externalCellID"`
 	Properties     Properties `json:"properties"`
+	Outputs        Properties `json:"outputs,omitempty"`
+	ErrorDetail    string     `json:"errorDetail,omitempty"`
+	// ManifestID is the ID of the orchestration manifest last sent for the deployment. It correlates orchestration
+	// responses with the deployment.
+	ManifestID string `json:"manifestId,omitempty"`
 }
 
 // Cell is a homogenous deployment zone. A cell could be a Kubernetes cluster or some other infrastructure.
@@ -116,8 +234,96 @@ type Cell struct {
 	DeployableEntity
 	ExternalID string     `json:"externalId"`
 	Properties Properties `json:"properties"`
+	// Cordoned cells are not selected for new VPAs.
+	Cordoned bool       `json:"cordoned,omitempty"`
+	Drain    *CellDrain `json:"drain,omitempty"`
+	// Health is reported by cells that send heartbeats. Cells that have never sent a heartbeat are not monitored.
+	Health *CellHealth `json:"health,omitempty"`
+}
+
+// CellHealth records the last heartbeat received from a cell.
+type CellHealth struct {
+	LastHeartbeat time.Time      `json:"lastHeartbeat"`
+	Metrics       map[string]any `json:"metrics,omitempty"`
 }
 
+// CellHeartbeat is periodically sent by a cell to report that it is available. Capacity, if set, replaces the capacity
+// property of the cell.
+type CellHeartbeat struct {
+	Capacity *int           `json:"capacity,omitempty"`
+	Metrics  map[string]any `json:"metrics,omitempty"`
+}
+
+type CellEventType string
+
+const (
+	// CellEventOffline is emitted when a cell misses heartbeats and is marked offline.
+	CellEventOffline CellEventType = "cell.offline"
+	// CellEventOnline is emitted when an offline cell resumes sending heartbeats and is marked active.
+	CellEventOnline CellEventType = "cell.online"
+)
+
+// CellEvent reports a cell availability transition and the participants with VPAs placed in the cell.
+type CellEvent struct {
+	Type           CellEventType `json:"type"`
+	CellID         string        `json:"cellId"`
+	ExternalCellID string        `json:"externalCellId"`
+	Timestamp      time.Time     `json:"timestamp"`
+	Participants   []string      `json:"participants"`
+}
+
+// CellDrain records the last drain of a cell.
+type CellDrain struct {
+	StartTimestamp time.Time `json:"startTimestamp"`
+	// Participants is the number of participants whose VPAs are being migrated.
+	Participants int `json:"participants"`
+	// VPAs is the number of VPAs that are being migrated.
+	VPAs int `json:"vpas"`
+}
+
+// CellDrainStatus reports the progress of a cell drain. Migrated VPAs have been moved to other cells, migrating VPAs
+// are in progress, failed VPAs are in the error state and can be retried, and remaining VPAs are still placed in the
+// cell and have not been migrated, for example, because their participant had an operation in progress.
+type CellDrainStatus struct {
+	CellID         string    `json:"cellId"`
+	Cordoned       bool      `json:"cordoned"`
+	StartTimestamp time.Time `json:"startTimestamp"`
+	Participants   int       `json:"participants"`
+	VPAs           int       `json:"vpas"`
+	Migrated       int       `json:"migrated"`
+	Migrating      int       `json:"migrating"`
+	Failed         int       `json:"failed"`
+	Remaining      int       `json:"remaining"`
+}
+
+// Complete returns true if all VPAs have been migrated out of the cell.
+func (s CellDrainStatus) Complete() bool {
+	return s.Migrating == 0 && s.Failed == 0 && s.Remaining == 0
+}
+
+// OutboxEntry is an orchestration manifest recorded in the transaction of the operation that produced it. Pending
+// entries are published to the provision manager after the transaction commits.
+type OutboxEntry struct {
+	Entity
+	State            OutboxState                 `json:"state"`
+	Manifest         model.OrchestrationManifest `json:"manifest"`
+	Attempts         int                         `json:"attempts"`
+	LastError        string                      `json:"lastError,omitempty"`
+	CreatedTimestamp time.Time                   `json:"createdTimestamp"`
+	SentTimestamp    time.Time                   `json:"sentTimestamp"`
+}
+
+// OutboxState is the delivery state of an outbox entry.
+type OutboxState string
+
+const (
+	OutboxStatePending OutboxState = "pending"
+	OutboxStateSent    OutboxState = "sent"
+	// OutboxStateFailed indicates the entry could not be published within the maximum number of attempts. Failed
+	// entries are kept for inspection and are not retried.
+	OutboxStateFailed OutboxState = "failed"
+)
+
 // DeploymentState represents the current state of a deployable entity
 type DeploymentState string
 
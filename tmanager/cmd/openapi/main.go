@@ -57,6 +57,19 @@ func generateParticipantQueryEndpoint(r spec.Generator) {
 		option.Description("Perform a Participant Profile query"),
 		option.Request(model.Query{}),
 		option.Response(http.StatusOK, []v1alpha1.ParticipantProfile{}))
+	participants.Get("schema",
+		option.Summary("Get the Participant Profile property schema"),
+		option.Description("Retrieve the effective JSON Schema for participant and VPA properties declared by the given Dataspace Profiles, or by all Dataspace Profiles if none are specified"),
+		option.Request(propertySchemaQuery{}),
+		option.Response(http.StatusOK, map[string]any{}))
+}
+
+type tenantDeleteQuery struct {
+	Cascade bool `query:"cascade" description:"Dispose and delete the Tenant's participants before deleting the Tenant"`
+}
+
+type propertySchemaQuery struct {
+	DataspaceProfileIDs string `query:"dataspaceProfileIds" description:"Comma-separated list of Dataspace Profile IDs"`
 }
 
 func generateTenantEndpoints(r spec.Generator) {
@@ -90,9 +103,33 @@ func generateTenantEndpoints(r spec.Generator) {
 
 	tenants.Delete("/{id}",
 		option.Summary("Delete Tenant"),
-		option.Description("Deletes a Tenant by ID"),
+		option.Description("Deletes a Tenant by ID. Returns 400 if the Tenant has participants unless cascade is set, in which case the participants are disposed and deleted before the Tenant is deleted and 202 is returned with the deletion status."),
 		option.Request(new(IDParam)),
+		option.Request(tenantDeleteQuery{}),
 		option.Response(http.StatusOK, nil),
+		option.Response(http.StatusAccepted, v1alpha1.TenantDeletionStatus{}),
+	)
+
+	tenants.Get("/{id}/deletion",
+		option.Summary("Get Tenant Deletion Status"),
+		option.Description("Retrieve the progress of a cascading Tenant deletion. Returns 404 once the Tenant has been deleted."),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.TenantDeletionStatus{}),
+	)
+
+	tenants.Get("/{id}/usage",
+		option.Summary("Get Tenant Usage"),
+		option.Description("Retrieve the participants and VPAs of a Tenant that have not been disposed together with the Tenant's quota"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.TenantUsage{}),
+	)
+
+	tenants.Put("/{id}/quota",
+		option.Summary("Update Tenant Quota"),
+		option.Description("Replaces the quota of a Tenant. Limits that are not set are not enforced."),
+		option.Request(new(IDParam)),
+		option.Request(v1alpha1.TenantQuota{}),
+		option.Response(http.StatusOK, v1alpha1.Tenant{}),
 	)
 
 	tenants.Patch("/{id}",
@@ -116,10 +153,10 @@ func generateParticipantEndpoints(r spec.Generator) {
 
 	participants.Post("",
 		option.Summary("Create Participant Profile"),
-		option.Description("Create a new Participant Profile"),
+		option.Description("Create a new Participant Profile. Returns 403 if the Tenant's quota does not allow the requested dataspace profiles or any cell, and 409 if the deployment would exceed the Tenant's participant or VPA limits."),
 		option.Request(new(IDParam)),
-		option.Request(v1alpha1.ParticipantProfile{}),
-		option.Response(http.StatusCreated, v1alpha1.ParticipantProfile{}),
+		option.Request(v1alpha1.NewParticipantProfileDeployment{}),
+		option.Response(http.StatusAccepted, v1alpha1.ParticipantProfile{}),
 	)
 
 	participants.Get("/{participantID}",
@@ -129,6 +166,21 @@ func generateParticipantEndpoints(r spec.Generator) {
 		option.Request(new(ParticipantIDParam)),
 		option.Response(http.StatusOK, v1alpha1.ParticipantProfile{}),
 	)
+	participants.Patch("/{participantID}",
+		option.Summary("Update Participant Profile"),
+		option.Description("Update the VPA properties and properties of a deployed Participant Profile. Changed VPA properties are applied by a VPA update orchestration."),
+		option.Request(new(IDParam)),
+		option.Request(new(ParticipantIDParam)),
+		option.Request(v1alpha1.ParticipantProfileUpdate{}),
+		option.Response(http.StatusAccepted, v1alpha1.ParticipantProfile{}),
+	)
+	participants.Post("/{participantID}/retry",
+		option.Summary("Retry Participant Profile"),
+		option.Description("Retry the failed operation of a Participant Profile in the error state. Returns 409 if the profile is not in the error state or a retry is in progress."),
+		option.Request(new(IDParam)),
+		option.Request(new(ParticipantIDParam)),
+		option.Response(http.StatusAccepted, v1alpha1.ParticipantProfile{}),
+	)
 	participants.Delete("/{participantID}",
 		option.Summary("Dispose Participant Profile"),
 		option.Description("Dispose a Participant Profile"),
@@ -157,11 +209,46 @@ func generateCellEndpoints(r spec.Generator) {
 
 	cells.Delete("/{id}",
 		option.Summary("Delete Cell"),
-		option.Description("Deletes a Cell by ID"),
+		option.Description("Deletes a Cell by ID. Returns 409 if VPAs are placed in or being migrated from the cell."),
 		option.Request(new(IDParam)),
 		option.Response(http.StatusOK, nil),
 	)
 
+	cells.Post("/{id}/heartbeats",
+		option.Summary("Record Cell Heartbeat"),
+		option.Description("Records a heartbeat sent by a Cell with its health metrics and capacity. Cells that miss heartbeats are marked offline together with their VPAs and are marked active again when heartbeats resume."),
+		option.Request(new(IDParam)),
+		option.Request(v1alpha1.CellHeartbeat{}),
+		option.Response(http.StatusOK, v1alpha1.Cell{}),
+	)
+
+	cells.Post("/{id}/cordon",
+		option.Summary("Cordon Cell"),
+		option.Description("Cordons a Cell so that no new VPAs are placed in it. Existing VPAs are not affected."),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.Cell{}),
+	)
+
+	cells.Post("/{id}/uncordon",
+		option.Summary("Uncordon Cell"),
+		option.Description("Uncordons a Cell so that new VPAs can be placed in it. Returns 409 if VPAs are being migrated from the cell."),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.Cell{}),
+	)
+
+	cells.Post("/{id}/drain",
+		option.Summary("Drain Cell"),
+		option.Description("Cordons a Cell and migrates the VPAs placed in it to other cells. Participants with operations in progress are skipped and can be migrated by draining the cell again."),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusAccepted, v1alpha1.CellDrainStatus{}),
+	)
+
+	cells.Get("/{id}/drain",
+		option.Summary("Get Cell Drain Status"),
+		option.Description("Retrieve the progress of draining a Cell"),
+		option.Request(new(IDParam)),
+		option.Response(http.StatusOK, v1alpha1.CellDrainStatus{}),
+	)
 }
 
 func generateDataspaceEndpoints(r spec.Generator) {
@@ -196,8 +283,17 @@ func generateDataspaceEndpoints(r spec.Generator) {
 
 	dataspaces.Post("/{id}/deployments",
 		option.Summary("Deploy a Dataspace Profile"),
-		option.Description("Deploy a Dataspace Profile"),
+		option.Description("Deploy a Dataspace Profile to a cell. The deployment is pending until the deployment orchestration completes. Returns 409 if the profile is already deployed to the cell."),
 		option.Request(new(IDParam)),
+		option.Request(v1alpha1.NewDataspaceProfileDeployment{}),
+		option.Response(http.StatusAccepted, v1alpha1.DataspaceDeployment{}),
+	)
+
+	dataspaces.Delete("/{id}/deployments/{deploymentID}",
+		option.Summary("Undeploy a Dataspace Profile"),
+		option.Description("Remove a Dataspace Profile deployment from its cell using a dispose orchestration. Returns 409 if the deployment is not active or in the error state."),
+		option.Request(new(IDParam)),
+		option.Request(new(DeploymentIDParam)),
 		option.Response(http.StatusAccepted, nil),
 	)
 }
@@ -209,3 +305,7 @@ type IDParam struct {
 type ParticipantIDParam struct {
 	ID string `path:"participantID" required:"true"`
 }
+
+type DeploymentIDParam struct {
+	ID string `path:"deploymentID" required:"true"`
+}
@@ -15,7 +15,10 @@ package launcher
 import (
 	"fmt"
 
+	"github.com/metaform/connector-fabric-manager/assembly/httpclient"
+	"github.com/metaform/connector-fabric-manager/assembly/metrics"
 	"github.com/metaform/connector-fabric-manager/assembly/routing"
+	"github.com/metaform/connector-fabric-manager/assembly/tracing"
 	"github.com/metaform/connector-fabric-manager/common/runtime"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
@@ -23,6 +26,7 @@ import (
 	"github.com/metaform/connector-fabric-manager/tmanager/handler"
 	"github.com/metaform/connector-fabric-manager/tmanager/memorystore"
 	"github.com/metaform/connector-fabric-manager/tmanager/natsprovision"
+	"github.com/metaform/connector-fabric-manager/tmanager/pmclient"
 	"github.com/metaform/connector-fabric-manager/tmanager/sqlstore"
 )
 
@@ -67,6 +71,8 @@ func Launch(shutdown <-chan struct{}) {
 
 	assembler := system.NewServiceAssembler(logMonitor, vConfig, mode)
 	assembler.Register(&routing.RouterServiceAssembly{})
+	assembler.Register(&metrics.MetricsServiceAssembly{})
+	assembler.Register(&tracing.TracingServiceAssembly{ServiceName: logPrefix})
 	assembler.Register(&handler.HandlerServiceAssembly{})
 	assembler.Register(&core.TMCoreServiceAssembly{})
 
@@ -80,6 +86,8 @@ func Launch(shutdown <-chan struct{}) {
 
 
 	assembler.Register(natsprovision.NewNatsOrchestrationServiceAssembly(uri, bucketValue, streamValue))
+	assembler.Register(&httpclient.HttpClientServiceAssembly{})
+	assembler.Register(&pmclient.PMClientServiceAssembly{})
 
 	runtime.AssembleAndLaunch(assembler, "Tenant Manager", logMonitor, shutdown)
 }
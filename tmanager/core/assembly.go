@@ -13,15 +13,33 @@
 package core
 
 import (
+	"context"
+	"time"
+
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 )
 
+const (
+	cellSelectorKey         = "cell.selector"
+	cellHeartbeatTimeoutKey = "cell.heartbeat.timeout"
+	cellHealthIntervalKey   = "cell.health.interval"
+	reconcileThresholdKey   = "reconcile.threshold"
+	reconcileIntervalKey    = "reconcile.interval"
+	outboxIntervalKey       = "outbox.interval"
+	outboxRetentionKey      = "outbox.retention"
+	outboxMaxAttemptsKey    = "outbox.max.attempts"
+)
+
 type TMCoreServiceAssembly struct {
 	system.DefaultServiceAssembly
-	vpaGenerator participantGenerator
+	vpaGenerator  *participantGenerator
+	healthMonitor *cellHealthMonitor
+	reconciler    *reconciler
+	outboxRelay   *outboxRelay
+	healthCancel  context.CancelFunc
 }
 
 func (a *TMCoreServiceAssembly) Name() string {
@@ -34,63 +52,146 @@ func (a *TMCoreServiceAssembly) Requires() []system.ServiceType {
 		store.TransactionContextKey,
 		api.ParticipantProfileStoreKey,
 		api.DataspaceProfileStoreKey,
-		api.CellStoreKey}
+		api.CellStoreKey,
+		api.OutboxStoreKey}
 }
 
 func (a *TMCoreServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.ParticipantProfileServiceKey, api.CellServiceKey, api.DataspaceProfileServiceKey}
+	return []system.ServiceType{
+		api.ParticipantProfileServiceKey,
+		api.CellServiceKey,
+		api.DataspaceProfileServiceKey,
+		api.VPATypeRegistryKey,
+		api.CellEventRegistryKey}
 }
 
 func (a *TMCoreServiceAssembly) Init(context *system.InitContext) error {
-	a.vpaGenerator = participantGenerator{
-		CellSelector: defaultCellSelector, // Register the default selector, which may be overridden
+	// Register the configured built-in selector, which may be overridden by a custom selector
+	selector, err := newCellSelector(context.GetConfigStrOrDefault(cellSelectorKey, firstActiveStrategy))
+	if err != nil {
+		return err
+	}
+	a.vpaGenerator = &participantGenerator{
+		CellSelector: selector,
 	}
 
 	trxContext := context.Registry.Resolve(store.TransactionContextKey).(store.TransactionContext)
-	provisionClient := context.Registry.Resolve(api.ProvisionClientKey).(api.ProvisionClient)
+	// Callback handlers and background workers retry updates that conflict with concurrent changes, while API requests
+	// report the conflict to the client
+	retryTrxContext := store.WithConflictRetry(trxContext)
+	outboxStore := context.Registry.Resolve(api.OutboxStoreKey).(store.EntityStore[*api.OutboxEntry])
 	participantStore := context.Registry.Resolve(api.ParticipantProfileStoreKey).(store.EntityStore[*api.ParticipantProfile])
 	cellStore := context.Registry.Resolve(api.CellStoreKey).(store.EntityStore[*api.Cell])
 	dataspaceStore := context.Registry.Resolve(api.DataspaceProfileStoreKey).(store.EntityStore[*api.DataspaceProfile])
 	tenantStore := context.Registry.Resolve(api.TenantStoreKey).(store.EntityStore[*api.Tenant])
 
-	tenantService := tenantService{
-		trxContext:       trxContext,
-		tenantStore:      tenantStore,
-		participantStore: participantStore,
-		monitor:          context.LogMonitor,
+	// Services record manifests in the outbox, which are published by the relay after their transaction commits
+	provisionClient := outboxProvisionClient{outboxStore: outboxStore, now: time.Now}
+	a.outboxRelay = &outboxRelay{
+		trxContext:      trxContext,
+		outboxStore:     outboxStore,
+		provisionClient: context.Registry.Resolve(api.ProvisionClientKey).(api.ProvisionClient),
+		interval:        time.Duration(context.GetConfigIntOrDefault(outboxIntervalKey, int(defaultOutboxInterval.Seconds()))) * time.Second,
+		retention:       time.Duration(context.GetConfigIntOrDefault(outboxRetentionKey, int(defaultOutboxRetention.Seconds()))) * time.Second,
+		maxAttempts:     context.GetConfigIntOrDefault(outboxMaxAttemptsKey, defaultOutboxMaxAttempts),
+		monitor:         context.LogMonitor,
+		now:             time.Now,
 	}
-	context.Registry.Register(api.TenantServiceKey, tenantService)
+
+	vpaTypes := newVPATypeRegistry()
+	context.Registry.Register(api.VPATypeRegistryKey, vpaTypes)
 
 	participantService := participantService{
 		participantGenerator: a.vpaGenerator,
 		provisionClient:      provisionClient,
 		trxContext:           trxContext,
 		participantStore:     participantStore,
+		tenantStore:          tenantStore,
 		dataspaceStore:       dataspaceStore,
 		cellStore:            cellStore,
 		monitor:              context.LogMonitor,
 	}
 	context.Registry.Register(api.ParticipantProfileServiceKey, participantService)
 
+	context.Registry.Register(api.TenantServiceKey, tenantService{
+		trxContext:         trxContext,
+		tenantStore:        tenantStore,
+		participantStore:   participantStore,
+		participantService: participantService,
+		monitor:            context.LogMonitor,
+	})
+
+	cellEvents := newCellEventDispatcher(context.LogMonitor)
+	context.Registry.Register(api.CellEventRegistryKey, cellEvents)
+
 	context.Registry.Register(api.CellServiceKey, cellService{
-		trxContext: trxContext,
-		cellStore:      cellStore,
+		trxContext:         trxContext,
+		cellStore:          cellStore,
+		participantStore:   participantStore,
+		participantService: participantService,
+		events:             cellEvents,
+		monitor:            context.LogMonitor,
 	})
 
+	a.healthMonitor = &cellHealthMonitor{
+		trxContext:       retryTrxContext,
+		cellStore:        cellStore,
+		participantStore: participantStore,
+		events:           cellEvents,
+		timeout:          time.Duration(context.GetConfigIntOrDefault(cellHeartbeatTimeoutKey, int(defaultHeartbeatTimeout.Seconds()))) * time.Second,
+		interval:         time.Duration(context.GetConfigIntOrDefault(cellHealthIntervalKey, int(defaultHealthCheckInterval.Seconds()))) * time.Second,
+		monitor:          context.LogMonitor,
+		now:              time.Now,
+	}
+
 	context.Registry.Register(api.DataspaceProfileServiceKey, dataspaceProfileService{
-		trxContext:   trxContext,
-		profileStore: dataspaceStore,
-		cellStore:    cellStore,
+		trxContext:      trxContext,
+		profileStore:    dataspaceStore,
+		cellStore:       cellStore,
+		vpaTypes:        vpaTypes,
+		provisionClient: provisionClient,
 	})
 
 	registry := context.Registry.Resolve(api.ProvisionHandlerRegistryKey).(api.ProvisionHandlerRegistry)
 	deploymentHandler := vpaCallbackHandler{
-		trxContext:       trxContext,
+		trxContext:       retryTrxContext,
 		participantStore: participantStore,
+		tenantStore:      tenantStore,
 		monitor:          context.LogMonitor,
 	}
-	registry.Register(model.VPADeployType, deploymentHandler.handleDeploy)
-	registry.Register(model.VPADisposeType, deploymentHandler.handleDispose)
+	vpaHandlers := map[model.OrchestrationType]api.ProvisionCallbackHandler{
+		model.VPADeployType:  deploymentHandler.handleDeploy,
+		model.VPADisposeType: deploymentHandler.handleDispose,
+		model.VPAUpdateType:  deploymentHandler.handleUpdate,
+		model.VPAMigrateType: deploymentHandler.handleMigrate,
+	}
+	for orchestrationType, handler := range vpaHandlers {
+		registry.Register(orchestrationType, handler)
+	}
+	registry.RegisterProgress(model.VPADeployType, deploymentHandler.handleProgress)
+	registry.RegisterProgress(model.VPADisposeType, deploymentHandler.handleProgress)
+	registry.RegisterProgress(model.VPAUpdateType, deploymentHandler.handleProgress)
+	registry.RegisterProgress(model.VPAMigrateType, deploymentHandler.handleProgress)
+
+	// The orchestration client and leader election are resolved when the assembly is prepared
+	a.reconciler = &reconciler{
+		trxContext:         retryTrxContext,
+		participantStore:   participantStore,
+		participantService: participantService,
+		handlers:           vpaHandlers,
+		threshold:          time.Duration(context.GetConfigIntOrDefault(reconcileThresholdKey, int(defaultReconcileThreshold.Seconds()))) * time.Second,
+		interval:           time.Duration(context.GetConfigIntOrDefault(reconcileIntervalKey, int(defaultReconcileInterval.Seconds()))) * time.Second,
+		monitor:            context.LogMonitor,
+		now:                time.Now,
+	}
+
+	dataspaceHandler := dataspaceCallbackHandler{
+		trxContext:   retryTrxContext,
+		profileStore: dataspaceStore,
+		monitor:      context.LogMonitor,
+	}
+	registry.Register(model.DataspaceDeployType, dataspaceHandler.handleDeploy)
+	registry.Register(model.DataspaceDisposeType, dataspaceHandler.handleDispose)
 
 	return nil
 }
@@ -98,8 +199,43 @@ func (a *TMCoreServiceAssembly) Init(context *system.InitContext) error {
 func (a *TMCoreServiceAssembly) Prepare(context *system.InitContext) error {
 	selector, found := context.Registry.ResolveOptional(api.CellSelectorKey)
 	if found {
-		// Override the default selector with a custom implementation
-		a.vpaGenerator = selector.(participantGenerator)
+		// Override the configured selector with a custom implementation. The generator is shared with the participant
+		// service, so the override applies to services registered during Init.
+		a.vpaGenerator.CellSelector = selector.(api.CellSelector)
+	}
+
+	var leaderElection api.LeaderElection
+	if election, found := context.Registry.ResolveOptional(api.LeaderElectionKey); found {
+		leaderElection = election.(api.LeaderElection)
+	}
+	a.outboxRelay.leaderElection = leaderElection
+	a.healthMonitor.leaderElection = leaderElection
+
+	// Participant profiles are only reconciled if the provision manager can be queried
+	client, found := context.Registry.ResolveOptional(api.OrchestrationClientKey)
+	if !found {
+		a.reconciler = nil
+		return nil
+	}
+	a.reconciler.orchestrationClient = client.(api.OrchestrationClient)
+	a.reconciler.leaderElection = leaderElection
+	return nil
+}
+
+func (a *TMCoreServiceAssembly) Start(_ *system.StartContext) error {
+	healthContext, cancel := context.WithCancel(context.Background())
+	a.healthCancel = cancel
+	a.healthMonitor.start(healthContext)
+	a.outboxRelay.start(healthContext)
+	if a.reconciler != nil {
+		a.reconciler.start(healthContext)
+	}
+	return nil
+}
+
+func (a *TMCoreServiceAssembly) Shutdown() error {
+	if a.healthCancel != nil {
+		a.healthCancel()
 	}
 	return nil
 }
@@ -0,0 +1,103 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTMCoreServiceAssembly_CellSelector(t *testing.T) {
+	newInitContext := func(config map[string]any) *system.InitContext {
+		vConfig := viper.New()
+		for k, v := range config {
+			vConfig.Set(k, v)
+		}
+		registry := system.NewServiceRegistry()
+		registry.Register(store.TransactionContextKey, store.NoOpTransactionContext{})
+		registry.Register(api.ProvisionClientKey, new(mockProvisionClient))
+		registry.Register(api.ProvisionHandlerRegistryKey, noopProvisionHandlerRegistry{})
+		registry.Register(api.TenantStoreKey, memorystore.NewInMemoryEntityStore[*api.Tenant]())
+		registry.Register(api.ParticipantProfileStoreKey, memorystore.NewInMemoryEntityStore[*api.ParticipantProfile]())
+		registry.Register(api.CellStoreKey, memorystore.NewInMemoryEntityStore[*api.Cell]())
+		registry.Register(api.DataspaceProfileStoreKey, memorystore.NewInMemoryEntityStore[*api.DataspaceProfile]())
+		registry.Register(api.OutboxStoreKey, memorystore.NewInMemoryEntityStore[*api.OutboxEntry]())
+		return &system.InitContext{
+			StartContext: system.StartContext{
+				Registry:   registry,
+				LogMonitor: system.NoopMonitor{},
+				Config:     vConfig,
+				Mode:       system.DebugMode,
+			},
+		}
+	}
+	selectStrategy := func(ictx *system.InitContext) string {
+		service := ictx.Registry.Resolve(api.ParticipantProfileServiceKey).(participantService)
+		cells := []api.Cell{{DeployableEntity: api.DeployableEntity{Entity: api.Entity{ID: "cell-1"}, State: api.DeploymentStateActive}}}
+		_, selection, err := service.participantGenerator.CellSelector(api.CellSelectionRequest{
+			Cells: cells,
+			DataspaceProfiles: []api.DataspaceProfile{{Deployments: []api.DataspaceDeployment{{
+				DeployableEntity: api.DeployableEntity{State: api.DeploymentStateActive},
+				CellID:           "cell-1",
+			}}}},
+		})
+		require.NoError(t, err)
+		return selection.Strategy
+	}
+
+	t.Run("uses the configured strategy", func(t *testing.T) {
+		ictx := newInitContext(map[string]any{cellSelectorKey: leastLoadedStrategy})
+		assembly := &TMCoreServiceAssembly{}
+
+		require.NoError(t, assembly.Init(ictx))
+		require.NoError(t, assembly.Prepare(ictx))
+
+		assert.Equal(t, leastLoadedStrategy, selectStrategy(ictx))
+	})
+
+	t.Run("unknown strategy fails initialization", func(t *testing.T) {
+		err := (&TMCoreServiceAssembly{}).Init(newInitContext(map[string]any{cellSelectorKey: "random"}))
+
+		require.Error(t, err)
+	})
+
+	t.Run("registered selector overrides the configured strategy", func(t *testing.T) {
+		ictx := newInitContext(nil)
+		assembly := &TMCoreServiceAssembly{}
+		require.NoError(t, assembly.Init(ictx))
+
+		ictx.Registry.Register(api.CellSelectorKey, api.CellSelector(
+			func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+				return &request.Cells[0], api.CellSelection{Strategy: "custom"}, nil
+			}))
+		require.NoError(t, assembly.Prepare(ictx))
+
+		assert.Equal(t, "custom", selectStrategy(ictx))
+	})
+}
+
+type noopProvisionHandlerRegistry struct{}
+
+func (n noopProvisionHandlerRegistry) Register(model.OrchestrationType, api.ProvisionCallbackHandler) {
+}
+
+func (n noopProvisionHandlerRegistry) RegisterProgress(model.OrchestrationType, api.ProvisionProgressHandler) {
+}
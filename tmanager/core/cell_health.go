@@ -0,0 +1,216 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/metrics"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+const (
+	defaultHeartbeatTimeout    = 90 * time.Second
+	defaultHealthCheckInterval = 30 * time.Second
+)
+
+// cellEventDispatcher notifies the registered listeners of cell events.
+type cellEventDispatcher struct {
+	mu        sync.RWMutex
+	listeners []api.CellEventListener
+	monitor   system.LogMonitor
+}
+
+func newCellEventDispatcher(monitor system.LogMonitor) *cellEventDispatcher {
+	return &cellEventDispatcher{monitor: monitor}
+}
+
+func (d *cellEventDispatcher) Register(listener api.CellEventListener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, listener)
+}
+
+func (d *cellEventDispatcher) dispatch(ctx context.Context, event api.CellEvent) {
+	if event.Type == api.CellEventOffline {
+		d.monitor.Warnf("Cell %s is offline, affected participants: %v", event.CellID, event.Participants)
+	} else {
+		d.monitor.Infof("Cell %s is online, affected participants: %v", event.CellID, event.Participants)
+	}
+	metrics.CellEvent(string(event.Type))
+
+	d.mu.RLock()
+	listeners := d.listeners
+	d.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(ctx, event)
+	}
+}
+
+// cellHealthMonitor marks active cells offline when they have not sent a heartbeat within the timeout. Cells that have
+// never sent a heartbeat are not monitored. Only the leader checks cells when a leader election is configured. The check
+// is performed in a transaction per cell and re-reads the cell, so checks run concurrently while leadership changes
+// only mark a cell offline once.
+type cellHealthMonitor struct {
+	trxContext       store.TransactionContext
+	cellStore        store.EntityStore[*api.Cell]
+	participantStore store.EntityStore[*api.ParticipantProfile]
+	events           *cellEventDispatcher
+	leaderElection   api.LeaderElection
+	timeout          time.Duration
+	interval         time.Duration
+	monitor          system.LogMonitor
+	now              func() time.Time
+}
+
+// start checks cell heartbeats at the monitor interval until the context is canceled.
+func (m *cellHealthMonitor) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if m.leaderElection != nil && !m.leaderElection.IsLeader() {
+					continue
+				}
+				if err := m.checkCells(ctx); err != nil {
+					m.monitor.Warnf("Error checking cell heartbeats: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// checkCells marks cells whose last heartbeat is older than the timeout offline.
+func (m *cellHealthMonitor) checkCells(ctx context.Context) error {
+	now := m.now().UTC()
+	var stale []string
+	err := m.trxContext.Execute(ctx, func(ctx context.Context) error {
+		for cell, err := range m.cellStore.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			if m.isStale(cell, now) {
+				stale = append(stale, cell.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, cellID := range stale {
+		if err = m.markOffline(ctx, cellID, now); err != nil {
+			m.monitor.Warnf("Error marking cell %s offline: %v", cellID, err)
+		}
+	}
+	return nil
+}
+
+func (m *cellHealthMonitor) isStale(cell *api.Cell, now time.Time) bool {
+	return cell.State == api.DeploymentStateActive && cell.Health != nil && now.Sub(cell.Health.LastHeartbeat) > m.timeout
+}
+
+// markOffline transitions the cell and its active VPAs to offline and emits an offline event.
+func (m *cellHealthMonitor) markOffline(ctx context.Context, cellID string, now time.Time) error {
+	var event *api.CellEvent
+	err := m.trxContext.Execute(ctx, func(ctx context.Context) error {
+		// Re-read the cell since a heartbeat may have been received after the check
+		cell, err := m.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return err
+		}
+		if !m.isStale(cell, now) {
+			return nil
+		}
+		cell.State = api.DeploymentStateOffline
+		cell.StateTimestamp = now
+		if err = m.cellStore.Update(ctx, cell); err != nil {
+			return err
+		}
+		participants, err := transitionCellVPAs(ctx, m.participantStore, cellID, api.DeploymentStateActive, api.DeploymentStateOffline, now)
+		if err != nil {
+			return err
+		}
+		event = &api.CellEvent{
+			Type:           api.CellEventOffline,
+			CellID:         cellID,
+			ExternalCellID: cell.ExternalID,
+			Timestamp:      now,
+			Participants:   participants,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if event != nil {
+		// Only emit the event if the storage operation succeeded
+		m.events.dispatch(ctx, *event)
+	}
+	return nil
+}
+
+// transitionCellVPAs moves the VPAs placed in the cell that are in the from state to the to state. VPAs in other states,
+// such as VPAs with operations in progress, are not changed. Returns the IDs of the participants with VPAs placed in
+// the cell that are not disposed.
+func transitionCellVPAs(
+	ctx context.Context,
+	participantStore store.EntityStore[*api.ParticipantProfile],
+	cellID string,
+	from api.DeploymentState,
+	to api.DeploymentState,
+	now time.Time) ([]string, error) {
+
+	participants := make([]string, 0)
+	var updated []*api.ParticipantProfile
+	for profile, err := range participantStore.GetAll(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		placed, changed := false, false
+		for i, vpa := range profile.VPAs {
+			if vpa.CellID != cellID || vpa.State == api.DeploymentStateDisposed {
+				continue
+			}
+			placed = true
+			if vpa.State == from {
+				vpa.State = to
+				vpa.StateTimestamp = now
+				profile.VPAs[i] = vpa // Use range index because vpa is a copy
+				changed = true
+			}
+		}
+		if placed {
+			participants = append(participants, profile.ID)
+		}
+		if changed {
+			updated = append(updated, profile)
+		}
+	}
+
+	for _, profile := range updated {
+		if err := participantStore.Update(ctx, profile); err != nil {
+			return nil, err
+		}
+	}
+	return participants, nil
+}
@@ -0,0 +1,224 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHealthMonitor(service *cellService, now time.Time) *cellHealthMonitor {
+	return &cellHealthMonitor{
+		trxContext:       service.trxContext,
+		cellStore:        service.cellStore,
+		participantStore: service.participantStore,
+		events:           service.events,
+		timeout:          time.Minute,
+		interval:         time.Minute,
+		monitor:          system.NoopMonitor{},
+		now:              func() time.Time { return now },
+	}
+}
+
+func recordCellEvents(service *cellService) *[]api.CellEvent {
+	events := &[]api.CellEvent{}
+	service.events.Register(func(_ context.Context, event api.CellEvent) {
+		*events = append(*events, event)
+	})
+	return events
+}
+
+func TestRecordHeartbeat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records health and capacity", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		events := recordCellEvents(service)
+		capacity := 20
+
+		cell, err := service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{
+			Capacity: &capacity,
+			Metrics:  map[string]any{"cpu": 0.5},
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, cell.Health)
+		assert.WithinDuration(t, time.Now(), cell.Health.LastHeartbeat, time.Minute)
+		assert.Equal(t, map[string]any{"cpu": 0.5}, cell.Health.Metrics)
+		assert.Equal(t, api.DeploymentStateActive, cell.State)
+		stored, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.EqualValues(t, 20, stored.Properties[api.CellCapacityProperty])
+		assert.Empty(t, *events)
+	})
+
+	t.Run("marks offline cell and its VPAs active", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		events := recordCellEvents(service)
+		cell, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		cell.State = api.DeploymentStateOffline
+		require.NoError(t, service.cellStore.Update(ctx, cell))
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		profile.VPAs[0].State = api.DeploymentStateOffline
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		cell, err = service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{})
+
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, cell.State)
+		profile, err = service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, profile.VPAs[0].State)
+		require.Len(t, *events, 1)
+		assert.Equal(t, api.CellEventOnline, (*events)[0].Type)
+		assert.Equal(t, "cell-1", (*events)[0].CellID)
+		assert.Equal(t, "external-cell-1", (*events)[0].ExternalCellID)
+		assert.Equal(t, []string{"participant-1"}, (*events)[0].Participants)
+	})
+
+	t.Run("heartbeat for non-existent cell returns not found", func(t *testing.T) {
+		service := newTestCellService()
+
+		_, err := service.RecordHeartbeat(ctx, "non-existent", api.CellHeartbeat{})
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
+func TestCellHealthMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("does not check cells if not the leader", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		events := recordCellEvents(service)
+		_, err := service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{})
+		require.NoError(t, err)
+
+		monitor := newTestHealthMonitor(service, time.Now().Add(2*time.Minute))
+		monitor.interval = 10 * time.Millisecond
+		monitor.leaderElection = fixedLeaderElection(false)
+		monitorCtx, cancel := context.WithCancel(ctx)
+		monitor.start(monitorCtx)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		cell, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, cell.State)
+		assert.Empty(t, *events)
+	})
+
+	t.Run("marks cell with missed heartbeats offline", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		events := recordCellEvents(service)
+		_, err := service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{})
+		require.NoError(t, err)
+		_, err = service.RecordHeartbeat(ctx, "cell-2", api.CellHeartbeat{})
+		require.NoError(t, err)
+		cell2, err := service.cellStore.FindByID(ctx, "cell-2")
+		require.NoError(t, err)
+		cell2.Health.LastHeartbeat = time.Now().Add(2 * time.Minute)
+		require.NoError(t, service.cellStore.Update(ctx, cell2))
+
+		err = newTestHealthMonitor(service, time.Now().Add(2*time.Minute)).checkCells(ctx)
+
+		require.NoError(t, err)
+		cell, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateOffline, cell.State)
+		cell2, err = service.cellStore.FindByID(ctx, "cell-2")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, cell2.State)
+
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateOffline, profile.VPAs[0].State)
+
+		require.Len(t, *events, 1)
+		assert.Equal(t, api.CellEventOffline, (*events)[0].Type)
+		assert.Equal(t, "cell-1", (*events)[0].CellID)
+		assert.Equal(t, []string{"participant-1"}, (*events)[0].Participants)
+
+		// Offline cells are not selected for new VPAs
+		cells, err := service.ListCells(ctx)
+		require.NoError(t, err)
+		candidates := candidateCells(api.CellSelectionRequest{
+			Cells:             cells,
+			DataspaceProfiles: []api.DataspaceProfile{*dataspaceProfile(t, service)},
+		})
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "cell-2", candidates[0].ID)
+	})
+
+	t.Run("does not change VPAs with operations in progress", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{})
+		require.NoError(t, err)
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		profile.VPAs[0].State = api.DeploymentStatePending
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		err = newTestHealthMonitor(service, time.Now().Add(2*time.Minute)).checkCells(ctx)
+
+		require.NoError(t, err)
+		profile, err = service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, profile.VPAs[0].State)
+	})
+
+	t.Run("does not monitor cells that have not sent heartbeats", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		events := recordCellEvents(service)
+
+		err := newTestHealthMonitor(service, time.Now().Add(time.Hour)).checkCells(ctx)
+
+		require.NoError(t, err)
+		cell, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, cell.State)
+		assert.Empty(t, *events)
+	})
+
+	t.Run("VPAs in offline cell can be migrated", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.RecordHeartbeat(ctx, "cell-1", api.CellHeartbeat{})
+		require.NoError(t, err)
+		err = newTestHealthMonitor(service, time.Now().Add(2*time.Minute)).checkCells(ctx)
+		require.NoError(t, err)
+
+		status, err := service.DrainCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Migrating)
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, "cell-2", profile.VPAs[0].CellID)
+		assert.Equal(t, api.DeploymentStatePending, profile.VPAs[0].State)
+	})
+}
+
+func dataspaceProfile(t *testing.T, service *cellService) *api.DataspaceProfile {
+	profile, err := service.participantService.dataspaceStore.FindByID(context.Background(), "dataspace-1")
+	require.NoError(t, err)
+	return profile
+}
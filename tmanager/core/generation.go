@@ -14,24 +14,223 @@ package core
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 )
 
-// defaultCellSelector iterates through cells and dataspace profiles to find and return the first active cell; returns an
-// error if none are found.
-func defaultCellSelector(_ model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
-	for _, cell := range cells {
-		if cell.State == api.DeploymentStateActive {
-			for _, dProfile := range dProfiles {
-				for _, deployment := range dProfile.Deployments {
-					if deployment.State == api.DeploymentStateActive && deployment.CellID == cell.ID {
-						return &cell, nil
-					}
-				}
-			}
-		}
-	}
-	return nil, errors.New("no active cell found")
+const (
+	firstActiveStrategy = "first-active"
+	leastLoadedStrategy = "least-loaded"
+	roundRobinStrategy  = "round-robin"
+	labelsStrategy      = "labels"
+)
+
+// newCellSelector returns the built-in cell selector for the given strategy.
+func newCellSelector(strategy string) (api.CellSelector, error) {
+	switch strategy {
+	case firstActiveStrategy:
+		return defaultCellSelector, nil
+	case leastLoadedStrategy:
+		return leastLoadedCellSelector, nil
+	case roundRobinStrategy:
+		return (&roundRobinCellSelector{}).selectCell, nil
+	case labelsStrategy:
+		return labelCellSelector, nil
+	default:
+		return nil, fmt.Errorf("unknown cell selection strategy: %s", strategy)
+	}
+}
+
+// defaultCellSelector iterates through cells and dataspace profiles to find and return the first active cell that has
+// the required labels; returns an error if none are found.
+func defaultCellSelector(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+	candidates := candidateCells(request)
+	if len(candidates) == 0 {
+		return nil, api.CellSelection{}, errors.New("no active cell found")
+	}
+	return &candidates[0], api.CellSelection{Strategy: firstActiveStrategy, Reason: "first active cell"}, nil
+}
+
+// leastLoadedCellSelector selects the cell hosting the fewest VPAs. A cell that declares a capacity is only selected if
+// it can host the requested VPAs; ties are broken by the larger remaining capacity.
+func leastLoadedCellSelector(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+	candidates := candidateCells(request)
+	var selected *api.Cell
+	for i, cell := range candidates {
+		if !hasCapacity(cell, request) {
+			continue
+		}
+		if selected == nil || compareLoad(cell, *selected, request.VPACounts) < 0 {
+			selected = &candidates[i]
+		}
+	}
+	if selected == nil {
+		return nil, api.CellSelection{}, errors.New("no active cell with available capacity found")
+	}
+	return selected, api.CellSelection{Strategy: leastLoadedStrategy, Reason: describeLoad(*selected, request.VPACounts)}, nil
+}
+
+// roundRobinCellSelector rotates through the candidate cells ordered by ID. The position is kept in memory and is not
+// shared between Tenant Manager instances.
+type roundRobinCellSelector struct {
+	next atomic.Uint64
+}
+
+func (r *roundRobinCellSelector) selectCell(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+	candidates := candidateCells(request)
+	if len(candidates) == 0 {
+		return nil, api.CellSelection{}, errors.New("no active cell found")
+	}
+	slices.SortFunc(candidates, func(a, b api.Cell) int { return strings.Compare(a.ID, b.ID) })
+	position := (r.next.Add(1) - 1) % uint64(len(candidates))
+	reason := fmt.Sprintf("position %d of %d active cells", position+1, len(candidates))
+	return &candidates[position], api.CellSelection{Strategy: roundRobinStrategy, Reason: reason}, nil
+}
+
+// labelCellSelector selects the cell with the most preferred labels among the cells with the required labels and
+// available capacity. Ties are broken by load.
+func labelCellSelector(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+	candidates := candidateCells(request)
+	selected, selectedScore := -1, 0
+	for i, cell := range candidates {
+		if !hasCapacity(cell, request) {
+			continue
+		}
+		score := matchingLabels(cell, request.Placement.PreferredLabels)
+		if selected < 0 || score > selectedScore ||
+			(score == selectedScore && compareLoad(cell, candidates[selected], request.VPACounts) < 0) {
+			selected, selectedScore = i, score
+		}
+	}
+	if selected < 0 {
+		return nil, api.CellSelection{}, errors.New("no active cell matching the placement constraints found")
+	}
+	reason := fmt.Sprintf("matched %d required and %d of %d preferred labels",
+		len(request.Placement.RequiredLabels), selectedScore, len(request.Placement.PreferredLabels))
+	return &candidates[selected], api.CellSelection{Strategy: labelsStrategy, Reason: reason}, nil
+}
+
+// candidateCells returns the active cells that are not cordoned and have an active deployment of one of the dataspace
+// profiles and all required labels, in the order of the request.
+func candidateCells(request api.CellSelectionRequest) []api.Cell {
+	candidates := make([]api.Cell, 0, len(request.Cells))
+	for _, cell := range request.Cells {
+		if cell.State != api.DeploymentStateActive || cell.Cordoned {
+			continue
+		}
+		if matchingLabels(cell, request.Placement.RequiredLabels) != len(request.Placement.RequiredLabels) {
+			continue
+		}
+		if slices.ContainsFunc(request.DataspaceProfiles, func(dProfile api.DataspaceProfile) bool {
+			return slices.ContainsFunc(dProfile.Deployments, func(deployment api.DataspaceDeployment) bool {
+				return deployment.State == api.DeploymentStateActive && deployment.CellID == cell.ID
+			})
+		}) {
+			candidates = append(candidates, cell)
+		}
+	}
+	return candidates
+}
+
+// matchingLabels returns the number of the given labels the cell has.
+func matchingLabels(cell api.Cell, labels map[string]string) int {
+	cellLabels, _ := cell.Properties[api.CellLabelsProperty].(map[string]any)
+	matches := 0
+	for key, value := range labels {
+		if cellValue, found := cellLabels[key]; found && fmt.Sprint(cellValue) == value {
+			matches++
+		}
+	}
+	return matches
+}
+
+// cellCapacity returns the capacity declared by the cell and whether it is declared.
+func cellCapacity(cell api.Cell) (int, bool) {
+	switch capacity := cell.Properties[api.CellCapacityProperty].(type) {
+	case int:
+		return capacity, true
+	case int64:
+		return int(capacity), true
+	case float64:
+		return int(capacity), true
+	default:
+		return 0, false
+	}
+}
+
+func hasCapacity(cell api.Cell, request api.CellSelectionRequest) bool {
+	capacity, declared := cellCapacity(cell)
+	return !declared || request.VPACounts[cell.ID]+max(request.VPACount, 1) <= capacity
+}
+
+// compareLoad orders cells by number of VPAs and then by remaining capacity, where an undeclared capacity is unlimited.
+func compareLoad(a api.Cell, b api.Cell, vpaCounts map[string]int) int {
+	if c := vpaCounts[a.ID] - vpaCounts[b.ID]; c != 0 {
+		return c
+	}
+	aCapacity, aDeclared := cellCapacity(a)
+	bCapacity, bDeclared := cellCapacity(b)
+	switch {
+	case aDeclared && bDeclared:
+		return bCapacity - aCapacity
+	case aDeclared:
+		return 1
+	case bDeclared:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func describeLoad(cell api.Cell, vpaCounts map[string]int) string {
+	if capacity, declared := cellCapacity(cell); declared {
+		return fmt.Sprintf("cell hosts %d of %d VPAs", vpaCounts[cell.ID], capacity)
+	}
+	return fmt.Sprintf("cell hosts %d VPAs", vpaCounts[cell.ID])
+}
+
+// defaultVPATopology is used when none of the dataspace profiles of a participant declares a VPA topology.
+var defaultVPATopology = []api.VPASpec{
+	{Type: model.ConnectorType, Count: 1},
+	{Type: model.CredentialServiceType, Count: 1},
+	{Type: model.DataPlaneType, Count: 1},
+}
+
+// vpaTypeRegistry is a thread-safe api.VPATypeRegistry.
+type vpaTypeRegistry struct {
+	mu    sync.RWMutex
+	types []model.VPAType
+}
+
+// newVPATypeRegistry creates a registry containing the built-in VPA types.
+func newVPATypeRegistry() *vpaTypeRegistry {
+	return &vpaTypeRegistry{
+		types: []model.VPAType{model.ConnectorType, model.CredentialServiceType, model.DataPlaneType},
+	}
+}
+
+func (r *vpaTypeRegistry) Register(vpaType model.VPAType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.types, vpaType) {
+		r.types = append(r.types, vpaType)
+	}
+}
+
+func (r *vpaTypeRegistry) IsRegistered(vpaType model.VPAType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Contains(r.types, vpaType)
+}
+
+func (r *vpaTypeRegistry) Types() []model.VPAType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.types)
 }
@@ -60,7 +60,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -126,7 +126,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -169,7 +169,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.Error(t, err)
 		require.Nil(t, result)
@@ -212,7 +212,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.Error(t, err)
 		require.Nil(t, result)
@@ -244,7 +244,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.Error(t, err)
 		require.Nil(t, result)
@@ -267,7 +267,7 @@ func TestResolveCell(t *testing.T) {
 
 		dProfiles := []api.DataspaceProfile{}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.Error(t, err)
 		require.Nil(t, result)
@@ -298,7 +298,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.Error(t, err)
 		require.Nil(t, result)
@@ -360,7 +360,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -424,7 +424,7 @@ func TestResolveCell(t *testing.T) {
 			},
 		}
 
-		result, err := defaultCellSelector("test", cells, dProfiles)
+		result, _, err := defaultCellSelector(api.CellSelectionRequest{OrchestrationType: "test", Cells: cells, DataspaceProfiles: dProfiles})
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -432,3 +432,107 @@ func TestResolveCell(t *testing.T) {
 		assert.Equal(t, api.DeploymentStateActive, result.State)
 	})
 }
+
+func TestCellSelectionStrategies(t *testing.T) {
+	newCell := func(id string, properties api.Properties) api.Cell {
+		return api.Cell{
+			DeployableEntity: api.DeployableEntity{
+				Entity: api.Entity{ID: id},
+				State:  api.DeploymentStateActive,
+			},
+			Properties: properties,
+		}
+	}
+	newRequest := func(cells ...api.Cell) api.CellSelectionRequest {
+		deployments := make([]api.DataspaceDeployment, len(cells))
+		for i, cell := range cells {
+			deployments[i] = api.DataspaceDeployment{
+				DeployableEntity: api.DeployableEntity{State: api.DeploymentStateActive},
+				CellID:           cell.ID,
+			}
+		}
+		return api.CellSelectionRequest{
+			Cells:             cells,
+			DataspaceProfiles: []api.DataspaceProfile{{Entity: api.Entity{ID: "ds-1"}, Deployments: deployments}},
+			VPACounts:         map[string]int{},
+			VPACount:          3,
+		}
+	}
+
+	t.Run("least loaded selects the cell with the fewest VPAs and available capacity", func(t *testing.T) {
+		request := newRequest(
+			newCell("cell-1", api.Properties{api.CellCapacityProperty: 10}),
+			newCell("cell-2", api.Properties{api.CellCapacityProperty: float64(5)}),
+			newCell("cell-3", api.Properties{api.CellCapacityProperty: 20}))
+		request.VPACounts = map[string]int{"cell-1": 6, "cell-2": 3, "cell-3": 6}
+
+		cell, selection, err := leastLoadedCellSelector(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "cell-3", cell.ID, "cell-2 lacks capacity for 3 VPAs and cell-3 has more remaining capacity than cell-1")
+		assert.Equal(t, leastLoadedStrategy, selection.Strategy)
+		assert.Equal(t, "cell hosts 6 of 20 VPAs", selection.Reason)
+	})
+
+	t.Run("least loaded returns error when all cells are full", func(t *testing.T) {
+		request := newRequest(newCell("cell-1", api.Properties{api.CellCapacityProperty: 3}))
+		request.VPACounts = map[string]int{"cell-1": 1}
+
+		_, _, err := leastLoadedCellSelector(request)
+
+		require.Error(t, err)
+	})
+
+	t.Run("round robin rotates through cells ordered by ID", func(t *testing.T) {
+		selector := &roundRobinCellSelector{}
+		request := newRequest(newCell("cell-b", nil), newCell("cell-a", nil), newCell("cell-c", nil))
+
+		selected := make([]string, 0, 4)
+		for range 4 {
+			cell, selection, err := selector.selectCell(request)
+			require.NoError(t, err)
+			assert.Equal(t, roundRobinStrategy, selection.Strategy)
+			selected = append(selected, cell.ID)
+		}
+
+		assert.Equal(t, []string{"cell-a", "cell-b", "cell-c", "cell-a"}, selected)
+	})
+
+	t.Run("labels selects the cell with required and most preferred labels", func(t *testing.T) {
+		request := newRequest(
+			newCell("cell-1", api.Properties{api.CellLabelsProperty: map[string]any{"region": "us"}}),
+			newCell("cell-2", api.Properties{api.CellLabelsProperty: map[string]any{"region": "eu", "zone": "standard"}}),
+			newCell("cell-3", api.Properties{api.CellLabelsProperty: map[string]any{"region": "eu", "zone": "hardened"}}))
+		request.Placement = api.CellPlacement{
+			RequiredLabels:  map[string]string{"region": "eu"},
+			PreferredLabels: map[string]string{"zone": "hardened"},
+		}
+
+		cell, selection, err := labelCellSelector(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "cell-3", cell.ID)
+		assert.Equal(t, labelsStrategy, selection.Strategy)
+		assert.Equal(t, "matched 1 required and 1 of 1 preferred labels", selection.Reason)
+	})
+
+	t.Run("required labels are enforced by all strategies", func(t *testing.T) {
+		request := newRequest(newCell("cell-1", api.Properties{api.CellLabelsProperty: map[string]any{"region": "us"}}))
+		request.Placement.RequiredLabels = map[string]string{"region": "eu"}
+
+		for _, strategy := range []string{firstActiveStrategy, leastLoadedStrategy, roundRobinStrategy, labelsStrategy} {
+			selector, err := newCellSelector(strategy)
+			require.NoError(t, err)
+
+			_, _, err = selector(request)
+
+			require.Error(t, err, strategy)
+		}
+	})
+
+	t.Run("unknown strategy returns error", func(t *testing.T) {
+		_, err := newCellSelector("random")
+
+		require.Error(t, err)
+	})
+}
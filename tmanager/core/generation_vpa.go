@@ -13,6 +13,7 @@
 package core
 
 import (
+	"maps"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,7 +32,9 @@ func (g participantGenerator) Generate(
 	participantRoles map[string][]string,
 	vpaProperties api.VPAPropMap,
 	properties map[string]any,
+	placement api.ParticipantPlacement,
 	cells []api.Cell,
+	vpaCounts map[string]int,
 	dProfiles []api.DataspaceProfile) (*api.ParticipantProfile, error) {
 
 	dProfileIDs := make([]string, len(dProfiles))
@@ -39,15 +42,35 @@ func (g participantGenerator) Generate(
 		dProfileIDs[i] = profile.ID
 	}
 
-	cell, err := g.CellSelector(model.VPADeployType, cells, dProfiles)
-	if err != nil {
-		return nil, err
+	// Track VPAs placed for this participant so that load-based selectors account for them
+	counts := maps.Clone(vpaCounts)
+	if counts == nil {
+		counts = make(map[string]int)
 	}
 
-	connector := g.generateVPA(model.ConnectorType, vpaProperties, cell)
-	cService := g.generateVPA(model.CredentialServiceType, vpaProperties, cell)
-	dPlane := g.generateVPA(model.DataPlaneType, vpaProperties, cell)
-	vpas := []api.VirtualParticipantAgent{connector, cService, dPlane}
+	vpas := make([]api.VirtualParticipantAgent, 0)
+	for _, spec := range resolveVPATopology(dProfiles) {
+		cell, selection, err := g.CellSelector(api.CellSelectionRequest{
+			OrchestrationType: model.VPADeployType,
+			VPAType:           spec.Type,
+			Cells:             cells,
+			DataspaceProfiles: dProfiles,
+			Placement:         placement.ForType(spec.Type),
+			VPACounts:         maps.Clone(counts),
+			VPACount:          spec.Count,
+		})
+		if err != nil {
+			return nil, err
+		}
+		counts[cell.ID] += spec.Count
+
+		for range spec.Count {
+			vpa := g.generateVPA(spec.Type, spec.Properties, vpaProperties[spec.Type], cell)
+			vpaSelection := selection
+			vpa.CellSelection = &vpaSelection
+			vpas = append(vpas, vpa)
+		}
+	}
 
 	pProfile := &api.ParticipantProfile{
 		Entity: api.Entity{
@@ -64,10 +87,11 @@ func (g participantGenerator) Generate(
 	return pProfile, nil
 }
 
-// generateVPA creates a VPA targeted at given cell.
+// generateVPA creates a VPA targeted at given cell. The request properties override the topology default properties.
 func (g participantGenerator) generateVPA(
 	vpaType model.VPAType,
-	vpaProperties api.VPAPropMap,
+	defaults map[string]any,
+	properties map[string]any,
 	cell *api.Cell) api.VirtualParticipantAgent {
 
 	vpa := api.VirtualParticipantAgent{
@@ -82,17 +106,39 @@ func (g participantGenerator) generateVPA(
 		Type:           vpaType,
 		CellID:         cell.ID,
 		ExternalCellID: cell.ExternalID,
-		Properties:     make(api.Properties),
+		Properties:     make(api.Properties, len(defaults)+len(properties)),
 	}
 
-	// Decompose the properties and add them to the VPA
-	props, found := vpaProperties[vpaType]
-	if found {
-		vpa.Properties = make(api.Properties)
-		for k, v := range props {
-			vpa.Properties[k] = v
-		}
-	}
+	maps.Copy(vpa.Properties, defaults)
+	maps.Copy(vpa.Properties, properties)
 
 	return vpa
 }
+
+// resolveVPATopology combines the VPA topologies declared by the dataspace profiles. Types are ordered by first
+// declaration, the count of a type is the largest declared count, and default properties are merged in profile order.
+// The default topology is returned if no profile declares a topology.
+func resolveVPATopology(dProfiles []api.DataspaceProfile) []api.VPASpec {
+	topology := make([]api.VPASpec, 0)
+	indexes := make(map[model.VPAType]int)
+	for _, dProfile := range dProfiles {
+		for _, spec := range dProfile.DataspaceSpec.VPATopology {
+			count := max(spec.Count, 1)
+			i, found := indexes[spec.Type]
+			if !found {
+				indexes[spec.Type] = len(topology)
+				topology = append(topology, api.VPASpec{Type: spec.Type, Count: count, Properties: maps.Clone(spec.Properties)})
+				continue
+			}
+			topology[i].Count = max(topology[i].Count, count)
+			if topology[i].Properties == nil && len(spec.Properties) > 0 {
+				topology[i].Properties = make(map[string]any, len(spec.Properties))
+			}
+			maps.Copy(topology[i].Properties, spec.Properties)
+		}
+	}
+	if len(topology) == 0 {
+		return defaultVPATopology
+	}
+	return topology
+}
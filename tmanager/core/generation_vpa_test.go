@@ -27,7 +27,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 	now := time.Now().UTC()
 
 	t.Run("successful generation", func(t *testing.T) {
-		mockCellSelector := func(deploymentType model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
+		mockCellSelector := func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
 			return &api.Cell{
 				DeployableEntity: api.DeployableEntity{
 					Entity: api.Entity{
@@ -38,7 +38,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 					StateTimestamp: now,
 				},
 				Properties: make(api.Properties),
-			}, nil
+			}, api.CellSelection{}, nil
 		}
 
 		generator := participantGenerator{
@@ -83,7 +83,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 			dProfileIDs[i] = profile.ID
 		}
 
-		profile, err := generator.Generate(identifier, "123", map[string][]string{}, vpaProperties, properties, cells, dProfiles)
+		profile, err := generator.Generate(identifier, "123", map[string][]string{}, vpaProperties, properties, api.ParticipantPlacement{}, cells, nil, dProfiles)
 
 		require.NoError(t, err)
 		require.NotNil(t, profile)
@@ -127,8 +127,8 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 	})
 
 	t.Run("error when cell selector fails", func(t *testing.T) {
-		mockCellSelector := func(deploymentType model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
-			return nil, assert.AnError
+		mockCellSelector := func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+			return nil, api.CellSelection{}, assert.AnError
 		}
 
 		generator := participantGenerator{
@@ -141,7 +141,9 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 			map[string][]string{},
 			make(api.VPAPropMap),
 			map[string]any{},
+			api.ParticipantPlacement{},
 			[]api.Cell{},
+			nil,
 			[]api.DataspaceProfile{})
 
 		require.Error(t, err)
@@ -151,8 +153,8 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 
 	t.Run("cell selector receives correct deployment type", func(t *testing.T) {
 		var receivedDeploymentType model.OrchestrationType
-		mockCellSelector := func(deploymentType model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
-			receivedDeploymentType = deploymentType
+		mockCellSelector := func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+			receivedDeploymentType = request.OrchestrationType
 			return &api.Cell{
 				DeployableEntity: api.DeployableEntity{
 					Entity: api.Entity{
@@ -163,7 +165,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 					StateTimestamp: now,
 				},
 				Properties: make(api.Properties),
-			}, nil
+			}, api.CellSelection{}, nil
 		}
 
 		generator := participantGenerator{
@@ -176,7 +178,9 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 			map[string][]string{},
 			make(api.VPAPropMap),
 			map[string]any{},
+			api.ParticipantPlacement{},
 			[]api.Cell{},
+			nil,
 			[]api.DataspaceProfile{})
 
 		require.NoError(t, err)
@@ -187,9 +191,9 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 		var receivedCells []api.Cell
 		var receivedProfiles []api.DataspaceProfile
 
-		mockCellSelector := func(deploymentType model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
-			receivedCells = cells
-			receivedProfiles = dProfiles
+		mockCellSelector := func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+			receivedCells = request.Cells
+			receivedProfiles = request.DataspaceProfiles
 			return &api.Cell{
 				DeployableEntity: api.DeployableEntity{
 					Entity: api.Entity{
@@ -200,7 +204,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 					StateTimestamp: now,
 				},
 				Properties: make(api.Properties),
-			}, nil
+			}, api.CellSelection{}, nil
 		}
 
 		generator := participantGenerator{
@@ -226,7 +230,9 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 			map[string][]string{},
 			make(api.VPAPropMap),
 			map[string]any{},
+			api.ParticipantPlacement{},
 			inputCells,
+			nil,
 			inputProfiles)
 
 		require.NoError(t, err)
@@ -235,7 +241,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 	})
 
 	t.Run("multiple dataspace profiles are correctly assigned", func(t *testing.T) {
-		mockCellSelector := func(deploymentType model.OrchestrationType, cells []api.Cell, dProfiles []api.DataspaceProfile) (*api.Cell, error) {
+		mockCellSelector := func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
 			return &api.Cell{
 				DeployableEntity: api.DeployableEntity{
 					Entity: api.Entity{
@@ -246,7 +252,7 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 					StateTimestamp: now,
 				},
 				Properties: make(api.Properties),
-			}, nil
+			}, api.CellSelection{}, nil
 		}
 
 		generator := participantGenerator{
@@ -285,7 +291,9 @@ func TestParticipantProfileGenerator_Generate(t *testing.T) {
 			map[string][]string{},
 			make(api.VPAPropMap),
 			map[string]any{},
+			api.ParticipantPlacement{},
 			[]api.Cell{},
+			nil,
 			dProfiles)
 
 		require.NoError(t, err)
@@ -325,7 +333,7 @@ func TestParticipantProfileGenerator_generateConnector(t *testing.T) {
 			Properties: cellProperties,
 		}
 
-		connector := generator.generateVPA(model.ConnectorType, make(api.VPAPropMap), inputCell)
+		connector := generator.generateVPA(model.ConnectorType, nil, nil, inputCell)
 
 		assert.Equal(t, inputCell.ID, connector.CellID)
 		assert.Equal(t, inputCell.ExternalID, connector.ExternalCellID)
@@ -346,9 +354,9 @@ func TestParticipantProfileGenerator_generateConnector(t *testing.T) {
 			Properties: make(api.Properties),
 		}
 
-		connector1 := generator.generateVPA(model.ConnectorType, make(api.VPAPropMap), inputCell)
-		connector2 := generator.generateVPA(model.ConnectorType, make(api.VPAPropMap), inputCell)
-		connector3 := generator.generateVPA(model.ConnectorType, make(api.VPAPropMap), inputCell)
+		connector1 := generator.generateVPA(model.ConnectorType, nil, nil, inputCell)
+		connector2 := generator.generateVPA(model.ConnectorType, nil, nil, inputCell)
+		connector3 := generator.generateVPA(model.ConnectorType, nil, nil, inputCell)
 
 		ids := map[string]bool{
 			connector1.ID: true,
@@ -359,3 +367,87 @@ func TestParticipantProfileGenerator_generateConnector(t *testing.T) {
 	})
 
 }
+
+func TestParticipantProfileGenerator_GenerateTopology(t *testing.T) {
+	issuerType := model.VPAType("test.issuerservice")
+	cells := []api.Cell{{DeployableEntity: api.DeployableEntity{Entity: api.Entity{ID: "cell-1"}}, ExternalID: "external-1"}}
+	generator := participantGenerator{
+		CellSelector: func(api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+			return &cells[0], api.CellSelection{Strategy: "test"}, nil
+		},
+	}
+
+	countTypes := func(vpas []api.VirtualParticipantAgent) map[model.VPAType]int {
+		counts := make(map[model.VPAType]int)
+		for _, vpa := range vpas {
+			counts[vpa.Type]++
+		}
+		return counts
+	}
+
+	t.Run("generates VPAs declared by the topology", func(t *testing.T) {
+		dProfile := api.DataspaceProfile{Entity: api.Entity{ID: "ds-1"}}
+		dProfile.DataspaceSpec.VPATopology = []api.VPASpec{
+			{Type: model.ConnectorType},
+			{Type: model.DataPlaneType, Count: 2, Properties: map[string]any{"mode": "pull", "region": "eu"}},
+			{Type: issuerType, Count: 1},
+		}
+
+		profile, err := generator.Generate("participant", "tenant", nil,
+			api.VPAPropMap{model.DataPlaneType: {"mode": "push"}}, nil, api.ParticipantPlacement{}, cells, nil, []api.DataspaceProfile{dProfile})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[model.VPAType]int{model.ConnectorType: 1, model.DataPlaneType: 2, issuerType: 1}, countTypes(profile.VPAs))
+		for _, vpa := range profile.VPAs {
+			if vpa.Type == model.DataPlaneType {
+				assert.Equal(t, api.Properties{"mode": "push", "region": "eu"}, vpa.Properties)
+			}
+		}
+		assert.NotEqual(t, profile.VPAs[1].ID, profile.VPAs[2].ID)
+	})
+
+	t.Run("combines topologies of multiple dataspace profiles", func(t *testing.T) {
+		ds1 := api.DataspaceProfile{Entity: api.Entity{ID: "ds-1"}}
+		ds1.DataspaceSpec.VPATopology = []api.VPASpec{
+			{Type: model.ConnectorType, Properties: map[string]any{"a": 1}},
+			{Type: model.DataPlaneType, Count: 2},
+		}
+		ds2 := api.DataspaceProfile{Entity: api.Entity{ID: "ds-2"}}
+		ds2.DataspaceSpec.VPATopology = []api.VPASpec{
+			{Type: model.ConnectorType, Properties: map[string]any{"b": 2}},
+			{Type: model.DataPlaneType, Count: 1},
+		}
+		ds3 := api.DataspaceProfile{Entity: api.Entity{ID: "ds-3"}}
+
+		topology := resolveVPATopology([]api.DataspaceProfile{ds1, ds2, ds3})
+
+		require.Len(t, topology, 2)
+		assert.Equal(t, api.VPASpec{Type: model.ConnectorType, Count: 1, Properties: map[string]any{"a": 1, "b": 2}}, topology[0])
+		assert.Equal(t, api.VPASpec{Type: model.DataPlaneType, Count: 2}, topology[1])
+		assert.Equal(t, map[string]any{"a": 1}, ds1.DataspaceSpec.VPATopology[0].Properties, "declared defaults must not be modified")
+	})
+
+	t.Run("uses the default topology when none is declared", func(t *testing.T) {
+		topology := resolveVPATopology([]api.DataspaceProfile{{Entity: api.Entity{ID: "ds-1"}}})
+
+		assert.Equal(t, defaultVPATopology, topology)
+	})
+}
+
+func TestVPATypeRegistry(t *testing.T) {
+	registry := newVPATypeRegistry()
+
+	assert.True(t, registry.IsRegistered(model.ConnectorType))
+	assert.False(t, registry.IsRegistered("test.issuerservice"))
+
+	registry.Register("test.issuerservice")
+	registry.Register("test.issuerservice")
+
+	assert.True(t, registry.IsRegistered("test.issuerservice"))
+	assert.Equal(t, []model.VPAType{
+		model.ConnectorType,
+		model.CredentialServiceType,
+		model.DataPlaneType,
+		"test.issuerservice",
+	}, registry.Types())
+}
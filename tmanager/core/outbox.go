@@ -0,0 +1,174 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+const (
+	defaultOutboxInterval    = time.Second
+	defaultOutboxRetention   = 24 * time.Hour
+	defaultOutboxMaxAttempts = 10
+)
+
+// outboxProvisionClient records manifests in the outbox instead of publishing them. The entry is written in the
+// transaction of the calling operation, so a manifest is only published if the operation commits.
+type outboxProvisionClient struct {
+	outboxStore store.EntityStore[*api.OutboxEntry]
+	now         func() time.Time
+}
+
+func (c outboxProvisionClient) Send(ctx context.Context, manifest model.OrchestrationManifest) error {
+	_, err := c.outboxStore.Create(ctx, &api.OutboxEntry{
+		Entity:           api.Entity{ID: uuid.New().String()},
+		State:            api.OutboxStatePending,
+		Manifest:         manifest,
+		CreatedTimestamp: c.now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("error recording manifest %s in outbox: %w", manifest.ID, err)
+	}
+	return nil
+}
+
+// outboxRelay publishes pending outbox entries to the provision manager in the order they were recorded and marks them
+// sent. An entry that cannot be published is retried at the next interval, and entries recorded after it are held back
+// so that operations on a participant are not delivered out of order. An entry that cannot be published within the
+// maximum number of attempts is marked failed so that it does not block later entries. Sent entries are removed after
+// the retention period. Only the leader relays entries when a leader election is configured.
+//
+// Delivery is at-least-once: an entry is published again if the relay stops before marking it sent, or if leadership
+// changes while an entry is relayed. The provision manager de-duplicates manifests by ID.
+type outboxRelay struct {
+	trxContext      store.TransactionContext
+	outboxStore     store.EntityStore[*api.OutboxEntry]
+	provisionClient api.ProvisionClient
+	leaderElection  api.LeaderElection
+	interval        time.Duration
+	retention       time.Duration
+	maxAttempts     int
+	monitor         system.LogMonitor
+	now             func() time.Time
+}
+
+// start relays pending entries at the relay interval until the context is canceled.
+func (r *outboxRelay) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if r.leaderElection != nil && !r.leaderElection.IsLeader() {
+					continue
+				}
+				if err := r.relay(ctx); err != nil {
+					r.monitor.Warnf("Error relaying outbox entries: %v", err)
+				}
+				if err := r.purge(ctx); err != nil {
+					r.monitor.Warnf("Error purging outbox entries: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// relay publishes the pending entries, stopping at the first entry that cannot be published. Entries that reach the
+// maximum number of attempts are marked failed and skipped.
+func (r *outboxRelay) relay(ctx context.Context) error {
+	var pending []*api.OutboxEntry
+	err := r.trxContext.Execute(ctx, func(ctx context.Context) error {
+		for entry, err := range r.outboxStore.FindByPredicate(ctx, query.Eq("state", string(api.OutboxStatePending))) {
+			if err != nil {
+				return err
+			}
+			pending = append(pending, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(pending, func(a, b *api.OutboxEntry) int {
+		return a.CreatedTimestamp.Compare(b.CreatedTimestamp)
+	})
+
+	for _, entry := range pending {
+		entry.Attempts++
+		sendErr := r.provisionClient.Send(ctx, entry.Manifest)
+		failed := sendErr != nil && r.maxAttempts > 0 && entry.Attempts >= r.maxAttempts
+		if sendErr != nil {
+			entry.LastError = sendErr.Error()
+			if failed {
+				entry.State = api.OutboxStateFailed
+			}
+		} else {
+			entry.State = api.OutboxStateSent
+			entry.SentTimestamp = r.now().UTC()
+			entry.LastError = ""
+		}
+		err = r.trxContext.Execute(ctx, func(ctx context.Context) error {
+			return r.outboxStore.Update(ctx, entry)
+		})
+		// The entry may have been relayed and updated by another instance, or purged
+		if err != nil && !errors.Is(err, types.ErrNotFound) && !errors.Is(err, types.ErrVersionConflict) {
+			return fmt.Errorf("error updating outbox entry %s: %w", entry.ID, err)
+		}
+		if failed {
+			r.monitor.Severef("Giving up publishing manifest %s of outbox entry %s after %d attempts: %v",
+				entry.Manifest.ID, entry.ID, entry.Attempts, sendErr)
+			continue
+		}
+		if sendErr != nil {
+			return fmt.Errorf("error publishing manifest %s (attempt %d): %w", entry.Manifest.ID, entry.Attempts, sendErr)
+		}
+	}
+	return nil
+}
+
+// purge removes entries that were sent before the retention period.
+func (r *outboxRelay) purge(ctx context.Context) error {
+	cutoff := r.now().Add(-r.retention)
+	return r.trxContext.Execute(ctx, func(ctx context.Context) error {
+		var expired []string
+		for entry, err := range r.outboxStore.FindByPredicate(ctx, query.Eq("state", string(api.OutboxStateSent))) {
+			if err != nil {
+				return err
+			}
+			if entry.SentTimestamp.Before(cutoff) {
+				expired = append(expired, entry.ID)
+			}
+		}
+		for _, id := range expired {
+			if err := r.outboxStore.Delete(ctx, id); err != nil && !errors.Is(err, types.ErrNotFound) {
+				return err
+			}
+		}
+		return nil
+	})
+}
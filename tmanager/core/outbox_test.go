@@ -0,0 +1,284 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testOutboxTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestOutboxProvisionClient_Send(t *testing.T) {
+	ctx := context.Background()
+	outboxStore := memorystore.NewInMemoryEntityStore[*api.OutboxEntry]()
+	client := outboxProvisionClient{outboxStore: outboxStore, now: func() time.Time { return testOutboxTime }}
+
+	err := client.Send(ctx, model.OrchestrationManifest{ID: "manifest-1", CorrelationID: "participant-1"})
+
+	require.NoError(t, err)
+	entries := outboxEntries(t, outboxStore)
+	require.Len(t, entries, 1)
+	assert.NotEmpty(t, entries[0].ID)
+	assert.Equal(t, api.OutboxStatePending, entries[0].State)
+	assert.Equal(t, "manifest-1", entries[0].Manifest.ID)
+	assert.Equal(t, testOutboxTime, entries[0].CreatedTimestamp)
+	assert.Zero(t, entries[0].Attempts)
+}
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes pending entries in order and marks them sent", func(t *testing.T) {
+		relay, outboxStore, provisionClient := newTestOutboxRelay()
+		provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+		createOutboxEntry(t, outboxStore, "entry-2", "manifest-2", api.OutboxStatePending, testOutboxTime.Add(-time.Second))
+		createOutboxEntry(t, outboxStore, "entry-1", "manifest-1", api.OutboxStatePending, testOutboxTime.Add(-time.Minute))
+		createOutboxEntry(t, outboxStore, "entry-0", "manifest-0", api.OutboxStateSent, testOutboxTime.Add(-time.Hour))
+
+		require.NoError(t, relay.relay(ctx))
+
+		require.Len(t, provisionClient.Calls, 2)
+		assert.Equal(t, "manifest-1", provisionClient.Calls[0].Arguments.Get(1).(model.OrchestrationManifest).ID)
+		assert.Equal(t, "manifest-2", provisionClient.Calls[1].Arguments.Get(1).(model.OrchestrationManifest).ID)
+		for _, id := range []string{"entry-1", "entry-2"} {
+			entry, err := outboxStore.FindByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, api.OutboxStateSent, entry.State)
+			assert.Equal(t, 1, entry.Attempts)
+			assert.Equal(t, testOutboxTime, entry.SentTimestamp)
+		}
+	})
+
+	t.Run("retries failed entries and holds back later entries", func(t *testing.T) {
+		relay, outboxStore, provisionClient := newTestOutboxRelay()
+		provisionClient.On("Send", mock.Anything, mock.Anything).Return(errors.New("unavailable")).Once()
+		createOutboxEntry(t, outboxStore, "entry-1", "manifest-1", api.OutboxStatePending, testOutboxTime.Add(-time.Minute))
+		createOutboxEntry(t, outboxStore, "entry-2", "manifest-2", api.OutboxStatePending, testOutboxTime.Add(-time.Second))
+
+		require.Error(t, relay.relay(ctx))
+
+		require.Len(t, provisionClient.Calls, 1)
+		entry, err := outboxStore.FindByID(ctx, "entry-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.OutboxStatePending, entry.State)
+		assert.Equal(t, 1, entry.Attempts)
+		assert.Equal(t, "unavailable", entry.LastError)
+
+		provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+		require.NoError(t, relay.relay(ctx))
+
+		require.Len(t, provisionClient.Calls, 3)
+		assert.Equal(t, "manifest-1", provisionClient.Calls[1].Arguments.Get(1).(model.OrchestrationManifest).ID)
+		assert.Equal(t, "manifest-2", provisionClient.Calls[2].Arguments.Get(1).(model.OrchestrationManifest).ID)
+		entry, err = outboxStore.FindByID(ctx, "entry-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.OutboxStateSent, entry.State)
+		assert.Equal(t, 2, entry.Attempts)
+		assert.Empty(t, entry.LastError)
+	})
+
+	t.Run("marks entries failed after the maximum attempts and publishes later entries", func(t *testing.T) {
+		relay, outboxStore, provisionClient := newTestOutboxRelay()
+		relay.maxAttempts = 2
+		provisionClient.On("Send", mock.Anything, model.OrchestrationManifest{ID: "manifest-1"}).Return(errors.New("invalid manifest"))
+		provisionClient.On("Send", mock.Anything, model.OrchestrationManifest{ID: "manifest-2"}).Return(nil)
+		createOutboxEntry(t, outboxStore, "entry-1", "manifest-1", api.OutboxStatePending, testOutboxTime.Add(-time.Minute))
+		createOutboxEntry(t, outboxStore, "entry-2", "manifest-2", api.OutboxStatePending, testOutboxTime.Add(-time.Second))
+
+		require.Error(t, relay.relay(ctx))
+		require.Len(t, provisionClient.Calls, 1)
+
+		require.NoError(t, relay.relay(ctx))
+
+		require.Len(t, provisionClient.Calls, 3)
+		failed, err := outboxStore.FindByID(ctx, "entry-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.OutboxStateFailed, failed.State)
+		assert.Equal(t, 2, failed.Attempts)
+		assert.Equal(t, "invalid manifest", failed.LastError)
+		sent, err := outboxStore.FindByID(ctx, "entry-2")
+		require.NoError(t, err)
+		assert.Equal(t, api.OutboxStateSent, sent.State)
+
+		// Failed entries are not retried
+		require.NoError(t, relay.relay(ctx))
+		assert.Len(t, provisionClient.Calls, 3)
+	})
+
+	t.Run("does not relay entries if not the leader", func(t *testing.T) {
+		relay, outboxStore, provisionClient := newTestOutboxRelay()
+		relay.interval = 10 * time.Millisecond
+		relay.leaderElection = fixedLeaderElection(false)
+		createOutboxEntry(t, outboxStore, "entry-1", "manifest-1", api.OutboxStatePending, testOutboxTime.Add(-time.Minute))
+
+		relayCtx, cancel := context.WithCancel(ctx)
+		relay.start(relayCtx)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		assert.Empty(t, provisionClient.Calls)
+		entry, err := outboxStore.FindByID(ctx, "entry-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.OutboxStatePending, entry.State)
+	})
+
+	t.Run("purges entries sent before the retention period", func(t *testing.T) {
+		relay, outboxStore, _ := newTestOutboxRelay()
+		expired := createOutboxEntry(t, outboxStore, "entry-1", "manifest-1", api.OutboxStateSent, testOutboxTime.Add(-48*time.Hour))
+		expired.SentTimestamp = testOutboxTime.Add(-48 * time.Hour)
+		require.NoError(t, outboxStore.Update(ctx, expired))
+		recent := createOutboxEntry(t, outboxStore, "entry-2", "manifest-2", api.OutboxStateSent, testOutboxTime.Add(-time.Hour))
+		recent.SentTimestamp = testOutboxTime.Add(-time.Hour)
+		require.NoError(t, outboxStore.Update(ctx, recent))
+		createOutboxEntry(t, outboxStore, "entry-3", "manifest-3", api.OutboxStatePending, testOutboxTime.Add(-48*time.Hour))
+
+		require.NoError(t, relay.purge(ctx))
+
+		ids := make([]string, 0)
+		for _, entry := range outboxEntries(t, outboxStore) {
+			ids = append(ids, entry.ID)
+		}
+		assert.ElementsMatch(t, []string{"entry-2", "entry-3"}, ids)
+	})
+}
+
+func TestOutboxProvisionClient_DeployProfile(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+	outboxStore := memorystore.NewInMemoryEntityStore[*api.OutboxEntry]()
+	service.provisionClient = outboxProvisionClient{outboxStore: outboxStore, now: time.Now}
+	cell := newTestCell("cell-1", "external-id")
+	cell.State = api.DeploymentStateActive
+	_, err := service.cellStore.Create(ctx, cell)
+	require.NoError(t, err)
+	_, err = service.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-1"))
+	require.NoError(t, err)
+
+	profile, err := service.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+		Identifier:    "participant-identifier",
+		VPAProperties: api.VPAPropMap{model.ConnectorType: {}},
+	})
+
+	require.NoError(t, err)
+	entries := outboxEntries(t, outboxStore)
+	require.Len(t, entries, 1)
+	assert.Equal(t, api.OutboxStatePending, entries[0].State)
+	assert.Equal(t, profile.ID, entries[0].Manifest.CorrelationID)
+	assert.Equal(t, profile.VPAs[0].ManifestID, entries[0].Manifest.ID)
+}
+
+func TestOutboxProvisionClient_DisposeProfile(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+	service.trxContext = markingTransactionContext{}
+	outboxStore := trxRequiredEntityStore[*api.OutboxEntry]{
+		EntityStore: memorystore.NewInMemoryEntityStore[*api.OutboxEntry](),
+	}
+	service.provisionClient = outboxProvisionClient{outboxStore: outboxStore, now: time.Now}
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.Properties[model.VPAStateData] = map[string]any{"state": "deployed"}
+	profile.VPAs[0].State = api.DeploymentStateActive
+	created, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+
+	err = service.DisposeProfile(ctx, "tenant-1", created.ID)
+
+	require.NoError(t, err)
+	entries := outboxEntries(t, outboxStore)
+	require.Len(t, entries, 1)
+	assert.Equal(t, model.VPADisposeType, entries[0].Manifest.OrchestrationType)
+	assert.Equal(t, created.ID, entries[0].Manifest.CorrelationID)
+}
+
+func newTestOutboxRelay() (*outboxRelay, store.EntityStore[*api.OutboxEntry], *mockProvisionClient) {
+	outboxStore := memorystore.NewInMemoryEntityStore[*api.OutboxEntry]()
+	provisionClient := new(mockProvisionClient)
+	return &outboxRelay{
+		trxContext:      store.NoOpTransactionContext{},
+		outboxStore:     outboxStore,
+		provisionClient: provisionClient,
+		interval:        time.Second,
+		retention:       24 * time.Hour,
+		maxAttempts:     defaultOutboxMaxAttempts,
+		monitor:         system.NoopMonitor{},
+		now:             func() time.Time { return testOutboxTime },
+	}, outboxStore, provisionClient
+}
+
+func createOutboxEntry(
+	t *testing.T,
+	outboxStore store.EntityStore[*api.OutboxEntry],
+	id string,
+	manifestID string,
+	state api.OutboxState,
+	created time.Time) *api.OutboxEntry {
+
+	entry, err := outboxStore.Create(context.Background(), &api.OutboxEntry{
+		Entity:           api.Entity{ID: id},
+		State:            state,
+		Manifest:         model.OrchestrationManifest{ID: manifestID},
+		CreatedTimestamp: created,
+	})
+	require.NoError(t, err)
+	return entry
+}
+
+type trxMarker struct{}
+
+// markingTransactionContext marks the context passed to callbacks as transactional.
+type markingTransactionContext struct{}
+
+func (markingTransactionContext) Execute(ctx context.Context, callback func(ctx context.Context) error) error {
+	return callback(context.WithValue(ctx, trxMarker{}, true))
+}
+
+// trxRequiredEntityStore rejects entity creation outside a markingTransactionContext transaction.
+type trxRequiredEntityStore[T store.EntityType] struct {
+	store.EntityStore[T]
+}
+
+func (s trxRequiredEntityStore[T]) Create(ctx context.Context, entity T) (T, error) {
+	if ctx.Value(trxMarker{}) == nil {
+		var zero T
+		return zero, errors.New("no transaction in context")
+	}
+	return s.EntityStore.Create(ctx, entity)
+}
+
+// fixedLeaderElection reports a fixed leadership state.
+type fixedLeaderElection bool
+
+func (f fixedLeaderElection) IsLeader() bool {
+	return bool(f)
+}
+
+func outboxEntries(t *testing.T, outboxStore store.EntityStore[*api.OutboxEntry]) []*api.OutboxEntry {
+	var entries []*api.OutboxEntry
+	for entry, err := range outboxStore.GetAll(context.Background()) {
+		require.NoError(t, err)
+		entries = append(entries, entry)
+	}
+	return entries
+}
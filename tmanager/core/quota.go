@@ -0,0 +1,151 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+// validateQuota returns a client error if the quota contains negative limits.
+func validateQuota(quota *api.TenantQuota) error {
+	if quota == nil {
+		return nil
+	}
+	if quota.MaxParticipants != nil && *quota.MaxParticipants < 0 {
+		return types.NewClientError("maxParticipants cannot be negative")
+	}
+	for vpaType, limit := range quota.MaxVPAs {
+		if limit < 0 {
+			return types.NewClientError("maxVPAs for %s cannot be negative", vpaType)
+		}
+	}
+	return nil
+}
+
+// filterAllowedProfiles returns the dataspace profiles the tenant is allowed to use. Returns types.ErrForbidden if one
+// of the requested profiles is not allowed or if no allowed profile remains.
+func filterAllowedProfiles(tenant *api.Tenant, requestedIDs []string, dProfiles []api.DataspaceProfile) ([]api.DataspaceProfile, error) {
+	if tenant == nil || tenant.Quota == nil || len(tenant.Quota.AllowedDataspaceProfiles) == 0 {
+		return dProfiles, nil
+	}
+	allowed := tenant.Quota.AllowedDataspaceProfiles
+	for _, id := range requestedIDs {
+		if !slices.Contains(allowed, id) {
+			return nil, types.NewRecoverableWrappedError(types.ErrForbidden, "tenant %s is not allowed to use dataspace profile %s", tenant.ID, id)
+		}
+	}
+	filtered := make([]api.DataspaceProfile, 0, len(dProfiles))
+	for _, profile := range dProfiles {
+		if slices.Contains(allowed, profile.ID) {
+			filtered = append(filtered, profile)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, types.NewRecoverableWrappedError(types.ErrForbidden, "tenant %s is not allowed to use any of the dataspace profiles", tenant.ID)
+	}
+	return filtered, nil
+}
+
+// filterAllowedCells returns the cells the tenant is allowed to place VPAs in. Returns types.ErrForbidden if no allowed
+// cell exists.
+func filterAllowedCells(tenant *api.Tenant, cells []api.Cell) ([]api.Cell, error) {
+	if tenant == nil || tenant.Quota == nil || len(tenant.Quota.AllowedCells) == 0 {
+		return cells, nil
+	}
+	filtered := make([]api.Cell, 0, len(cells))
+	for _, cell := range cells {
+		if slices.Contains(tenant.Quota.AllowedCells, cell.ID) {
+			filtered = append(filtered, cell)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, types.NewRecoverableWrappedError(types.ErrForbidden, "tenant %s is not allowed to use any of the cells", tenant.ID)
+	}
+	return filtered, nil
+}
+
+// checkQuota returns types.ErrConflict if deploying the participant would exceed the participant or VPA limits of the
+// tenant.
+func checkQuota(tenant *api.Tenant, usage *api.TenantUsage, profile *api.ParticipantProfile) error {
+	if tenant == nil || tenant.Quota == nil {
+		return nil
+	}
+	quota := tenant.Quota
+	if quota.MaxParticipants != nil && usage.Participants+1 > *quota.MaxParticipants {
+		return types.NewRecoverableWrappedError(types.ErrConflict,
+			"tenant %s has reached its limit of %d participants", tenant.ID, *quota.MaxParticipants)
+	}
+	requested := make(map[model.VPAType]int)
+	for _, vpa := range profile.VPAs {
+		requested[vpa.Type]++
+	}
+	for vpaType, count := range requested {
+		limit, found := quota.MaxVPAs[vpaType]
+		if found && usage.VPAs[vpaType]+count > limit {
+			return types.NewRecoverableWrappedError(types.ErrConflict,
+				"tenant %s would exceed its limit of %d VPAs of type %s", tenant.ID, limit, vpaType)
+		}
+	}
+	return nil
+}
+
+// reserveQuota serializes deployments of a tenant with a quota by updating the tenant in the deployment transaction. A
+// concurrent deployment that checked the quota against the same usage fails with types.ErrVersionConflict.
+func reserveQuota(ctx context.Context, tenantStore store.EntityStore[*api.Tenant], tenant *api.Tenant) error {
+	if tenant == nil || tenant.Quota == nil {
+		return nil
+	}
+	return tenantStore.Update(ctx, tenant)
+}
+
+// tenantUsage counts the participants and VPAs of a tenant that have not been disposed.
+func tenantUsage(
+	ctx context.Context,
+	participantStore store.EntityStore[*api.ParticipantProfile],
+	tenantID string) (*api.TenantUsage, error) {
+
+	usage := &api.TenantUsage{
+		TenantID:          tenantID,
+		VPAs:              make(map[model.VPAType]int),
+		DataspaceProfiles: make(map[string]int),
+		Cells:             make(map[string]int),
+	}
+	for profile, err := range participantStore.FindByPredicate(ctx, query.Eq("tenantId", tenantID)) {
+		if err != nil {
+			return nil, fmt.Errorf("error computing usage of tenant %s: %w", tenantID, err)
+		}
+		if len(profile.VPAs) > 0 && isDisposed(profile) {
+			continue
+		}
+		usage.Participants++
+		for _, id := range profile.DataspaceProfileIDs {
+			usage.DataspaceProfiles[id]++
+		}
+		for _, vpa := range profile.VPAs {
+			if vpa.State == api.DeploymentStateDisposed {
+				continue
+			}
+			usage.VPAs[vpa.Type]++
+			usage.Cells[vpa.CellID]++
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,309 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployProfileQuota(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deploy within quota", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{
+			MaxParticipants: intPtr(2),
+			MaxVPAs:         map[model.VPAType]int{model.ConnectorType: 2},
+		})
+
+		result, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", result.TenantID)
+	})
+
+	t.Run("deploy exceeding max participants returns conflict", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{MaxParticipants: intPtr(1)})
+		_, err := service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", "participant-1"))
+		require.NoError(t, err)
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.ErrorIs(t, err, types.ErrConflict)
+		assert.Contains(t, err.Error(), "limit of 1 participants")
+	})
+
+	t.Run("disposed participants do not count towards max participants", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{MaxParticipants: intPtr(1)})
+		disposed := newTestDeployedParticipant("tenant-1", "participant-1")
+		disposed.VPAs[0].State = api.DeploymentStateDisposed
+		_, err := service.participantStore.Create(ctx, disposed)
+		require.NoError(t, err)
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("deploy exceeding max VPAs of a type returns conflict", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{
+			MaxVPAs: map[model.VPAType]int{model.ConnectorType: 1},
+		})
+		_, err := service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", "participant-1"))
+		require.NoError(t, err)
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.ErrorIs(t, err, types.ErrConflict)
+		assert.Contains(t, err.Error(), "VPAs of type "+model.ConnectorType.String())
+	})
+
+	t.Run("deploy to dataspace profile that is not allowed returns forbidden", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{AllowedDataspaceProfiles: []string{"ds-2"}})
+		_, err := service.participantService.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-2"))
+		require.NoError(t, err)
+		deployment := newTestQuotaDeployment()
+		deployment.DataspaceProfileIDs = []string{"ds-1"}
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", deployment)
+
+		require.ErrorIs(t, err, types.ErrForbidden)
+		assert.Contains(t, err.Error(), "dataspace profile ds-1")
+	})
+
+	t.Run("deploy without dataspace profiles uses allowed profiles", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{AllowedDataspaceProfiles: []string{"ds-2"}})
+		_, err := service.participantService.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-2"))
+		require.NoError(t, err)
+
+		result, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ds-2"}, result.DataspaceProfileIDs)
+	})
+
+	t.Run("deploy without allowed dataspace profiles returns forbidden", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{AllowedDataspaceProfiles: []string{"ds-unknown"}})
+
+		_, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		assert.ErrorIs(t, err, types.ErrForbidden)
+	})
+
+	t.Run("VPAs are placed in allowed cells", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{AllowedCells: []string{"cell-2"}})
+		cell := newTestCell("cell-2", "external-2")
+		cell.State = api.DeploymentStateActive
+		_, err := service.participantService.cellStore.Create(ctx, cell)
+		require.NoError(t, err)
+
+		result, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		require.NoError(t, err)
+		require.NotEmpty(t, result.VPAs)
+		for _, vpa := range result.VPAs {
+			assert.Equal(t, "cell-2", vpa.CellID)
+		}
+	})
+
+	t.Run("deploy without allowed cells returns forbidden", func(t *testing.T) {
+		service := newTestQuotaService(t, &api.TenantQuota{AllowedCells: []string{"cell-unknown"}})
+
+		_, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+
+		assert.ErrorIs(t, err, types.ErrForbidden)
+	})
+}
+
+func TestDeployProfileQuotaConcurrent(t *testing.T) {
+	ctx := context.Background()
+	service := newTestQuotaService(t, &api.TenantQuota{MaxParticipants: intPtr(1)})
+
+	// Both deployments compute the usage before either creates its participant, so both pass the quota check
+	barrier := &sync.WaitGroup{}
+	barrier.Add(2)
+	service.participantService.participantStore = barrierParticipantStore{EntityStore: service.participantStore, barrier: barrier}
+
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := service.participantService.DeployProfile(ctx, "tenant-1", newTestQuotaDeployment())
+			errs <- err
+		}()
+	}
+
+	var failed []error
+	for range 2 {
+		if err := <-errs; err != nil {
+			failed = append(failed, err)
+		}
+	}
+	require.Len(t, failed, 1)
+	assert.ErrorIs(t, failed[0], types.ErrVersionConflict)
+
+	usage, err := service.GetUsage(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, usage.Participants)
+}
+
+// barrierParticipantStore blocks queries for participants until the barrier is released.
+type barrierParticipantStore struct {
+	store.EntityStore[*api.ParticipantProfile]
+	barrier *sync.WaitGroup
+}
+
+func (b barrierParticipantStore) FindByPredicate(ctx context.Context, predicate query.Predicate) iter.Seq2[*api.ParticipantProfile, error] {
+	var profiles []*api.ParticipantProfile
+	var findErr error
+	for profile, err := range b.EntityStore.FindByPredicate(ctx, predicate) {
+		if err != nil {
+			findErr = err
+			break
+		}
+		profiles = append(profiles, profile)
+	}
+	b.barrier.Done()
+	b.barrier.Wait()
+	return func(yield func(*api.ParticipantProfile, error) bool) {
+		if findErr != nil {
+			yield(nil, findErr)
+			return
+		}
+		for _, profile := range profiles {
+			if !yield(profile, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestGetUsage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports participants and VPAs that are not disposed", func(t *testing.T) {
+		quota := &api.TenantQuota{MaxParticipants: intPtr(5)}
+		service := newTestQuotaService(t, quota)
+		_, err := service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", "participant-1"))
+		require.NoError(t, err)
+		disposed := newTestDeployedParticipant("tenant-1", "participant-2")
+		disposed.VPAs[0].State = api.DeploymentStateDisposed
+		_, err = service.participantStore.Create(ctx, disposed)
+		require.NoError(t, err)
+		_, err = service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-2", "participant-3"))
+		require.NoError(t, err)
+
+		usage, err := service.GetUsage(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", usage.TenantID)
+		assert.Equal(t, 1, usage.Participants)
+		assert.Equal(t, map[model.VPAType]int{model.ConnectorType: 1}, usage.VPAs)
+		assert.Equal(t, map[string]int{"dataspace-1": 1}, usage.DataspaceProfiles)
+		assert.Equal(t, map[string]int{"cell-1": 1}, usage.Cells)
+		require.NotNil(t, usage.Quota)
+		assert.EqualValues(t, 5, *usage.Quota.MaxParticipants)
+	})
+
+	t.Run("usage of non-existent tenant returns not found", func(t *testing.T) {
+		service := newTestTenantService()
+
+		_, err := service.GetUsage(ctx, "non-existent")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
+func TestUpdateQuota(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("update and remove quota", func(t *testing.T) {
+		service := newTestQuotaService(t, nil)
+
+		updated, err := service.UpdateQuota(ctx, "tenant-1", &api.TenantQuota{AllowedCells: []string{"cell-1"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cell-1"}, updated.Quota.AllowedCells)
+
+		_, err = service.UpdateQuota(ctx, "tenant-1", nil)
+		require.NoError(t, err)
+		tenant, err := service.GetTenant(ctx, "tenant-1")
+		require.NoError(t, err)
+		assert.Nil(t, tenant.Quota)
+	})
+
+	t.Run("negative limits are rejected", func(t *testing.T) {
+		service := newTestQuotaService(t, nil)
+
+		_, err := service.UpdateQuota(ctx, "tenant-1", &api.TenantQuota{MaxParticipants: intPtr(-1)})
+		assert.ErrorAs(t, err, &types.BadRequestError{})
+
+		_, err = service.UpdateQuota(ctx, "tenant-1", &api.TenantQuota{
+			MaxVPAs: map[model.VPAType]int{model.ConnectorType: -1},
+		})
+		assert.ErrorAs(t, err, &types.BadRequestError{})
+
+		tenant := newTestTenant("tenant-2")
+		tenant.Quota = &api.TenantQuota{MaxParticipants: intPtr(-1)}
+		_, err = service.CreateTenant(ctx, tenant)
+		assert.ErrorAs(t, err, &types.BadRequestError{})
+	})
+
+	t.Run("update quota of non-existent tenant returns not found", func(t *testing.T) {
+		service := newTestTenantService()
+
+		_, err := service.UpdateQuota(ctx, "non-existent", &api.TenantQuota{})
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
+// newTestQuotaService returns a tenant service with tenant-1 limited by the quota, an active cell-1 and dataspace
+// profile ds-1.
+func newTestQuotaService(t *testing.T, quota *api.TenantQuota) *tenantService {
+	ctx := context.Background()
+	service := newTestTenantService()
+	tenant := newTestTenant("tenant-1")
+	tenant.Quota = quota
+	_, err := service.CreateTenant(ctx, tenant)
+	require.NoError(t, err)
+
+	cell := newTestCell("cell-1", "external-1")
+	cell.State = api.DeploymentStateActive
+	_, err = service.participantService.cellStore.Create(ctx, cell)
+	require.NoError(t, err)
+	_, err = service.participantService.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-1"))
+	require.NoError(t, err)
+	return service
+}
+
+func newTestQuotaDeployment() *api.NewParticipantProfileDeployment {
+	return &api.NewParticipantProfileDeployment{
+		Identifier: "participant-identifier",
+		VPAProperties: api.VPAPropMap{
+			model.ConnectorType: {},
+		},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
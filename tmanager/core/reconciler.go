@@ -0,0 +1,263 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+const (
+	defaultReconcileThreshold = 5 * time.Minute
+	defaultReconcileInterval  = time.Minute
+)
+
+// reconciler resolves participant profiles whose VPAs have been pending or disposing past a threshold, for example,
+// because an orchestration response was lost. The orchestration of the operation is looked up in the provision manager
+// by the profile ID it is correlated to: terminal results are applied through the callback handlers, and manifests the
+// provision manager never received are resent. Only the leader reconciles when a leader election is configured.
+//
+// Manifests are resent with the ID they were originally sent with, so the provision manager de-duplicates a manifest
+// that is delivered late.
+type reconciler struct {
+	trxContext          store.TransactionContext
+	participantStore    store.EntityStore[*api.ParticipantProfile]
+	participantService  participantService
+	orchestrationClient api.OrchestrationClient
+	handlers            map[model.OrchestrationType]api.ProvisionCallbackHandler
+	leaderElection      api.LeaderElection
+	threshold           time.Duration
+	interval            time.Duration
+	monitor             system.LogMonitor
+	now                 func() time.Time
+}
+
+// stuckOperation is an operation whose VPAs have not reported a result or progress since before the threshold.
+type stuckOperation struct {
+	operation  model.OrchestrationType
+	manifestID string
+	indices    []int
+}
+
+// start reconciles participant profiles at the reconciler interval until the context is canceled.
+func (r *reconciler) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if r.leaderElection != nil && !r.leaderElection.IsLeader() {
+					continue
+				}
+				if err := r.reconcile(ctx); err != nil {
+					r.monitor.Warnf("Error reconciling participant profiles: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// reconcile reconciles each participant profile with a stuck operation.
+func (r *reconciler) reconcile(ctx context.Context) error {
+	cutoff := r.now().Add(-r.threshold)
+	var stuck []string
+	err := r.trxContext.Execute(ctx, func(ctx context.Context) error {
+		for profile, err := range r.participantStore.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			if findStuckOperation(profile, cutoff) != nil {
+				stuck = append(stuck, profile.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, participantID := range stuck {
+		if err = r.reconcileProfile(ctx, participantID, cutoff); err != nil {
+			r.monitor.Warnf("Error reconciling participant %s: %v", participantID, err)
+		}
+	}
+	return nil
+}
+
+// reconcileProfile applies the result of the stuck operation of the participant if its orchestration has terminated,
+// or resends its manifest if the provision manager has no orchestration for it.
+func (r *reconciler) reconcileProfile(ctx context.Context, participantID string, cutoff time.Time) error {
+	profile, err := store.Trx[api.ParticipantProfile](r.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.ParticipantProfile, error) {
+		return r.participantStore.FindByID(ctx, participantID)
+	})
+	if err != nil {
+		if errors.Is(err, types.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	stuck := findStuckOperation(profile, cutoff)
+	if stuck == nil {
+		// Resolved in the meantime
+		return nil
+	}
+
+	orchestrations, err := r.orchestrationClient.QueryOrchestrations(ctx, participantID)
+	if err != nil {
+		return err
+	}
+	orchestration := selectOrchestration(orchestrations, stuck)
+	if orchestration == nil {
+		return r.resend(ctx, participantID, cutoff)
+	}
+	if !orchestration.State.Terminal() {
+		r.monitor.Debugf("Orchestration %s of participant %s is still running", orchestration.ID, participantID)
+		return nil
+	}
+
+	handler, found := r.handlers[orchestration.OrchestrationType]
+	if !found {
+		return fmt.Errorf("no handler registered for orchestration type %s", orchestration.OrchestrationType)
+	}
+	orchestration, err = r.orchestrationClient.GetOrchestration(ctx, orchestration.ID)
+	if err != nil {
+		return err
+	}
+	response := toOrchestrationResponse(orchestration)
+	if err = handler(ctx, response); err != nil {
+		return err
+	}
+	r.monitor.Infof("Applied result of orchestration %s to participant %s", orchestration.ID, participantID)
+	return nil
+}
+
+// resend sends the manifest of the stuck operation again and resets the state timestamp of its VPAs, so that it is only
+// resent again after the threshold has passed.
+func (r *reconciler) resend(ctx context.Context, participantID string, cutoff time.Time) error {
+	return r.trxContext.Execute(ctx, func(ctx context.Context) error {
+		profile, err := r.participantStore.FindByID(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		stuck := findStuckOperation(profile, cutoff)
+		if stuck == nil {
+			return nil
+		}
+		oManifest, err := r.participantService.operationManifest(ctx, profile, stuck.operation, stuck.indices)
+		if err != nil {
+			return err
+		}
+		if stuck.manifestID != "" {
+			oManifest.ID = stuck.manifestID
+		}
+
+		now := r.now().UTC()
+		for _, i := range stuck.indices {
+			profile.VPAs[i].StateTimestamp = now
+			profile.VPAs[i].Operation = stuck.operation
+			profile.VPAs[i].ManifestID = oManifest.ID
+		}
+		if err = r.participantStore.Update(ctx, profile); err != nil {
+			return fmt.Errorf("error reconciling participant %s: %w", participantID, err)
+		}
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		if err = r.participantService.provisionClient.Send(ctx, *oManifest); err != nil {
+			return fmt.Errorf("error resending manifest %s for participant %s: %w", oManifest.ID, participantID, err)
+		}
+		r.monitor.Infof("Resent manifest %s for participant %s", oManifest.ID, participantID)
+		return nil
+	})
+}
+
+// findStuckOperation returns the operation of the VPAs that are pending or disposing if none of them has changed state
+// or reported progress since the cutoff. Returns nil if the profile has no stuck operation.
+func findStuckOperation(profile *api.ParticipantProfile, cutoff time.Time) *stuckOperation {
+	var stuck *stuckOperation
+	for i, vpa := range profile.VPAs {
+		if vpa.State != api.DeploymentStatePending && vpa.State != api.DeploymentStateDisposing {
+			continue
+		}
+		if vpa.StateTimestamp.After(cutoff) || (vpa.Progress != nil && vpa.Progress.Timestamp.After(cutoff)) {
+			return nil
+		}
+		if stuck == nil {
+			stuck = &stuckOperation{operation: vpa.Operation}
+		}
+		if stuck.operation == "" {
+			// The operation is not recorded for VPAs created by previous versions
+			if vpa.State == api.DeploymentStateDisposing {
+				stuck.operation = model.VPADisposeType
+			} else {
+				stuck.operation = model.VPADeployType
+			}
+		}
+		if stuck.manifestID == "" {
+			stuck.manifestID = vpa.ManifestID
+		}
+		stuck.indices = append(stuck.indices, i)
+	}
+	return stuck
+}
+
+// selectOrchestration returns the orchestration started for the manifest of the stuck operation. If the manifest ID was
+// not recorded, the most recently created orchestration of the operation type is returned. Returns nil if no
+// orchestration was found.
+func selectOrchestration(orchestrations []api.OrchestrationStatus, stuck *stuckOperation) *api.OrchestrationStatus {
+	var selected *api.OrchestrationStatus
+	for i, orchestration := range orchestrations {
+		if stuck.manifestID != "" {
+			if orchestration.ID == stuck.manifestID {
+				return &orchestrations[i]
+			}
+			continue
+		}
+		if orchestration.OrchestrationType != stuck.operation {
+			continue
+		}
+		if selected == nil || orchestration.CreatedTimestamp.After(selected.CreatedTimestamp) {
+			selected = &orchestrations[i]
+		}
+	}
+	return selected
+}
+
+// toOrchestrationResponse creates the response the provision manager sends when the orchestration terminates.
+func toOrchestrationResponse(orchestration *api.OrchestrationStatus) model.OrchestrationResponse {
+	vpaResults, properties := model.ExtractVPAResults(orchestration.OutputData)
+	response := model.OrchestrationResponse{
+		ID:                uuid.New().String(),
+		ManifestID:        orchestration.ID,
+		CorrelationID:     orchestration.CorrelationID,
+		OrchestrationType: orchestration.OrchestrationType,
+		Success:           orchestration.State == api.OrchestrationStateCompleted,
+		Properties:        properties,
+		VPAResults:        vpaResults,
+	}
+	if !response.Success {
+		response.ErrorDetail = fmt.Sprintf("orchestration %s failed", orchestration.ID)
+	}
+	return response
+}
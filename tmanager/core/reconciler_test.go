@@ -0,0 +1,257 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies result of completed orchestration", func(t *testing.T) {
+		r, client := newTestReconciler(t, newTestStuckParticipant(model.VPADeployType, "manifest-1"))
+		client.On("QueryOrchestrations", mock.Anything, "participant-1").Return([]api.OrchestrationStatus{
+			{ID: "manifest-0", State: api.OrchestrationStateCompleted, OrchestrationType: model.VPADeployType},
+			{ID: "manifest-1", State: api.OrchestrationStateCompleted, OrchestrationType: model.VPADeployType},
+		}, nil)
+		client.On("GetOrchestration", mock.Anything, "manifest-1").Return(&api.OrchestrationStatus{
+			ID:                "manifest-1",
+			CorrelationID:     "participant-1",
+			State:             api.OrchestrationStateCompleted,
+			OrchestrationType: model.VPADeployType,
+			OutputData:        map[string]any{"key": "value"},
+		}, nil)
+
+		require.NoError(t, r.reconcile(ctx))
+
+		profile, err := r.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, profile.VPAs[0].State)
+		assert.Equal(t, map[string]any{"key": "value"}, profile.Properties[model.VPAStateData])
+		assert.False(t, profile.Error)
+		assert.Empty(t, sentReconcileManifests(r))
+	})
+
+	t.Run("applies result of errored orchestration", func(t *testing.T) {
+		r, client := newTestReconciler(t, newTestStuckParticipant(model.VPADisposeType, "manifest-1"))
+		client.On("QueryOrchestrations", mock.Anything, "participant-1").Return([]api.OrchestrationStatus{
+			{ID: "manifest-1", State: api.OrchestrationStateErrored, OrchestrationType: model.VPADisposeType},
+		}, nil)
+		client.On("GetOrchestration", mock.Anything, "manifest-1").Return(&api.OrchestrationStatus{
+			ID:                "manifest-1",
+			CorrelationID:     "participant-1",
+			State:             api.OrchestrationStateErrored,
+			OrchestrationType: model.VPADisposeType,
+		}, nil)
+
+		require.NoError(t, r.reconcile(ctx))
+
+		profile, err := r.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateError, profile.VPAs[0].State)
+		assert.True(t, profile.Error)
+		assert.Contains(t, profile.ErrorDetail, "manifest-1")
+	})
+
+	t.Run("running orchestration is not reconciled", func(t *testing.T) {
+		r, client := newTestReconciler(t, newTestStuckParticipant(model.VPADeployType, "manifest-1"))
+		client.On("QueryOrchestrations", mock.Anything, "participant-1").Return([]api.OrchestrationStatus{
+			{ID: "manifest-1", State: api.OrchestrationStateRunning, OrchestrationType: model.VPADeployType},
+		}, nil)
+
+		require.NoError(t, r.reconcile(ctx))
+
+		profile, err := r.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, profile.VPAs[0].State)
+		client.AssertNotCalled(t, "GetOrchestration", mock.Anything, mock.Anything)
+		assert.Empty(t, sentReconcileManifests(r))
+	})
+
+	t.Run("resends manifest the provision manager did not receive", func(t *testing.T) {
+		r, client := newTestReconciler(t, newTestStuckParticipant(model.VPADisposeType, "manifest-1"))
+		client.On("QueryOrchestrations", mock.Anything, "participant-1").Return([]api.OrchestrationStatus{
+			{ID: "manifest-0", State: api.OrchestrationStateCompleted, OrchestrationType: model.VPADeployType},
+		}, nil)
+
+		require.NoError(t, r.reconcile(ctx))
+
+		manifests := sentReconcileManifests(r)
+		require.Len(t, manifests, 1)
+		assert.Equal(t, "manifest-1", manifests[0].ID)
+		assert.Equal(t, "participant-1", manifests[0].CorrelationID)
+		assert.Equal(t, model.VPADisposeType, manifests[0].OrchestrationType)
+		assert.Equal(t, map[string]any{"key": "value"}, manifests[0].Payload[model.VPAStateData])
+		require.Len(t, manifests[0].Payload[model.VPAData], 1)
+
+		profile, err := r.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateDisposing, profile.VPAs[0].State)
+		assert.True(t, r.now().Equal(profile.VPAs[0].StateTimestamp))
+
+		// The resent operation is not reconciled again until the threshold has passed
+		require.NoError(t, r.reconcile(ctx))
+		assert.Len(t, sentReconcileManifests(r), 1)
+	})
+
+	t.Run("selects latest orchestration of the operation if the manifest ID was not recorded", func(t *testing.T) {
+		profile := newTestStuckParticipant(model.VPADeployType, "")
+		profile.VPAs[0].Operation = ""
+		r, client := newTestReconciler(t, profile)
+		created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		client.On("QueryOrchestrations", mock.Anything, "participant-1").Return([]api.OrchestrationStatus{
+			{ID: "manifest-2", State: api.OrchestrationStateCompleted, OrchestrationType: model.VPADeployType, CreatedTimestamp: created.Add(time.Minute)},
+			{ID: "manifest-1", State: api.OrchestrationStateErrored, OrchestrationType: model.VPADeployType, CreatedTimestamp: created},
+			{ID: "manifest-3", State: api.OrchestrationStateErrored, OrchestrationType: model.VPAUpdateType, CreatedTimestamp: created.Add(time.Hour)},
+		}, nil)
+		client.On("GetOrchestration", mock.Anything, "manifest-2").Return(&api.OrchestrationStatus{
+			ID:                "manifest-2",
+			CorrelationID:     "participant-1",
+			State:             api.OrchestrationStateCompleted,
+			OrchestrationType: model.VPADeployType,
+		}, nil)
+
+		require.NoError(t, r.reconcile(ctx))
+
+		updated, err := r.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, updated.VPAs[0].State)
+	})
+
+	t.Run("operations within the threshold are not reconciled", func(t *testing.T) {
+		profile := newTestStuckParticipant(model.VPADeployType, "manifest-1")
+		r, client := newTestReconciler(t, profile)
+		profile.VPAs[0].StateTimestamp = r.now().Add(-time.Minute)
+		require.NoError(t, r.participantStore.Update(ctx, profile))
+
+		require.NoError(t, r.reconcile(ctx))
+
+		client.AssertNotCalled(t, "QueryOrchestrations", mock.Anything, mock.Anything)
+	})
+
+	t.Run("operations reporting progress are not reconciled", func(t *testing.T) {
+		profile := newTestStuckParticipant(model.VPADeployType, "manifest-1")
+		r, client := newTestReconciler(t, profile)
+		profile.VPAs[0].Progress = &api.DeploymentProgress{Percent: 50, Timestamp: r.now().Add(-time.Minute)}
+		require.NoError(t, r.participantStore.Update(ctx, profile))
+
+		require.NoError(t, r.reconcile(ctx))
+
+		client.AssertNotCalled(t, "QueryOrchestrations", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOperationsRecordManifestID(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+	provisionClient := new(mockProvisionClient)
+	provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+	service.provisionClient = provisionClient
+	cell := newTestCell("cell-1", "external-id")
+	cell.State = api.DeploymentStateActive
+	_, err := service.cellStore.Create(ctx, cell)
+	require.NoError(t, err)
+	_, err = service.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-1"))
+	require.NoError(t, err)
+
+	profile, err := service.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+		Identifier:    "participant-identifier",
+		VPAProperties: api.VPAPropMap{model.ConnectorType: {}},
+	})
+
+	require.NoError(t, err)
+	manifest := provisionClient.Calls[0].Arguments.Get(1).(model.OrchestrationManifest)
+	for _, vpa := range profile.VPAs {
+		assert.Equal(t, manifest.ID, vpa.ManifestID)
+	}
+}
+
+// newTestStuckParticipant returns participant-1 with a VPA that has been performing the operation for an hour.
+func newTestStuckParticipant(operation model.OrchestrationType, manifestID string) *api.ParticipantProfile {
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.VPAs[0].State = api.DeploymentStatePending
+	if operation == model.VPADisposeType {
+		profile.VPAs[0].State = api.DeploymentStateDisposing
+		profile.Properties[model.VPAStateData] = map[string]any{"key": "value"}
+	}
+	profile.VPAs[0].Operation = operation
+	profile.VPAs[0].ManifestID = manifestID
+	profile.VPAs[0].StateTimestamp = testReconcileTime.Add(-time.Hour)
+	return profile
+}
+
+var testReconcileTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestReconciler(t *testing.T, profile *api.ParticipantProfile) (*reconciler, *mockOrchestrationClient) {
+	participants := newTestParticipantService()
+	provisionClient := new(mockProvisionClient)
+	provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+	participants.provisionClient = provisionClient
+	_, err := participants.participantStore.Create(context.Background(), profile)
+	require.NoError(t, err)
+
+	handler := vpaCallbackHandler{
+		trxContext:       participants.trxContext,
+		participantStore: participants.participantStore,
+		tenantStore:      participants.tenantStore,
+		monitor:          system.NoopMonitor{},
+	}
+	client := new(mockOrchestrationClient)
+	return &reconciler{
+		trxContext:          participants.trxContext,
+		participantStore:    participants.participantStore,
+		participantService:  *participants,
+		orchestrationClient: client,
+		handlers: map[model.OrchestrationType]api.ProvisionCallbackHandler{
+			model.VPADeployType:  handler.handleDeploy,
+			model.VPADisposeType: handler.handleDispose,
+		},
+		threshold: 5 * time.Minute,
+		interval:  time.Minute,
+		monitor:   system.NoopMonitor{},
+		now:       func() time.Time { return testReconcileTime },
+	}, client
+}
+
+func sentReconcileManifests(r *reconciler) []model.OrchestrationManifest {
+	calls := r.participantService.provisionClient.(*mockProvisionClient).Calls
+	manifests := make([]model.OrchestrationManifest, 0, len(calls))
+	for _, call := range calls {
+		manifests = append(manifests, call.Arguments.Get(1).(model.OrchestrationManifest))
+	}
+	return manifests
+}
+
+type mockOrchestrationClient struct {
+	mock.Mock
+}
+
+func (m *mockOrchestrationClient) QueryOrchestrations(ctx context.Context, correlationID string) ([]api.OrchestrationStatus, error) {
+	args := m.Called(ctx, correlationID)
+	return args.Get(0).([]api.OrchestrationStatus), args.Error(1)
+}
+
+func (m *mockOrchestrationClient) GetOrchestration(ctx context.Context, orchestrationID string) (*api.OrchestrationStatus, error) {
+	args := m.Called(ctx, orchestrationID)
+	return args.Get(0).(*api.OrchestrationStatus), args.Error(1)
+}
@@ -0,0 +1,198 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	schemaPropertiesKey    = "properties"
+	schemaVPAPropertiesKey = "vpaProperties"
+	schemaResourceURL      = "cfm://schemas/participant-properties.json"
+)
+
+// effectivePropertySchema combines the property schemas declared by the dataspace profiles into a single JSON Schema.
+// The schema describes an object with a properties member for the participant properties and a vpaProperties member
+// keyed by VPA type. When more than one profile declares a schema for the same member, all must be satisfied.
+func effectivePropertySchema(dProfiles []api.DataspaceProfile) map[string]any {
+	participantSchemas := make([]map[string]any, 0)
+	vpaSchemas := make(map[model.VPAType][]map[string]any)
+	for _, dProfile := range dProfiles {
+		schemas := dProfile.DataspaceSpec.PropertySchemas
+		if schemas == nil {
+			continue
+		}
+		if len(schemas.Participant) > 0 {
+			participantSchemas = append(participantSchemas, schemas.Participant)
+		}
+		for vpaType, schema := range schemas.VPAs {
+			if len(schema) > 0 {
+				vpaSchemas[vpaType] = append(vpaSchemas[vpaType], schema)
+			}
+		}
+	}
+
+	vpaProperties := make(map[string]any, len(vpaSchemas))
+	for vpaType, schemas := range vpaSchemas {
+		vpaProperties[vpaType.String()] = combineSchemas(schemas)
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			schemaPropertiesKey: combineSchemas(participantSchemas),
+			schemaVPAPropertiesKey: map[string]any{
+				"type":       "object",
+				"properties": vpaProperties,
+			},
+		},
+	}
+}
+
+// combineSchemas returns a schema that requires all the given schemas to be satisfied.
+func combineSchemas(schemas []map[string]any) map[string]any {
+	switch len(schemas) {
+	case 0:
+		return map[string]any{"type": "object"}
+	case 1:
+		return withoutDialect(schemas[0])
+	default:
+		allOf := make([]any, len(schemas))
+		for i, schema := range schemas {
+			allOf[i] = withoutDialect(schema)
+		}
+		return map[string]any{"allOf": allOf}
+	}
+}
+
+// withoutDialect removes the $schema keyword, which is only permitted at the root of a schema resource.
+func withoutDialect(schema map[string]any) map[string]any {
+	if _, found := schema["$schema"]; !found {
+		return schema
+	}
+	result := maps.Clone(schema)
+	delete(result, "$schema")
+	return result
+}
+
+// compileSchema compiles a JSON Schema document.
+func compileSchema(schema map[string]any) (*jsonschema.Schema, error) {
+	doc, err := toJSONValue(schema)
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResourceURL, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaResourceURL)
+}
+
+// validatePropertySchemas verifies that the schemas declared by a dataspace profile are valid JSON Schemas.
+func validatePropertySchemas(schemas *api.PropertySchemas) error {
+	if schemas == nil {
+		return nil
+	}
+	if len(schemas.Participant) > 0 {
+		if _, err := compileSchema(withoutDialect(schemas.Participant)); err != nil {
+			return types.NewClientWrappedError(err, "invalid participant property schema")
+		}
+	}
+	for _, vpaType := range slices.Sorted(maps.Keys(schemas.VPAs)) {
+		if _, err := compileSchema(withoutDialect(schemas.VPAs[vpaType])); err != nil {
+			return types.NewClientWrappedError(err, "invalid property schema for VPA type %s", vpaType)
+		}
+	}
+	return nil
+}
+
+// validateProfileProperties validates the properties of a participant profile and its VPAs against the effective schema
+// of the dataspace profiles. VPA state data maintained by the system is excluded. A types.ValidationError describing
+// each offending field is returned if validation fails.
+func validateProfileProperties(dProfiles []api.DataspaceProfile, profile *api.ParticipantProfile) error {
+	schema, err := compileSchema(effectivePropertySchema(dProfiles))
+	if err != nil {
+		return fmt.Errorf("error compiling property schema: %w", err)
+	}
+
+	properties := make(map[string]any, len(profile.Properties))
+	for k, v := range profile.Properties {
+		if k != model.VPAStateData {
+			properties[k] = v
+		}
+	}
+	vpaProperties := make(map[string]any, len(profile.VPAs))
+	for _, vpa := range profile.VPAs {
+		props := make(map[string]any, len(vpa.Properties))
+		maps.Copy(props, vpa.Properties)
+		vpaProperties[vpa.Type.String()] = props
+	}
+
+	instance, err := toJSONValue(map[string]any{
+		schemaPropertiesKey:    properties,
+		schemaVPAPropertiesKey: vpaProperties,
+	})
+	if err != nil {
+		return types.NewClientWrappedError(err, "invalid properties")
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return fmt.Errorf("error validating properties: %w", err)
+	}
+	return types.NewValidationError(toFieldErrors(validationErr), "properties do not conform to the dataspace profile schema")
+}
+
+// toFieldErrors flattens a schema validation error into field errors keyed by the JSON Pointer of the offending value,
+// ordered by field.
+func toFieldErrors(err *jsonschema.ValidationError) []types.FieldError {
+	fields := make([]types.FieldError, 0)
+	for _, unit := range err.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		field := unit.InstanceLocation
+		if field == "" {
+			field = "/"
+		}
+		fields = append(fields, types.FieldError{Field: field, Message: unit.Error.String()})
+	}
+	slices.SortStableFunc(fields, func(a, b types.FieldError) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+	return fields
+}
+
+// toJSONValue converts a value to the generic representation expected by the schema validator.
+func toJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
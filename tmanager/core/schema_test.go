@@ -0,0 +1,120 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectivePropertySchema(t *testing.T) {
+	t.Run("profiles without schemas accept any object", func(t *testing.T) {
+		schema := effectivePropertySchema([]api.DataspaceProfile{*newTestDataspaceProfile("ds-1")})
+
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "object"}, properties[schemaPropertiesKey])
+		assert.Empty(t, properties[schemaVPAPropertiesKey].(map[string]any)["properties"])
+	})
+
+	t.Run("schemas from multiple profiles are combined", func(t *testing.T) {
+		ds1 := newTestDataspaceProfile("ds-1")
+		ds1.DataspaceSpec.PropertySchemas = &api.PropertySchemas{
+			Participant: map[string]any{"$schema": "https://json-schema.org/draft/2020-12/schema", "required": []any{"name"}},
+			VPAs:        map[model.VPAType]map[string]any{model.ConnectorType: {"required": []any{"port"}}},
+		}
+		ds2 := newTestDataspaceProfile("ds-2")
+		ds2.DataspaceSpec.PropertySchemas = &api.PropertySchemas{
+			Participant: map[string]any{"required": []any{"region"}},
+		}
+
+		schema := effectivePropertySchema([]api.DataspaceProfile{*ds1, *ds2})
+
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"allOf": []any{
+			map[string]any{"required": []any{"name"}},
+			map[string]any{"required": []any{"region"}},
+		}}, properties[schemaPropertiesKey])
+		vpaProperties := properties[schemaVPAPropertiesKey].(map[string]any)["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"required": []any{"port"}}, vpaProperties[model.ConnectorType.String()])
+		assert.Contains(t, ds1.DataspaceSpec.PropertySchemas.Participant, "$schema", "source schema must not be modified")
+	})
+}
+
+func TestValidatePropertySchemas(t *testing.T) {
+	t.Run("valid schemas", func(t *testing.T) {
+		require.NoError(t, validatePropertySchemas(nil))
+		require.NoError(t, validatePropertySchemas(newTestPropertySchemas()))
+	})
+
+	t.Run("invalid participant schema returns client error", func(t *testing.T) {
+		err := validatePropertySchemas(&api.PropertySchemas{Participant: map[string]any{"type": "no-such-type"}})
+
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+	})
+
+	t.Run("invalid VPA schema returns client error", func(t *testing.T) {
+		err := validatePropertySchemas(&api.PropertySchemas{
+			VPAs: map[model.VPAType]map[string]any{model.DataPlaneType: {"minimum": "zero"}},
+		})
+
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+		assert.Contains(t, err.Error(), model.DataPlaneType.String())
+	})
+}
+
+func TestValidateProfileProperties(t *testing.T) {
+	ds := newTestDataspaceProfile("ds-1")
+	ds.DataspaceSpec.PropertySchemas = newTestPropertySchemas()
+	ds.DataspaceSpec.PropertySchemas.Participant["additionalProperties"] = false
+	ds.DataspaceSpec.PropertySchemas.Participant["properties"] = map[string]any{"name": map[string]any{"type": "string"}}
+	dProfiles := []api.DataspaceProfile{*ds}
+
+	t.Run("conforming properties", func(t *testing.T) {
+		profile := newTestParticipantProfile("tenant-1", "participant-1")
+		profile.Properties[model.VPAStateData] = map[string]any{"state": "deployed"}
+		profile.VPAs[0].Properties["port"] = 8080
+
+		require.NoError(t, validateProfileProperties(dProfiles, profile))
+	})
+
+	t.Run("non-conforming properties return field errors", func(t *testing.T) {
+		profile := newTestParticipantProfile("tenant-1", "participant-1")
+		profile.Properties["name"] = 42
+		profile.VPAs[0].Properties["port"] = 8080.5
+
+		err := validateProfileProperties(dProfiles, profile)
+
+		var validationErr types.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Fields, 2)
+		assert.Equal(t, "/properties/name", validationErr.Fields[0].Field)
+		assert.Equal(t, "/vpaProperties/cfm.connector/port", validationErr.Fields[1].Field)
+		for _, field := range validationErr.Fields {
+			assert.NotEmpty(t, field.Message)
+		}
+	})
+
+	t.Run("profiles without schemas accept any properties", func(t *testing.T) {
+		profile := newTestParticipantProfile("tenant-1", "participant-1")
+		profile.Properties["anything"] = []any{1, "two"}
+
+		require.NoError(t, validateProfileProperties([]api.DataspaceProfile{*newTestDataspaceProfile("ds-2")}, profile))
+	})
+}
@@ -14,17 +14,26 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/collection"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 )
 
 type cellService struct {
-	trxContext store.TransactionContext
-	cellStore  store.EntityStore[*api.Cell]
+	trxContext         store.TransactionContext
+	cellStore          store.EntityStore[*api.Cell]
+	participantStore   store.EntityStore[*api.ParticipantProfile]
+	participantService participantService
+	events             *cellEventDispatcher
+	monitor            system.LogMonitor
 }
 
 func (d cellService) RecordExternalDeployment(ctx context.Context, cell *api.Cell) (*api.Cell, error) {
@@ -44,6 +53,28 @@ func (d cellService) RecordExternalDeployment(ctx context.Context, cell *api.Cel
 
 func (t cellService) DeleteCell(ctx context.Context, cellID string) error {
 	return t.trxContext.Execute(ctx, func(ctx context.Context) error {
+		cell, err := t.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return err
+		}
+		if err = store.CheckExpectedVersion(ctx, cell); err != nil {
+			return err
+		}
+		count := 0
+		for profile, err := range t.participantStore.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			for _, vpa := range profile.VPAs {
+				if vpa.State != api.DeploymentStateDisposed &&
+					(vpa.CellID == cellID || (vpa.Migration != nil && vpa.Migration.SourceCellID == cellID)) {
+					count++
+				}
+			}
+		}
+		if count > 0 {
+			return types.NewRecoverableWrappedError(types.ErrConflict, "cell %s is referenced by %d VPAs", cellID, count)
+		}
 		return t.cellStore.Delete(ctx, cellID)
 	})
 }
@@ -57,3 +88,222 @@ func (p cellService) ListCells(ctx context.Context) ([]api.Cell, error) {
 	})
 	return result, err
 }
+
+func (d cellService) CordonCell(ctx context.Context, cellID string) (*api.Cell, error) {
+	return store.Trx[api.Cell](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Cell, error) {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return nil, err
+		}
+		if err = store.CheckExpectedVersion(ctx, cell); err != nil {
+			return nil, err
+		}
+		if cell.Cordoned {
+			return cell, nil
+		}
+		cell.Cordoned = true
+		if err = d.cellStore.Update(ctx, cell); err != nil {
+			return nil, fmt.Errorf("error cordoning cell %s: %w", cellID, err)
+		}
+		return cell, nil
+	})
+}
+
+func (d cellService) UncordonCell(ctx context.Context, cellID string) (*api.Cell, error) {
+	return store.Trx[api.Cell](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Cell, error) {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return nil, err
+		}
+		if err = store.CheckExpectedVersion(ctx, cell); err != nil {
+			return nil, err
+		}
+		if !cell.Cordoned {
+			return cell, nil
+		}
+		status, err := d.drainStatus(ctx, cell)
+		if err != nil {
+			return nil, err
+		}
+		if status.Migrating > 0 {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "cell %s has %d VPAs being migrated", cellID, status.Migrating)
+		}
+		cell.Cordoned = false
+		if err = d.cellStore.Update(ctx, cell); err != nil {
+			return nil, fmt.Errorf("error uncordoning cell %s: %w", cellID, err)
+		}
+		return cell, nil
+	})
+}
+
+func (d cellService) DrainCell(ctx context.Context, cellID string) (*api.CellDrainStatus, error) {
+	var cells []api.Cell
+	var participantIDs []string
+	var vpaCounts map[string]int
+	err := d.trxContext.Execute(ctx, func(ctx context.Context) error {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return err
+		}
+		if err = store.CheckExpectedVersion(ctx, cell); err != nil {
+			return err
+		}
+		if !cell.Cordoned || cell.Drain == nil {
+			// Start a new drain; otherwise, the drain is resumed for VPAs that were not migrated
+			cell.Drain = &api.CellDrain{StartTimestamp: time.Now().UTC()}
+		}
+		cell.Cordoned = true
+		if err = d.cellStore.Update(ctx, cell); err != nil {
+			return fmt.Errorf("error draining cell %s: %w", cellID, err)
+		}
+
+		if cells, err = collection.CollectAllDeref(d.cellStore.GetAll(ctx)); err != nil {
+			return err
+		}
+		for profile, err := range d.participantStore.GetAll(ctx) {
+			if err != nil {
+				return err
+			}
+			if slices.ContainsFunc(profile.VPAs, func(vpa api.VirtualParticipantAgent) bool {
+				return vpa.CellID == cellID && vpa.State != api.DeploymentStateDisposed
+			}) {
+				participantIDs = append(participantIDs, profile.ID)
+			}
+		}
+		vpaCounts, err = d.participantService.countVPAsByCell(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Each participant is migrated in its own transaction so that a failure does not affect migrations already started
+	participants, vpas := 0, 0
+	var migrateErr error
+	for _, participantID := range participantIDs {
+		count, err := d.participantService.migrateVPAs(ctx, participantID, cellID, cells, vpaCounts)
+		if err != nil {
+			if errors.Is(err, types.ErrConflict) || errors.Is(err, types.ErrNotFound) || errors.Is(err, types.ErrForbidden) {
+				d.monitor.Infof("Skipping migration of participant %s from cell %s: %v", participantID, cellID, err)
+				continue
+			}
+			migrateErr = err
+			break
+		}
+		if count > 0 {
+			participants++
+			vpas += count
+		}
+	}
+
+	var status *api.CellDrainStatus
+	err = d.trxContext.Execute(ctx, func(ctx context.Context) error {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return err
+		}
+		cell.Drain.Participants += participants
+		cell.Drain.VPAs += vpas
+		if err = d.cellStore.Update(ctx, cell); err != nil {
+			return fmt.Errorf("error draining cell %s: %w", cellID, err)
+		}
+		status, err = d.drainStatus(ctx, cell)
+		return err
+	})
+	if migrateErr != nil {
+		return nil, fmt.Errorf("error draining cell %s: %w", cellID, migrateErr)
+	}
+	return status, err
+}
+
+func (d cellService) GetDrainStatus(ctx context.Context, cellID string) (*api.CellDrainStatus, error) {
+	return store.Trx[api.CellDrainStatus](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.CellDrainStatus, error) {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return nil, err
+		}
+		if cell.Drain == nil {
+			return nil, types.NewRecoverableWrappedError(types.ErrNotFound, "cell %s has not been drained", cellID)
+		}
+		return d.drainStatus(ctx, cell)
+	})
+}
+
+// drainStatus computes the progress of the drain of the cell from the VPAs placed in or being migrated from the cell.
+func (d cellService) drainStatus(ctx context.Context, cell *api.Cell) (*api.CellDrainStatus, error) {
+	status := &api.CellDrainStatus{
+		CellID:   cell.ID,
+		Cordoned: cell.Cordoned,
+	}
+	if cell.Drain != nil {
+		status.StartTimestamp = cell.Drain.StartTimestamp
+		status.Participants = cell.Drain.Participants
+		status.VPAs = cell.Drain.VPAs
+	}
+	for profile, err := range d.participantStore.GetAll(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		for _, vpa := range profile.VPAs {
+			switch {
+			case vpa.State == api.DeploymentStateDisposed:
+				continue
+			case vpa.CellID == cell.ID:
+				status.Remaining++
+			case vpa.Migration == nil || vpa.Migration.SourceCellID != cell.ID:
+				continue
+			case vpa.State == api.DeploymentStateError:
+				status.Failed++
+			default:
+				status.Migrating++
+			}
+		}
+	}
+	status.Migrated = max(0, status.VPAs-status.Migrating-status.Failed)
+	return status, nil
+}
+
+func (d cellService) RecordHeartbeat(ctx context.Context, cellID string, heartbeat api.CellHeartbeat) (*api.Cell, error) {
+	var event *api.CellEvent
+	cell, err := store.Trx[api.Cell](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Cell, error) {
+		cell, err := d.cellStore.FindByID(ctx, cellID)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC()
+		cell.Health = &api.CellHealth{LastHeartbeat: now, Metrics: heartbeat.Metrics}
+		if heartbeat.Capacity != nil {
+			if cell.Properties == nil {
+				cell.Properties = make(api.Properties)
+			}
+			cell.Properties[api.CellCapacityProperty] = *heartbeat.Capacity
+		}
+		if cell.State == api.DeploymentStateOffline {
+			cell.State = api.DeploymentStateActive
+			cell.StateTimestamp = now
+			participants, err := transitionCellVPAs(ctx, d.participantStore, cellID, api.DeploymentStateOffline, api.DeploymentStateActive, now)
+			if err != nil {
+				return nil, err
+			}
+			event = &api.CellEvent{
+				Type:           api.CellEventOnline,
+				CellID:         cellID,
+				ExternalCellID: cell.ExternalID,
+				Timestamp:      now,
+				Participants:   participants,
+			}
+		}
+		if err = d.cellStore.Update(ctx, cell); err != nil {
+			return nil, fmt.Errorf("error recording heartbeat of cell %s: %w", cellID, err)
+		}
+		return cell, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		// Only emit the event if the storage operation succeeded
+		d.events.dispatch(ctx, *event)
+	}
+	return cell, nil
+}
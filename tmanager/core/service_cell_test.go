@@ -17,11 +17,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,6 +70,31 @@ func TestDeleteCell(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("delete cell referenced by VPAs returns conflict", func(t *testing.T) {
+		ctx := context.Background()
+		service := newTestDrainCellService(t)
+
+		err := service.DeleteCell(ctx, "cell-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrConflict)
+		_, err = service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("delete cell referenced by disposed VPAs", func(t *testing.T) {
+		ctx := context.Background()
+		service := newTestDrainCellService(t)
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		profile.VPAs[0].State = api.DeploymentStateDisposed
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		err = service.DeleteCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+	})
+
 	t.Run("delete non-existent cell returns error", func(t *testing.T) {
 		ctx := context.Background()
 		service := newTestCellService()
@@ -76,6 +103,19 @@ func TestDeleteCell(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, types.ErrNotFound, err)
 	})
+
+	t.Run("delete cell at another version returns precondition failed", func(t *testing.T) {
+		ctx := context.Background()
+		service := newTestCellService()
+		created, err := service.RecordExternalDeployment(ctx, newTestCell("cell-delete-2", "external-id"))
+		require.NoError(t, err)
+
+		err = service.DeleteCell(store.WithExpectedVersion(ctx, "cell-delete-2", created.Version+1), "cell-delete-2")
+
+		assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+		_, err = service.cellStore.FindByID(ctx, "cell-delete-2")
+		require.NoError(t, err)
+	})
 }
 
 func TestListCells(t *testing.T) {
@@ -138,8 +178,296 @@ func newTestCell(id string, externalId string) *api.Cell {
 }
 
 func newTestCellService() *cellService {
+	participants := newTestParticipantService()
+	provisionClient := new(mockProvisionClient)
+	provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+	participants.provisionClient = provisionClient
+	participants.participantGenerator.CellSelector = defaultCellSelector
 	return &cellService{
-		trxContext: store.NoOpTransactionContext{},
-		cellStore:  memorystore.NewInMemoryEntityStore[*api.Cell](),
+		trxContext:         store.NoOpTransactionContext{},
+		cellStore:          participants.cellStore,
+		participantStore:   participants.participantStore,
+		participantService: *participants,
+		events:             newCellEventDispatcher(system.NoopMonitor{}),
+		monitor:            system.NoopMonitor{},
+	}
+}
+
+// newTestDrainCellService returns a cell service with two active cells the dataspace profile is deployed to and a
+// deployed participant with an active VPA placed in cell-1.
+func newTestDrainCellService(t *testing.T) *cellService {
+	ctx := context.Background()
+	service := newTestCellService()
+	for _, id := range []string{"cell-1", "cell-2"} {
+		cell := newTestCell(id, "external-"+id)
+		cell.State = api.DeploymentStateActive
+		_, err := service.cellStore.Create(ctx, cell)
+		require.NoError(t, err)
+	}
+
+	dProfile := newTestDataspaceProfile("dataspace-1")
+	for _, id := range []string{"cell-1", "cell-2"} {
+		deployment := newTestDataspaceDeployment("deployment-"+id, api.DeploymentStateActive)
+		deployment.CellID = id
+		dProfile.Deployments = append(dProfile.Deployments, deployment)
 	}
+	_, err := service.participantService.dataspaceStore.Create(ctx, dProfile)
+	require.NoError(t, err)
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.VPAs[0].State = api.DeploymentStateActive
+	profile.VPAs[0].ExternalCellID = "external-cell-1"
+	profile.Properties[model.VPAStateData] = map[string]any{"key": "value"}
+	_, err = service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+	return service
+}
+
+func sentManifests(service *cellService) []model.OrchestrationManifest {
+	calls := service.participantService.provisionClient.(*mockProvisionClient).Calls
+	manifests := make([]model.OrchestrationManifest, 0, len(calls))
+	for _, call := range calls {
+		manifests = append(manifests, call.Arguments.Get(1).(model.OrchestrationManifest))
+	}
+	return manifests
+}
+
+func TestCordonCell(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cordon and uncordon cell", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+
+		cell, err := service.CordonCell(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.True(t, cell.Cordoned)
+		stored, err := service.cellStore.FindByID(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.True(t, stored.Cordoned)
+
+		cell, err = service.UncordonCell(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.False(t, cell.Cordoned)
+	})
+
+	t.Run("cordon non-existent cell returns not found", func(t *testing.T) {
+		service := newTestCellService()
+
+		_, err := service.CordonCell(ctx, "non-existent")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("cordoned cell is not selected for new VPAs", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.CordonCell(ctx, "cell-2")
+		require.NoError(t, err)
+		_, err = service.CordonCell(ctx, "cell-1")
+		require.NoError(t, err)
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+			Identifier: "participant-identifier",
+		})
+
+		require.Error(t, err)
+	})
+
+	t.Run("uncordon cell with VPAs being migrated returns conflict", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.DrainCell(ctx, "cell-1")
+		require.NoError(t, err)
+
+		_, err = service.UncordonCell(ctx, "cell-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrConflict)
+	})
+}
+
+func TestDrainCell(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("drain migrates VPAs to other cells", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+
+		status, err := service.DrainCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+		assert.True(t, status.Cordoned)
+		assert.Equal(t, 1, status.Participants)
+		assert.Equal(t, 1, status.VPAs)
+		assert.Equal(t, 1, status.Migrating)
+		assert.Equal(t, 0, status.Remaining)
+		assert.False(t, status.Complete())
+
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		vpa := profile.VPAs[0]
+		assert.Equal(t, api.DeploymentStatePending, vpa.State)
+		assert.Equal(t, model.VPAMigrateType, vpa.Operation)
+		assert.Equal(t, "cell-2", vpa.CellID)
+		assert.Equal(t, "external-cell-2", vpa.ExternalCellID)
+		require.NotNil(t, vpa.Migration)
+		assert.Equal(t, "cell-1", vpa.Migration.SourceCellID)
+		assert.Equal(t, "external-cell-1", vpa.Migration.SourceExternalCellID)
+
+		manifests := sentManifests(service)
+		require.Len(t, manifests, 1)
+		assert.Equal(t, model.VPAMigrateType, manifests[0].OrchestrationType)
+		assert.Equal(t, "participant-1", manifests[0].CorrelationID)
+		assert.Equal(t, map[string]any{"key": "value"}, manifests[0].Payload[model.VPAStateData])
+		vpaManifests := manifests[0].Payload[model.VPAData].([]model.VPAManifest)
+		require.Len(t, vpaManifests, 1)
+		assert.Equal(t, "external-cell-2", vpaManifests[0].ExternalCellID)
+		assert.Equal(t, []model.VPAMigration{{
+			ID:                   "vpa-1",
+			VPAType:              model.ConnectorType,
+			SourceCellID:         "cell-1",
+			SourceExternalCellID: "external-cell-1",
+		}}, manifests[0].Payload[model.VPAMigrateData])
+	})
+
+	t.Run("drain only migrates VPAs to cells allowed by the tenant quota", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		tenant := newTestTenant("tenant-1")
+		tenant.Quota = &api.TenantQuota{AllowedCells: []string{"cell-1"}}
+		_, err := service.participantService.tenantStore.Create(ctx, tenant)
+		require.NoError(t, err)
+
+		status, err := service.DrainCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.Migrating)
+		assert.Equal(t, 1, status.Remaining)
+		assert.Empty(t, sentManifests(service))
+	})
+
+	t.Run("drain completes when the migration succeeds", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.DrainCell(ctx, "cell-1")
+		require.NoError(t, err)
+
+		handler := vpaCallbackHandler{
+			participantStore: service.participantStore,
+			trxContext:       service.trxContext,
+			monitor:          system.NoopMonitor{},
+		}
+		err = handler.handleMigrate(ctx, model.OrchestrationResponse{
+			ID:            "response-1",
+			ManifestID:    sentManifests(service)[0].ID,
+			CorrelationID: "participant-1",
+			Success:       true,
+			Properties:    map[string]any{"migrated": true},
+		})
+		require.NoError(t, err)
+
+		status, err := service.GetDrainStatus(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Migrated)
+		assert.True(t, status.Complete())
+
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, profile.VPAs[0].State)
+		assert.Equal(t, "cell-2", profile.VPAs[0].CellID)
+		assert.Nil(t, profile.VPAs[0].Migration)
+		assert.Equal(t, true, profile.Properties[model.VPAStateData].(map[string]any)["migrated"])
+
+		err = service.DeleteCell(ctx, "cell-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("failed migration can be retried", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.DrainCell(ctx, "cell-1")
+		require.NoError(t, err)
+
+		handler := vpaCallbackHandler{
+			participantStore: service.participantStore,
+			trxContext:       service.trxContext,
+			monitor:          system.NoopMonitor{},
+		}
+		err = handler.handleMigrate(ctx, model.OrchestrationResponse{
+			ID:            "response-1",
+			ManifestID:    sentManifests(service)[0].ID,
+			CorrelationID: "participant-1",
+			Success:       false,
+			ErrorDetail:   "migration failed",
+		})
+		require.NoError(t, err)
+
+		status, err := service.GetDrainStatus(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Failed)
+		assert.Equal(t, 0, status.Migrated)
+		err = service.DeleteCell(ctx, "cell-1")
+		assert.ErrorIs(t, err, types.ErrConflict)
+
+		profile, err := service.participantService.RetryProfile(ctx, "tenant-1", "participant-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, profile.VPAs[0].State)
+		manifests := sentManifests(service)
+		require.Len(t, manifests, 2)
+		assert.Equal(t, model.VPAMigrateType, manifests[1].OrchestrationType)
+		migrations := manifests[1].Payload[model.VPAMigrateData].([]model.VPAMigration)
+		require.Len(t, migrations, 1)
+		assert.Equal(t, "cell-1", migrations[0].SourceCellID)
+	})
+
+	t.Run("drain skips participants with operations in progress", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		profile, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		profile.VPAs[0].State = api.DeploymentStatePending
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		status, err := service.DrainCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.VPAs)
+		assert.Equal(t, 1, status.Remaining)
+		assert.Empty(t, sentManifests(service))
+
+		// Draining again once the operation completes migrates the remaining VPAs
+		profile.VPAs[0].State = api.DeploymentStateActive
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		status, err = service.DrainCell(ctx, "cell-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.VPAs)
+		assert.Equal(t, 1, status.Migrating)
+		assert.Equal(t, 0, status.Remaining)
+	})
+
+	t.Run("drain returns error when no cell is available", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+		_, err := service.CordonCell(ctx, "cell-2")
+		require.NoError(t, err)
+
+		_, err = service.DrainCell(ctx, "cell-1")
+
+		require.Error(t, err)
+		assert.Empty(t, sentManifests(service))
+		status, err := service.GetDrainStatus(ctx, "cell-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Remaining)
+	})
+
+	t.Run("drain non-existent cell returns not found", func(t *testing.T) {
+		service := newTestCellService()
+
+		_, err := service.DrainCell(ctx, "non-existent")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("drain status of cell that has not been drained returns not found", func(t *testing.T) {
+		service := newTestDrainCellService(t)
+
+		_, err := service.GetDrainStatus(ctx, "cell-1")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
 }
@@ -14,18 +14,26 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/collection"
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 )
 
 type dataspaceProfileService struct {
-	trxContext   store.TransactionContext
-	profileStore store.EntityStore[*api.DataspaceProfile]
-	cellStore    store.EntityStore[*api.Cell]
+	trxContext      store.TransactionContext
+	profileStore    store.EntityStore[*api.DataspaceProfile]
+	cellStore       store.EntityStore[*api.Cell]
+	vpaTypes        api.VPATypeRegistry
+	provisionClient api.ProvisionClient
 }
 
 func (d dataspaceProfileService) GetProfile(ctx context.Context, profileID string) (*api.DataspaceProfile, error) {
@@ -35,52 +43,164 @@ func (d dataspaceProfileService) GetProfile(ctx context.Context, profileID strin
 }
 
 func (d dataspaceProfileService) CreateProfile(ctx context.Context, profile *api.DataspaceProfile) (*api.DataspaceProfile, error) {
+	if err := validatePropertySchemas(profile.DataspaceSpec.PropertySchemas); err != nil {
+		return nil, err
+	}
+	if err := validateVPATopology(d.vpaTypes, profile.DataspaceSpec.VPATopology); err != nil {
+		return nil, err
+	}
 	return store.Trx[api.DataspaceProfile](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.DataspaceProfile, error) {
 		return d.profileStore.Create(ctx, profile)
 	})
 }
 
+// validateVPATopology verifies that a topology declares each registered VPA type at most once with a non-negative count.
+func validateVPATopology(vpaTypes api.VPATypeRegistry, topology []api.VPASpec) error {
+	declared := make(map[model.VPAType]bool, len(topology))
+	for _, spec := range topology {
+		if !vpaTypes.IsRegistered(spec.Type) {
+			return types.NewClientError("VPA type %s is not registered; registered types are %v", spec.Type, vpaTypes.Types())
+		}
+		if declared[spec.Type] {
+			return types.NewClientError("VPA type %s is declared more than once", spec.Type)
+		}
+		if spec.Count < 0 {
+			return types.NewClientError("invalid count for VPA type %s: %d", spec.Type, spec.Count)
+		}
+		declared[spec.Type] = true
+	}
+	return nil
+}
+
 func (t dataspaceProfileService) DeleteProfile(ctx context.Context, profileID string) error {
 	return t.trxContext.Execute(ctx, func(ctx context.Context) error {
+		profile, err := t.profileStore.FindByID(ctx, profileID)
+		if err != nil {
+			return err
+		}
+		if err = store.CheckExpectedVersion(ctx, profile); err != nil {
+			return err
+		}
 		return t.profileStore.Delete(ctx, profileID)
 	})
 }
 
-func (d dataspaceProfileService) DeployProfile(ctx context.Context, profileID string, cellID string) error {
-	return d.trxContext.Execute(ctx, func(ctx context.Context) error {
+func (d dataspaceProfileService) DeployProfile(ctx context.Context, profileID string, cellID string) (*api.DataspaceDeployment, error) {
+	return store.Trx[api.DataspaceDeployment](d.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.DataspaceDeployment, error) {
 		profile, err := d.profileStore.FindByID(ctx, profileID)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if err = store.CheckExpectedVersion(ctx, profile); err != nil {
+			return nil, err
 		}
 
 		cell, err := d.cellStore.FindByID(ctx, cellID)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		for _, deployment := range profile.Deployments {
+			if deployment.CellID == cellID && deployment.State != api.DeploymentStateDisposed {
+				return nil, types.NewRecoverableWrappedError(types.ErrConflict,
+					"dataspace profile %s is already deployed to cell %s in state %s", profileID, cellID, deployment.State)
+			}
 		}
 
-		// TODO validate not already deployed and handle deployment
-		profile.Deployments = append(profile.Deployments, api.DataspaceDeployment{
+		deployment := api.DataspaceDeployment{
 			DeployableEntity: api.DeployableEntity{
 				Entity: api.Entity{
 					ID:      uuid.New().String(),
 					Version: 0,
 				},
-				State:          api.DeploymentStateActive,
-				StateTimestamp: time.Time{}.UTC(),
+				State:          api.DeploymentStatePending,
+				StateTimestamp: time.Now().UTC(),
 			},
 			CellID:         cell.ID,
 			ExternalCellID: cell.ExternalID,
 			Properties:     make(map[string]any),
-		})
-		err = d.profileStore.Update(ctx, profile)
+		}
+		oManifest := newDataspaceManifest(profile, &deployment, model.DataspaceDeployType)
+
+		profile.Deployments = append(profile.Deployments, deployment)
+		if err = d.profileStore.Update(ctx, profile); err != nil {
+			return nil, fmt.Errorf("error deploying dataspace profile %s: %w", profileID, err)
+		}
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		if err = d.provisionClient.Send(ctx, oManifest); err != nil {
+			return nil, fmt.Errorf("error deploying dataspace profile %s: %w", profileID, err)
+		}
+		return &deployment, nil
+	})
+}
+
+func (d dataspaceProfileService) DisposeDeployment(ctx context.Context, profileID string, deploymentID string) error {
+	return d.trxContext.Execute(ctx, func(ctx context.Context) error {
+		profile, err := d.profileStore.FindByID(ctx, profileID)
 		if err != nil {
 			return err
 		}
-		return nil
+		if err = store.CheckExpectedVersion(ctx, profile); err != nil {
+			return err
+		}
+
+		index := slices.IndexFunc(profile.Deployments, func(deployment api.DataspaceDeployment) bool {
+			return deployment.ID == deploymentID
+		})
+		if index < 0 {
+			return types.ErrNotFound
+		}
 
+		deployment := &profile.Deployments[index]
+		if deployment.State != api.DeploymentStateActive && deployment.State != api.DeploymentStateError {
+			return types.NewRecoverableWrappedError(types.ErrConflict,
+				"cannot dispose deployment %s of dataspace profile %s in state %s", deploymentID, profileID, deployment.State)
+		}
+
+		deployment.State = api.DeploymentStateDisposing
+		deployment.StateTimestamp = time.Now().UTC()
+		deployment.ErrorDetail = ""
+		oManifest := newDataspaceManifest(profile, deployment, model.DataspaceDisposeType)
+		oManifest.Payload[model.DataspaceStateData] = deployment.Outputs
+
+		if err = d.profileStore.Update(ctx, profile); err != nil {
+			return fmt.Errorf("error disposing deployment %s of dataspace profile %s: %w", deploymentID, profileID, err)
+		}
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		if err = d.provisionClient.Send(ctx, oManifest); err != nil {
+			return fmt.Errorf("error disposing deployment %s of dataspace profile %s: %w", deploymentID, profileID, err)
+		}
+		return nil
 	})
 }
 
+// newDataspaceManifest creates an orchestration manifest for the deployment and records its ID on the deployment so
+// that the orchestration response can be correlated. The profile ID is used as the correlation ID.
+func newDataspaceManifest(
+	profile *api.DataspaceProfile,
+	deployment *api.DataspaceDeployment,
+	orchestrationType model.OrchestrationType) model.OrchestrationManifest {
+
+	oManifest := model.OrchestrationManifest{
+		ID:                uuid.New().String(),
+		CorrelationID:     profile.ID,
+		OrchestrationType: orchestrationType,
+		Payload:           make(map[string]any),
+	}
+	oManifest.Payload[model.DataspaceData] = model.DataspaceManifest{
+		ID:                 deployment.ID,
+		DataspaceProfileID: profile.ID,
+		CellID:             deployment.CellID,
+		ExternalCellID:     deployment.ExternalCellID,
+		Artifacts:          profile.Artifacts,
+		Properties:         deployment.Properties,
+	}
+	deployment.ManifestID = oManifest.ID
+	return oManifest
+}
+
 func (d dataspaceProfileService) ListProfiles(ctx context.Context) ([]api.DataspaceProfile, error) {
 	result := []api.DataspaceProfile{}
 	err := d.trxContext.Execute(ctx, func(ctx context.Context) error {
@@ -90,3 +210,67 @@ func (d dataspaceProfileService) ListProfiles(ctx context.Context) ([]api.Datasp
 	})
 	return result, err
 }
+
+type dataspaceCallbackHandler struct {
+	trxContext   store.TransactionContext
+	profileStore store.EntityStore[*api.DataspaceProfile]
+	monitor      system.LogMonitor
+}
+
+// handleDeploy activates the deployment and records the orchestration output values.
+func (h dataspaceCallbackHandler) handleDeploy(ctx context.Context, response model.OrchestrationResponse) error {
+	return h.handle(ctx, response, func(deployment *api.DataspaceDeployment) {
+		deployment.State = api.DeploymentStateActive
+		deployment.Outputs = response.Properties
+	})
+}
+
+func (h dataspaceCallbackHandler) handleDispose(ctx context.Context, response model.OrchestrationResponse) error {
+	return h.handle(ctx, response, func(deployment *api.DataspaceDeployment) {
+		deployment.State = api.DeploymentStateDisposed
+	})
+}
+
+// handle processes the asynchronous response to a dataspace profile deployment or dispose request. The deployment is
+// put into the error state if the orchestration failed; otherwise, the handler is applied.
+func (h dataspaceCallbackHandler) handle(
+	ctx context.Context,
+	response model.OrchestrationResponse,
+	handler func(deployment *api.DataspaceDeployment)) error {
+
+	return h.trxContext.Execute(ctx, func(c context.Context) error {
+		profile, err := h.profileStore.FindByID(c, response.CorrelationID)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				h.monitor.Infof("Dataspace profile '%s' not found for manifest %s", response.CorrelationID, response.ManifestID)
+				// Do not return error as this is fatal and the message must be acked
+				return nil
+			}
+			return fmt.Errorf("error retrieving dataspace profile %s for manifest %s: %w", response.CorrelationID, response.ManifestID, err)
+		}
+
+		index := slices.IndexFunc(profile.Deployments, func(deployment api.DataspaceDeployment) bool {
+			return deployment.ManifestID == response.ManifestID
+		})
+		if index < 0 {
+			// The deployment was superseded by a later operation
+			h.monitor.Infof("No deployment of dataspace profile '%s' found for manifest %s", response.CorrelationID, response.ManifestID)
+			return nil
+		}
+
+		deployment := &profile.Deployments[index]
+		if response.Success {
+			handler(deployment)
+			deployment.ErrorDetail = ""
+		} else {
+			deployment.State = api.DeploymentStateError
+			deployment.ErrorDetail = response.ErrorDetail
+		}
+		deployment.StateTimestamp = time.Now().UTC()
+
+		if err = h.profileStore.Update(c, profile); err != nil {
+			return fmt.Errorf("error updating dataspace profile %s processing response for manifest %s: %w", response.CorrelationID, response.ManifestID, err)
+		}
+		return nil
+	})
+}
@@ -14,15 +14,20 @@ package core
 
 import (
 	"context"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -131,6 +136,55 @@ func TestCreateDataspaceProfile(t *testing.T) {
 		require.NotNil(t, result)
 		assert.Equal(t, 1, len(result.Artifacts))
 	})
+
+	t.Run("create dataspace profile with VPA topology", func(t *testing.T) {
+		service := newTestDataspaceService()
+		service.vpaTypes.Register("test.issuerservice")
+		profile := newTestDataspaceProfile("profile-topology")
+		profile.DataspaceSpec.VPATopology = []api.VPASpec{
+			{Type: model.ConnectorType},
+			{Type: model.DataPlaneType, Count: 2},
+			{Type: "test.issuerservice", Count: 1},
+		}
+
+		result, err := service.CreateProfile(ctx, profile)
+
+		require.NoError(t, err)
+		assert.Len(t, result.DataspaceSpec.VPATopology, 3)
+	})
+
+	t.Run("create dataspace profile with invalid VPA topology returns client error", func(t *testing.T) {
+		service := newTestDataspaceService()
+		topologies := map[string][]api.VPASpec{
+			"unregistered type": {{Type: "test.unknown"}},
+			"duplicate type":    {{Type: model.ConnectorType}, {Type: model.ConnectorType}},
+			"negative count":    {{Type: model.DataPlaneType, Count: -1}},
+		}
+		for name, topology := range topologies {
+			profile := newTestDataspaceProfile("profile-" + strings.ReplaceAll(name, " ", "-"))
+			profile.DataspaceSpec.VPATopology = topology
+
+			_, err := service.CreateProfile(ctx, profile)
+
+			require.Error(t, err, name)
+			assert.True(t, types.IsClientError(err), name)
+		}
+	})
+
+	t.Run("create dataspace profile with invalid property schema returns client error", func(t *testing.T) {
+		service := newTestDataspaceService()
+		profile := newTestDataspaceProfile("profile-invalid")
+		profile.DataspaceSpec.PropertySchemas = &api.PropertySchemas{
+			Participant: map[string]any{"type": "no-such-type"},
+		}
+
+		_, err := service.CreateProfile(ctx, profile)
+
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+		_, err = service.profileStore.FindByID(ctx, "profile-invalid")
+		require.ErrorIs(t, err, types.ErrNotFound)
+	})
 }
 
 func TestDeleteDataspaceProfile(t *testing.T) {
@@ -161,6 +215,18 @@ func TestDeleteDataspaceProfile(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, types.ErrNotFound, err)
 	})
+
+	t.Run("delete dataspace profile at another version returns precondition failed", func(t *testing.T) {
+		service := newTestDataspaceService()
+		created, err := service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-delete-2"))
+		require.NoError(t, err)
+
+		err = service.DeleteProfile(store.WithExpectedVersion(ctx, "dataspace-delete-2", created.Version+1), "dataspace-delete-2")
+
+		assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+		_, err = service.GetProfile(ctx, "dataspace-delete-2")
+		require.NoError(t, err)
+	})
 }
 
 func TestDeployDataspaceProfile(t *testing.T) {
@@ -182,7 +248,7 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		require.NotNil(t, createdProfile)
 
 		// Deploy profile
-		err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
 
 		require.NoError(t, err)
 
@@ -193,7 +259,7 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		assert.Equal(t, 1, len(updated.Deployments))
 		assert.Equal(t, "cell-1", updated.Deployments[0].CellID)
 		assert.Equal(t, "external-id", updated.Deployments[0].ExternalCellID)
-		assert.Equal(t, api.DeploymentStateActive, updated.Deployments[0].State)
+		assert.Equal(t, api.DeploymentStatePending, updated.Deployments[0].State)
 	})
 
 	t.Run("deploy profile to non-existent cell returns error", func(t *testing.T) {
@@ -205,7 +271,7 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		require.NoError(t, err)
 
 		// Try to deploy to non-existent cell
-		err = service.DeployProfile(ctx, "dataspace-1", "non-existent-cell")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "non-existent-cell")
 
 		require.Error(t, err)
 		assert.Equal(t, types.ErrNotFound, err)
@@ -220,7 +286,7 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		require.NoError(t, err)
 
 		// Try to deploy non-existent profile
-		err = service.DeployProfile(ctx, "non-existent-profile", "cell-1")
+		_, err = service.DeployProfile(ctx, "non-existent-profile", "cell-1")
 
 		require.Error(t, err)
 		assert.Equal(t, types.ErrNotFound, err)
@@ -243,11 +309,11 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		require.NoError(t, err)
 
 		// Deploy to first cell
-		err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
 		require.NoError(t, err)
 
 		// Deploy to second cell
-		err = service.DeployProfile(ctx, "dataspace-1", "cell-2")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-2")
 		require.NoError(t, err)
 
 		// Verify both deployments exist
@@ -273,7 +339,7 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		require.NoError(t, err)
 
 		// Deploy profile
-		err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
 		require.NoError(t, err)
 
 		// Verify deployment metadata
@@ -285,9 +351,300 @@ func TestDeployDataspaceProfile(t *testing.T) {
 		deployment := updated.Deployments[0]
 		assert.NotEmpty(t, deployment.ID)
 		assert.Equal(t, int64(0), deployment.Version)
-		assert.Equal(t, api.DeploymentStateActive, deployment.State)
+		assert.Equal(t, api.DeploymentStatePending, deployment.State)
+		assert.False(t, deployment.StateTimestamp.IsZero())
+		assert.NotEmpty(t, deployment.ManifestID)
 		assert.NotNil(t, deployment.Properties)
 	})
+
+	t.Run("deploy profile at another version returns precondition failed", func(t *testing.T) {
+		service := newTestDataspaceService()
+		_, err := service.cellStore.Create(ctx, newTestCell("cell-1", "external-id"))
+		require.NoError(t, err)
+		created, err := service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-1"))
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(store.WithExpectedVersion(ctx, "dataspace-1", created.Version+1), "dataspace-1", "cell-1")
+
+		assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Empty(t, updated.Deployments)
+	})
+}
+
+func TestDeployDataspaceProfileOrchestration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deploy sends a deployment orchestration", func(t *testing.T) {
+		service := newTestDataspaceService()
+		provisionClient := new(mockProvisionClient)
+		provisionClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			dManifest := manifest.Payload[model.DataspaceData].(model.DataspaceManifest)
+			return manifest.OrchestrationType == model.DataspaceDeployType &&
+				manifest.CorrelationID == "dataspace-1" &&
+				dManifest.DataspaceProfileID == "dataspace-1" &&
+				dManifest.CellID == "cell-1" &&
+				dManifest.ExternalCellID == "external-id" &&
+				slices.Equal(dManifest.Artifacts, []string{"artifact-1"})
+		})).Return(nil)
+		service.provisionClient = provisionClient
+
+		_, err := service.cellStore.Create(ctx, newTestCell("cell-1", "external-id"))
+		require.NoError(t, err)
+		_, err = service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-1"))
+		require.NoError(t, err)
+
+		deployment, err := service.DeployProfile(ctx, "dataspace-1", "cell-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, deployment.State)
+		provisionClient.AssertExpectations(t)
+		manifest := provisionClient.Calls[0].Arguments.Get(1).(model.OrchestrationManifest)
+		assert.Equal(t, manifest.ID, deployment.ManifestID)
+		assert.Equal(t, deployment.ID, manifest.Payload[model.DataspaceData].(model.DataspaceManifest).ID)
+	})
+
+	t.Run("deploy rejects duplicate deployment to a cell", func(t *testing.T) {
+		service := newTestDataspaceService()
+		_, err := service.cellStore.Create(ctx, newTestCell("cell-1", "external-id"))
+		require.NoError(t, err)
+		_, err = service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-1"))
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrConflict)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Len(t, updated.Deployments, 1)
+	})
+
+	t.Run("deploy to a cell with a disposed deployment", func(t *testing.T) {
+		service := newTestDataspaceService()
+		_, err := service.cellStore.Create(ctx, newTestCell("cell-1", "external-id"))
+		require.NoError(t, err)
+		profile := newTestDataspaceProfile("dataspace-1")
+		profile.Deployments = []api.DataspaceDeployment{newTestDataspaceDeployment("deployment-1", api.DeploymentStateDisposed)}
+		_, err = service.profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+
+		require.NoError(t, err)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Len(t, updated.Deployments, 2)
+	})
+
+	t.Run("deploy returns error when the orchestration cannot be sent", func(t *testing.T) {
+		service := newTestDataspaceService()
+		provisionClient := new(mockProvisionClient)
+		provisionClient.On("Send", ctx, mock.Anything).Return(assert.AnError)
+		service.provisionClient = provisionClient
+
+		_, err := service.cellStore.Create(ctx, newTestCell("cell-1", "external-id"))
+		require.NoError(t, err)
+		_, err = service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-1"))
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestDisposeDataspaceDeployment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dispose sends a dispose orchestration", func(t *testing.T) {
+		service := newTestDataspaceService()
+		provisionClient := new(mockProvisionClient)
+		provisionClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			dManifest := manifest.Payload[model.DataspaceData].(model.DataspaceManifest)
+			return manifest.OrchestrationType == model.DataspaceDisposeType &&
+				dManifest.ID == "deployment-1" &&
+				manifest.Payload[model.DataspaceStateData].(api.Properties)["key"] == "value"
+		})).Return(nil)
+		service.provisionClient = provisionClient
+
+		profile := newTestDataspaceProfile("dataspace-1")
+		deployment := newTestDataspaceDeployment("deployment-1", api.DeploymentStateActive)
+		deployment.Outputs = api.Properties{"key": "value"}
+		profile.Deployments = []api.DataspaceDeployment{deployment}
+		_, err := service.profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		err = service.DisposeDeployment(ctx, "dataspace-1", "deployment-1")
+
+		require.NoError(t, err)
+		provisionClient.AssertExpectations(t)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateDisposing, updated.Deployments[0].State)
+		manifest := provisionClient.Calls[0].Arguments.Get(1).(model.OrchestrationManifest)
+		assert.Equal(t, manifest.ID, updated.Deployments[0].ManifestID)
+	})
+
+	t.Run("dispose deployment in the error state", func(t *testing.T) {
+		service := newTestDataspaceService()
+		profile := newTestDataspaceProfile("dataspace-1")
+		deployment := newTestDataspaceDeployment("deployment-1", api.DeploymentStateError)
+		deployment.ErrorDetail = "failed"
+		profile.Deployments = []api.DataspaceDeployment{deployment}
+		_, err := service.profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		err = service.DisposeDeployment(ctx, "dataspace-1", "deployment-1")
+
+		require.NoError(t, err)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateDisposing, updated.Deployments[0].State)
+		assert.Empty(t, updated.Deployments[0].ErrorDetail)
+	})
+
+	t.Run("dispose pending deployment returns conflict", func(t *testing.T) {
+		service := newTestDataspaceService()
+		profile := newTestDataspaceProfile("dataspace-1")
+		profile.Deployments = []api.DataspaceDeployment{newTestDataspaceDeployment("deployment-1", api.DeploymentStatePending)}
+		_, err := service.profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		err = service.DisposeDeployment(ctx, "dataspace-1", "deployment-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrConflict)
+	})
+
+	t.Run("dispose non-existent deployment returns not found", func(t *testing.T) {
+		service := newTestDataspaceService()
+		_, err := service.profileStore.Create(ctx, newTestDataspaceProfile("dataspace-1"))
+		require.NoError(t, err)
+
+		err = service.DisposeDeployment(ctx, "dataspace-1", "non-existent")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("dispose deployment of non-existent profile returns not found", func(t *testing.T) {
+		service := newTestDataspaceService()
+
+		err := service.DisposeDeployment(ctx, "non-existent", "deployment-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("dispose deployment of profile at another version returns precondition failed", func(t *testing.T) {
+		service := newTestDataspaceService()
+		profile := newTestDataspaceProfile("dataspace-1")
+		profile.Deployments = []api.DataspaceDeployment{newTestDataspaceDeployment("deployment-1", api.DeploymentStateActive)}
+		created, err := service.profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		err = service.DisposeDeployment(store.WithExpectedVersion(ctx, "dataspace-1", created.Version+1), "dataspace-1", "deployment-1")
+
+		assert.ErrorIs(t, err, types.ErrPreconditionFailed)
+		updated, err := service.GetProfile(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, updated.Deployments[0].State)
+	})
+}
+
+func TestDataspaceCallbackHandler(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, state api.DeploymentState) (dataspaceCallbackHandler, store.EntityStore[*api.DataspaceProfile]) {
+		profileStore := memorystore.NewInMemoryEntityStore[*api.DataspaceProfile]()
+		profile := newTestDataspaceProfile("dataspace-1")
+		deployment := newTestDataspaceDeployment("deployment-1", state)
+		deployment.ManifestID = "manifest-1"
+		profile.Deployments = []api.DataspaceDeployment{deployment}
+		_, err := profileStore.Create(ctx, profile)
+		require.NoError(t, err)
+		return dataspaceCallbackHandler{
+			trxContext:   store.NoOpTransactionContext{},
+			profileStore: profileStore,
+			monitor:      system.NoopMonitor{},
+		}, profileStore
+	}
+
+	response := func(success bool) model.OrchestrationResponse {
+		return model.OrchestrationResponse{
+			ID:            "response-1",
+			ManifestID:    "manifest-1",
+			CorrelationID: "dataspace-1",
+			Success:       success,
+			Properties:    map[string]any{"key": "value"},
+		}
+	}
+
+	t.Run("deploy response activates the deployment", func(t *testing.T) {
+		handler, profileStore := setup(t, api.DeploymentStatePending)
+
+		err := handler.handleDeploy(ctx, response(true))
+
+		require.NoError(t, err)
+		profile, err := profileStore.FindByID(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, profile.Deployments[0].State)
+		assert.Equal(t, "value", profile.Deployments[0].Outputs["key"])
+	})
+
+	t.Run("dispose response disposes the deployment", func(t *testing.T) {
+		handler, profileStore := setup(t, api.DeploymentStateDisposing)
+
+		err := handler.handleDispose(ctx, response(true))
+
+		require.NoError(t, err)
+		profile, err := profileStore.FindByID(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateDisposed, profile.Deployments[0].State)
+	})
+
+	t.Run("failed response puts the deployment into the error state", func(t *testing.T) {
+		handler, profileStore := setup(t, api.DeploymentStatePending)
+		failed := response(false)
+		failed.ErrorDetail = "deployment failed"
+
+		err := handler.handleDeploy(ctx, failed)
+
+		require.NoError(t, err)
+		profile, err := profileStore.FindByID(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateError, profile.Deployments[0].State)
+		assert.Equal(t, "deployment failed", profile.Deployments[0].ErrorDetail)
+	})
+
+	t.Run("response for unknown manifest is ignored", func(t *testing.T) {
+		handler, profileStore := setup(t, api.DeploymentStatePending)
+		unknown := response(true)
+		unknown.ManifestID = "manifest-2"
+
+		err := handler.handleDeploy(ctx, unknown)
+
+		require.NoError(t, err)
+		profile, err := profileStore.FindByID(ctx, "dataspace-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, profile.Deployments[0].State)
+	})
+
+	t.Run("response for non-existent profile is acknowledged", func(t *testing.T) {
+		handler, _ := setup(t, api.DeploymentStatePending)
+		unknown := response(true)
+		unknown.CorrelationID = "non-existent"
+
+		err := handler.handleDeploy(ctx, unknown)
+
+		require.NoError(t, err)
+	})
 }
 
 func TestListDataspaceProfiles(t *testing.T) {
@@ -362,7 +719,7 @@ func TestListDataspaceProfiles(t *testing.T) {
 		require.NoError(t, err)
 
 		// Deploy profile
-		err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
+		_, err = service.DeployProfile(ctx, "dataspace-1", "cell-1")
 		require.NoError(t, err)
 
 		results, err := service.ListProfiles(ctx)
@@ -391,10 +748,26 @@ func newTestDataspaceProfile(id string) *api.DataspaceProfile {
 	}
 }
 
+func newTestDataspaceDeployment(id string, state api.DeploymentState) api.DataspaceDeployment {
+	return api.DataspaceDeployment{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{ID: id},
+			State:  state,
+		},
+		CellID:         "cell-1",
+		ExternalCellID: "external-id",
+		Properties:     make(api.Properties),
+	}
+}
+
 func newTestDataspaceService() *dataspaceProfileService {
+	provisionClient := new(mockProvisionClient)
+	provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
 	return &dataspaceProfileService{
-		trxContext:   store.NoOpTransactionContext{},
-		profileStore: memorystore.NewInMemoryEntityStore[*api.DataspaceProfile](),
-		cellStore:    memorystore.NewInMemoryEntityStore[*api.Cell](),
+		trxContext:      store.NoOpTransactionContext{},
+		profileStore:    memorystore.NewInMemoryEntityStore[*api.DataspaceProfile](),
+		cellStore:       memorystore.NewInMemoryEntityStore[*api.Cell](),
+		vpaTypes:        newVPATypeRegistry(),
+		provisionClient: provisionClient,
 	}
 }
@@ -17,10 +17,15 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/collection"
+	"github.com/metaform/connector-fabric-manager/common/metrics"
 	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
@@ -30,10 +35,11 @@ import (
 )
 
 type participantService struct {
-	participantGenerator participantGenerator
+	participantGenerator *participantGenerator
 	provisionClient      api.ProvisionClient
 	trxContext           store.TransactionContext
 	participantStore     store.EntityStore[*api.ParticipantProfile]
+	tenantStore          store.EntityStore[*api.Tenant]
 	cellStore            store.EntityStore[*api.Cell]
 	dataspaceStore       store.EntityStore[*api.DataspaceProfile]
 	monitor              system.LogMonitor
@@ -73,17 +79,35 @@ func (p participantService) DeployProfile(
 	tenantID string,
 	deployment *api.NewParticipantProfileDeployment) (*api.ParticipantProfile, error) {
 
-	// TODO perform property validation against a custom schema
 	return store.Trx[api.ParticipantProfile](p.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.ParticipantProfile, error) {
+		tenant, err := p.tenantStore.FindByID(ctx, tenantID)
+		if err != nil && !errors.Is(err, types.ErrNotFound) {
+			return nil, err
+		}
+		if tenant != nil && tenant.Deletion != nil {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "tenant %s is being deleted", tenantID)
+		}
+
 		cells, err := collection.CollectAllDeref(p.cellStore.GetAll(ctx))
 		if err != nil {
 			return nil, err
 		}
+		if cells, err = filterAllowedCells(tenant, cells); err != nil {
+			return nil, err
+		}
 
 		dProfiles, err := p.getFilteredProfiles(ctx, deployment)
 		if err != nil {
 			return nil, err
 		}
+		if dProfiles, err = filterAllowedProfiles(tenant, deployment.DataspaceProfileIDs, dProfiles); err != nil {
+			return nil, err
+		}
+
+		vpaCounts, err := p.countVPAsByCell(ctx)
+		if err != nil {
+			return nil, err
+		}
 
 		participantProfile, err := p.participantGenerator.Generate(
 			deployment.Identifier,
@@ -91,12 +115,33 @@ func (p participantService) DeployProfile(
 			deployment.ParticipantRoles,
 			deployment.VPAProperties,
 			deployment.Properties,
+			deployment.Placement,
 			cells,
+			vpaCounts,
 			dProfiles)
 		if err != nil {
 			return nil, err
 		}
 
+		for vpaType := range deployment.VPAProperties {
+			if !slices.ContainsFunc(participantProfile.VPAs, func(vpa api.VirtualParticipantAgent) bool { return vpa.Type == vpaType }) {
+				return nil, types.NewClientError("the VPA topology of the dataspace profiles does not contain VPA type %s", vpaType)
+			}
+		}
+		if err = validateProfileProperties(dProfiles, participantProfile); err != nil {
+			return nil, err
+		}
+		usage, err := tenantUsage(ctx, p.participantStore, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkQuota(tenant, usage, participantProfile); err != nil {
+			return nil, err
+		}
+		if err = reserveQuota(ctx, p.tenantStore, tenant); err != nil {
+			return nil, err
+		}
+
 		oManifest := model.OrchestrationManifest{
 			ID:                uuid.New().String(),
 			CorrelationID:     participantProfile.ID,
@@ -107,7 +152,7 @@ func (p participantService) DeployProfile(
 		oManifest.Payload[model.ParticipantIdentifier] = participantProfile.Identifier
 
 		vpaManifests := make([]model.VPAManifest, 0, len(participantProfile.VPAs))
-		for _, vpa := range participantProfile.VPAs {
+		for i, vpa := range participantProfile.VPAs {
 			vpaManifest := model.VPAManifest{
 				ID:             vpa.ID,
 				VPAType:        vpa.Type,
@@ -116,6 +161,8 @@ func (p participantService) DeployProfile(
 				Properties:     vpa.Properties,
 			}
 			vpaManifests = append(vpaManifests, vpaManifest)
+			participantProfile.VPAs[i].Operation = model.VPADeployType
+			participantProfile.VPAs[i].ManifestID = oManifest.ID
 		}
 		oManifest.Payload[model.VPAData] = vpaManifests
 
@@ -127,12 +174,12 @@ func (p participantService) DeployProfile(
 			return nil, fmt.Errorf("error creating participant %s: %w", deployment.Identifier, err)
 		}
 
-		// Only send the orchestration message if the storage operation succeeded. If the send fails, the transaction
-		// will be rolled back.
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
 		err = p.provisionClient.Send(ctx, oManifest)
 		if err != nil {
 			return nil, fmt.Errorf("error deploying participant %s: %w", deployment.Identifier, err)
 		}
+		metrics.ParticipantDeployment(api.DeploymentStatePending.String())
 
 		return result, nil
 	})
@@ -167,6 +214,298 @@ func (p participantService) getFilteredProfiles(
 	return dProfiles, nil
 }
 
+// countVPAsByCell returns the number of VPAs that are not disposed in each cell, keyed by cell ID.
+func (p participantService) countVPAsByCell(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	for profile, err := range p.participantStore.GetAll(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		for _, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStateDisposed {
+				counts[vpa.CellID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// getProfilesByID returns the dataspace profiles with the given IDs, skipping profiles that no longer exist.
+func (p participantService) getProfilesByID(ctx context.Context, ids []string) ([]api.DataspaceProfile, error) {
+	dProfiles := make([]api.DataspaceProfile, 0, len(ids))
+	for _, id := range ids {
+		dProfile, err := p.dataspaceStore.FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		dProfiles = append(dProfiles, *dProfile)
+	}
+	return dProfiles, nil
+}
+
+// GetPropertySchema returns the effective JSON Schema that participant and VPA properties must conform to when a
+// profile is deployed to the given dataspace profiles. If no IDs are specified, all dataspace profiles are included.
+func (p participantService) GetPropertySchema(ctx context.Context, dataspaceProfileIDs []string) (map[string]any, error) {
+	var schema map[string]any
+	err := p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		dProfiles, err := p.getFilteredProfiles(ctx, &api.NewParticipantProfileDeployment{DataspaceProfileIDs: dataspaceProfileIDs})
+		if err != nil {
+			return types.NewRecoverableWrappedError(types.ErrNotFound, "dataspace profiles %v", dataspaceProfileIDs)
+		}
+		schema = effectivePropertySchema(dProfiles)
+		return nil
+	})
+	return schema, err
+}
+
+// UpdateProfile merges the update into the properties of a deployed profile. VPAs whose properties change transition to
+// pending and an update orchestration carrying the changes is sent. Changes to profile properties are not orchestrated.
+func (p participantService) UpdateProfile(
+	ctx context.Context,
+	tenantID string,
+	participantID string,
+	update *api.ParticipantProfileUpdate) (*api.ParticipantProfile, error) {
+
+	return store.Trx[api.ParticipantProfile](p.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.ParticipantProfile, error) {
+		profile, err := p.participantStore.FindByID(ctx, participantID)
+		if err != nil {
+			return nil, err
+		}
+		if profile.TenantID != tenantID {
+			return nil, types.ErrNotFound
+		}
+		if err = store.CheckExpectedVersion(ctx, profile); err != nil {
+			return nil, err
+		}
+		if _, found := update.Properties[model.VPAStateData]; found {
+			return nil, types.NewClientError("property %s cannot be updated", model.VPAStateData)
+		}
+		for vpaType := range update.VPAProperties {
+			if !slices.ContainsFunc(profile.VPAs, func(vpa api.VirtualParticipantAgent) bool { return vpa.Type == vpaType }) {
+				return nil, types.NewClientError("participant %s does not have a VPA of type %s", participantID, vpaType)
+			}
+		}
+		states := make([]string, 0, len(profile.VPAs))
+		for _, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStateActive {
+				states = append(states, vpa.ID+":"+vpa.State.String())
+			}
+		}
+		if len(states) > 0 {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "cannot update VPAs %s in states: %s", participantID, strings.Join(states, ","))
+		}
+		stateData, found := profile.Properties[model.VPAStateData]
+		if !found {
+			return nil, fmt.Errorf("profile is not deployed or is missing state data: %s", participantID)
+		}
+
+		manifestID := uuid.New().String()
+		diffs := make([]model.VPAPropertiesDiff, 0, len(update.VPAProperties))
+		for i, vpa := range profile.VPAs {
+			props, found := update.VPAProperties[vpa.Type]
+			if !found {
+				continue
+			}
+			if vpa.Properties == nil {
+				vpa.Properties = make(api.Properties)
+			}
+			changed, removed := mergeProperties(vpa.Properties, props)
+			if len(changed) == 0 && len(removed) == 0 {
+				continue
+			}
+			diffs = append(diffs, model.VPAPropertiesDiff{ID: vpa.ID, VPAType: vpa.Type, Changed: changed, Removed: removed})
+
+			vpa.State = api.DeploymentStatePending
+			vpa.StateTimestamp = time.Now().UTC()
+			vpa.Progress = nil
+			vpa.Operation = model.VPAUpdateType
+			vpa.ManifestID = manifestID
+			profile.VPAs[i] = vpa // Use range index because vpa is a copy
+		}
+		if profile.Properties == nil {
+			profile.Properties = make(api.Properties)
+		}
+		mergeProperties(profile.Properties, update.Properties)
+
+		dProfiles, err := p.getProfilesByID(ctx, profile.DataspaceProfileIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err = validateProfileProperties(dProfiles, profile); err != nil {
+			return nil, err
+		}
+
+		if err = p.participantStore.Update(ctx, profile); err != nil {
+			return nil, fmt.Errorf("error updating participant %s: %w", participantID, err)
+		}
+		if len(diffs) == 0 {
+			return profile, nil
+		}
+
+		oManifest := model.OrchestrationManifest{
+			ID:                manifestID,
+			CorrelationID:     participantID,
+			OrchestrationType: model.VPAUpdateType,
+			Payload:           make(map[string]any),
+		}
+
+		oManifest.Payload[model.ParticipantIdentifier] = profile.Identifier
+		oManifest.Payload[model.VPAStateData] = stateData
+
+		vpaManifests := make([]model.VPAManifest, 0, len(profile.VPAs))
+		for _, vpa := range profile.VPAs {
+			vpaManifests = append(vpaManifests, toVPAManifest(vpa))
+		}
+		oManifest.Payload[model.VPAData] = vpaManifests
+		oManifest.Payload[model.VPAUpdateData] = diffs
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		err = p.provisionClient.Send(ctx, oManifest)
+		if err != nil {
+			return nil, fmt.Errorf("error updating participant %s: %w", participantID, err)
+		}
+		metrics.ParticipantDeployment(api.DeploymentStatePending.String())
+
+		return profile, nil
+	})
+}
+
+// RetryProfile retries the failed operation of a participant profile by sending a new manifest for the VPAs in the
+// error state, correlated to the same profile. The profile error is cleared and the VPAs transition back to pending or
+// disposing, so that another retry is rejected until the retried operation has failed again.
+func (p participantService) RetryProfile(ctx context.Context, tenantID string, participantID string) (*api.ParticipantProfile, error) {
+	return store.Trx[api.ParticipantProfile](p.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.ParticipantProfile, error) {
+		profile, err := p.participantStore.FindByID(ctx, participantID)
+		if err != nil {
+			return nil, err
+		}
+		if profile.TenantID != tenantID {
+			return nil, types.ErrNotFound
+		}
+		if err = store.CheckExpectedVersion(ctx, profile); err != nil {
+			return nil, err
+		}
+		if !profile.Error {
+			return nil, types.NewRecoverableWrappedError(types.ErrConflict, "participant %s is not in the error state", participantID)
+		}
+
+		var operation model.OrchestrationType
+		failed := make([]int, 0, len(profile.VPAs))
+		for i, vpa := range profile.VPAs {
+			switch vpa.State {
+			case api.DeploymentStatePending, api.DeploymentStateDisposing:
+				return nil, types.NewRecoverableWrappedError(types.ErrConflict, "participant %s has VPAs in progress", participantID)
+			case api.DeploymentStateError:
+				if operation != "" && vpa.Operation != operation {
+					return nil, types.NewClientError("participant %s has VPAs that failed in different operations", participantID)
+				}
+				operation = vpa.Operation
+				failed = append(failed, i)
+			}
+		}
+		if len(failed) == 0 {
+			return nil, types.NewClientError("participant %s has no failed VPAs to retry", participantID)
+		}
+		if _, deployed := profile.Properties[model.VPAStateData]; operation == "" && !deployed {
+			// The operation is not recorded for VPAs created by previous versions
+			operation = model.VPADeployType
+		}
+
+		oManifest, err := p.operationManifest(ctx, profile, operation, failed)
+		if err != nil {
+			return nil, err
+		}
+
+		state := api.DeploymentStatePending
+		if operation == model.VPADisposeType {
+			state = api.DeploymentStateDisposing
+		}
+		now := time.Now().UTC()
+		for _, i := range failed {
+			profile.VPAs[i].State = state
+			profile.VPAs[i].StateTimestamp = now
+			profile.VPAs[i].ErrorDetail = ""
+			profile.VPAs[i].Progress = nil
+			profile.VPAs[i].ManifestID = oManifest.ID
+		}
+		profile.Error = false
+		profile.ErrorDetail = ""
+
+		if err = p.participantStore.Update(ctx, profile); err != nil {
+			return nil, fmt.Errorf("error retrying participant %s: %w", participantID, err)
+		}
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		if err = p.provisionClient.Send(ctx, *oManifest); err != nil {
+			return nil, fmt.Errorf("error retrying participant %s: %w", participantID, err)
+		}
+		metrics.ParticipantDeployment(state.String())
+
+		return profile, nil
+	})
+}
+
+// operationManifest creates the manifest that performs the operation on the VPAs of the profile at the given indices.
+// Updates send all properties of the VPAs as changed, since the changes of a previous update are not retained.
+func (p participantService) operationManifest(
+	ctx context.Context,
+	profile *api.ParticipantProfile,
+	operation model.OrchestrationType,
+	indices []int) (*model.OrchestrationManifest, error) {
+
+	oManifest := &model.OrchestrationManifest{
+		ID:                uuid.New().String(),
+		CorrelationID:     profile.ID,
+		OrchestrationType: operation,
+		Payload:           make(map[string]any),
+	}
+	oManifest.Payload[model.ParticipantIdentifier] = profile.Identifier
+
+	switch operation {
+	case model.VPADeployType:
+		dProfiles, err := p.getFilteredProfiles(ctx, &api.NewParticipantProfileDeployment{DataspaceProfileIDs: profile.DataspaceProfileIDs})
+		if err != nil {
+			return nil, err
+		}
+		oManifest.Payload[model.CredentialData] = generateCredentialSpecs(profile.ParticipantRoles, dProfiles)
+	case model.VPAUpdateType, model.VPADisposeType, model.VPAMigrateType:
+		stateData, found := profile.Properties[model.VPAStateData]
+		if !found {
+			return nil, fmt.Errorf("profile is not deployed or is missing state data: %s", profile.ID)
+		}
+		oManifest.Payload[model.VPAStateData] = stateData
+		switch operation {
+		case model.VPAUpdateType:
+			diffs := make([]model.VPAPropertiesDiff, 0, len(indices))
+			for _, i := range indices {
+				vpa := profile.VPAs[i]
+				diffs = append(diffs, model.VPAPropertiesDiff{ID: vpa.ID, VPAType: vpa.Type, Changed: vpa.Properties})
+			}
+			oManifest.Payload[model.VPAUpdateData] = diffs
+		case model.VPAMigrateType:
+			migrations := make([]model.VPAMigration, 0, len(indices))
+			for _, i := range indices {
+				if migration := toVPAMigration(profile.VPAs[i]); migration != nil {
+					migrations = append(migrations, *migration)
+				}
+			}
+			oManifest.Payload[model.VPAMigrateData] = migrations
+		}
+	default:
+		return nil, types.NewClientError("participant %s cannot be retried: unknown operation '%s'", profile.ID, operation)
+	}
+
+	vpaManifests := make([]model.VPAManifest, 0, len(indices))
+	for _, i := range indices {
+		vpaManifests = append(vpaManifests, toVPAManifest(profile.VPAs[i]))
+	}
+	oManifest.Payload[model.VPAData] = vpaManifests
+	return oManifest, nil
+}
+
 func (p participantService) DisposeProfile(ctx context.Context, tenantID string, participantID string) error {
 	return p.trxContext.Execute(ctx, func(c context.Context) error {
 		profile, err := p.participantStore.FindByID(c, participantID)
@@ -176,6 +515,9 @@ func (p participantService) DisposeProfile(ctx context.Context, tenantID string,
 		if profile.TenantID != tenantID {
 			return types.ErrNotFound
 		}
+		if err = store.CheckExpectedVersion(c, profile); err != nil {
+			return err
+		}
 		states := make([]string, 0, len(profile.VPAs))
 		for _, vpa := range profile.VPAs {
 			if vpa.State != api.DeploymentStateActive {
@@ -213,6 +555,8 @@ func (p participantService) DisposeProfile(ctx context.Context, tenantID string,
 
 			// Set to disposing - updates the slice element
 			profile.VPAs[i].State = api.DeploymentStateDisposing
+			profile.VPAs[i].Operation = model.VPADisposeType
+			profile.VPAs[i].ManifestID = oManifest.ID
 		}
 
 		oManifest.Payload[model.VPAData] = vpaManifests
@@ -222,17 +566,146 @@ func (p participantService) DisposeProfile(ctx context.Context, tenantID string,
 			return fmt.Errorf("error disposing participant %s: %w", participantID, err)
 		}
 
-		// Only send the orchestration message if the storage operation succeeded. If the send fails, the transaction
-		// will be rolled back.
-		err = p.provisionClient.Send(ctx, oManifest)
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		err = p.provisionClient.Send(c, oManifest)
 		if err != nil {
 			return fmt.Errorf("error disposing participant %s: %w", participantID, err)
 		}
+		metrics.ParticipantDeployment(api.DeploymentStateDisposing.String())
 
 		return nil
 	})
 }
 
+// migrateVPAs migrates the VPAs of the participant placed in the source cell to cells selected from the given cells and
+// sends a migrate orchestration. The VPA counts are updated with the VPAs placed in the target cells. Returns the number
+// of VPAs being migrated, or types.ErrConflict if the participant has an operation in progress.
+func (p participantService) migrateVPAs(
+	ctx context.Context,
+	participantID string,
+	sourceCellID string,
+	cells []api.Cell,
+	vpaCounts map[string]int) (int, error) {
+
+	var count int
+	err := p.trxContext.Execute(ctx, func(ctx context.Context) error {
+		profile, err := p.participantStore.FindByID(ctx, participantID)
+		if err != nil {
+			return err
+		}
+
+		vpaTypes := make([]model.VPAType, 0, len(profile.VPAs))
+		typeCounts := make(map[model.VPAType]int)
+		for _, vpa := range profile.VPAs {
+			switch {
+			case vpa.State == api.DeploymentStateDisposed:
+				continue
+			case !isMigratable(vpa):
+				return types.NewRecoverableWrappedError(types.ErrConflict, "participant %s has VPAs that are not active", participantID)
+			case vpa.CellID == sourceCellID:
+				if typeCounts[vpa.Type] == 0 {
+					vpaTypes = append(vpaTypes, vpa.Type)
+				}
+				typeCounts[vpa.Type]++
+			}
+		}
+		if len(vpaTypes) == 0 {
+			return nil
+		}
+		stateData, found := profile.Properties[model.VPAStateData]
+		if !found {
+			return fmt.Errorf("profile is not deployed or is missing state data: %s", participantID)
+		}
+
+		dProfiles, err := p.getProfilesByID(ctx, profile.DataspaceProfileIDs)
+		if err != nil {
+			return err
+		}
+
+		tenant, err := p.tenantStore.FindByID(ctx, profile.TenantID)
+		if err != nil && !errors.Is(err, types.ErrNotFound) {
+			return err
+		}
+		targets := slices.DeleteFunc(slices.Clone(cells), func(cell api.Cell) bool { return cell.ID == sourceCellID })
+		if targets, err = filterAllowedCells(tenant, targets); err != nil {
+			return err
+		}
+
+		manifestID := uuid.New().String()
+		now := time.Now().UTC()
+		for _, vpaType := range vpaTypes {
+			cell, selection, err := p.participantGenerator.CellSelector(api.CellSelectionRequest{
+				OrchestrationType: model.VPAMigrateType,
+				VPAType:           vpaType,
+				Cells:             targets,
+				DataspaceProfiles: dProfiles,
+				VPACounts:         maps.Clone(vpaCounts),
+				VPACount:          typeCounts[vpaType],
+			})
+			if err != nil {
+				return fmt.Errorf("error selecting cell to migrate VPA type %s of participant %s: %w", vpaType, participantID, err)
+			}
+			vpaCounts[cell.ID] += typeCounts[vpaType]
+
+			for i, vpa := range profile.VPAs {
+				if vpa.Type != vpaType || vpa.CellID != sourceCellID || !isMigratable(vpa) {
+					continue
+				}
+				vpaSelection := selection
+				vpa.Migration = &api.VPAMigration{
+					SourceCellID:         vpa.CellID,
+					SourceExternalCellID: vpa.ExternalCellID,
+					StartTimestamp:       now,
+				}
+				vpa.CellID = cell.ID
+				vpa.ExternalCellID = cell.ExternalID
+				vpa.CellSelection = &vpaSelection
+				vpa.State = api.DeploymentStatePending
+				vpa.StateTimestamp = now
+				vpa.Progress = nil
+				vpa.Operation = model.VPAMigrateType
+				vpa.ManifestID = manifestID
+				profile.VPAs[i] = vpa // Use range index because vpa is a copy
+			}
+		}
+
+		oManifest := model.OrchestrationManifest{
+			ID:                manifestID,
+			CorrelationID:     participantID,
+			OrchestrationType: model.VPAMigrateType,
+			Payload:           make(map[string]any),
+		}
+		oManifest.Payload[model.ParticipantIdentifier] = profile.Identifier
+		oManifest.Payload[model.VPAStateData] = stateData
+
+		vpaManifests := make([]model.VPAManifest, 0, len(profile.VPAs))
+		migrations := make([]model.VPAMigration, 0, len(profile.VPAs))
+		for _, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStatePending {
+				continue
+			}
+			vpaManifests = append(vpaManifests, toVPAManifest(vpa))
+			migrations = append(migrations, *toVPAMigration(vpa))
+		}
+		oManifest.Payload[model.VPAData] = vpaManifests
+		oManifest.Payload[model.VPAMigrateData] = migrations
+
+		if err = p.participantStore.Update(ctx, profile); err != nil {
+			return fmt.Errorf("error migrating participant %s: %w", participantID, err)
+		}
+
+		// Record the manifest in the outbox within this transaction. The outbox relay publishes it after commit.
+		if err = p.provisionClient.Send(ctx, oManifest); err != nil {
+			return fmt.Errorf("error migrating participant %s: %w", participantID, err)
+		}
+		metrics.ParticipantDeployment(api.DeploymentStatePending.String())
+
+		count = len(vpaManifests)
+		return nil
+	})
+	return count, err
+}
+
 // executeStoreIterator wraps store iterator operations in a transaction context
 func (p participantService) executeStoreIterator(ctx context.Context, storeOp func(context.Context) iter.Seq2[*api.ParticipantProfile, error]) iter.Seq2[*api.ParticipantProfile, error] {
 	return func(yield func(*api.ParticipantProfile, error) bool) {
@@ -250,6 +723,57 @@ func (p participantService) executeStoreIterator(ctx context.Context, storeOp fu
 	}
 }
 
+func toVPAManifest(vpa api.VirtualParticipantAgent) model.VPAManifest {
+	return model.VPAManifest{
+		ID:             vpa.ID,
+		VPAType:        vpa.Type,
+		CellID:         vpa.CellID,
+		ExternalCellID: vpa.ExternalCellID,
+		Properties:     vpa.Properties,
+	}
+}
+
+// isMigratable returns true if the VPA has no operation in progress. VPAs placed in offline cells can be migrated to
+// recover from a cell outage.
+func isMigratable(vpa api.VirtualParticipantAgent) bool {
+	return vpa.State == api.DeploymentStateActive || vpa.State == api.DeploymentStateOffline
+}
+
+func toVPAMigration(vpa api.VirtualParticipantAgent) *model.VPAMigration {
+	if vpa.Migration == nil {
+		return nil
+	}
+	return &model.VPAMigration{
+		ID:                   vpa.ID,
+		VPAType:              vpa.Type,
+		SourceCellID:         vpa.Migration.SourceCellID,
+		SourceExternalCellID: vpa.Migration.SourceExternalCellID,
+	}
+}
+
+// mergeProperties merges the changes into the target properties, removing properties whose value is nil. The properties
+// that differ from their previous values and the removed properties are returned.
+func mergeProperties(target api.Properties, changes map[string]any) (map[string]any, []string) {
+	changed := make(map[string]any)
+	removed := make([]string, 0)
+	for key, value := range changes {
+		current, found := target[key]
+		if value == nil {
+			if found {
+				delete(target, key)
+				removed = append(removed, key)
+			}
+			continue
+		}
+		if !found || !reflect.DeepEqual(current, value) {
+			target[key] = value
+			changed[key] = value
+		}
+	}
+	slices.Sort(removed)
+	return changed, removed
+}
+
 func generateCredentialSpecs(
 	participantRoles map[string][]string,
 	dProfiles []api.DataspaceProfile) []model.CredentialSpec {
@@ -278,14 +802,19 @@ func generateCredentialSpecs(
 
 type vpaCallbackHandler struct {
 	participantStore store.EntityStore[*api.ParticipantProfile]
+	tenantStore      store.EntityStore[*api.Tenant]
 	trxContext       store.TransactionContext
 	monitor          system.LogMonitor
 }
 
 func (h vpaCallbackHandler) handleDeploy(ctx context.Context, response model.OrchestrationResponse) error {
-	return h.handle(ctx, response, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
-		// Place all output values under VPStateData key
-		vpaProps := make(map[string]any)
+	return h.handle(ctx, response, api.DeploymentStateActive, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
+		// Place all output values under VPStateData key. Values from a previous attempt are retained if a deployment is
+		// retried.
+		vpaProps, ok := profile.Properties[model.VPAStateData].(map[string]any)
+		if !ok {
+			vpaProps = make(map[string]any)
+		}
 		for key, value := range resp.Properties {
 			vpaProps[key] = value
 		}
@@ -293,27 +822,155 @@ func (h vpaCallbackHandler) handleDeploy(ctx context.Context, response model.Orc
 
 		for i, vpa := range profile.VPAs {
 			vpa.State = api.DeploymentStateActive
-			// TODO update timestamp based on returned data
+			vpa.Progress = nil
+			profile.VPAs[i] = vpa // Use range index because vpa is a copy
+		}
+	})
+}
+
+// handleUpdate activates the VPAs updated by the orchestration and merges its output values into the VPA state data.
+func (h vpaCallbackHandler) handleUpdate(ctx context.Context, response model.OrchestrationResponse) error {
+	return h.handle(ctx, response, api.DeploymentStateActive, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
+		vpaProps, ok := profile.Properties[model.VPAStateData].(map[string]any)
+		if !ok {
+			vpaProps = make(map[string]any)
+		}
+		for key, value := range resp.Properties {
+			vpaProps[key] = value
+		}
+		profile.Properties[model.VPAStateData] = vpaProps
+
+		for i, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStatePending {
+				continue
+			}
+			vpa.State = api.DeploymentStateActive
+			vpa.Progress = nil
+			profile.VPAs[i] = vpa // Use range index because vpa is a copy
+		}
+	})
+}
+
+// handleMigrate activates the VPAs migrated by the orchestration in their target cells and merges its output values into
+// the VPA state data.
+func (h vpaCallbackHandler) handleMigrate(ctx context.Context, response model.OrchestrationResponse) error {
+	return h.handle(ctx, response, api.DeploymentStateActive, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
+		vpaProps, ok := profile.Properties[model.VPAStateData].(map[string]any)
+		if !ok {
+			vpaProps = make(map[string]any)
+		}
+		for key, value := range resp.Properties {
+			vpaProps[key] = value
+		}
+		profile.Properties[model.VPAStateData] = vpaProps
+
+		for i, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStatePending || vpa.Migration == nil {
+				continue
+			}
+			vpa.State = api.DeploymentStateActive
+			vpa.Migration = nil
+			vpa.Progress = nil
 			profile.VPAs[i] = vpa // Use range index because vpa is a copy
 		}
 	})
 }
 
 func (h vpaCallbackHandler) handleDispose(ctx context.Context, response model.OrchestrationResponse) error {
-	return h.handle(ctx, response, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
+	err := h.handle(ctx, response, api.DeploymentStateDisposed, func(profile *api.ParticipantProfile, resp model.OrchestrationResponse) {
 		for i, vpa := range profile.VPAs {
 			// Update state
 			vpa.State = api.DeploymentStateDisposed
-			// TODO update timestamp based on returned data
+			vpa.Progress = nil
 			profile.VPAs[i] = vpa // Use range index because vpa is a copy
 		}
 	})
+	if err != nil {
+		return err
+	}
+	return h.trxContext.Execute(ctx, func(ctx context.Context) error {
+		// The participant is deleted if its tenant is being deleted. A failure to do so is retried when the response is
+		// redelivered, since processing the response is idempotent.
+		profile, err := h.participantStore.FindByID(ctx, response.CorrelationID)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		deleted, err := completeTenantDeletion(ctx, h.tenantStore, h.participantStore, profile.TenantID)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			h.monitor.Infof("Deleted tenant %s", profile.TenantID)
+		}
+		return nil
+	})
+}
+
+// handleProgress records the progress reported by the provision manager on the VPAs that are being deployed or disposed.
+// Each VPA is assigned its own completion percentage. The reporting activity and message are only recorded on the VPA
+// the progress was reported for, or on all VPAs if it was not reported for a specific VPA.
+func (h vpaCallbackHandler) handleProgress(ctx context.Context, progress model.OrchestrationProgress) error {
+	return h.trxContext.Execute(ctx, func(c context.Context) error {
+		profile, err := h.participantStore.FindByID(c, progress.CorrelationID)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				// Progress is informational, drop it
+				return nil
+			}
+			return fmt.Errorf("error retrieving participant profile %s for progress: %w", progress.CorrelationID, err)
+		}
+
+		updated := false
+		for i, vpa := range profile.VPAs {
+			if vpa.State != api.DeploymentStatePending && vpa.State != api.DeploymentStateDisposing {
+				continue
+			}
+			if vpa.Progress != nil && vpa.Progress.Timestamp.After(progress.Timestamp) {
+				continue // Progress messages may arrive out of order
+			}
+			percent, found := progress.VPAPercents[vpa.ID]
+			if !found {
+				percent = progress.OrchestrationPercent
+			}
+			if progress.VPAID == "" || progress.VPAID == vpa.ID {
+				profile.VPAs[i].Progress = &api.DeploymentProgress{
+					Percent:      percent,
+					Message:      progress.Message,
+					ActivityID:   progress.ActivityID,
+					ActivityType: progress.ActivityType,
+					Timestamp:    progress.Timestamp,
+				}
+			} else {
+				vpaProgress := api.DeploymentProgress{}
+				if vpa.Progress != nil {
+					vpaProgress = *vpa.Progress
+				}
+				vpaProgress.Percent = percent
+				vpaProgress.Timestamp = progress.Timestamp
+				profile.VPAs[i].Progress = &vpaProgress
+			}
+			updated = true
+		}
+		if !updated {
+			return nil
+		}
+		if err = h.participantStore.Update(c, profile); err != nil {
+			return fmt.Errorf("error updating participant profile %s processing progress for manifest %s: %w", progress.CorrelationID, progress.ManifestID, err)
+		}
+		return nil
+	})
 }
 
-// handle processes the asynchronous response to participant VPA deployment request.
+// handle processes the asynchronous response to participant VPA deployment request. The state is the deployment state
+// the VPAs transition to when the response is successful. Results reported for individual VPAs are applied after the
+// response is processed, so that a VPA that failed is put into the error state even if other VPAs succeeded.
 func (h vpaCallbackHandler) handle(
 	ctx context.Context,
 	response model.OrchestrationResponse,
+	state api.DeploymentState,
 	handler func(profile *api.ParticipantProfile, resp model.OrchestrationResponse)) error {
 
 	return h.trxContext.Execute(ctx, func(c context.Context) error {
@@ -324,18 +981,76 @@ func (h vpaCallbackHandler) handle(
 			// Do not return error as this is fatal and the message must be acked
 			return nil
 		}
-		switch {
-		case response.Success:
+		previous := make(map[string]api.DeploymentState, len(profile.VPAs))
+		for _, vpa := range profile.VPAs {
+			previous[vpa.ID] = vpa.State
+		}
+
+		if response.Success {
 			handler(profile, response)
-		default:
-			// TODO update VPA status
+		}
+		failures := applyVPAResults(profile, response, state)
+
+		now := time.Now().UTC()
+		for i, vpa := range profile.VPAs {
+			_, reported := response.VPAResults[vpa.ID]
+			if vpa.State != previous[vpa.ID] && (!reported || response.VPAResults[vpa.ID].Timestamp.IsZero()) {
+				profile.VPAs[i].StateTimestamp = now
+			}
+		}
+
+		switch {
+		case !response.Success:
 			profile.Error = true
 			profile.ErrorDetail = response.ErrorDetail
+			state = api.DeploymentStateError
+		case len(failures) > 0:
+			profile.Error = true
+			profile.ErrorDetail = strings.Join(failures, "; ")
+			state = api.DeploymentStateError
 		}
 		err = h.participantStore.Update(c, profile)
 		if err != nil {
 			return fmt.Errorf("error updating participant profile %s processing VPA response for manifest %s: %w", response.CorrelationID, response.ManifestID, err)
 		}
+		metrics.ParticipantDeployment(state.String())
 		return nil
 	})
 }
+
+// applyVPAResults updates the VPAs with the results reported for them. VPAs that succeeded transition to the given
+// state and VPAs that failed to the error state. If the orchestration failed, VPAs that are being deployed or disposed
+// and have no result are put into the error state. Returns a description of each failed VPA.
+func applyVPAResults(profile *api.ParticipantProfile, response model.OrchestrationResponse, state api.DeploymentState) []string {
+	failures := make([]string, 0)
+	for i, vpa := range profile.VPAs {
+		result, found := response.VPAResults[vpa.ID]
+		switch {
+		case found:
+			if result.Outputs != nil {
+				vpa.Outputs = result.Outputs
+			}
+			if !result.Timestamp.IsZero() {
+				vpa.StateTimestamp = result.Timestamp.UTC()
+			}
+			vpa.Progress = nil
+			if result.State == model.VPAResultFailed {
+				vpa.State = api.DeploymentStateError
+				vpa.ErrorDetail = result.Error
+				failures = append(failures, fmt.Sprintf("%s %s: %s", vpa.Type, vpa.ID, result.Error))
+			} else {
+				vpa.State = state
+				vpa.ErrorDetail = ""
+				vpa.Migration = nil
+			}
+		case !response.Success && (vpa.State == api.DeploymentStatePending || vpa.State == api.DeploymentStateDisposing):
+			vpa.State = api.DeploymentStateError
+			vpa.ErrorDetail = response.ErrorDetail
+			vpa.Progress = nil
+		default:
+			continue
+		}
+		profile.VPAs[i] = vpa // Use range index because vpa is a copy
+	}
+	return failures
+}
@@ -197,6 +197,112 @@ func TestDeployProfile(t *testing.T) {
 		assert.NotEmpty(t, result.ID)
 		assert.Equal(t, "tenant-1", result.TenantID)
 		assert.Equal(t, "participant-identifier", result.Identifier)
+		for _, vpa := range result.VPAs {
+			assert.Equal(t, &api.CellSelection{Strategy: "test", Reason: "first cell"}, vpa.CellSelection)
+		}
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("deploy passes placement and cell VPA counts to the cell selector", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.Anything).Return(nil)
+		service.provisionClient = mockClient
+
+		var received []api.CellSelectionRequest
+		service.participantGenerator.CellSelector = func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+			received = append(received, request)
+			return &request.Cells[0], api.CellSelection{Strategy: "test"}, nil
+		}
+
+		cell := newTestCell("cell-1", "external-id")
+		cell.State = api.DeploymentStateActive
+		_, err := service.cellStore.Create(ctx, cell)
+		require.NoError(t, err)
+		_, err = service.dataspaceStore.Create(ctx, newTestDataspaceProfile("ds-1"))
+		require.NoError(t, err)
+
+		existing := newTestParticipantProfile("tenant-1", "participant-1")
+		existing.VPAs = append(existing.VPAs, existing.VPAs[0])
+		existing.VPAs[1].State = api.DeploymentStateDisposed
+		_, err = service.participantStore.Create(ctx, existing)
+		require.NoError(t, err)
+
+		placement := api.ParticipantPlacement{
+			CellPlacement: api.CellPlacement{RequiredLabels: map[string]string{"region": "eu"}},
+			VPAs: map[model.VPAType]api.CellPlacement{
+				model.DataPlaneType: {RequiredLabels: map[string]string{"tier": "edge"}},
+			},
+		}
+		_, err = service.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+			Identifier: "participant-identifier",
+			Placement:  placement,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, received, 3)
+		for i, vpaType := range []model.VPAType{model.ConnectorType, model.CredentialServiceType, model.DataPlaneType} {
+			assert.Equal(t, vpaType, received[i].VPAType)
+			assert.Equal(t, 1, received[i].VPACount)
+			assert.Equal(t, map[string]int{"cell-1": 1 + i}, received[i].VPACounts)
+		}
+		assert.Equal(t, api.CellPlacement{RequiredLabels: map[string]string{"region": "eu"}}, received[0].Placement)
+		assert.Equal(t, api.CellPlacement{
+			RequiredLabels: map[string]string{"region": "eu", "tier": "edge"},
+		}, received[2].Placement)
+	})
+
+	t.Run("deploy places VPAs of a participant in different cells", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			cells := make(map[model.VPAType]string)
+			for _, vpaManifest := range manifest.Payload[model.VPAData].([]model.VPAManifest) {
+				cells[vpaManifest.VPAType] = vpaManifest.ExternalCellID
+			}
+			return cells[model.ConnectorType] == "external-core" && cells[model.DataPlaneType] == "external-edge"
+		})).Return(nil)
+		service.provisionClient = mockClient
+		selector, err := newCellSelector(labelsStrategy)
+		require.NoError(t, err)
+		service.participantGenerator.CellSelector = selector
+
+		coreCell := newTestCell("cell-core", "external-core")
+		coreCell.State = api.DeploymentStateActive
+		coreCell.Properties = api.Properties{api.CellLabelsProperty: map[string]any{"tier": "core"}}
+		edgeCell := newTestCell("cell-edge", "external-edge")
+		edgeCell.State = api.DeploymentStateActive
+		edgeCell.Properties = api.Properties{api.CellLabelsProperty: map[string]any{"tier": "edge"}}
+		for _, cell := range []*api.Cell{coreCell, edgeCell} {
+			_, err = service.cellStore.Create(ctx, cell)
+			require.NoError(t, err)
+		}
+		dProfile := newTestDataspaceProfile("ds-1")
+		dProfile.Deployments = []api.DataspaceDeployment{
+			{CellID: "cell-core", DeployableEntity: api.DeployableEntity{State: api.DeploymentStateActive}},
+			{CellID: "cell-edge", DeployableEntity: api.DeployableEntity{State: api.DeploymentStateActive}},
+		}
+		_, err = service.dataspaceStore.Create(ctx, dProfile)
+		require.NoError(t, err)
+
+		result, err := service.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+			Identifier: "participant-identifier",
+			Placement: api.ParticipantPlacement{
+				CellPlacement: api.CellPlacement{RequiredLabels: map[string]string{"tier": "core"}},
+				VPAs: map[model.VPAType]api.CellPlacement{
+					model.DataPlaneType: {RequiredLabels: map[string]string{"tier": "edge"}},
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		cells := make(map[model.VPAType]string)
+		for _, vpa := range result.VPAs {
+			cells[vpa.Type] = vpa.CellID
+		}
+		assert.Equal(t, "cell-core", cells[model.ConnectorType])
+		assert.Equal(t, "cell-core", cells[model.CredentialServiceType])
+		assert.Equal(t, "cell-edge", cells[model.DataPlaneType])
 		mockClient.AssertExpectations(t)
 	})
 
@@ -353,6 +459,43 @@ func TestDeployProfile(t *testing.T) {
 		assert.Equal(t, "ds-1", result.DataspaceProfileIDs[0])
 		mockClient.AssertExpectations(t)
 	})
+
+	t.Run("deploy with properties not conforming to the dataspace schema returns validation error", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		service.provisionClient = mockClient
+
+		cell := newTestCell("cell-1", "external-id")
+		cell.State = api.DeploymentStateActive
+		_, err := service.cellStore.Create(ctx, cell)
+		require.NoError(t, err)
+
+		ds1 := newTestDataspaceProfile("ds-1")
+		ds1.DataspaceSpec.PropertySchemas = newTestPropertySchemas()
+		_, err = service.dataspaceStore.Create(ctx, ds1)
+		require.NoError(t, err)
+
+		_, err = service.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+			Identifier:    "participant-identifier",
+			VPAProperties: api.VPAPropMap{model.ConnectorType: {"port": "not-a-number"}},
+		})
+
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+		var validationErr types.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		fields := make([]string, len(validationErr.Fields))
+		for i, field := range validationErr.Fields {
+			fields[i] = field.Field
+		}
+		assert.Contains(t, fields, "/properties")
+		assert.Contains(t, fields, "/vpaProperties/cfm.connector/port")
+		mockClient.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+
+		count, err := service.participantStore.CountByPredicate(ctx, &query.MatchAllPredicate{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
 }
 
 func TestDisposeProfile(t *testing.T) {
@@ -390,6 +533,7 @@ func TestDisposeProfile(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, updated)
 		assert.Equal(t, api.DeploymentStateDisposing, updated.VPAs[0].State)
+		assert.Equal(t, model.VPADisposeType, updated.VPAs[0].Operation)
 	})
 
 	t.Run("dispose non-existent participant returns error", func(t *testing.T) {
@@ -472,6 +616,295 @@ func TestDisposeProfile(t *testing.T) {
 	})
 }
 
+func TestUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+
+	newActiveProfile := func() *api.ParticipantProfile {
+		profile := newTestParticipantProfile("tenant-1", "participant-1")
+		profile.Properties[model.VPAStateData] = map[string]any{"state": "deployed"}
+		profile.VPAs[0].State = api.DeploymentStateActive
+		profile.VPAs[0].Properties["endpoint"] = "https://old.example.com"
+		return profile
+	}
+
+	t.Run("update VPA properties sends update manifest", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			diffs := manifest.Payload[model.VPAUpdateData].([]model.VPAPropertiesDiff)
+			require.Len(t, diffs, 1)
+			assert.Equal(t, "vpa-1", diffs[0].ID)
+			assert.Equal(t, map[string]any{"endpoint": "https://new.example.com", "replicas": 2}, diffs[0].Changed)
+			assert.Equal(t, []string{"connectorType"}, diffs[0].Removed)
+			assert.Equal(t, map[string]any{"state": "deployed"}, manifest.Payload[model.VPAStateData])
+			vpaManifest := manifest.Payload[model.VPAData].([]model.VPAManifest)[0]
+			assert.Equal(t, "https://new.example.com", vpaManifest.Properties["endpoint"])
+			return manifest.OrchestrationType == model.VPAUpdateType && manifest.CorrelationID == "participant-1"
+		})).Return(nil)
+		service.provisionClient = mockClient
+
+		_, err := service.participantStore.Create(ctx, newActiveProfile())
+		require.NoError(t, err)
+
+		result, err := service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			VPAProperties: api.VPAPropMap{model.ConnectorType: {
+				"endpoint":      "https://new.example.com",
+				"replicas":      2,
+				"connectorType": nil,
+			}},
+			Properties: map[string]any{"name": "Renamed"},
+		})
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, "Renamed", result.Properties["name"])
+
+		updated, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStatePending, updated.VPAs[0].State)
+		assert.Equal(t, model.VPAUpdateType, updated.VPAs[0].Operation)
+		assert.Equal(t, "https://new.example.com", updated.VPAs[0].Properties["endpoint"])
+		assert.NotContains(t, updated.VPAs[0].Properties, "connectorType")
+		assert.Equal(t, "Renamed", updated.Properties["name"])
+	})
+
+	t.Run("update without VPA changes does not send manifest", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		service.provisionClient = mockClient
+
+		_, err := service.participantStore.Create(ctx, newActiveProfile())
+		require.NoError(t, err)
+
+		result, err := service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			VPAProperties: api.VPAPropMap{model.ConnectorType: {"endpoint": "https://old.example.com"}},
+			Properties:    map[string]any{"name": "Renamed"},
+		})
+
+		require.NoError(t, err)
+		mockClient.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+		assert.Equal(t, api.DeploymentStateActive, result.VPAs[0].State)
+		assert.Equal(t, "Renamed", result.Properties["name"])
+	})
+
+	t.Run("update profile with non-active VPAs returns conflict", func(t *testing.T) {
+		service := newTestParticipantService()
+		profile := newActiveProfile()
+		profile.VPAs[0].State = api.DeploymentStatePending
+		_, err := service.participantStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		_, err = service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			VPAProperties: api.VPAPropMap{model.ConnectorType: {"endpoint": "https://new.example.com"}},
+		})
+
+		require.ErrorIs(t, err, types.ErrConflict)
+	})
+
+	t.Run("update state data or unknown VPA type returns client error", func(t *testing.T) {
+		service := newTestParticipantService()
+		_, err := service.participantStore.Create(ctx, newActiveProfile())
+		require.NoError(t, err)
+
+		_, err = service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			Properties: map[string]any{model.VPAStateData: map[string]any{}},
+		})
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+
+		_, err = service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			VPAProperties: api.VPAPropMap{model.DataPlaneType: {"endpoint": "https://new.example.com"}},
+		})
+		require.Error(t, err)
+		assert.True(t, types.IsClientError(err))
+	})
+
+	t.Run("update participant from different tenant returns error", func(t *testing.T) {
+		service := newTestParticipantService()
+		_, err := service.participantStore.Create(ctx, newActiveProfile())
+		require.NoError(t, err)
+
+		_, err = service.UpdateProfile(ctx, "tenant-2", "participant-1", &api.ParticipantProfileUpdate{})
+
+		require.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("update validates merged properties against the dataspace schema", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		service.provisionClient = mockClient
+
+		ds := newTestDataspaceProfile("dataspace-1")
+		ds.DataspaceSpec.PropertySchemas = newTestPropertySchemas()
+		_, err := service.dataspaceStore.Create(ctx, ds)
+		require.NoError(t, err)
+		_, err = service.participantStore.Create(ctx, newActiveProfile())
+		require.NoError(t, err)
+
+		_, err = service.UpdateProfile(ctx, "tenant-1", "participant-1", &api.ParticipantProfileUpdate{
+			VPAProperties: api.VPAPropMap{model.ConnectorType: {"port": 70000}},
+			Properties:    map[string]any{"name": nil},
+		})
+
+		var validationErr types.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Fields, 2)
+		mockClient.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+
+		stored, err := service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.DeploymentStateActive, stored.VPAs[0].State)
+	})
+}
+
+func TestVPACallbackHandlerUpdate(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.Properties[model.VPAStateData] = map[string]any{"connectionString": "test-value"}
+	profile.VPAs[0].State = api.DeploymentStatePending
+	createdProfile, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+
+	handler := vpaCallbackHandler{
+		participantStore: service.participantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+
+	err = handler.handleUpdate(ctx, model.OrchestrationResponse{
+		ID:                "response-1",
+		ManifestID:        "manifest-1",
+		CorrelationID:     createdProfile.ID,
+		OrchestrationType: model.VPAUpdateType,
+		Success:           true,
+		Properties:        map[string]any{"endpoint": "https://new.example.com"},
+	})
+
+	require.NoError(t, err)
+	updated, err := service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	assert.False(t, updated.Error)
+	assert.Equal(t, api.DeploymentStateActive, updated.VPAs[0].State)
+	assert.Equal(t, map[string]any{
+		"connectionString": "test-value",
+		"endpoint":         "https://new.example.com",
+	}, updated.Properties[model.VPAStateData])
+}
+
+func TestRetryProfile(t *testing.T) {
+	ctx := context.Background()
+
+	newFailedProfile := func(operation model.OrchestrationType) *api.ParticipantProfile {
+		profile := newTestParticipantProfile("tenant-1", "participant-1")
+		second := profile.VPAs[0]
+		second.ID = "vpa-2"
+		second.Type = model.DataPlaneType
+		profile.VPAs = append(profile.VPAs, second)
+		profile.VPAs[0].State = api.DeploymentStateActive
+		profile.VPAs[0].Operation = operation
+		profile.VPAs[1].State = api.DeploymentStateError
+		profile.VPAs[1].Operation = operation
+		profile.VPAs[1].ErrorDetail = "dataplane unreachable"
+		profile.Error = true
+		profile.ErrorDetail = "dataplane unreachable"
+		return profile
+	}
+
+	t.Run("retry failed deployment", func(t *testing.T) {
+		service := newTestParticipantService()
+		_, err := service.dataspaceStore.Create(ctx, &api.DataspaceProfile{Entity: api.Entity{ID: "dataspace-1"}})
+		require.NoError(t, err)
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			vpaManifests := manifest.Payload[model.VPAData].([]model.VPAManifest)
+			require.Len(t, vpaManifests, 1)
+			assert.Equal(t, "vpa-2", vpaManifests[0].ID)
+			assert.Contains(t, manifest.Payload, model.CredentialData)
+			return manifest.OrchestrationType == model.VPADeployType && manifest.CorrelationID == "participant-1"
+		})).Return(nil)
+		service.provisionClient = mockClient
+
+		_, err = service.participantStore.Create(ctx, newFailedProfile(model.VPADeployType))
+		require.NoError(t, err)
+
+		result, err := service.RetryProfile(ctx, "tenant-1", "participant-1")
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.False(t, result.Error)
+		assert.Empty(t, result.ErrorDetail)
+		assert.Equal(t, api.DeploymentStateActive, result.VPAs[0].State)
+		assert.Equal(t, api.DeploymentStatePending, result.VPAs[1].State)
+		assert.Empty(t, result.VPAs[1].ErrorDetail)
+
+		// A concurrent retry is rejected while the retried operation is in progress
+		_, err = service.RetryProfile(ctx, "tenant-1", "participant-1")
+		require.ErrorIs(t, err, types.ErrConflict)
+	})
+
+	t.Run("retry failed disposal", func(t *testing.T) {
+		service := newTestParticipantService()
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.MatchedBy(func(manifest model.OrchestrationManifest) bool {
+			assert.Equal(t, map[string]any{"state": "deployed"}, manifest.Payload[model.VPAStateData])
+			return manifest.OrchestrationType == model.VPADisposeType
+		})).Return(nil)
+		service.provisionClient = mockClient
+
+		profile := newFailedProfile(model.VPADisposeType)
+		profile.Properties[model.VPAStateData] = map[string]any{"state": "deployed"}
+		profile.VPAs[0].State = api.DeploymentStateDisposed
+		_, err := service.participantStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		result, err := service.RetryProfile(ctx, "tenant-1", "participant-1")
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, api.DeploymentStateDisposing, result.VPAs[1].State)
+	})
+
+	t.Run("retry profile not in the error state returns conflict", func(t *testing.T) {
+		service := newTestParticipantService()
+		profile := newFailedProfile(model.VPADeployType)
+		profile.Error = false
+		_, err := service.participantStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		_, err = service.RetryProfile(ctx, "tenant-1", "participant-1")
+
+		require.ErrorIs(t, err, types.ErrConflict)
+	})
+
+	t.Run("retry send failure returns error", func(t *testing.T) {
+		service := newTestParticipantService()
+		_, err := service.dataspaceStore.Create(ctx, &api.DataspaceProfile{Entity: api.Entity{ID: "dataspace-1"}})
+		require.NoError(t, err)
+		mockClient := new(mockProvisionClient)
+		mockClient.On("Send", ctx, mock.Anything).Return(assert.AnError)
+		service.provisionClient = mockClient
+
+		_, err = service.participantStore.Create(ctx, newFailedProfile(model.VPADeployType))
+		require.NoError(t, err)
+
+		_, err = service.RetryProfile(ctx, "tenant-1", "participant-1")
+
+		require.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("retry participant from different tenant returns error", func(t *testing.T) {
+		service := newTestParticipantService()
+		_, err := service.participantStore.Create(ctx, newFailedProfile(model.VPADeployType))
+		require.NoError(t, err)
+
+		_, err = service.RetryProfile(ctx, "tenant-2", "participant-1")
+
+		require.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
 func TestVPACallbackHandlerDeploy(t *testing.T) {
 	ctx := context.Background()
 	service := newTestParticipantService()
@@ -521,6 +954,7 @@ func TestVPACallbackHandlerDispose(t *testing.T) {
 
 	handler := vpaCallbackHandler{
 		participantStore: service.participantStore,
+		tenantStore:      service.tenantStore,
 		trxContext:       service.trxContext,
 		monitor:          nil,
 	}
@@ -581,6 +1015,105 @@ func TestVPACallbackHandlerFailedResponse(t *testing.T) {
 	assert.Equal(t, "Deployment failed due to network error", updated.ErrorDetail)
 }
 
+func TestVPACallbackHandlerVPAResults(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	connector := profile.VPAs[0]
+	dataPlane := profile.VPAs[0]
+	dataPlane.ID = "vpa-2"
+	dataPlane.Type = model.DataPlaneType
+	credentialService := profile.VPAs[0]
+	credentialService.ID = "vpa-3"
+	credentialService.Type = model.CredentialServiceType
+	profile.VPAs = []api.VirtualParticipantAgent{connector, dataPlane, credentialService}
+	for i := range profile.VPAs {
+		profile.VPAs[i].State = api.DeploymentStatePending
+	}
+	_, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+
+	handler := vpaCallbackHandler{
+		participantStore: service.participantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+
+	completed := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	err = handler.handleDeploy(ctx, model.OrchestrationResponse{
+		ID:                "response-1",
+		ManifestID:        "manifest-1",
+		CorrelationID:     "participant-1",
+		OrchestrationType: model.VPADeployType,
+		Success:           true,
+		Properties:        map[string]any{},
+		VPAResults: map[string]model.VPAResult{
+			"vpa-1": {State: model.VPAResultSucceeded, Timestamp: completed, Outputs: map[string]any{"url": "https://example.com"}},
+			"vpa-2": {State: model.VPAResultFailed, Timestamp: completed, Error: "dataplane unreachable"},
+		},
+	})
+	require.NoError(t, err)
+
+	updated, err := service.participantStore.FindByID(ctx, "participant-1")
+	require.NoError(t, err)
+	assert.True(t, updated.Error)
+	assert.Contains(t, updated.ErrorDetail, "vpa-2: dataplane unreachable")
+
+	assert.Equal(t, api.DeploymentStateActive, updated.VPAs[0].State)
+	assert.Equal(t, completed, updated.VPAs[0].StateTimestamp)
+	assert.Equal(t, api.Properties{"url": "https://example.com"}, updated.VPAs[0].Outputs)
+
+	assert.Equal(t, api.DeploymentStateError, updated.VPAs[1].State)
+	assert.Equal(t, "dataplane unreachable", updated.VPAs[1].ErrorDetail)
+
+	// VPAs without a result follow the orchestration outcome
+	assert.Equal(t, api.DeploymentStateActive, updated.VPAs[2].State)
+	assert.True(t, updated.VPAs[2].StateTimestamp.After(completed))
+}
+
+func TestVPACallbackHandlerFailedResponseUpdatesVPAs(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	second := profile.VPAs[0]
+	second.ID = "vpa-2"
+	second.Type = model.DataPlaneType
+	profile.VPAs = append(profile.VPAs, second)
+	profile.VPAs[0].State = api.DeploymentStatePending
+	profile.VPAs[1].State = api.DeploymentStatePending
+	_, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+
+	handler := vpaCallbackHandler{
+		participantStore: service.participantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+
+	err = handler.handleDeploy(ctx, model.OrchestrationResponse{
+		ID:                "response-1",
+		ManifestID:        "manifest-1",
+		CorrelationID:     "participant-1",
+		OrchestrationType: model.VPADeployType,
+		Success:           false,
+		ErrorDetail:       "activity timed out",
+		VPAResults: map[string]model.VPAResult{
+			"vpa-1": {State: model.VPAResultSucceeded},
+		},
+	})
+	require.NoError(t, err)
+
+	updated, err := service.participantStore.FindByID(ctx, "participant-1")
+	require.NoError(t, err)
+	assert.True(t, updated.Error)
+	assert.Equal(t, "activity timed out", updated.ErrorDetail)
+	assert.Equal(t, api.DeploymentStateActive, updated.VPAs[0].State)
+	assert.Equal(t, api.DeploymentStateError, updated.VPAs[1].State)
+	assert.Equal(t, "activity timed out", updated.VPAs[1].ErrorDetail)
+}
+
 func TestVPACallbackHandlerNonExistentProfile(t *testing.T) {
 	ctx := context.Background()
 
@@ -605,6 +1138,156 @@ func TestVPACallbackHandlerNonExistentProfile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestVPACallbackHandlerProgress(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.VPAs[0].State = api.DeploymentStatePending
+	createdProfile, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+
+	handler := vpaCallbackHandler{
+		participantStore: service.participantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+
+	now := time.Now()
+	progress := model.OrchestrationProgress{
+		ID:                   "progress-1",
+		ManifestID:           "manifest-1",
+		CorrelationID:        createdProfile.ID,
+		OrchestrationType:    model.VPADeployType,
+		ActivityID:           "activity-1",
+		ActivityType:         "test.activity",
+		Percent:              50,
+		OrchestrationPercent: 25,
+		Message:              "issuing credentials",
+		Timestamp:            now,
+	}
+
+	require.NoError(t, handler.handleProgress(ctx, progress))
+
+	updated, err := service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.VPAs[0].Progress)
+	assert.Equal(t, 25, updated.VPAs[0].Progress.Percent)
+	assert.Equal(t, "issuing credentials", updated.VPAs[0].Progress.Message)
+	assert.Equal(t, "activity-1", updated.VPAs[0].Progress.ActivityID)
+
+	// Out-of-order progress is ignored
+	stale := progress
+	stale.OrchestrationPercent = 10
+	stale.Timestamp = now.Add(-time.Minute)
+	require.NoError(t, handler.handleProgress(ctx, stale))
+
+	updated, err = service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 25, updated.VPAs[0].Progress.Percent)
+
+	// Completion clears progress
+	err = handler.handleDeploy(ctx, model.OrchestrationResponse{
+		ID:                "response-1",
+		ManifestID:        "manifest-1",
+		CorrelationID:     createdProfile.ID,
+		OrchestrationType: model.VPADeployType,
+		Success:           true,
+		Properties:        map[string]any{},
+	})
+	require.NoError(t, err)
+
+	updated, err = service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	assert.Nil(t, updated.VPAs[0].Progress)
+}
+
+func TestVPACallbackHandlerProgressPerVPA(t *testing.T) {
+	ctx := context.Background()
+	service := newTestParticipantService()
+
+	profile := newTestParticipantProfile("tenant-1", "participant-1")
+	profile.VPAs = append(profile.VPAs, api.VirtualParticipantAgent{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{ID: "vpa-2", Version: 1},
+			State:  api.DeploymentStatePending,
+		},
+		Type:   model.CredentialServiceType,
+		CellID: "cell-1",
+	})
+	profile.VPAs[0].State = api.DeploymentStatePending
+	createdProfile, err := service.participantStore.Create(ctx, profile)
+	require.NoError(t, err)
+	vpa1 := createdProfile.VPAs[0].ID
+
+	handler := vpaCallbackHandler{
+		participantStore: service.participantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+
+	now := time.Now()
+	progress := model.OrchestrationProgress{
+		ID:                   "progress-1",
+		ManifestID:           "manifest-1",
+		CorrelationID:        createdProfile.ID,
+		OrchestrationType:    model.VPADeployType,
+		ActivityID:           "keycloak-activity",
+		ActivityType:         "keycloak.activity",
+		Percent:              100,
+		OrchestrationPercent: 60,
+		VPAID:                "vpa-2",
+		VPAPercents:          map[string]int{vpa1: 20, "vpa-2": 100},
+		Message:              "realm created",
+		Timestamp:            now,
+	}
+	require.NoError(t, handler.handleProgress(ctx, progress))
+
+	updated, err := service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.VPAs[0].Progress)
+	require.NotNil(t, updated.VPAs[1].Progress)
+	assert.Equal(t, 20, updated.VPAs[0].Progress.Percent)
+	assert.Empty(t, updated.VPAs[0].Progress.Message)
+	assert.Equal(t, 100, updated.VPAs[1].Progress.Percent)
+	assert.Equal(t, "realm created", updated.VPAs[1].Progress.Message)
+	assert.Equal(t, "keycloak-activity", updated.VPAs[1].Progress.ActivityID)
+
+	// Progress reported for the first VPA keeps the message recorded on the second
+	progress.ID = "progress-2"
+	progress.ActivityID = "edc-activity"
+	progress.VPAID = vpa1
+	progress.VPAPercents = map[string]int{vpa1: 50, "vpa-2": 100}
+	progress.Message = "deploying connector"
+	progress.Timestamp = now.Add(time.Second)
+	require.NoError(t, handler.handleProgress(ctx, progress))
+
+	updated, err = service.participantStore.FindByID(ctx, createdProfile.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, updated.VPAs[0].Progress.Percent)
+	assert.Equal(t, "deploying connector", updated.VPAs[0].Progress.Message)
+	assert.Equal(t, 100, updated.VPAs[1].Progress.Percent)
+	assert.Equal(t, "realm created", updated.VPAs[1].Progress.Message)
+}
+
+func TestVPACallbackHandlerProgressNonExistentProfile(t *testing.T) {
+	handler := vpaCallbackHandler{
+		participantStore: memorystore.NewInMemoryEntityStore[*api.ParticipantProfile](),
+		trxContext:       store.NoOpTransactionContext{},
+		monitor:          system.NoopMonitor{},
+	}
+
+	progress := model.OrchestrationProgress{
+		ID:                "progress-1",
+		ManifestID:        "manifest-1",
+		CorrelationID:     "non-existent-profile",
+		OrchestrationType: model.VPADeployType,
+		ActivityID:        "activity-1",
+	}
+
+	require.NoError(t, handler.handleProgress(context.Background(), progress))
+}
+
 func TestGetFilteredProfiles(t *testing.T) {
 	ctx := context.Background()
 
@@ -782,18 +1465,34 @@ func newTestParticipantProfile(tenantID string, participantID string) *api.Parti
 	}
 }
 
+// newTestPropertySchemas returns schemas requiring a name participant property and an integer connector port.
+func newTestPropertySchemas() *api.PropertySchemas {
+	return &api.PropertySchemas{
+		Participant: map[string]any{
+			"type":     "object",
+			"required": []any{"name"},
+		},
+		VPAs: map[model.VPAType]map[string]any{
+			model.ConnectorType: {
+				"type": "object",
+				"properties": map[string]any{
+					"port": map[string]any{"type": "integer", "maximum": 65535},
+				},
+			},
+		},
+	}
+}
+
 func newTestParticipantService() *participantService {
 	return &participantService{
 		trxContext:       store.NoOpTransactionContext{},
 		participantStore: memorystore.NewInMemoryEntityStore[*api.ParticipantProfile](),
+		tenantStore:      memorystore.NewInMemoryEntityStore[*api.Tenant](),
 		cellStore:        memorystore.NewInMemoryEntityStore[*api.Cell](),
 		dataspaceStore:   memorystore.NewInMemoryEntityStore[*api.DataspaceProfile](),
-		participantGenerator: participantGenerator{
-			CellSelector: func(
-				orchestrationType model.OrchestrationType,
-				cells []api.Cell,
-				profiles []api.DataspaceProfile) (*api.Cell, error) {
-				return &cells[0], nil
+		participantGenerator: &participantGenerator{
+			CellSelector: func(request api.CellSelectionRequest) (*api.Cell, api.CellSelection, error) {
+				return &request.Cells[0], api.CellSelection{Strategy: "test", Reason: "first cell"}, nil
 			},
 		},
 		monitor: system.NoopMonitor{},
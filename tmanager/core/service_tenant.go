@@ -17,6 +17,8 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"slices"
+	"time"
 
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
@@ -26,10 +28,11 @@ import (
 )
 
 type tenantService struct {
-	trxContext       store.TransactionContext
-	tenantStore      store.EntityStore[*api.Tenant]
-	participantStore store.EntityStore[*api.ParticipantProfile]
-	monitor          system.LogMonitor
+	trxContext         store.TransactionContext
+	tenantStore        store.EntityStore[*api.Tenant]
+	participantStore   store.EntityStore[*api.ParticipantProfile]
+	participantService participantService
+	monitor            system.LogMonitor
 }
 
 func (t tenantService) GetTenant(ctx context.Context, tenantID string) (*api.Tenant, error) {
@@ -39,19 +42,60 @@ func (t tenantService) GetTenant(ctx context.Context, tenantID string) (*api.Ten
 }
 
 func (t tenantService) CreateTenant(ctx context.Context, tenant *api.Tenant) (*api.Tenant, error) {
+	if err := validateQuota(tenant.Quota); err != nil {
+		return nil, err
+	}
 	return store.Trx[api.Tenant](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Tenant, error) {
 		return t.tenantStore.Create(ctx, tenant)
 	})
 }
 
-func (t tenantService) PatchTenant(ctx context.Context, id string, properties map[string]any, remove []string) error {
-	return t.trxContext.Execute(ctx, func(ctx context.Context) error {
+func (t tenantService) UpdateQuota(ctx context.Context, tenantID string, quota *api.TenantQuota) (*api.Tenant, error) {
+	if err := validateQuota(quota); err != nil {
+		return nil, err
+	}
+	return store.Trx[api.Tenant](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Tenant, error) {
+		tenant, err := t.tenantStore.FindByID(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if err = store.CheckExpectedVersion(ctx, tenant); err != nil {
+			return nil, err
+		}
+		tenant.Quota = quota
+		if err = t.tenantStore.Update(ctx, tenant); err != nil {
+			return nil, fmt.Errorf("unable to update quota of tenant %s: %w", tenantID, err)
+		}
+		return tenant, nil
+	})
+}
+
+func (t tenantService) GetUsage(ctx context.Context, tenantID string) (*api.TenantUsage, error) {
+	return store.Trx[api.TenantUsage](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.TenantUsage, error) {
+		tenant, err := t.tenantStore.FindByID(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := tenantUsage(ctx, t.participantStore, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		usage.Quota = tenant.Quota
+		return usage, nil
+	})
+}
+
+func (t tenantService) PatchTenant(ctx context.Context, id string, properties map[string]any, remove []string) (*api.Tenant, error) {
+	return store.Trx[api.Tenant](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.Tenant, error) {
 		tenant, err := t.tenantStore.FindByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, types.ErrNotFound) {
-				return err
+				return nil, err
 			}
-			return fmt.Errorf("tenant %s not found: %w", id, err)
+			return nil, fmt.Errorf("tenant %s not found: %w", id, err)
+		}
+		if err = store.CheckExpectedVersion(ctx, tenant); err != nil {
+			return nil, err
 		}
 		for key, value := range properties {
 			tenant.Properties[key] = value
@@ -61,9 +105,9 @@ func (t tenantService) PatchTenant(ctx context.Context, id string, properties ma
 		}
 		err = t.tenantStore.Update(ctx, tenant)
 		if err != nil {
-			return fmt.Errorf("unable to patch tenant %s: %w", id, err)
+			return nil, fmt.Errorf("unable to patch tenant %s: %w", id, err)
 		}
-		return nil
+		return tenant, nil
 	})
 }
 
@@ -73,6 +117,9 @@ func (t tenantService) DeleteTenant(ctx context.Context, tenantID string) error
 		if err != nil {
 			return err
 		}
+		if err = store.CheckExpectedVersion(ctx, tenant); err != nil {
+			return err
+		}
 
 		count, err := t.participantStore.CountByPredicate(ctx, &query.AtomicPredicate{
 			Field:    "tenantId",
@@ -91,6 +138,217 @@ func (t tenantService) DeleteTenant(ctx context.Context, tenantID string) error
 	})
 }
 
+func (t tenantService) DeleteTenantCascade(ctx context.Context, tenantID string) (*api.TenantDeletionStatus, error) {
+	var participantIDs []string
+	err := t.trxContext.Execute(ctx, func(ctx context.Context) error {
+		tenant, err := t.tenantStore.FindByID(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if err = store.CheckExpectedVersion(ctx, tenant); err != nil {
+			return err
+		}
+		participantIDs, err = t.findParticipantIDs(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if tenant.Deletion == nil {
+			tenant.Deletion = &api.TenantDeletion{
+				StartTimestamp: time.Now().UTC(),
+				Participants:   len(participantIDs),
+			}
+		}
+		// Failed participants are retried
+		tenant.Deletion.Failures = nil
+		if err = t.tenantStore.Update(ctx, tenant); err != nil {
+			return fmt.Errorf("error deleting tenant %s: %w", tenantID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make(map[string]string)
+	for _, participantID := range participantIDs {
+		if err = t.disposeParticipant(ctx, tenantID, participantID); err != nil {
+			t.monitor.Warnf("Unable to dispose participant %s of tenant %s: %v", participantID, tenantID, err)
+			failures[participantID] = err.Error()
+		}
+	}
+
+	return store.Trx[api.TenantDeletionStatus](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.TenantDeletionStatus, error) {
+		tenant, err := t.tenantStore.FindByID(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if len(failures) > 0 {
+			if tenant.Deletion.Failures == nil {
+				tenant.Deletion.Failures = make(map[string]string)
+			}
+			for participantID, failure := range failures {
+				tenant.Deletion.Failures[participantID] = failure
+			}
+			if err = t.tenantStore.Update(ctx, tenant); err != nil {
+				return nil, fmt.Errorf("error deleting tenant %s: %w", tenantID, err)
+			}
+		}
+		status, err := t.deletionStatus(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = completeTenantDeletion(ctx, t.tenantStore, t.participantStore, tenantID); err != nil {
+			return nil, err
+		}
+		return status, nil
+	})
+}
+
+// disposeParticipant deletes the participant if its VPAs have been disposed and otherwise sends a dispose orchestration
+// unless the participant is already being disposed.
+func (t tenantService) disposeParticipant(ctx context.Context, tenantID string, participantID string) error {
+	dispose := false
+	err := t.trxContext.Execute(ctx, func(ctx context.Context) error {
+		profile, err := t.participantStore.FindByID(ctx, participantID)
+		if err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case isDisposed(profile):
+			return t.participantStore.Delete(ctx, participantID)
+		case isDisposing(profile):
+			return nil
+		default:
+			dispose = true
+			return nil
+		}
+	})
+	if err != nil || !dispose {
+		return err
+	}
+	return t.participantService.DisposeProfile(ctx, tenantID, participantID)
+}
+
+func (t tenantService) GetDeletionStatus(ctx context.Context, tenantID string) (*api.TenantDeletionStatus, error) {
+	return store.Trx[api.TenantDeletionStatus](t.trxContext).AndReturn(ctx, func(ctx context.Context) (*api.TenantDeletionStatus, error) {
+		tenant, err := t.tenantStore.FindByID(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if tenant.Deletion == nil {
+			return nil, fmt.Errorf("tenant %s is not being deleted: %w", tenantID, types.ErrNotFound)
+		}
+		return t.deletionStatus(ctx, tenant)
+	})
+}
+
+// deletionStatus computes the deletion status from the participants the tenant has left.
+func (t tenantService) deletionStatus(ctx context.Context, tenant *api.Tenant) (*api.TenantDeletionStatus, error) {
+	status := &api.TenantDeletionStatus{
+		TenantID:       tenant.ID,
+		StartTimestamp: tenant.Deletion.StartTimestamp,
+		Participants:   tenant.Deletion.Participants,
+		Failed:         []api.ParticipantFailure{},
+	}
+	remaining := 0
+	for profile, err := range t.participantStore.FindByPredicate(ctx, query.Eq("tenantId", tenant.ID)) {
+		if err != nil {
+			return nil, err
+		}
+		remaining++
+		failure, failed := tenant.Deletion.Failures[profile.ID]
+		switch {
+		case isDisposed(profile):
+			// Deleted when the tenant deletion completes
+			remaining--
+		case isDisposing(profile):
+			status.Disposing++
+		case failed:
+			status.Failed = append(status.Failed, api.ParticipantFailure{ParticipantID: profile.ID, Error: failure})
+		case profile.Error:
+			status.Failed = append(status.Failed, api.ParticipantFailure{ParticipantID: profile.ID, Error: profile.ErrorDetail})
+		default:
+			status.Remaining++
+		}
+	}
+	status.Deleted = max(0, status.Participants-remaining)
+	status.Complete = remaining == 0
+	return status, nil
+}
+
+func (t tenantService) findParticipantIDs(ctx context.Context, tenantID string) ([]string, error) {
+	ids := make([]string, 0)
+	for profile, err := range t.participantStore.FindByPredicate(ctx, query.Eq("tenantId", tenantID)) {
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, profile.ID)
+	}
+	return ids, nil
+}
+
+// completeTenantDeletion deletes the participants of a tenant that is being deleted once their VPAs have been disposed,
+// and the tenant once it has no participants. Returns true if the tenant was deleted.
+func completeTenantDeletion(
+	ctx context.Context,
+	tenantStore store.EntityStore[*api.Tenant],
+	participantStore store.EntityStore[*api.ParticipantProfile],
+	tenantID string) (bool, error) {
+
+	tenant, err := tenantStore.FindByID(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, types.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if tenant.Deletion == nil {
+		return false, nil
+	}
+
+	var disposed []string
+	remaining := 0
+	for profile, err := range participantStore.FindByPredicate(ctx, query.Eq("tenantId", tenantID)) {
+		if err != nil {
+			return false, err
+		}
+		if isDisposed(profile) {
+			disposed = append(disposed, profile.ID)
+		} else {
+			remaining++
+		}
+	}
+	for _, participantID := range disposed {
+		if err = participantStore.Delete(ctx, participantID); err != nil && !errors.Is(err, types.ErrNotFound) {
+			return false, fmt.Errorf("error deleting participant %s of tenant %s: %w", participantID, tenantID, err)
+		}
+	}
+	if remaining > 0 {
+		return false, nil
+	}
+	if err = tenantStore.Delete(ctx, tenantID); err != nil && !errors.Is(err, types.ErrNotFound) {
+		return false, fmt.Errorf("error deleting tenant %s: %w", tenantID, err)
+	}
+	return true, nil
+}
+
+// isDisposed returns true if all VPAs of the participant have been disposed.
+func isDisposed(profile *api.ParticipantProfile) bool {
+	return !slices.ContainsFunc(profile.VPAs, func(vpa api.VirtualParticipantAgent) bool {
+		return vpa.State != api.DeploymentStateDisposed
+	})
+}
+
+// isDisposing returns true if VPAs of the participant are being disposed.
+func isDisposing(profile *api.ParticipantProfile) bool {
+	return slices.ContainsFunc(profile.VPAs, func(vpa api.VirtualParticipantAgent) bool {
+		return vpa.State == api.DeploymentStateDisposing
+	})
+}
+
 func (t tenantService) QueryTenants(ctx context.Context, predicate query.Predicate, options store.PaginationOptions) iter.Seq2[*api.Tenant, error] {
 	return t.executeStoreIterator(ctx, func(ctx context.Context) iter.Seq2[*api.Tenant, error] {
 		return t.tenantStore.FindByPredicatePaginated(ctx, predicate, options)
@@ -16,6 +16,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 	"github.com/stretchr/testify/assert"
@@ -37,12 +38,13 @@ func TestPatchTenant(t *testing.T) {
 			"region":      "US-West",
 			"environment": "production",
 		}
-		err = service.PatchTenant(ctx, "tenant-1", props, []string{})
+		patched, err := service.PatchTenant(ctx, "tenant-1", props, []string{})
 
 		// Verify
 		require.NoError(t, err)
 		result, err := service.GetTenant(ctx, "tenant-1")
 		require.NoError(t, err)
+		assert.Equal(t, result.Version, patched.Version)
 		assert.Equal(t, "US-West", result.Properties["region"])
 		assert.Equal(t, "production", result.Properties["environment"])
 		assert.Equal(t, "Test Tenant tenant-1", result.Properties["name"]) // Existing property preserved
@@ -65,7 +67,7 @@ func TestPatchTenant(t *testing.T) {
 		props := map[string]any{
 			"region": "US-West",
 		}
-		err = service.PatchTenant(ctx, "tenant-2", props, []string{})
+		_, err = service.PatchTenant(ctx, "tenant-2", props, []string{})
 
 		// Verify
 		require.NoError(t, err)
@@ -89,7 +91,7 @@ func TestPatchTenant(t *testing.T) {
 		_, err := service.CreateTenant(ctx, tenant)
 		require.NoError(t, err)
 
-		err = service.PatchTenant(ctx, "tenant-3", map[string]any{}, []string{"deprecated"})
+		_, err = service.PatchTenant(ctx, "tenant-3", map[string]any{}, []string{"deprecated"})
 
 		// Verify
 		require.NoError(t, err)
@@ -118,7 +120,7 @@ func TestPatchTenant(t *testing.T) {
 			"name":        "Updated", // Update
 			"environment": "staging", // Add
 		}
-		err = service.PatchTenant(ctx, "tenant-4", props, []string{"old_setting"})
+		_, err = service.PatchTenant(ctx, "tenant-4", props, []string{"old_setting"})
 
 		// Verify
 		require.NoError(t, err)
@@ -134,7 +136,7 @@ func TestPatchTenant(t *testing.T) {
 		props := map[string]any{
 			"region": "US-West",
 		}
-		err := service.PatchTenant(ctx, "non-existent", props, []string{})
+		_, err := service.PatchTenant(ctx, "non-existent", props, []string{})
 
 		require.Error(t, err)
 		assert.Equal(t, types.ErrNotFound, err)
@@ -148,7 +150,7 @@ func TestPatchTenant(t *testing.T) {
 
 		// Execute: Patch with empty diff
 		props := make(map[string]any)
-		err = service.PatchTenant(ctx, "tenant-5", props, []string{})
+		_, err = service.PatchTenant(ctx, "tenant-5", props, []string{})
 
 		// Verify
 		require.NoError(t, err)
@@ -176,7 +178,7 @@ func TestPatchTenant(t *testing.T) {
 		props := map[string]any{
 			"property2": "updated_value2",
 		}
-		err = service.PatchTenant(ctx, "tenant-6", props, []string{})
+		_, err = service.PatchTenant(ctx, "tenant-6", props, []string{})
 
 		// Verify
 		require.NoError(t, err)
@@ -201,7 +203,7 @@ func TestPatchTenant(t *testing.T) {
 			"count":    42,
 			"active":   true,
 		}
-		err = service.PatchTenant(ctx, "tenant-7", props, []string{})
+		_, err = service.PatchTenant(ctx, "tenant-7", props, []string{})
 
 		// Verify
 		require.NoError(t, err)
@@ -214,3 +216,24 @@ func TestPatchTenant(t *testing.T) {
 	})
 
 }
+
+func TestPatchTenant_ExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTenantService()
+	_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+	require.NoError(t, err)
+	tenant, err := service.GetTenant(ctx, "tenant-1")
+	require.NoError(t, err)
+
+	_, err = service.PatchTenant(store.WithExpectedVersion(ctx, "tenant-1", tenant.Version), "tenant-1", map[string]any{"region": "US-West"}, nil)
+	require.NoError(t, err)
+
+	// The expected version is outdated after the first patch
+	_, err = service.PatchTenant(store.WithExpectedVersion(ctx, "tenant-1", tenant.Version), "tenant-1", map[string]any{"region": "US-East"}, nil)
+	require.ErrorIs(t, err, types.ErrPreconditionFailed)
+
+	result, err := service.GetTenant(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, "US-West", result.Properties["region"])
+	assert.Equal(t, tenant.Version+1, result.Version)
+}
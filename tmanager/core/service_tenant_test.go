@@ -17,11 +17,14 @@ import (
 	"testing"
 
 	"github.com/metaform/connector-fabric-manager/common/memorystore"
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -367,6 +370,167 @@ func TestDeleteTenant(t *testing.T) {
 	})
 }
 
+func TestDeleteTenantCascade(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("disposes participants and deletes tenant", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+		for _, id := range []string{"participant-1", "participant-2"} {
+			_, err = service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", id))
+			require.NoError(t, err)
+		}
+
+		status, err := service.DeleteTenantCascade(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.Participants)
+		assert.Equal(t, 2, status.Disposing)
+		assert.Equal(t, 0, status.Deleted)
+		assert.Empty(t, status.Failed)
+		assert.False(t, status.Complete)
+		tenant, err := service.GetTenant(ctx, "tenant-1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.Deletion)
+		calls := service.participantService.provisionClient.(*mockProvisionClient).Calls
+		require.Len(t, calls, 2)
+		assert.Equal(t, model.VPADisposeType, calls[0].Arguments.Get(1).(model.OrchestrationManifest).OrchestrationType)
+
+		handler := newTestDisposeHandler(service)
+		require.NoError(t, handler.handleDispose(ctx, disposeResponse("participant-1", true)))
+
+		status, err = service.GetDeletionStatus(ctx, "tenant-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Deleted)
+		assert.Equal(t, 1, status.Disposing)
+		_, err = service.participantStore.FindByID(ctx, "participant-1")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+
+		require.NoError(t, handler.handleDispose(ctx, disposeResponse("participant-2", true)))
+
+		_, err = service.GetTenant(ctx, "tenant-1")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		_, err = service.GetDeletionStatus(ctx, "tenant-1")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("deletes tenant without participants", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+
+		status, err := service.DeleteTenantCascade(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		assert.True(t, status.Complete)
+		_, err = service.GetTenant(ctx, "tenant-1")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("deletes participants that are already disposed", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+		profile := newTestDeployedParticipant("tenant-1", "participant-1")
+		profile.VPAs[0].State = api.DeploymentStateDisposed
+		_, err = service.participantStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		status, err := service.DeleteTenantCascade(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Deleted)
+		assert.True(t, status.Complete)
+		assert.Empty(t, service.participantService.provisionClient.(*mockProvisionClient).Calls)
+		_, err = service.GetTenant(ctx, "tenant-1")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("reports participants that cannot be disposed and retries them", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+		profile := newTestDeployedParticipant("tenant-1", "participant-1")
+		profile.VPAs[0].State = api.DeploymentStatePending
+		_, err = service.participantStore.Create(ctx, profile)
+		require.NoError(t, err)
+
+		status, err := service.DeleteTenantCascade(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		require.Len(t, status.Failed, 1)
+		assert.Equal(t, "participant-1", status.Failed[0].ParticipantID)
+		assert.NotEmpty(t, status.Failed[0].Error)
+
+		profile.VPAs[0].State = api.DeploymentStateActive
+		require.NoError(t, service.participantStore.Update(ctx, profile))
+
+		status, err = service.DeleteTenantCascade(ctx, "tenant-1")
+
+		require.NoError(t, err)
+		assert.Empty(t, status.Failed)
+		assert.Equal(t, 1, status.Disposing)
+		assert.Equal(t, 1, status.Participants)
+	})
+
+	t.Run("reports participants whose disposal failed", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+		_, err = service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", "participant-1"))
+		require.NoError(t, err)
+		_, err = service.DeleteTenantCascade(ctx, "tenant-1")
+		require.NoError(t, err)
+
+		handler := newTestDisposeHandler(service)
+		require.NoError(t, handler.handleDispose(ctx, disposeResponse("participant-1", false)))
+
+		status, err := service.GetDeletionStatus(ctx, "tenant-1")
+		require.NoError(t, err)
+		require.Len(t, status.Failed, 1)
+		assert.Equal(t, "dispose failed", status.Failed[0].Error)
+		_, err = service.participantStore.FindByID(ctx, "participant-1")
+		require.NoError(t, err)
+		_, err = service.GetTenant(ctx, "tenant-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("participants cannot be deployed to tenant being deleted", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+		_, err = service.participantStore.Create(ctx, newTestDeployedParticipant("tenant-1", "participant-1"))
+		require.NoError(t, err)
+		_, err = service.DeleteTenantCascade(ctx, "tenant-1")
+		require.NoError(t, err)
+
+		_, err = service.participantService.DeployProfile(ctx, "tenant-1", &api.NewParticipantProfileDeployment{
+			Identifier: "participant-identifier",
+		})
+
+		assert.ErrorIs(t, err, types.ErrConflict)
+	})
+
+	t.Run("cascade delete of non-existent tenant returns not found", func(t *testing.T) {
+		service := newTestTenantService()
+
+		_, err := service.DeleteTenantCascade(ctx, "non-existent")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("deletion status of tenant that is not being deleted returns not found", func(t *testing.T) {
+		service := newTestTenantService()
+		_, err := service.CreateTenant(ctx, newTestTenant("tenant-1"))
+		require.NoError(t, err)
+
+		_, err = service.GetDeletionStatus(ctx, "tenant-1")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
 func newTestTenant(id string) *api.Tenant {
 	return &api.Tenant{
 		Entity: api.Entity{
@@ -380,10 +544,44 @@ func newTestTenant(id string) *api.Tenant {
 }
 
 func newTestTenantService() *tenantService {
+	participants := newTestParticipantService()
+	provisionClient := new(mockProvisionClient)
+	provisionClient.On("Send", mock.Anything, mock.Anything).Return(nil)
+	participants.provisionClient = provisionClient
 	return &tenantService{
-		trxContext:       store.NoOpTransactionContext{},
-		tenantStore:      memorystore.NewInMemoryEntityStore[*api.Tenant](),
-		participantStore: memorystore.NewInMemoryEntityStore[*api.ParticipantProfile](),
-		monitor:          nil,
+		trxContext:         store.NoOpTransactionContext{},
+		tenantStore:        participants.tenantStore,
+		participantStore:   participants.participantStore,
+		participantService: *participants,
+		monitor:            system.NoopMonitor{},
+	}
+}
+
+// newTestDeployedParticipant returns a participant of the tenant with an active VPA.
+func newTestDeployedParticipant(tenantID string, participantID string) *api.ParticipantProfile {
+	profile := newTestParticipantProfile(tenantID, participantID)
+	profile.VPAs[0].State = api.DeploymentStateActive
+	profile.Properties[model.VPAStateData] = map[string]any{"key": "value"}
+	return profile
+}
+
+func newTestDisposeHandler(service *tenantService) vpaCallbackHandler {
+	return vpaCallbackHandler{
+		participantStore: service.participantStore,
+		tenantStore:      service.tenantStore,
+		trxContext:       service.trxContext,
+		monitor:          system.NoopMonitor{},
+	}
+}
+
+func disposeResponse(participantID string, success bool) model.OrchestrationResponse {
+	return model.OrchestrationResponse{
+		ID:                "response-" + participantID,
+		ManifestID:        "manifest-" + participantID,
+		CorrelationID:     participantID,
+		OrchestrationType: model.VPADisposeType,
+		Success:           success,
+		ErrorDetail:       "dispose failed",
+		Properties:        map[string]any{},
 	}
 }
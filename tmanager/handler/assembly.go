@@ -81,6 +81,7 @@ func (h *HandlerServiceAssembly) registerProfileQueryRoutes(router chi.Router, h
 		r.Post("/query", func(w http.ResponseWriter, req *http.Request) {
 			handler.queryParticipantProfiles(w, req, "/participant-profiles/query")
 		})
+		r.Get("/schema", handler.getPropertySchema)
 	})
 }
 
@@ -96,6 +97,41 @@ func (h *HandlerServiceAssembly) registerCellRoutes(router chi.Router, handler *
 				}
 				handler.deleteCell(w, req, cellID)
 			})
+			r.Post("/heartbeats", func(w http.ResponseWriter, req *http.Request) {
+				cellID, found := handler.ExtractPathVariable(w, req, "cellID")
+				if !found {
+					return
+				}
+				handler.recordCellHeartbeat(w, req, cellID)
+			})
+			r.Post("/cordon", func(w http.ResponseWriter, req *http.Request) {
+				cellID, found := handler.ExtractPathVariable(w, req, "cellID")
+				if !found {
+					return
+				}
+				handler.cordonCell(w, req, cellID)
+			})
+			r.Post("/uncordon", func(w http.ResponseWriter, req *http.Request) {
+				cellID, found := handler.ExtractPathVariable(w, req, "cellID")
+				if !found {
+					return
+				}
+				handler.uncordonCell(w, req, cellID)
+			})
+			r.Post("/drain", func(w http.ResponseWriter, req *http.Request) {
+				cellID, found := handler.ExtractPathVariable(w, req, "cellID")
+				if !found {
+					return
+				}
+				handler.drainCell(w, req, cellID)
+			})
+			r.Get("/drain", func(w http.ResponseWriter, req *http.Request) {
+				cellID, found := handler.ExtractPathVariable(w, req, "cellID")
+				if !found {
+					return
+				}
+				handler.getCellDrainStatus(w, req, cellID)
+			})
 		})
 	})
 }
@@ -120,6 +156,17 @@ func (h *HandlerServiceAssembly) registerDataspaceProfileRoutes(router chi.Route
 		})
 		r.Route("/{id}/deployments", func(r chi.Router) {
 			r.Post("/", handler.deployDataspaceProfile)
+			r.Delete("/{deploymentID}", func(w http.ResponseWriter, req *http.Request) {
+				profileID, found := handler.ExtractPathVariable(w, req, "id")
+				if !found {
+					return
+				}
+				deploymentID, found := handler.ExtractPathVariable(w, req, "deploymentID")
+				if !found {
+					return
+				}
+				handler.disposeDataspaceDeployment(w, req, profileID, deploymentID)
+			})
 		})
 	})
 }
@@ -149,6 +196,27 @@ func (h *HandlerServiceAssembly) registerTenantRoutes(router chi.Router, handler
 				}
 				handler.deleteTenant(w, req, tenantID)
 			})
+			r.Get("/deletion", func(w http.ResponseWriter, req *http.Request) {
+				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
+				if !found {
+					return
+				}
+				handler.getTenantDeletionStatus(w, req, tenantID)
+			})
+			r.Get("/usage", func(w http.ResponseWriter, req *http.Request) {
+				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
+				if !found {
+					return
+				}
+				handler.getTenantUsage(w, req, tenantID)
+			})
+			r.Put("/quota", func(w http.ResponseWriter, req *http.Request) {
+				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
+				if !found {
+					return
+				}
+				handler.updateTenantQuota(w, req, tenantID)
+			})
 			r.Patch("/", func(w http.ResponseWriter, req *http.Request) {
 				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
 				if !found {
@@ -190,6 +258,17 @@ func (h *HandlerServiceAssembly) registerParticipantRoutes(r chi.Router, handler
 				}
 				handler.getParticipantProfile(w, req, tenantID, participantID)
 			})
+			r.Patch("/", func(w http.ResponseWriter, req *http.Request) {
+				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
+				if !found {
+					return
+				}
+				participantID, found := handler.ExtractPathVariable(w, req, "participantID")
+				if !found {
+					return
+				}
+				handler.updateParticipantProfile(w, req, tenantID, participantID)
+			})
 			r.Delete("/", func(w http.ResponseWriter, req *http.Request) {
 				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
 				if !found {
@@ -201,6 +280,17 @@ func (h *HandlerServiceAssembly) registerParticipantRoutes(r chi.Router, handler
 				}
 				handler.disposeParticipantProfile(w, req, tenantID, participantID)
 			})
+			r.Post("/retry", func(w http.ResponseWriter, req *http.Request) {
+				tenantID, found := handler.ExtractPathVariable(w, req, "tenantID")
+				if !found {
+					return
+				}
+				participantID, found := handler.ExtractPathVariable(w, req, "participantID")
+				if !found {
+					return
+				}
+				handler.retryParticipantProfile(w, req, tenantID, participantID)
+			})
 		})
 	})
 }
@@ -14,15 +14,23 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/metaform/connector-fabric-manager/common/handler"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/store"
 	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/common/types"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 	"github.com/metaform/connector-fabric-manager/tmanager/model/v1alpha1"
 )
 
+const (
+	dataspaceProfileIDsParam = "dataspaceProfileIds"
+	cascadeParam             = "cascade"
+)
+
 type TMHandler struct {
 	handler.HttpHandler
 	tenantService      api.TenantService
@@ -68,6 +76,7 @@ func (h *TMHandler) getParticipantProfile(
 	}
 
 	response := v1alpha1.ToParticipantProfile(profile)
+	h.WriteETag(w, profile.Version)
 	h.ResponseOK(w, response)
 }
 
@@ -115,6 +124,69 @@ func (h *TMHandler) deployParticipantProfile(
 	}
 
 	response := v1alpha1.ToParticipantProfile(profile)
+	h.WriteETag(w, profile.Version)
+	h.ResponseAccepted(w, response)
+}
+
+func (h *TMHandler) updateParticipantProfile(
+	w http.ResponseWriter,
+	req *http.Request,
+	tenantID string,
+	participantID string) {
+
+	if h.InvalidMethod(w, req, http.MethodPatch) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, participantID)
+	if !ok {
+		return
+	}
+
+	var update v1alpha1.ParticipantProfileUpdate
+	if !h.ReadPayload(w, req, &update) {
+		return
+	}
+
+	profile, err := h.participantService.UpdateProfile(
+		ctx,
+		tenantID,
+		participantID,
+		v1alpha1.ToAPIParticipantProfileUpdate(&update))
+
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	response := v1alpha1.ToParticipantProfile(profile)
+	h.WriteETag(w, profile.Version)
+	h.ResponseAccepted(w, response)
+}
+
+func (h *TMHandler) retryParticipantProfile(
+	w http.ResponseWriter,
+	req *http.Request,
+	tenantID string,
+	participantID string) {
+
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, participantID)
+	if !ok {
+		return
+	}
+
+	profile, err := h.participantService.RetryProfile(ctx, tenantID, participantID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	response := v1alpha1.ToParticipantProfile(profile)
+	h.WriteETag(w, profile.Version)
 	h.ResponseAccepted(w, response)
 }
 
@@ -128,7 +200,12 @@ func (h *TMHandler) disposeParticipantProfile(
 		return
 	}
 
-	err := h.participantService.DisposeProfile(req.Context(), tenantID, participantID)
+	ctx, ok := h.ReadIfMatch(w, req, participantID)
+	if !ok {
+		return
+	}
+
+	err := h.participantService.DisposeProfile(ctx, tenantID, participantID)
 	if err != nil {
 		h.HandleError(w, err)
 	}
@@ -150,6 +227,30 @@ func (h *TMHandler) queryParticipantProfiles(w http.ResponseWriter, req *http.Re
 		h.txContext)
 }
 
+// getPropertySchema returns the effective property schema for the dataspace profiles given as a comma-separated list in
+// the dataspaceProfileIds query parameter, or for all dataspace profiles if the parameter is absent.
+func (h *TMHandler) getPropertySchema(w http.ResponseWriter, req *http.Request) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	var dProfileIDs []string
+	if param := req.URL.Query().Get(dataspaceProfileIDsParam); param != "" {
+		for _, id := range strings.Split(param, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				dProfileIDs = append(dProfileIDs, id)
+			}
+		}
+	}
+
+	schema, err := h.participantService.GetPropertySchema(req.Context(), dProfileIDs)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+	h.ResponseOK(w, schema)
+}
+
 func (h *TMHandler) createTenant(w http.ResponseWriter, req *http.Request) {
 	if h.InvalidMethod(w, req, http.MethodPost) {
 		return
@@ -167,6 +268,7 @@ func (h *TMHandler) createTenant(w http.ResponseWriter, req *http.Request) {
 	}
 
 	response := v1alpha1.ToTenant(tenant)
+	h.WriteETag(w, tenant.Version)
 	h.ResponseCreated(w, response)
 }
 
@@ -174,17 +276,22 @@ func (h *TMHandler) patchTenant(w http.ResponseWriter, req *http.Request, tenant
 	if h.InvalidMethod(w, req, http.MethodPatch) {
 		return
 	}
+	ctx, ok := h.ReadIfMatch(w, req, tenantID)
+	if !ok {
+		return
+	}
 	var diff v1alpha1.TenantPropertiesDiff
 	if !h.ReadPayload(w, req, &diff) {
 		return
 	}
 
-	err := h.tenantService.PatchTenant(req.Context(), tenantID, diff.Properties, diff.Removed)
+	tenant, err := h.tenantService.PatchTenant(ctx, tenantID, diff.Properties, diff.Removed)
 	if err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
+	h.WriteETag(w, tenant.Version)
 	h.OK(w)
 }
 
@@ -192,7 +299,31 @@ func (h *TMHandler) deleteTenant(w http.ResponseWriter, req *http.Request, tenan
 	if h.InvalidMethod(w, req, http.MethodDelete) {
 		return
 	}
-	err := h.tenantService.DeleteTenant(req.Context(), tenantID)
+
+	ctx, ok := h.ReadIfMatch(w, req, tenantID)
+	if !ok {
+		return
+	}
+
+	cascade := false
+	if param := req.URL.Query().Get(cascadeParam); param != "" {
+		var err error
+		if cascade, err = strconv.ParseBool(param); err != nil {
+			h.HandleError(w, types.NewClientError("invalid %s parameter: %s", cascadeParam, param))
+			return
+		}
+	}
+	if cascade {
+		status, err := h.tenantService.DeleteTenantCascade(ctx, tenantID)
+		if err != nil {
+			h.HandleError(w, err)
+			return
+		}
+		h.ResponseAccepted(w, v1alpha1.ToTenantDeletionStatus(status))
+		return
+	}
+
+	err := h.tenantService.DeleteTenant(ctx, tenantID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
@@ -201,6 +332,57 @@ func (h *TMHandler) deleteTenant(w http.ResponseWriter, req *http.Request, tenan
 	h.OK(w)
 }
 
+func (h *TMHandler) getTenantDeletionStatus(w http.ResponseWriter, req *http.Request, tenantID string) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	status, err := h.tenantService.GetDeletionStatus(req.Context(), tenantID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseOK(w, v1alpha1.ToTenantDeletionStatus(status))
+}
+
+func (h *TMHandler) updateTenantQuota(w http.ResponseWriter, req *http.Request, tenantID string) {
+	if h.InvalidMethod(w, req, http.MethodPut) {
+		return
+	}
+	ctx, ok := h.ReadIfMatch(w, req, tenantID)
+	if !ok {
+		return
+	}
+	var quota v1alpha1.TenantQuota
+	if !h.ReadPayload(w, req, &quota) {
+		return
+	}
+
+	tenant, err := h.tenantService.UpdateQuota(ctx, tenantID, v1alpha1.ToAPITenantQuota(&quota))
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, tenant.Version)
+	h.ResponseOK(w, v1alpha1.ToTenant(tenant))
+}
+
+func (h *TMHandler) getTenantUsage(w http.ResponseWriter, req *http.Request, tenantID string) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	usage, err := h.tenantService.GetUsage(req.Context(), tenantID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseOK(w, v1alpha1.ToTenantUsage(usage))
+}
+
 func (h *TMHandler) getTenants(w http.ResponseWriter, req *http.Request, path string) {
 	handler.ListEntities[*api.Tenant](
 		&h.HttpHandler,
@@ -227,6 +409,7 @@ func (h *TMHandler) getTenant(w http.ResponseWriter, req *http.Request, tenantID
 	}
 
 	response := v1alpha1.ToTenant(tenant)
+	h.WriteETag(w, tenant.Version)
 	h.ResponseOK(w, response)
 }
 
@@ -262,6 +445,7 @@ func (h *TMHandler) createCell(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	h.WriteETag(w, recordedCell.Version)
 	h.ResponseCreated(w, v1alpha1.ToCell(recordedCell))
 }
 
@@ -270,7 +454,12 @@ func (h *TMHandler) deleteCell(w http.ResponseWriter, req *http.Request, cellID
 		return
 	}
 
-	err := h.cellService.DeleteCell(req.Context(), cellID)
+	ctx, ok := h.ReadIfMatch(w, req, cellID)
+	if !ok {
+		return
+	}
+
+	err := h.cellService.DeleteCell(ctx, cellID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
@@ -279,6 +468,99 @@ func (h *TMHandler) deleteCell(w http.ResponseWriter, req *http.Request, cellID
 	h.OK(w)
 }
 
+func (h *TMHandler) cordonCell(w http.ResponseWriter, req *http.Request, cellID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, cellID)
+	if !ok {
+		return
+	}
+
+	cell, err := h.cellService.CordonCell(ctx, cellID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, cell.Version)
+	h.ResponseOK(w, v1alpha1.ToCell(cell))
+}
+
+func (h *TMHandler) recordCellHeartbeat(w http.ResponseWriter, req *http.Request, cellID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	var heartbeat v1alpha1.CellHeartbeat
+	if !h.ReadPayload(w, req, &heartbeat) {
+		return
+	}
+
+	cell, err := h.cellService.RecordHeartbeat(req.Context(), cellID, v1alpha1.ToAPICellHeartbeat(&heartbeat))
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, cell.Version)
+	h.ResponseOK(w, v1alpha1.ToCell(cell))
+}
+
+func (h *TMHandler) uncordonCell(w http.ResponseWriter, req *http.Request, cellID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, cellID)
+	if !ok {
+		return
+	}
+
+	cell, err := h.cellService.UncordonCell(ctx, cellID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.WriteETag(w, cell.Version)
+	h.ResponseOK(w, v1alpha1.ToCell(cell))
+}
+
+func (h *TMHandler) drainCell(w http.ResponseWriter, req *http.Request, cellID string) {
+	if h.InvalidMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, cellID)
+	if !ok {
+		return
+	}
+
+	status, err := h.cellService.DrainCell(ctx, cellID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseAccepted(w, v1alpha1.ToCellDrainStatus(status))
+}
+
+func (h *TMHandler) getCellDrainStatus(w http.ResponseWriter, req *http.Request, cellID string) {
+	if h.InvalidMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	status, err := h.cellService.GetDrainStatus(req.Context(), cellID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseOK(w, v1alpha1.ToCellDrainStatus(status))
+}
+
 func (h *TMHandler) getCells(w http.ResponseWriter, req *http.Request) {
 	if h.InvalidMethod(w, req, http.MethodGet) {
 		return
@@ -315,6 +597,7 @@ func (h *TMHandler) createDataspaceProfile(w http.ResponseWriter, req *http.Requ
 	}
 
 	response := v1alpha1.ToDataspaceProfile(result)
+	h.WriteETag(w, result.Version)
 	h.ResponseCreated(w, response)
 }
 
@@ -323,7 +606,12 @@ func (h *TMHandler) deleteDataspaceProfile(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	err := h.dataspaceService.DeleteProfile(req.Context(), profileID)
+	ctx, ok := h.ReadIfMatch(w, req, profileID)
+	if !ok {
+		return
+	}
+
+	err := h.dataspaceService.DeleteProfile(ctx, profileID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
@@ -360,6 +648,7 @@ func (h *TMHandler) getDataspaceProfile(w http.ResponseWriter, req *http.Request
 		h.HandleError(w, err)
 		return
 	}
+	h.WriteETag(w, profile.Version)
 	h.ResponseOK(w, v1alpha1.ToDataspaceProfile(profile))
 }
 
@@ -373,7 +662,31 @@ func (h *TMHandler) deployDataspaceProfile(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	err := h.dataspaceService.DeployProfile(req.Context(), newDeployment.ProfileID, newDeployment.CellID)
+	ctx, ok := h.ReadIfMatch(w, req, newDeployment.ProfileID)
+	if !ok {
+		return
+	}
+
+	deployment, err := h.dataspaceService.DeployProfile(ctx, newDeployment.ProfileID, newDeployment.CellID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	h.ResponseAccepted(w, v1alpha1.ToDataspaceDeployment(deployment))
+}
+
+func (h *TMHandler) disposeDataspaceDeployment(w http.ResponseWriter, req *http.Request, profileID string, deploymentID string) {
+	if h.InvalidMethod(w, req, http.MethodDelete) {
+		return
+	}
+
+	ctx, ok := h.ReadIfMatch(w, req, profileID)
+	if !ok {
+		return
+	}
+
+	err := h.dataspaceService.DisposeDeployment(ctx, profileID, deploymentID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
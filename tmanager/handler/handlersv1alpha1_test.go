@@ -0,0 +1,161 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const testVersion = 2
+
+func TestTMHandler_IfMatch(t *testing.T) {
+	h := NewHandler(
+		versionedTenantService{},
+		nil,
+		versionedCellService{},
+		versionedDataspaceService{},
+		store.NoOpTransactionContext{},
+		system.NoopMonitor{})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		handle func(w http.ResponseWriter, req *http.Request)
+	}{
+		{
+			name:   "patch tenant",
+			method: http.MethodPatch,
+			body:   `{"properties":{"region":"eu"}}`,
+			status: http.StatusOK,
+			handle: func(w http.ResponseWriter, req *http.Request) { h.patchTenant(w, req, "tenant-1") },
+		},
+		{
+			name:   "delete cell",
+			method: http.MethodDelete,
+			status: http.StatusOK,
+			handle: func(w http.ResponseWriter, req *http.Request) { h.deleteCell(w, req, "cell-1") },
+		},
+		{
+			name:   "delete dataspace profile",
+			method: http.MethodDelete,
+			status: http.StatusOK,
+			handle: func(w http.ResponseWriter, req *http.Request) { h.deleteDataspaceProfile(w, req, "profile-1") },
+		},
+		{
+			name:   "deploy dataspace profile",
+			method: http.MethodPost,
+			body:   `{"profileId":"profile-1","cellId":"cell-1"}`,
+			status: http.StatusAccepted,
+			handle: h.deployDataspaceProfile,
+		},
+		{
+			name:   "dispose dataspace deployment",
+			method: http.MethodDelete,
+			status: http.StatusAccepted,
+			handle: func(w http.ResponseWriter, req *http.Request) {
+				h.disposeDataspaceDeployment(w, req, "profile-1", "deployment-1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for ifMatch, status := range map[string]int{
+				"":    tt.status,
+				`"2"`: tt.status,
+				`"1"`: http.StatusPreconditionFailed,
+			} {
+				req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				if ifMatch != "" {
+					req.Header.Set("If-Match", ifMatch)
+				}
+				w := httptest.NewRecorder()
+
+				tt.handle(w, req)
+
+				assert.Equal(t, status, w.Code, "If-Match %q", ifMatch)
+			}
+		})
+	}
+}
+
+func TestTMHandler_PatchTenant_ETag(t *testing.T) {
+	h := NewHandler(versionedTenantService{}, nil, nil, nil, store.NoOpTransactionContext{}, system.NoopMonitor{})
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"properties":{"region":"eu"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.patchTenant(w, req, "tenant-1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+}
+
+// versionedTenantService patches a tenant that is at testVersion.
+type versionedTenantService struct {
+	api.TenantService
+}
+
+func (versionedTenantService) PatchTenant(ctx context.Context, id string, _ map[string]any, _ []string) (*api.Tenant, error) {
+	tenant := &api.Tenant{Entity: api.Entity{ID: id, Version: testVersion}}
+	if err := store.CheckExpectedVersion(ctx, tenant); err != nil {
+		return nil, err
+	}
+	tenant.IncrementVersion()
+	return tenant, nil
+}
+
+// versionedCellService deletes a cell that is at testVersion.
+type versionedCellService struct {
+	api.CellService
+}
+
+func (versionedCellService) DeleteCell(ctx context.Context, cellID string) error {
+	return store.CheckExpectedVersion(ctx, &api.Cell{DeployableEntity: api.DeployableEntity{Entity: api.Entity{ID: cellID, Version: testVersion}}})
+}
+
+// versionedDataspaceService operates on a dataspace profile that is at testVersion.
+type versionedDataspaceService struct {
+	api.DataspaceProfileService
+}
+
+func (versionedDataspaceService) DeleteProfile(ctx context.Context, profileID string) error {
+	return store.CheckExpectedVersion(ctx, testDataspaceProfile(profileID))
+}
+
+func (versionedDataspaceService) DeployProfile(ctx context.Context, profileID string, cellID string) (*api.DataspaceDeployment, error) {
+	if err := store.CheckExpectedVersion(ctx, testDataspaceProfile(profileID)); err != nil {
+		return nil, err
+	}
+	return &api.DataspaceDeployment{CellID: cellID}, nil
+}
+
+func (versionedDataspaceService) DisposeDeployment(ctx context.Context, profileID string, _ string) error {
+	return store.CheckExpectedVersion(ctx, testDataspaceProfile(profileID))
+}
+
+func testDataspaceProfile(id string) *api.DataspaceProfile {
+	return &api.DataspaceProfile{Entity: api.Entity{ID: id, Version: testVersion}}
+}
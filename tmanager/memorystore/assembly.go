@@ -27,7 +27,7 @@ func (a *InMemoryServiceAssembly) Name() string {
 }
 
 func (a *InMemoryServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.CellStoreKey, api.DataspaceProfileStoreKey, api.ParticipantProfileStoreKey}
+	return []system.ServiceType{api.CellStoreKey, api.DataspaceProfileStoreKey, api.ParticipantProfileStoreKey, api.OutboxStoreKey}
 }
 
 func (a *InMemoryServiceAssembly) Init(ictx *system.InitContext) error {
@@ -35,10 +35,12 @@ func (a *InMemoryServiceAssembly) Init(ictx *system.InitContext) error {
 	dataspaceStore := memorystore2.NewInMemoryEntityStore[*api.DataspaceProfile]()
 	participantStore := memorystore2.NewInMemoryEntityStore[*api.ParticipantProfile]()
 	tenantStore := memorystore2.NewInMemoryEntityStore[*api.Tenant]()
+	outboxStore := memorystore2.NewInMemoryEntityStore[*api.OutboxEntry]()
 
 	ictx.Registry.Register(api.TenantStoreKey, tenantStore)
 	ictx.Registry.Register(api.ParticipantProfileStoreKey, participantStore)
 	ictx.Registry.Register(api.DataspaceProfileStoreKey, dataspaceStore)
 	ictx.Registry.Register(api.CellStoreKey, cellStore)
+	ictx.Registry.Register(api.OutboxStoreKey, outboxStore)
 	return nil
 }
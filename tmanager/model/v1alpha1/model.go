@@ -25,11 +25,49 @@ type Entity struct {
 
 type NewTenant struct {
 	Properties map[string]any `json:"properties,omitempty"`
+	Quota      *TenantQuota   `json:"quota,omitempty"`
+}
+
+// TenantQuota limits the resources a tenant can use. Limits that are not set are not enforced.
+type TenantQuota struct {
+	MaxParticipants          *int           `json:"maxParticipants,omitempty"`
+	MaxVPAs                  map[string]int `json:"maxVPAs,omitempty"`
+	AllowedDataspaceProfiles []string       `json:"allowedDataspaceProfiles,omitempty"`
+	AllowedCells             []string       `json:"allowedCells,omitempty"`
+}
+
+// TenantUsage reports the participants and VPAs of a tenant that have not been disposed.
+type TenantUsage struct {
+	TenantID          string         `json:"tenantId"`
+	Participants      int            `json:"participants"`
+	VPAs              map[string]int `json:"vpas"`
+	DataspaceProfiles map[string]int `json:"dataspaceProfiles"`
+	Cells             map[string]int `json:"cells"`
+	Quota             *TenantQuota   `json:"quota,omitempty"`
 }
 
 type Tenant struct {
 	Entity
 	NewTenant
+	// Disposing is true while the tenant's participants are being disposed before the tenant is deleted.
+	Disposing bool `json:"disposing"`
+}
+
+// TenantDeletionStatus reports the progress of a cascading tenant deletion.
+type TenantDeletionStatus struct {
+	TenantID       string               `json:"tenantId"`
+	StartTimestamp time.Time            `json:"startTimestamp"`
+	Participants   int                  `json:"participants"`
+	Deleted        int                  `json:"deleted"`
+	Disposing      int                  `json:"disposing"`
+	Remaining      int                  `json:"remaining"`
+	Failed         []ParticipantFailure `json:"failed"`
+	Complete       bool                 `json:"complete"`
+}
+
+type ParticipantFailure struct {
+	ParticipantID string `json:"participantId"`
+	Error         string `json:"error"`
 }
 
 type NewCell struct {
@@ -42,6 +80,33 @@ type NewCell struct {
 type Cell struct {
 	Entity
 	NewCell
+	Cordoned bool        `json:"cordoned"`
+	Health   *CellHealth `json:"health,omitempty"`
+}
+
+type CellHealth struct {
+	LastHeartbeat time.Time      `json:"lastHeartbeat"`
+	Metrics       map[string]any `json:"metrics,omitempty"`
+}
+
+// CellHeartbeat is sent by a cell to report that it is available. Capacity, if set, replaces the cell capacity property.
+type CellHeartbeat struct {
+	Capacity *int           `json:"capacity,omitempty"`
+	Metrics  map[string]any `json:"metrics,omitempty"`
+}
+
+// CellDrainStatus reports the progress of a cell drain.
+type CellDrainStatus struct {
+	CellID         string    `json:"cellId"`
+	Cordoned       bool      `json:"cordoned"`
+	StartTimestamp time.Time `json:"startTimestamp"`
+	Participants   int       `json:"participants"`
+	VPAs           int       `json:"vpas"`
+	Migrated       int       `json:"migrated"`
+	Migrating      int       `json:"migrating"`
+	Failed         int       `json:"failed"`
+	Remaining      int       `json:"remaining"`
+	Complete       bool      `json:"complete"`
 }
 
 type NewDataspaceProfile struct {
@@ -60,7 +125,10 @@ type DataspaceDeployment struct {
 	CellID         string         `json:"cellId,omitempty"`
 	ExternalCellID string         `json:"externalCellId"`
 	Properties     map[string]any `json:"properties,omitempty"`
+	Outputs        map[string]any `json:"outputs,omitempty"`
+	ErrorDetail    string         `json:"errorDetail,omitempty"`
 }
+
 type DataspaceProfile struct {
 	Entity
 	DataspaceSpec DataspaceSpec         `json:"dataspaceSpec,omitempty"`
@@ -72,6 +140,21 @@ type DataspaceProfile struct {
 type DataspaceSpec struct {
 	ProtocolStack   []string         `json:"protocolStack,omitempty"`
 	CredentialSpecs []CredentialSpec `json:"credentialSpecs,omitempty"`
+	PropertySchemas *PropertySchemas `json:"propertySchemas,omitempty"`
+	VPATopology     []VPASpec        `json:"vpaTopology,omitempty"`
+}
+
+// VPASpec declares the number of VPAs of a type to instantiate for a participant and their default properties.
+type VPASpec struct {
+	Type       string         `json:"type" required:"true"`
+	Count      int            `json:"count,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// PropertySchemas declares JSON Schemas for participant properties and for the properties of each VPA type.
+type PropertySchemas struct {
+	Participant map[string]any            `json:"participant,omitempty"`
+	VPAs        map[string]map[string]any `json:"vpas,omitempty"`
 }
 
 type CredentialSpec struct {
@@ -89,6 +172,12 @@ type NewParticipantProfileDeployment struct {
 	ParticipantRoles    map[string][]string       `json:"participantRoles,omitempty"`
 	VPAProperties       map[string]map[string]any `json:"vpaProperties,omitempty"`
 	Properties          map[string]any            `json:"properties,omitempty"`
+	Placement           *ParticipantPlacement     `json:"placement,omitempty"`
+}
+
+type ParticipantProfileUpdate struct {
+	VPAProperties map[string]map[string]any `json:"vpaProperties,omitempty"`
+	Properties    map[string]any            `json:"properties,omitempty"`
 }
 
 type ParticipantProfile struct {
@@ -104,9 +193,50 @@ type ParticipantProfile struct {
 
 type VirtualParticipantAgent struct {
 	DeployableEntity
-	Type       model.VPAType  `json:"type" required:"true"`
-	CellID     string         `json:"cellId" required:"true"`
-	Properties map[string]any `json:"properties,omitempty"`
+	Type          model.VPAType       `json:"type" required:"true"`
+	CellID        string              `json:"cellId" required:"true"`
+	Properties    map[string]any      `json:"properties,omitempty"`
+	Outputs       map[string]any      `json:"outputs,omitempty"`
+	ErrorDetail   string              `json:"errorDetail,omitempty"`
+	Progress      *DeploymentProgress `json:"progress,omitempty"`
+	Operation     string              `json:"operation,omitempty"`
+	CellSelection *CellSelection      `json:"cellSelection,omitempty"`
+	Migration     *VPAMigration       `json:"migration,omitempty"`
+}
+
+// VPAMigration identifies the cell a VPA is being migrated from.
+type VPAMigration struct {
+	SourceCellID         string    `json:"sourceCellId"`
+	SourceExternalCellID string    `json:"sourceExternalCellId,omitempty"`
+	StartTimestamp       time.Time `json:"startTimestamp"`
+}
+
+// CellSelection records the strategy used to select the cell of a VPA and why the cell was chosen.
+type CellSelection struct {
+	Strategy string `json:"strategy"`
+	Reason   string `json:"reason"`
+}
+
+// CellPlacement constrains the cells VPAs can be placed in using cell labels.
+type CellPlacement struct {
+	RequiredLabels  map[string]string `json:"requiredLabels,omitempty"`
+	PreferredLabels map[string]string `json:"preferredLabels,omitempty"`
+}
+
+// ParticipantPlacement constrains the cells the VPAs of a participant can be placed in. The labels apply to all VPAs;
+// VPAs contains constraints keyed by VPA type, which take precedence for the same label.
+type ParticipantPlacement struct {
+	RequiredLabels  map[string]string        `json:"requiredLabels,omitempty"`
+	PreferredLabels map[string]string        `json:"preferredLabels,omitempty"`
+	VPAs            map[string]CellPlacement `json:"vpas,omitempty"`
+}
+
+type DeploymentProgress struct {
+	Percent      int       `json:"percent"`
+	Message      string    `json:"message,omitempty"`
+	ActivityID   string    `json:"activityId,omitempty"`
+	ActivityType string    `json:"activityType,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
 }
 
 type DeployableEntity struct {
@@ -26,7 +26,79 @@ func ToTenant(input *api.Tenant) *Tenant {
 		},
 		NewTenant: NewTenant{
 			Properties: input.Properties,
+			Quota:      ToTenantQuota(input.Quota),
 		},
+		Disposing: input.Deletion != nil,
+	}
+}
+
+func ToTenantQuota(input *api.TenantQuota) *TenantQuota {
+	if input == nil {
+		return nil
+	}
+	var maxVPAs map[string]int
+	if input.MaxVPAs != nil {
+		maxVPAs = make(map[string]int, len(input.MaxVPAs))
+		for vpaType, limit := range input.MaxVPAs {
+			maxVPAs[vpaType.String()] = limit
+		}
+	}
+	return &TenantQuota{
+		MaxParticipants:          input.MaxParticipants,
+		MaxVPAs:                  maxVPAs,
+		AllowedDataspaceProfiles: input.AllowedDataspaceProfiles,
+		AllowedCells:             input.AllowedCells,
+	}
+}
+
+func ToAPITenantQuota(input *TenantQuota) *api.TenantQuota {
+	if input == nil {
+		return nil
+	}
+	var maxVPAs map[model.VPAType]int
+	if input.MaxVPAs != nil {
+		maxVPAs = make(map[model.VPAType]int, len(input.MaxVPAs))
+		for vpaType, limit := range input.MaxVPAs {
+			maxVPAs[model.VPAType(vpaType)] = limit
+		}
+	}
+	return &api.TenantQuota{
+		MaxParticipants:          input.MaxParticipants,
+		MaxVPAs:                  maxVPAs,
+		AllowedDataspaceProfiles: input.AllowedDataspaceProfiles,
+		AllowedCells:             input.AllowedCells,
+	}
+}
+
+func ToTenantUsage(input *api.TenantUsage) *TenantUsage {
+	vpas := make(map[string]int, len(input.VPAs))
+	for vpaType, count := range input.VPAs {
+		vpas[vpaType.String()] = count
+	}
+	return &TenantUsage{
+		TenantID:          input.TenantID,
+		Participants:      input.Participants,
+		VPAs:              vpas,
+		DataspaceProfiles: input.DataspaceProfiles,
+		Cells:             input.Cells,
+		Quota:             ToTenantQuota(input.Quota),
+	}
+}
+
+func ToTenantDeletionStatus(input *api.TenantDeletionStatus) *TenantDeletionStatus {
+	failed := make([]ParticipantFailure, 0, len(input.Failed))
+	for _, failure := range input.Failed {
+		failed = append(failed, ParticipantFailure{ParticipantID: failure.ParticipantID, Error: failure.Error})
+	}
+	return &TenantDeletionStatus{
+		TenantID:       input.TenantID,
+		StartTimestamp: input.StartTimestamp.UTC(),
+		Participants:   input.Participants,
+		Deleted:        input.Deleted,
+		Disposing:      input.Disposing,
+		Remaining:      input.Remaining,
+		Failed:         failed,
+		Complete:       input.Complete,
 	}
 }
 
@@ -37,6 +109,7 @@ func NewAPITenant(input *NewTenant) *api.Tenant {
 			Version: 0,
 		},
 		Properties: api.ToProperties(input.Properties),
+		Quota:      ToAPITenantQuota(input.Quota),
 	}
 }
 
@@ -59,6 +132,8 @@ func NewAPIDataspaceProfile(input *NewDataspaceProfile) *api.DataspaceProfile {
 		DataspaceSpec: api.DataspaceSpec{
 			ProtocolStack:   input.DataspaceSpec.ProtocolStack,
 			CredentialSpecs: cspecs,
+			PropertySchemas: toAPIPropertySchemas(input.DataspaceSpec.PropertySchemas),
+			VPATopology:     toAPIVPATopology(input.DataspaceSpec.VPATopology),
 		},
 		Artifacts:   input.Artifacts,
 		Deployments: make([]api.DataspaceDeployment, 0),
@@ -100,9 +175,49 @@ func ToVPA(input *api.VirtualParticipantAgent) *VirtualParticipantAgent {
 			State:          input.State.String(),
 			StateTimestamp: input.StateTimestamp,
 		},
-		Type:       input.Type,
-		CellID:     input.CellID,
-		Properties: input.Properties,
+		Type:          input.Type,
+		CellID:        input.CellID,
+		Properties:    input.Properties,
+		Outputs:       input.Outputs,
+		ErrorDetail:   input.ErrorDetail,
+		Progress:      toDeploymentProgress(input.Progress),
+		Operation:     input.Operation.String(),
+		CellSelection: toCellSelection(input.CellSelection),
+		Migration:     toVPAMigration(input.Migration),
+	}
+}
+
+func toVPAMigration(input *api.VPAMigration) *VPAMigration {
+	if input == nil {
+		return nil
+	}
+	return &VPAMigration{
+		SourceCellID:         input.SourceCellID,
+		SourceExternalCellID: input.SourceExternalCellID,
+		StartTimestamp:       input.StartTimestamp,
+	}
+}
+
+func toCellSelection(input *api.CellSelection) *CellSelection {
+	if input == nil {
+		return nil
+	}
+	return &CellSelection{
+		Strategy: input.Strategy,
+		Reason:   input.Reason,
+	}
+}
+
+func toDeploymentProgress(input *api.DeploymentProgress) *DeploymentProgress {
+	if input == nil {
+		return nil
+	}
+	return &DeploymentProgress{
+		Percent:      input.Percent,
+		Message:      input.Message,
+		ActivityID:   input.ActivityID,
+		ActivityType: input.ActivityType,
+		Timestamp:    input.Timestamp,
 	}
 }
 
@@ -118,6 +233,40 @@ func ToCell(input *api.Cell) *Cell {
 			Properties:     input.Properties,
 			ExternalID:     input.ExternalID,
 		},
+		Cordoned: input.Cordoned,
+		Health:   toCellHealth(input.Health),
+	}
+}
+
+func toCellHealth(input *api.CellHealth) *CellHealth {
+	if input == nil {
+		return nil
+	}
+	return &CellHealth{
+		LastHeartbeat: input.LastHeartbeat.UTC(),
+		Metrics:       input.Metrics,
+	}
+}
+
+func ToAPICellHeartbeat(input *CellHeartbeat) api.CellHeartbeat {
+	return api.CellHeartbeat{
+		Capacity: input.Capacity,
+		Metrics:  input.Metrics,
+	}
+}
+
+func ToCellDrainStatus(input *api.CellDrainStatus) *CellDrainStatus {
+	return &CellDrainStatus{
+		CellID:         input.CellID,
+		Cordoned:       input.Cordoned,
+		StartTimestamp: input.StartTimestamp.UTC(),
+		Participants:   input.Participants,
+		VPAs:           input.VPAs,
+		Migrated:       input.Migrated,
+		Migrating:      input.Migrating,
+		Failed:         input.Failed,
+		Remaining:      input.Remaining,
+		Complete:       input.Complete(),
 	}
 }
 
@@ -160,6 +309,21 @@ func ToAPINewParticipantProfileDeployment(input *NewParticipantProfileDeployment
 		properties = make(map[string]any)
 	}
 
+	var placement api.ParticipantPlacement
+	if input.Placement != nil {
+		placement.RequiredLabels = input.Placement.RequiredLabels
+		placement.PreferredLabels = input.Placement.PreferredLabels
+		if input.Placement.VPAs != nil {
+			placement.VPAs = make(map[model.VPAType]api.CellPlacement, len(input.Placement.VPAs))
+			for vpaType, vpaPlacement := range input.Placement.VPAs {
+				placement.VPAs[model.VPAType(vpaType)] = api.CellPlacement{
+					RequiredLabels:  vpaPlacement.RequiredLabels,
+					PreferredLabels: vpaPlacement.PreferredLabels,
+				}
+			}
+		}
+	}
+
 	return &api.NewParticipantProfileDeployment{
 		Identifier:          input.Identifier,
 		CellID:              input.CellID,
@@ -167,6 +331,26 @@ func ToAPINewParticipantProfileDeployment(input *NewParticipantProfileDeployment
 		ParticipantRoles:    participantRoles,
 		VPAProperties:       vpaProperties,
 		Properties:          properties,
+		Placement:           placement,
+	}
+}
+
+func ToAPIParticipantProfileUpdate(input *ParticipantProfileUpdate) *api.ParticipantProfileUpdate {
+	var vpaProperties api.VPAPropMap
+	if input.VPAProperties == nil {
+		vpaProperties = make(api.VPAPropMap)
+	} else {
+		vpaProperties = *api.ToVPAMap(input.VPAProperties)
+	}
+
+	properties := input.Properties
+	if properties == nil {
+		properties = make(map[string]any)
+	}
+
+	return &api.ParticipantProfileUpdate{
+		VPAProperties: vpaProperties,
+		Properties:    properties,
 	}
 }
 
@@ -189,9 +373,35 @@ func ToAPIVPA(input *VirtualParticipantAgent) *api.VirtualParticipantAgent {
 			State:          state,
 			StateTimestamp: input.StateTimestamp.UTC(), // Force UTC
 		},
-		Type:       input.Type,
-		CellID:     input.CellID,
-		Properties: api.ToProperties(input.Properties),
+		Type:          input.Type,
+		CellID:        input.CellID,
+		Properties:    api.ToProperties(input.Properties),
+		Outputs:       input.Outputs,
+		ErrorDetail:   input.ErrorDetail,
+		Operation:     model.OrchestrationType(input.Operation),
+		CellSelection: toAPICellSelection(input.CellSelection),
+		Migration:     toAPIVPAMigration(input.Migration),
+	}
+}
+
+func toAPIVPAMigration(input *VPAMigration) *api.VPAMigration {
+	if input == nil {
+		return nil
+	}
+	return &api.VPAMigration{
+		SourceCellID:         input.SourceCellID,
+		SourceExternalCellID: input.SourceExternalCellID,
+		StartTimestamp:       input.StartTimestamp.UTC(),
+	}
+}
+
+func toAPICellSelection(input *CellSelection) *api.CellSelection {
+	if input == nil {
+		return nil
+	}
+	return &api.CellSelection{
+		Strategy: input.Strategy,
+		Reason:   input.Reason,
 	}
 }
 
@@ -226,22 +436,28 @@ func NewAPICell(input *NewCell) *api.Cell {
 	}
 }
 
+func ToDataspaceDeployment(input *api.DataspaceDeployment) *DataspaceDeployment {
+	return &DataspaceDeployment{
+		DeployableEntity: DeployableEntity{
+			Entity: Entity{
+				ID:      input.ID,
+				Version: input.Version,
+			},
+			State:          input.State.String(),
+			StateTimestamp: input.StateTimestamp.UTC(), // Convert to UTC
+		},
+		CellID:         input.CellID,
+		ExternalCellID: input.ExternalCellID,
+		Properties:     input.Properties,
+		Outputs:        input.Outputs,
+		ErrorDetail:    input.ErrorDetail,
+	}
+}
+
 func ToDataspaceProfile(input *api.DataspaceProfile) *DataspaceProfile {
 	deployments := make([]DataspaceDeployment, len(input.Deployments))
 	for i, deployment := range input.Deployments {
-		deployments[i] = DataspaceDeployment{
-			DeployableEntity: DeployableEntity{
-				Entity: Entity{
-					ID:      deployment.ID,
-					Version: deployment.Version,
-				},
-				State:          deployment.State.String(),
-				StateTimestamp: deployment.StateTimestamp.UTC(), // Convert to UTC
-			},
-			CellID:         deployment.CellID,
-			ExternalCellID: deployment.ExternalCellID,
-			Properties:     deployment.Properties,
-		}
+		deployments[i] = *ToDataspaceDeployment(&deployment)
 	}
 
 	cspecs := make([]CredentialSpec, len(input.DataspaceSpec.CredentialSpecs))
@@ -263,9 +479,69 @@ func ToDataspaceProfile(input *api.DataspaceProfile) *DataspaceProfile {
 		DataspaceSpec: DataspaceSpec{
 			ProtocolStack:   input.DataspaceSpec.ProtocolStack,
 			CredentialSpecs: cspecs,
+			PropertySchemas: ToPropertySchemas(input.DataspaceSpec.PropertySchemas),
+			VPATopology:     ToVPATopology(input.DataspaceSpec.VPATopology),
 		},
 		Artifacts:   input.Artifacts,
 		Deployments: deployments,
 		Properties:  input.Properties,
 	}
 }
+
+func toAPIPropertySchemas(input *PropertySchemas) *api.PropertySchemas {
+	if input == nil {
+		return nil
+	}
+	vpas := make(map[model.VPAType]map[string]any, len(input.VPAs))
+	for vpaType, schema := range input.VPAs {
+		vpas[model.VPAType(vpaType)] = schema
+	}
+	return &api.PropertySchemas{
+		Participant: input.Participant,
+		VPAs:        vpas,
+	}
+}
+
+func ToPropertySchemas(input *api.PropertySchemas) *PropertySchemas {
+	if input == nil {
+		return nil
+	}
+	vpas := make(map[string]map[string]any, len(input.VPAs))
+	for vpaType, schema := range input.VPAs {
+		vpas[vpaType.String()] = schema
+	}
+	return &PropertySchemas{
+		Participant: input.Participant,
+		VPAs:        vpas,
+	}
+}
+
+func toAPIVPATopology(input []VPASpec) []api.VPASpec {
+	if input == nil {
+		return nil
+	}
+	topology := make([]api.VPASpec, len(input))
+	for i, spec := range input {
+		topology[i] = api.VPASpec{
+			Type:       model.VPAType(spec.Type),
+			Count:      spec.Count,
+			Properties: spec.Properties,
+		}
+	}
+	return topology
+}
+
+func ToVPATopology(input []api.VPASpec) []VPASpec {
+	if input == nil {
+		return nil
+	}
+	topology := make([]VPASpec, len(input))
+	for i, spec := range input {
+		topology[i] = VPASpec{
+			Type:       spec.Type.String(),
+			Count:      spec.Count,
+			Properties: spec.Properties,
+		}
+	}
+	return topology
+}
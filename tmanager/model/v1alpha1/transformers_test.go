@@ -137,9 +137,10 @@ func TestToVPA(t *testing.T) {
 			State:          api.DeploymentStateError,
 			StateTimestamp: testTime,
 		},
-		Type:       model.DataPlaneType,
-		CellID:     "cell-456",
-		Properties: api.Properties{"vpa-prop": "vpa-val"},
+		Type:          model.DataPlaneType,
+		CellID:        "cell-456",
+		Properties:    api.Properties{"vpa-prop": "vpa-val"},
+		CellSelection: &api.CellSelection{Strategy: "least-loaded", Reason: "cell hosts 2 VPAs"},
 	}
 
 	result := ToVPA(&input)
@@ -152,6 +153,37 @@ func TestToVPA(t *testing.T) {
 	assert.Equal(t, model.DataPlaneType, result.Type)
 	assert.Equal(t, "cell-456", result.CellID)
 	assert.Equal(t, map[string]any{"vpa-prop": "vpa-val"}, result.Properties)
+	assert.Nil(t, result.Progress)
+	assert.Equal(t, &CellSelection{Strategy: "least-loaded", Reason: "cell hosts 2 VPAs"}, result.CellSelection)
+	assert.Equal(t, input.CellSelection, ToAPIVPA(result).CellSelection)
+}
+
+func TestToVPA_WithProgress(t *testing.T) {
+	testTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input := api.VirtualParticipantAgent{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{ID: "vpa-456"},
+			State:  api.DeploymentStatePending,
+		},
+		Type: model.ConnectorType,
+		Progress: &api.DeploymentProgress{
+			Percent:      50,
+			Message:      "issuing credentials",
+			ActivityID:   "activity-1",
+			ActivityType: "test.activity",
+			Timestamp:    testTime,
+		},
+	}
+
+	result := ToVPA(&input)
+
+	require.NotNil(t, result.Progress)
+	assert.Equal(t, 50, result.Progress.Percent)
+	assert.Equal(t, "issuing credentials", result.Progress.Message)
+	assert.Equal(t, "activity-1", result.Progress.ActivityID)
+	assert.Equal(t, "test.activity", result.Progress.ActivityType)
+	assert.Equal(t, testTime, result.Progress.Timestamp)
 }
 
 func TestToCell(t *testing.T) {
@@ -172,6 +204,11 @@ func TestToCell(t *testing.T) {
 			"capacity":    100,
 		},
 		ExternalID: "external-id",
+		Cordoned:   true,
+		Health: &api.CellHealth{
+			LastHeartbeat: testTime,
+			Metrics:       map[string]any{"cpu": 0.5},
+		},
 	}
 
 	result := ToCell(&input)
@@ -182,6 +219,10 @@ func TestToCell(t *testing.T) {
 	assert.Equal(t, "locked", result.State)
 	assert.Equal(t, testTime, result.StateTimestamp)
 	assert.Equal(t, "external-id", result.ExternalID)
+	assert.True(t, result.Cordoned)
+	require.NotNil(t, result.Health)
+	assert.Equal(t, testTime, result.Health.LastHeartbeat)
+	assert.Equal(t, map[string]any{"cpu": 0.5}, result.Health.Metrics)
 	assert.Equal(t, map[string]any{
 		"environment": "production",
 		"region":      "us-west-2",
@@ -511,6 +552,8 @@ func TestToDataspaceProfile(t *testing.T) {
 					"orchestration-env": "production",
 					"replicas":          3,
 				},
+				Outputs:    api.Properties{"endpoint": "https://cell-1"},
+				ManifestID: "manifest-1",
 			},
 			{
 				DeployableEntity: api.DeployableEntity{
@@ -594,6 +637,7 @@ func TestToDataspaceProfile(t *testing.T) {
 		"orchestration-env": "production",
 		"replicas":          3,
 	}, deployment1.Properties)
+	assert.Equal(t, map[string]any{"endpoint": "https://cell-1"}, deployment1.Outputs)
 
 	// Second deployment
 	deployment2 := result.Deployments[1]
@@ -950,6 +994,67 @@ func TestToTenant(t *testing.T) {
 	assert.Equal(t, "tenant-123", result.ID)
 	assert.Equal(t, int64(2), result.Version)
 	assert.Equal(t, map[string]any{"tenant-key": "tenant-value"}, result.Properties)
+	assert.False(t, result.Disposing)
+
+	input.Deletion = &api.TenantDeletion{StartTimestamp: time.Now()}
+	assert.True(t, ToTenant(input).Disposing)
+}
+
+func TestToTenantDeletionStatus(t *testing.T) {
+	testTime := time.Date(2025, 6, 15, 10, 30, 45, 0, time.UTC)
+
+	result := ToTenantDeletionStatus(&api.TenantDeletionStatus{
+		TenantID:       "tenant-1",
+		StartTimestamp: testTime,
+		Participants:   3,
+		Deleted:        1,
+		Disposing:      1,
+		Failed:         []api.ParticipantFailure{{ParticipantID: "participant-1", Error: "dispose failed"}},
+	})
+
+	assert.Equal(t, "tenant-1", result.TenantID)
+	assert.Equal(t, testTime, result.StartTimestamp)
+	assert.Equal(t, 3, result.Participants)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, 1, result.Disposing)
+	assert.Equal(t, []ParticipantFailure{{ParticipantID: "participant-1", Error: "dispose failed"}}, result.Failed)
+	assert.False(t, result.Complete)
+}
+
+func TestTenantQuotaRoundTrip(t *testing.T) {
+	maxParticipants := 10
+	input := &TenantQuota{
+		MaxParticipants:          &maxParticipants,
+		MaxVPAs:                  map[string]int{"cfm.connector": 5},
+		AllowedDataspaceProfiles: []string{"dataspace-1"},
+		AllowedCells:             []string{"cell-1"},
+	}
+
+	quota := ToAPITenantQuota(input)
+
+	require.NotNil(t, quota)
+	assert.Equal(t, map[model.VPAType]int{model.ConnectorType: 5}, quota.MaxVPAs)
+	assert.Equal(t, input, ToTenantQuota(quota))
+	assert.Equal(t, input, ToTenantQuota(NewAPITenant(&NewTenant{Quota: input}).Quota))
+	assert.Nil(t, ToAPITenantQuota(nil))
+	assert.Nil(t, ToTenantQuota(nil))
+}
+
+func TestToTenantUsage(t *testing.T) {
+	result := ToTenantUsage(&api.TenantUsage{
+		TenantID:          "tenant-1",
+		Participants:      2,
+		VPAs:              map[model.VPAType]int{model.ConnectorType: 3},
+		DataspaceProfiles: map[string]int{"dataspace-1": 2},
+		Cells:             map[string]int{"cell-1": 3},
+	})
+
+	assert.Equal(t, "tenant-1", result.TenantID)
+	assert.Equal(t, 2, result.Participants)
+	assert.Equal(t, map[string]int{"cfm.connector": 3}, result.VPAs)
+	assert.Equal(t, map[string]int{"dataspace-1": 2}, result.DataspaceProfiles)
+	assert.Equal(t, map[string]int{"cell-1": 3}, result.Cells)
+	assert.Nil(t, result.Quota)
 }
 
 func TestToTenantNilProperties(t *testing.T) {
@@ -1036,6 +1141,51 @@ func TestNewAPIDataspaceProfile(t *testing.T) {
 
 	// Verify Properties
 	assert.Equal(t, "value1", result.Properties["key1"])
+	assert.Nil(t, result.DataspaceSpec.PropertySchemas)
+}
+
+func TestPropertySchemasRoundTrip(t *testing.T) {
+	input := &NewDataspaceProfile{
+		DataspaceSpec: DataspaceSpec{
+			PropertySchemas: &PropertySchemas{
+				Participant: map[string]any{"type": "object", "required": []any{"name"}},
+				VPAs: map[string]map[string]any{
+					"cfm.connector": {"type": "object"},
+				},
+			},
+		},
+	}
+
+	apiProfile := NewAPIDataspaceProfile(input)
+
+	require.NotNil(t, apiProfile.DataspaceSpec.PropertySchemas)
+	assert.Equal(t, input.DataspaceSpec.PropertySchemas.Participant, apiProfile.DataspaceSpec.PropertySchemas.Participant)
+	assert.Equal(t, map[string]any{"type": "object"}, apiProfile.DataspaceSpec.PropertySchemas.VPAs[model.ConnectorType])
+
+	result := ToDataspaceProfile(apiProfile)
+
+	assert.Equal(t, input.DataspaceSpec.PropertySchemas, result.DataspaceSpec.PropertySchemas)
+}
+
+func TestVPATopologyRoundTrip(t *testing.T) {
+	input := &NewDataspaceProfile{
+		DataspaceSpec: DataspaceSpec{
+			VPATopology: []VPASpec{
+				{Type: "cfm.connector"},
+				{Type: "cfm.dataplane", Count: 2, Properties: map[string]any{"mode": "pull"}},
+			},
+		},
+	}
+
+	apiProfile := NewAPIDataspaceProfile(input)
+
+	require.Len(t, apiProfile.DataspaceSpec.VPATopology, 2)
+	assert.Equal(t, model.ConnectorType, apiProfile.DataspaceSpec.VPATopology[0].Type)
+	assert.Equal(t, api.VPASpec{Type: model.DataPlaneType, Count: 2, Properties: map[string]any{"mode": "pull"}}, apiProfile.DataspaceSpec.VPATopology[1])
+
+	result := ToDataspaceProfile(apiProfile)
+
+	assert.Equal(t, input.DataspaceSpec.VPATopology, result.DataspaceSpec.VPATopology)
 }
 
 func TestToAPINewParticipantProfileDeployment(t *testing.T) {
@@ -1054,6 +1204,13 @@ func TestToAPINewParticipantProfileDeployment(t *testing.T) {
 		Properties: map[string]any{
 			"custom-key": "custom-value",
 		},
+		Placement: &ParticipantPlacement{
+			RequiredLabels:  map[string]string{"region": "eu"},
+			PreferredLabels: map[string]string{"zone": "hardened"},
+			VPAs: map[string]CellPlacement{
+				"cfm.connector": {RequiredLabels: map[string]string{"tier": "edge"}},
+			},
+		},
 	}
 
 	result := ToAPINewParticipantProfileDeployment(input)
@@ -1069,6 +1226,9 @@ func TestToAPINewParticipantProfileDeployment(t *testing.T) {
 	assert.Len(t, result.VPAProperties, 2)
 	assert.Equal(t, "value1", result.VPAProperties["vpa-1"]["key1"])
 	assert.Equal(t, "custom-value", result.Properties["custom-key"])
+	assert.Equal(t, map[string]string{"region": "eu"}, result.Placement.RequiredLabels)
+	assert.Equal(t, map[string]string{"zone": "hardened"}, result.Placement.PreferredLabels)
+	assert.Equal(t, map[string]string{"tier": "edge"}, result.Placement.VPAs[model.ConnectorType].RequiredLabels)
 }
 
 func TestToAPINewParticipantProfileDeployment_NilValuesHandling(t *testing.T) {
@@ -1100,3 +1260,62 @@ func TestToAPINewParticipantProfileDeployment_NilValuesHandling(t *testing.T) {
 	assert.NotNil(t, result.Properties, "Properties should not be nil")
 	assert.Len(t, result.Properties, 0, "Properties should be empty map")
 }
+
+func TestToAPIParticipantProfileUpdate(t *testing.T) {
+	input := &ParticipantProfileUpdate{
+		VPAProperties: map[string]map[string]any{
+			"cfm.connector": {"key1": "value1", "key2": nil},
+		},
+	}
+
+	result := ToAPIParticipantProfileUpdate(input)
+
+	require.NotNil(t, result)
+	assert.Equal(t, "value1", result.VPAProperties[model.ConnectorType]["key1"])
+	assert.Contains(t, result.VPAProperties[model.ConnectorType], "key2")
+	assert.NotNil(t, result.Properties)
+}
+
+func TestToCellDrainStatus(t *testing.T) {
+	testTime := time.Date(2025, 6, 15, 10, 30, 45, 0, time.UTC)
+
+	result := ToCellDrainStatus(&api.CellDrainStatus{
+		CellID:         "cell-1",
+		Cordoned:       true,
+		StartTimestamp: testTime,
+		Participants:   2,
+		VPAs:           3,
+		Migrated:       2,
+		Migrating:      1,
+	})
+
+	assert.Equal(t, "cell-1", result.CellID)
+	assert.True(t, result.Cordoned)
+	assert.Equal(t, testTime, result.StartTimestamp)
+	assert.Equal(t, 2, result.Participants)
+	assert.Equal(t, 3, result.VPAs)
+	assert.Equal(t, 2, result.Migrated)
+	assert.Equal(t, 1, result.Migrating)
+	assert.False(t, result.Complete)
+}
+
+func TestVPAMigrationRoundTrip(t *testing.T) {
+	testTime := time.Date(2025, 6, 15, 10, 30, 45, 0, time.UTC)
+	input := &api.VirtualParticipantAgent{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{ID: "vpa-1"},
+			State:  api.DeploymentStatePending,
+		},
+		Type:   model.ConnectorType,
+		CellID: "cell-2",
+		Migration: &api.VPAMigration{
+			SourceCellID:         "cell-1",
+			SourceExternalCellID: "external-cell-1",
+			StartTimestamp:       testTime,
+		},
+	}
+
+	result := ToAPIVPA(ToVPA(input))
+
+	assert.Equal(t, input.Migration, result.Migration)
+}
@@ -15,18 +15,28 @@ package natsprovision
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/metaform/connector-fabric-manager/common/natsclient"
 	"github.com/metaform/connector-fabric-manager/common/system"
 	"github.com/metaform/connector-fabric-manager/tmanager/api"
 )
 
+const (
+	leaderTTLKey     = "leader.ttl"
+	defaultLeaderTTL = 15 * time.Second
+	leaderKey        = "tmanager"
+)
+
 type natsOrchestrationServiceAssembly struct {
 	uri                 string
 	bucket              string
 	streamName          string
 	natsClient          *natsclient.NatsClient
 	orchestrationClient *natsOrchestrationClient
+	progressClient      *natsProgressClient
+	leaderElection      *natsclient.LeaderElection
 	processCancel       context.CancelFunc
 
 	system.DefaultServiceAssembly
@@ -45,7 +55,7 @@ func (a *natsOrchestrationServiceAssembly) Name() string {
 }
 
 func (a *natsOrchestrationServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.ProvisionClientKey}
+	return []system.ServiceType{api.ProvisionClientKey, api.LeaderElectionKey}
 }
 
 func (d *natsOrchestrationServiceAssembly) Requires() []system.ServiceType {
@@ -67,6 +77,22 @@ func (a *natsOrchestrationServiceAssembly) Init(ctx *system.InitContext) error {
 	a.orchestrationClient = newNatsOrchestrationClient(client, dispatcher, ctx.LogMonitor)
 	ctx.Registry.Register(api.ProvisionClientKey, a.orchestrationClient)
 
+	a.progressClient = newNatsProgressClient(client, dispatcher, ctx.LogMonitor)
+
+	leaderTTL := time.Duration(ctx.GetConfigIntOrDefault(leaderTTLKey, int(defaultLeaderTTL.Seconds()))) * time.Second
+	leaderKV, err := natsclient.SetupLeaderBucket(context.Background(), natsClient.JetStream, leaderTTL)
+	if err != nil {
+		return fmt.Errorf("error initializing NATS leader bucket: %w", err)
+	}
+	a.leaderElection = &natsclient.LeaderElection{
+		KV:       leaderKV,
+		Key:      leaderKey,
+		ID:       uuid.New().String(),
+		Interval: leaderTTL / 3,
+		Monitor:  ctx.LogMonitor,
+	}
+	ctx.Registry.Register(api.LeaderElectionKey, a.leaderElection)
+
 	return nil
 }
 
@@ -85,15 +111,32 @@ func (a *natsOrchestrationServiceAssembly) Start(_ *system.StartContext) error {
 		return fmt.Errorf("error initializing NATS orchestration consumer: %w", err)
 	}
 
+	progressConsumer, err := natsclient.SetupConsumer(natsContext, stream, natsclient.CFMOrchestrationProgress)
+	if err != nil {
+		return fmt.Errorf("error initializing NATS orchestration progress consumer: %w", err)
+	}
+
 	ctx, a.processCancel = context.WithCancel(context.Background())
 
-	return a.orchestrationClient.Init(ctx, consumer)
+	if err = a.orchestrationClient.Init(ctx, consumer); err != nil {
+		return err
+	}
+	if err = a.progressClient.Init(ctx, progressConsumer); err != nil {
+		return err
+	}
+	a.leaderElection.Start(ctx)
+	return nil
 }
 
 func (a *natsOrchestrationServiceAssembly) Shutdown() error {
 	if a.processCancel != nil {
 		a.processCancel()
 	}
+	if a.leaderElection != nil {
+		if err := a.leaderElection.Resign(context.Background()); err != nil {
+			a.leaderElection.Monitor.Warnf("Error resigning leadership: %v", err)
+		}
+	}
 	if a.natsClient != nil {
 		a.natsClient.Connection.Close()
 	}
@@ -63,9 +63,44 @@ func (n *natsOrchestrationClient) Send(ctx context.Context, manifest model.Orche
 	if err != nil {
 		return err
 	}
-	_, err = n.Client.Publish(ctx, natsclient.CFMOrchestrationSubject, serialized)
+	_, err = natsclient.PublishTraced(ctx, n.Client, natsclient.CFMOrchestrationSubject, serialized)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// natsProgressClient consumes progress messages published while orchestrations are executing.
+type natsProgressClient struct {
+	natsclient.RetriableMessageProcessor[model.OrchestrationProgress]
+}
+
+func newNatsProgressClient(
+	client natsclient.MsgClient,
+	dispatcher provisionCallbackDispatcher,
+	monitor system.LogMonitor) *natsProgressClient {
+	return &natsProgressClient{
+		RetriableMessageProcessor: natsclient.RetriableMessageProcessor[model.OrchestrationProgress]{
+			Client:     client,
+			Monitor:    monitor,
+			Processing: atomic.Bool{},
+			Dispatcher: func(ctx context.Context, payload model.OrchestrationProgress) error {
+				err := model.Validator.Struct(payload)
+				if err != nil {
+					return types.NewClientError("invalid progress: %s", err.Error())
+				}
+				return dispatcher.DispatchProgress(ctx, payload)
+			},
+		},
+	}
+}
+
+func (n *natsProgressClient) Init(ctx context.Context, consumer jetstream.Consumer) error {
+	go func() {
+		err := n.ProcessLoop(ctx, consumer)
+		if err != nil {
+			n.Monitor.Warnf("Error Processing progress message: %v", err)
+		}
+	}()
+	return nil
+}
@@ -221,12 +221,17 @@ func (m *mockOrchestrationDispatcher) Dispatch(ctx context.Context, response mod
 	return args.Error(0)
 }
 
+func (m *mockOrchestrationDispatcher) DispatchProgress(ctx context.Context, progress model.OrchestrationProgress) error {
+	args := m.Called(ctx, progress)
+	return args.Error(0)
+}
+
 type mockJetStreamMsg struct {
 	mock.Mock
 }
 
 func (m *mockJetStreamMsg) Headers() nats.Header {
-	panic("not implemented")
+	return nil
 }
 
 func (m *mockJetStreamMsg) Metadata() (*jetstream.MsgMetadata, error) {
@@ -467,6 +467,10 @@ type testOrchestrationDispatcher struct {
 	mu            sync.Mutex
 }
 
+func (t *testOrchestrationDispatcher) DispatchProgress(_ context.Context, _ model.OrchestrationProgress) error {
+	return nil
+}
+
 func (t *testOrchestrationDispatcher) Dispatch(ctx context.Context, response model.OrchestrationResponse) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -26,20 +26,32 @@ type provisionCallbackDispatcher interface {
 	// Dispatch is invoked when an orchestration is complete.
 	// If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
 	Dispatch(ctx context.Context, response model.OrchestrationResponse) error
+
+	// DispatchProgress is invoked when an activity reports progress.
+	// If a recoverable error is encountered one of model.RecoverableError, model.ClientError, or model.FatalError will be returned.
+	DispatchProgress(ctx context.Context, progress model.OrchestrationProgress) error
 }
 
 // provisionCallbackService registers api.ProvisionCallbackHandler instances and dispatches orchestration responses.
 type provisionCallbackService struct {
-	handlers map[string]api.ProvisionCallbackHandler
+	handlers         map[string]api.ProvisionCallbackHandler
+	progressHandlers map[string]api.ProvisionProgressHandler
 }
 
 func newProvisionCallbackService() *provisionCallbackService {
-	return &provisionCallbackService{handlers: make(map[string]api.ProvisionCallbackHandler)}
+	return &provisionCallbackService{
+		handlers:         make(map[string]api.ProvisionCallbackHandler),
+		progressHandlers: make(map[string]api.ProvisionProgressHandler),
+	}
 }
 func (d provisionCallbackService) Register(orchestrationType model.OrchestrationType, handler api.ProvisionCallbackHandler) {
 	d.handlers[orchestrationType.String()] = handler
 }
 
+func (d provisionCallbackService) RegisterProgress(orchestrationType model.OrchestrationType, handler api.ProvisionProgressHandler) {
+	d.progressHandlers[orchestrationType.String()] = handler
+}
+
 func (d provisionCallbackService) Dispatch(ctx context.Context, response model.OrchestrationResponse) error {
 	handler, found := d.handlers[response.OrchestrationType.String()]
 	if !found {
@@ -47,3 +59,13 @@ func (d provisionCallbackService) Dispatch(ctx context.Context, response model.O
 	}
 	return handler(ctx, response)
 }
+
+// DispatchProgress routes progress to the handler registered for the orchestration type. Progress is informational, so
+// progress for types without a registered handler is dropped.
+func (d provisionCallbackService) DispatchProgress(ctx context.Context, progress model.OrchestrationProgress) error {
+	handler, found := d.progressHandlers[progress.OrchestrationType.String()]
+	if !found {
+		return nil
+	}
+	return handler(ctx, progress)
+}
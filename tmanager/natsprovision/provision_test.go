@@ -221,3 +221,34 @@ func TestProvisionCallbackService_Integration(t *testing.T) {
 	})
 
 }
+
+func TestProvisionCallbackService_DispatchProgress(t *testing.T) {
+	t.Run("dispatch to registered handler", func(t *testing.T) {
+		service := newProvisionCallbackService()
+
+		var received model.OrchestrationProgress
+		service.RegisterProgress("vpa", func(ctx context.Context, progress model.OrchestrationProgress) error {
+			received = progress
+			return nil
+		})
+
+		progress := model.OrchestrationProgress{
+			ManifestID:        "manifest-123",
+			OrchestrationType: "vpa",
+			ActivityID:        "activity-1",
+			Percent:           40,
+		}
+
+		require.NoError(t, service.DispatchProgress(context.Background(), progress))
+		assert.Equal(t, progress, received)
+	})
+
+	t.Run("dispatch to unregistered orchestration type", func(t *testing.T) {
+		service := newProvisionCallbackService()
+
+		progress := model.OrchestrationProgress{OrchestrationType: "unknown"}
+
+		// Progress without a handler is dropped
+		require.NoError(t, service.DispatchProgress(context.Background(), progress))
+	})
+}
@@ -0,0 +1,56 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package pmclient
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/metaform/connector-fabric-manager/assembly/serviceapi"
+	"github.com/metaform/connector-fabric-manager/common/system"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+const pmURLKey = "pmanager.url"
+
+// PMClientServiceAssembly provides a client for the provision manager API. The client is only registered if the
+// provision manager URL is configured.
+type PMClientServiceAssembly struct {
+	system.DefaultServiceAssembly
+}
+
+func (a *PMClientServiceAssembly) Name() string {
+	return "Provision Manager Client"
+}
+
+func (a *PMClientServiceAssembly) Provides() []system.ServiceType {
+	return []system.ServiceType{api.OrchestrationClientKey}
+}
+
+func (a *PMClientServiceAssembly) Requires() []system.ServiceType {
+	return []system.ServiceType{serviceapi.HttpClientKey}
+}
+
+func (a *PMClientServiceAssembly) Init(ctx *system.InitContext) error {
+	pmURL := ctx.GetConfigStrOrDefault(pmURLKey, "")
+	if pmURL == "" {
+		ctx.LogMonitor.Infof("Provision manager URL not configured, participant profiles will not be reconciled")
+		return nil
+	}
+	httpClient := ctx.Registry.Resolve(serviceapi.HttpClientKey).(http.Client)
+	ctx.Registry.Register(api.OrchestrationClientKey, httpOrchestrationClient{
+		baseURL:    strings.TrimSuffix(pmURL, "/"),
+		httpClient: &httpClient,
+	})
+	return nil
+}
@@ -0,0 +1,91 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package pmclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+const orchestrationsPath = "/api/v1alpha1/orchestrations"
+
+// httpOrchestrationClient queries orchestrations using the provision manager API.
+type httpOrchestrationClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func (c httpOrchestrationClient) QueryOrchestrations(ctx context.Context, correlationID string) ([]api.OrchestrationStatus, error) {
+	payload, err := json.Marshal(model.Query{Predicate: fmt.Sprintf("correlationId = '%s'", correlationID)})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+orchestrationsPath+"/query", bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	orchestrations := make([]api.OrchestrationStatus, 0)
+	if err = c.do(req, &orchestrations); err != nil {
+		return nil, fmt.Errorf("failed to query orchestrations for %s: %w", correlationID, err)
+	}
+	return orchestrations, nil
+}
+
+func (c httpOrchestrationClient) GetOrchestration(ctx context.Context, orchestrationID string) (*api.OrchestrationStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+orchestrationsPath+"/"+url.PathEscape(orchestrationID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var orchestration api.OrchestrationStatus
+	if err = c.do(req, &orchestration); err != nil {
+		return nil, fmt.Errorf("failed to get orchestration %s: %w", orchestrationID, err)
+	}
+	return &orchestration, nil
+}
+
+// do sends the request and decodes the response body into result.
+func (c httpOrchestrationClient) do(req *http.Request, result any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return types.ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("received status code %d, body: %s", resp.StatusCode, string(body))
+	}
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package pmclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/types"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryOrchestrations(t *testing.T) {
+	var received model.Query
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/api/v1alpha1/orchestrations/query", req.URL.Path)
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		_, _ = w.Write([]byte(`[{"id":"orchestration-1","correlationId":"participant-1","state":2,"orchestrationType":"vpa.deploy"}]`))
+	}))
+	defer server.Close()
+
+	client := httpOrchestrationClient{baseURL: server.URL, httpClient: server.Client()}
+	orchestrations, err := client.QueryOrchestrations(context.Background(), "participant-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "correlationId = 'participant-1'", received.Predicate)
+	require.Len(t, orchestrations, 1)
+	assert.Equal(t, "orchestration-1", orchestrations[0].ID)
+	assert.Equal(t, "participant-1", orchestrations[0].CorrelationID)
+	assert.Equal(t, api.OrchestrationStateCompleted, orchestrations[0].State)
+	assert.Equal(t, model.OrchestrationType("vpa.deploy"), orchestrations[0].OrchestrationType)
+}
+
+func TestGetOrchestration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1alpha1/orchestrations/orchestration-1":
+			_, _ = w.Write([]byte(`{"id":"orchestration-1","state":3,"outputData":{"key":"value"}}`))
+		case "/api/v1alpha1/orchestrations/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := httpOrchestrationClient{baseURL: server.URL, httpClient: server.Client()}
+
+	t.Run("get orchestration", func(t *testing.T) {
+		orchestration, err := client.GetOrchestration(context.Background(), "orchestration-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, api.OrchestrationStateErrored, orchestration.State)
+		assert.Equal(t, map[string]any{"key": "value"}, orchestration.OutputData)
+	})
+
+	t.Run("get non-existent orchestration returns not found", func(t *testing.T) {
+		_, err := client.GetOrchestration(context.Background(), "non-existent")
+
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("error status returns error", func(t *testing.T) {
+		_, err := client.GetOrchestration(context.Background(), "error")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status code 500")
+	})
+}
@@ -39,7 +39,7 @@ func (a *PostgresServiceAssembly) Name() string {
 }
 
 func (a *PostgresServiceAssembly) Provides() []system.ServiceType {
-	return []system.ServiceType{api.CellStoreKey, api.DataspaceProfileStoreKey, api.ParticipantProfileStoreKey, store.TransactionContextKey}
+	return []system.ServiceType{api.CellStoreKey, api.DataspaceProfileStoreKey, api.ParticipantProfileStoreKey, api.OutboxStoreKey, store.TransactionContextKey}
 }
 
 func (a *PostgresServiceAssembly) Init(ictx *system.InitContext) error {
@@ -61,11 +61,13 @@ func (a *PostgresServiceAssembly) Init(ictx *system.InitContext) error {
 	dataspaceStore := newDataspaceProfileStore()
 	participantStore := newParticipantProfileStore()
 	tenantStore := newTenantStore()
+	outboxStore := newOutboxStore()
 
 	ictx.Registry.Register(api.TenantStoreKey, tenantStore)
 	ictx.Registry.Register(api.ParticipantProfileStoreKey, participantStore)
 	ictx.Registry.Register(api.DataspaceProfileStoreKey, dataspaceStore)
 	ictx.Registry.Register(api.CellStoreKey, cellStore)
+	ictx.Registry.Register(api.OutboxStoreKey, outboxStore)
 
 	txContext := sqlstore.NewDBTransactionContext(db)
 	ictx.Registry.Register(store.TransactionContextKey, txContext)
@@ -102,6 +104,11 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	err = createOutboxTable(db)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -24,11 +24,13 @@ import (
 )
 
 func newCellStore() store.EntityStore[*api.Cell] {
-	columnNames := []string{"id", "external_id", "version", "state", "state_timestamp", "properties"}
+	columnNames := []string{"id", "external_id", "version", "state", "state_timestamp", "properties", "cordoned", "drain", "health"}
 	builder := sqlstore.NewPostgresJSONBBuilder().
 		WithFieldMappings(map[string]string{"stateTimestamp": "state_timestamp"}).
 		WithJSONBFieldTypes(map[string]sqlstore.JSONBFieldType{
 			"properties": sqlstore.JSONBFieldTypeScalar,
+			"drain":      sqlstore.JSONBFieldTypeScalar,
+			"health":     sqlstore.JSONBFieldTypeScalar,
 		})
 
 	estore := sqlstore.NewPostgresEntityStore[*api.Cell](
@@ -77,6 +79,22 @@ func recordToCellEntity(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.Cell,
 			return nil, err
 		}
 	}
+
+	if cordoned, ok := record.Values["cordoned"].(bool); ok {
+		cell.Cordoned = cordoned
+	}
+
+	if bytes, ok := record.Values["drain"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &cell.Drain); err != nil {
+			return nil, err
+		}
+	}
+
+	if bytes, ok := record.Values["health"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &cell.Health); err != nil {
+			return nil, err
+		}
+	}
 	return cell, nil
 }
 
@@ -104,5 +122,23 @@ func cellEntityToRecord(cell *api.Cell) (*sqlstore.DatabaseRecord, error) {
 		record.Values["properties"] = metadataBytes
 	}
 
+	record.Values["cordoned"] = cell.Cordoned
+
+	if cell.Drain != nil {
+		drainBytes, err := json.Marshal(cell.Drain)
+		if err != nil {
+			return record, err
+		}
+		record.Values["drain"] = drainBytes
+	}
+
+	if cell.Health != nil {
+		healthBytes, err := json.Marshal(cell.Health)
+		if err != nil {
+			return record, err
+		}
+		record.Values["health"] = healthBytes
+	}
+
 	return record, nil
 }
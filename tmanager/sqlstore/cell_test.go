@@ -234,7 +234,6 @@ func TestNewCellStore_Update(t *testing.T) {
 
 	cell.State = api.DeploymentStateActive
 	cell.StateTimestamp = time.Now()
-	cell.IncrementVersion()
 	err = estore.Update(txCtx, cell)
 	require.NoError(t, err)
 
@@ -626,6 +625,53 @@ func TestNewCellStore_WithProperties(t *testing.T) {
 	assert.NotNil(t, retrieved.Properties["metadata"])
 }
 
+// TestNewCellStore_CordonAndDrain tests persisting the cordon and drain of a cell
+func TestNewCellStore_CordonAndDrain(t *testing.T) {
+	setupCellTable(t, testDB)
+	defer cleanupTestData(t, testDB)
+
+	estore := newCellStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	cell := &api.Cell{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{
+				ID:      "drained-cell",
+				Version: 1,
+			},
+			State:          api.DeploymentStateActive,
+			StateTimestamp: time.Now(),
+		},
+	}
+	_, err = estore.Create(txCtx, cell)
+	require.NoError(t, err)
+
+	retrieved, err := estore.FindByID(txCtx, "drained-cell")
+	require.NoError(t, err)
+	assert.False(t, retrieved.Cordoned)
+	assert.Nil(t, retrieved.Drain)
+
+	startTimestamp := time.Now().UTC().Truncate(time.Millisecond)
+	retrieved.Cordoned = true
+	retrieved.Drain = &api.CellDrain{StartTimestamp: startTimestamp, Participants: 2, VPAs: 5}
+	err = estore.Update(txCtx, retrieved)
+	require.NoError(t, err)
+
+	updated, err := estore.FindByID(txCtx, "drained-cell")
+	require.NoError(t, err)
+	assert.True(t, updated.Cordoned)
+	require.NotNil(t, updated.Drain)
+	assert.True(t, startTimestamp.Equal(updated.Drain.StartTimestamp))
+	assert.Equal(t, 2, updated.Drain.Participants)
+	assert.Equal(t, 5, updated.Drain.VPAs)
+}
+
 // TestNewCellStore_StateTransitions tests cell state transitions
 func TestNewCellStore_StateTransitions(t *testing.T) {
 	setupCellTable(t, testDB)
@@ -671,7 +717,6 @@ func TestNewCellStore_StateTransitions(t *testing.T) {
 
 	retrieved.State = api.DeploymentStateActive
 	retrieved.StateTimestamp = time.Now()
-	retrieved.IncrementVersion()
 	err = estore.Update(txCtx, retrieved)
 	require.NoError(t, err)
 
@@ -793,3 +838,48 @@ func setupCellTable(t *testing.T, db *sql.DB) {
 	err := createCellsTable(db)
 	require.NoError(t, err)
 }
+
+// TestNewCellStore_Health tests that the health reported by cell heartbeats is persisted
+func TestNewCellStore_Health(t *testing.T) {
+	setupCellTable(t, testDB)
+	defer cleanupTestData(t, testDB)
+
+	estore := newCellStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	cell := &api.Cell{
+		DeployableEntity: api.DeployableEntity{
+			Entity: api.Entity{
+				ID:      "monitored-cell",
+				Version: 1,
+			},
+			State:          api.DeploymentStateActive,
+			StateTimestamp: time.Now(),
+		},
+	}
+	_, err = estore.Create(txCtx, cell)
+	require.NoError(t, err)
+
+	retrieved, err := estore.FindByID(txCtx, "monitored-cell")
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.Health)
+
+	heartbeat := time.Now().UTC().Truncate(time.Millisecond)
+	retrieved.State = api.DeploymentStateOffline
+	retrieved.Health = &api.CellHealth{LastHeartbeat: heartbeat, Metrics: map[string]any{"cpu": 0.5}}
+	err = estore.Update(txCtx, retrieved)
+	require.NoError(t, err)
+
+	updated, err := estore.FindByID(txCtx, "monitored-cell")
+	require.NoError(t, err)
+	assert.Equal(t, api.DeploymentStateOffline, updated.State)
+	require.NotNil(t, updated.Health)
+	assert.True(t, heartbeat.Equal(updated.Health.LastHeartbeat))
+	assert.Equal(t, 0.5, updated.Health.Metrics["cpu"])
+}
@@ -0,0 +1,121 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/sqlstore"
+	"github.com/metaform/connector-fabric-manager/common/store"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+)
+
+func newOutboxStore() store.EntityStore[*api.OutboxEntry] {
+	columnNames := []string{"id", "version", "state", "manifest", "attempts", "last_error", "created_timestamp", "sent_timestamp"}
+	builder := sqlstore.NewPostgresJSONBBuilder().
+		WithFieldMappings(map[string]string{
+			"lastError":        "last_error",
+			"createdTimestamp": "created_timestamp",
+			"sentTimestamp":    "sent_timestamp",
+		}).
+		WithJSONBFieldTypes(map[string]sqlstore.JSONBFieldType{
+			"manifest": sqlstore.JSONBFieldTypeScalar,
+		})
+
+	estore := sqlstore.NewPostgresEntityStore[*api.OutboxEntry](
+		cfmOutboxTable,
+		columnNames,
+		recordToOutboxEntity,
+		outboxEntityToRecord,
+		builder,
+	)
+
+	return estore
+}
+
+func recordToOutboxEntity(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.OutboxEntry, error) {
+	entry := &api.OutboxEntry{}
+	if id, ok := record.Values["id"].(string); ok {
+		entry.ID = id
+	} else {
+		return nil, fmt.Errorf("invalid outbox entry id reading record")
+	}
+
+	if version, ok := record.Values["version"].(int64); ok {
+		entry.Version = version
+	} else {
+		return nil, fmt.Errorf("invalid outbox entry version reading record")
+	}
+
+	if state, ok := record.Values["state"].(string); ok {
+		entry.State = api.OutboxState(state)
+	} else {
+		return nil, fmt.Errorf("invalid outbox entry state reading record")
+	}
+
+	if bytes, ok := record.Values["manifest"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &entry.Manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	if attempts, ok := record.Values["attempts"].(int64); ok {
+		entry.Attempts = int(attempts)
+	}
+
+	if lastError, ok := record.Values["last_error"].(string); ok {
+		entry.LastError = lastError
+	}
+
+	if timestamp, ok := record.Values["created_timestamp"].(time.Time); ok {
+		entry.CreatedTimestamp = timestamp
+	} else {
+		return nil, fmt.Errorf("invalid outbox entry created timestamp reading record")
+	}
+
+	if timestamp, ok := record.Values["sent_timestamp"].(time.Time); ok {
+		entry.SentTimestamp = timestamp
+	}
+
+	return entry, nil
+}
+
+func outboxEntityToRecord(entry *api.OutboxEntry) (*sqlstore.DatabaseRecord, error) {
+	record := &sqlstore.DatabaseRecord{
+		Values: make(map[string]any),
+	}
+
+	record.Values["id"] = entry.ID
+	record.Values["version"] = entry.Version
+	record.Values["state"] = string(entry.State)
+	record.Values["attempts"] = entry.Attempts
+	record.Values["last_error"] = entry.LastError
+	record.Values["created_timestamp"] = entry.CreatedTimestamp
+
+	bytes, err := json.Marshal(entry.Manifest)
+	if err != nil {
+		return record, err
+	}
+	record.Values["manifest"] = bytes
+
+	// Pending entries have not been sent
+	record.Values["sent_timestamp"] = nil
+	if !entry.SentTimestamp.IsZero() {
+		record.Values["sent_timestamp"] = entry.SentTimestamp
+	}
+
+	return record, nil
+}
@@ -0,0 +1,121 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/metaform/connector-fabric-manager/common/model"
+	"github.com/metaform/connector-fabric-manager/common/query"
+	"github.com/metaform/connector-fabric-manager/common/sqlstore"
+	"github.com/metaform/connector-fabric-manager/tmanager/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutboxStore_CreateAndUpdate(t *testing.T) {
+	setupOutboxTable(t, testDB)
+	defer cleanupOutboxTestData(t, testDB)
+
+	estore := newOutboxStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	created := time.Now().UTC().Truncate(time.Microsecond)
+	_, err = estore.Create(txCtx, &api.OutboxEntry{
+		Entity: api.Entity{ID: "entry-1", Version: 1},
+		State:  api.OutboxStatePending,
+		Manifest: model.OrchestrationManifest{
+			ID:                "manifest-1",
+			CorrelationID:     "participant-1",
+			OrchestrationType: model.VPADeployType,
+			Payload:           map[string]any{"key": "value"},
+		},
+		CreatedTimestamp: created,
+	})
+	require.NoError(t, err)
+
+	retrieved, err := estore.FindByID(txCtx, "entry-1")
+	require.NoError(t, err)
+	assert.Equal(t, api.OutboxStatePending, retrieved.State)
+	assert.Equal(t, "manifest-1", retrieved.Manifest.ID)
+	assert.Equal(t, "participant-1", retrieved.Manifest.CorrelationID)
+	assert.Equal(t, model.VPADeployType, retrieved.Manifest.OrchestrationType)
+	assert.Equal(t, map[string]any{"key": "value"}, retrieved.Manifest.Payload)
+	assert.True(t, created.Equal(retrieved.CreatedTimestamp))
+	assert.True(t, retrieved.SentTimestamp.IsZero())
+
+	sent := created.Add(time.Second)
+	retrieved.State = api.OutboxStateSent
+	retrieved.Attempts = 2
+	retrieved.LastError = "error"
+	retrieved.SentTimestamp = sent
+	require.NoError(t, estore.Update(txCtx, retrieved))
+
+	updated, err := estore.FindByID(txCtx, "entry-1")
+	require.NoError(t, err)
+	assert.Equal(t, api.OutboxStateSent, updated.State)
+	assert.Equal(t, 2, updated.Attempts)
+	assert.Equal(t, "error", updated.LastError)
+	assert.True(t, sent.Equal(updated.SentTimestamp))
+}
+
+func TestNewOutboxStore_FindByState(t *testing.T) {
+	setupOutboxTable(t, testDB)
+	defer cleanupOutboxTestData(t, testDB)
+
+	estore := newOutboxStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	for i, state := range []api.OutboxState{api.OutboxStatePending, api.OutboxStateSent, api.OutboxStatePending} {
+		_, err = estore.Create(txCtx, &api.OutboxEntry{
+			Entity:           api.Entity{ID: fmt.Sprintf("entry-%d", i), Version: 1},
+			State:            state,
+			Manifest:         model.OrchestrationManifest{ID: fmt.Sprintf("manifest-%d", i)},
+			CreatedTimestamp: time.Now().UTC(),
+		})
+		require.NoError(t, err)
+	}
+
+	var ids []string
+	for entry, err := range estore.FindByPredicate(txCtx, query.Eq("state", string(api.OutboxStatePending))) {
+		require.NoError(t, err)
+		ids = append(ids, entry.ID)
+	}
+	assert.ElementsMatch(t, []string{"entry-0", "entry-2"}, ids)
+}
+
+func cleanupOutboxTestData(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(fmt.Sprintf("DELETE FROM %s", cfmOutboxTable))
+	require.NoError(t, err)
+}
+
+func setupOutboxTable(t *testing.T, db *sql.DB) {
+	err := createOutboxTable(db)
+	require.NoError(t, err)
+}
@@ -22,6 +22,7 @@ const (
 	cfmCellsTable               = "cells"
 	cfmParticipantProfilesTable = "participant_profiles"
 	cfmDataspaceProfilesTable   = "dataspace_profiles"
+	cfmOutboxTable              = "outbox"
 )
 
 // Note fields are quoted to avoid some IDEs (Goland) reformatting them to uppercase
@@ -31,9 +32,13 @@ func createTenantsTable(db *sql.DB) error {
 		CREATE TABLE IF NOT EXISTS %s (
 			id TEXT PRIMARY KEY,
 			version INT DEFAULT 1,
-			properties JSONB
-		)
-	`, cfmTenantsTable))
+			properties JSONB,
+			deletion JSONB,
+			quota JSONB
+		);
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS deletion JSONB;
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS quota JSONB
+	`, cfmTenantsTable, cfmTenantsTable, cfmTenantsTable))
 	return err
 }
 
@@ -78,8 +83,31 @@ func createCellsTable(db *sql.DB) error {
 			version INT DEFAULT 1,
 			"state" TEXT NOT NULL,
 			state_timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			properties JSONB
-		)
-	`, cfmCellsTable))
+			properties JSONB,
+			cordoned BOOLEAN NOT NULL DEFAULT FALSE,
+			drain JSONB,
+			health JSONB
+		);
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS cordoned BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS drain JSONB;
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS health JSONB
+	`, cfmCellsTable, cfmCellsTable, cfmCellsTable, cfmCellsTable))
+	return err
+}
+
+func createOutboxTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			version INT DEFAULT 1,
+			"state" TEXT NOT NULL,
+			manifest JSONB NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			sent_timestamp TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_outbox_state ON %s("state")
+	`, cfmOutboxTable, cfmOutboxTable))
 	return err
 }
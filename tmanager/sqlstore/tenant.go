@@ -23,9 +23,11 @@ import (
 )
 
 func newTenantStore() store.EntityStore[*api.Tenant] {
-	columnNames := []string{"id", "version", "properties"}
+	columnNames := []string{"id", "version", "properties", "deletion", "quota"}
 	builder := sqlstore.NewPostgresJSONBBuilder().WithJSONBFieldTypes(map[string]sqlstore.JSONBFieldType{
 		"properties": sqlstore.JSONBFieldTypeScalar,
+		"deletion":   sqlstore.JSONBFieldTypeScalar,
+		"quota":      sqlstore.JSONBFieldTypeScalar,
 	})
 
 	estore := sqlstore.NewPostgresEntityStore[*api.Tenant](
@@ -58,6 +60,18 @@ func recordToTenantEntity(_ *sql.Tx, record *sqlstore.DatabaseRecord) (*api.Tena
 			return nil, err
 		}
 	}
+
+	if bytes, ok := record.Values["deletion"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &profile.Deletion); err != nil {
+			return nil, err
+		}
+	}
+
+	if bytes, ok := record.Values["quota"].([]byte); ok && bytes != nil {
+		if err := json.Unmarshal(bytes, &profile.Quota); err != nil {
+			return nil, err
+		}
+	}
 	return profile, nil
 
 }
@@ -78,5 +92,23 @@ func tenantEntityToRecord(profile *api.Tenant) (*sqlstore.DatabaseRecord, error)
 		record.Values["properties"] = bytes
 	}
 
+	if profile.Deletion != nil {
+		bytes, err := json.Marshal(profile.Deletion)
+		if err != nil {
+			return record, err
+		}
+		record.Values["deletion"] = bytes
+	}
+
+	// Always written so that removing the quota clears the column
+	record.Values["quota"] = nil
+	if profile.Quota != nil {
+		bytes, err := json.Marshal(profile.Quota)
+		if err != nil {
+			return record, err
+		}
+		record.Values["quota"] = bytes
+	}
+
 	return record, nil
 }
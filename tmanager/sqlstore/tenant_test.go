@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/metaform/connector-fabric-manager/common/model"
 	"github.com/metaform/connector-fabric-manager/common/query"
 	"github.com/metaform/connector-fabric-manager/common/sqlstore"
 	"github.com/metaform/connector-fabric-manager/common/store"
@@ -140,6 +142,88 @@ func TestNewTenantStore_Create(t *testing.T) {
 	assert.Equal(t, int64(1), created.Version)
 }
 
+// TestNewTenantStore_Deletion tests that the cascading deletion of a tenant is persisted
+func TestNewTenantStore_Deletion(t *testing.T) {
+	setupTenantTable(t, testDB)
+	defer cleanupTenantTestData(t, testDB)
+
+	estore := newTenantStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	_, err = estore.Create(txCtx, &api.Tenant{Entity: api.Entity{ID: "deleted-tenant", Version: 1}})
+	require.NoError(t, err)
+
+	retrieved, err := estore.FindByID(txCtx, "deleted-tenant")
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.Deletion)
+
+	startTimestamp := time.Now().UTC().Truncate(time.Millisecond)
+	retrieved.Deletion = &api.TenantDeletion{
+		StartTimestamp: startTimestamp,
+		Participants:   2,
+		Failures:       map[string]string{"participant-1": "dispose failed"},
+	}
+	err = estore.Update(txCtx, retrieved)
+	require.NoError(t, err)
+
+	updated, err := estore.FindByID(txCtx, "deleted-tenant")
+	require.NoError(t, err)
+	require.NotNil(t, updated.Deletion)
+	assert.True(t, startTimestamp.Equal(updated.Deletion.StartTimestamp))
+	assert.Equal(t, 2, updated.Deletion.Participants)
+	assert.Equal(t, map[string]string{"participant-1": "dispose failed"}, updated.Deletion.Failures)
+}
+
+// TestNewTenantStore_Quota tests that the quota of a tenant is persisted and can be removed
+func TestNewTenantStore_Quota(t *testing.T) {
+	setupTenantTable(t, testDB)
+	defer cleanupTenantTestData(t, testDB)
+
+	estore := newTenantStore()
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(ctx, sqlstore.SQLTransactionKey, tx)
+
+	maxParticipants := 5
+	_, err = estore.Create(txCtx, &api.Tenant{
+		Entity: api.Entity{ID: "quota-tenant", Version: 1},
+		Quota: &api.TenantQuota{
+			MaxParticipants:          &maxParticipants,
+			MaxVPAs:                  map[model.VPAType]int{model.ConnectorType: 3},
+			AllowedDataspaceProfiles: []string{"dataspace-1"},
+			AllowedCells:             []string{"cell-1", "cell-2"},
+		},
+	})
+	require.NoError(t, err)
+
+	retrieved, err := estore.FindByID(txCtx, "quota-tenant")
+	require.NoError(t, err)
+	require.NotNil(t, retrieved.Quota)
+	require.NotNil(t, retrieved.Quota.MaxParticipants)
+	assert.Equal(t, 5, *retrieved.Quota.MaxParticipants)
+	assert.Equal(t, map[model.VPAType]int{model.ConnectorType: 3}, retrieved.Quota.MaxVPAs)
+	assert.Equal(t, []string{"dataspace-1"}, retrieved.Quota.AllowedDataspaceProfiles)
+	assert.Equal(t, []string{"cell-1", "cell-2"}, retrieved.Quota.AllowedCells)
+
+	retrieved.Quota = nil
+	err = estore.Update(txCtx, retrieved)
+	require.NoError(t, err)
+
+	updated, err := estore.FindByID(txCtx, "quota-tenant")
+	require.NoError(t, err)
+	assert.Nil(t, updated.Quota)
+}
+
 // TestNewTenantStore_SearchByPropertiesPredicate_NameEquality tests searching by properties.name
 func TestNewTenantStore_SearchByPropertiesPredicate_NameEquality(t *testing.T) {
 	setupTenantTable(t, testDB)